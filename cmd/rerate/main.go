@@ -0,0 +1,190 @@
+// Command rerate rebuilds Torrons ratings, the Results before/after columns
+// and every UserEloSnapshot by replaying the current rating season's votes
+// (everything since the active campaign reset the ratings) through a
+// rating engine. By default it only prints how the per-class rankings would
+// change; pass -commit to write the replay back in one transaction. Votes
+// from users the fraud analyzer has quarantined only rebuild those users'
+// snapshots, as they do live.
+//
+// With -commit the history is read, replayed and written back in one
+// transaction that holds off new votes throughout, so the committed
+// ratings have seen every vote; voting waits for it to finish.
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/viper"
+
+	"github.com/krtffl/torro/internal/api"
+	"github.com/krtffl/torro/internal/config"
+	"github.com/krtffl/torro/internal/domain"
+	"github.com/krtffl/torro/internal/logger"
+	"github.com/krtffl/torro/internal/rating"
+	"github.com/krtffl/torro/internal/replay"
+	"github.com/krtffl/torro/internal/repository"
+)
+
+var (
+	configPath = flag.String(
+		"config",
+		"config/config.yaml",
+		"path from where the config file will be loaded",
+	)
+	algorithm = flag.String(
+		"algorithm",
+		"",
+		"rating engine to replay with: elo, glicko2 or trueskill (defaults to the configured one)",
+	)
+	eloK            = flag.Float64("elo-k", 0, "Elo K-factor (defaults to the configured one)")
+	glicko2Tau      = flag.Float64("glicko2-tau", 0, "Glicko-2 tau (defaults to the configured one)")
+	trueSkillBeta   = flag.Float64("trueskill-beta", 0, "TrueSkill beta (defaults to the configured one)")
+	trueSkillTau    = flag.Float64("trueskill-tau", 0, "TrueSkill tau (defaults to the configured one)")
+	trueSkillDraw   = flag.Float64("trueskill-draw-probability", 0, "TrueSkill draw probability (defaults to the configured one)")
+	excludeUsers    = flag.String("exclude-users", "", "comma-separated user ids whose votes are left out")
+	excludeFile     = flag.String("exclude-users-file", "", "file with one user id per line whose votes are left out")
+	excludeCampaign = flag.String("exclude-campaign", "", "campaign id whose votes are left out")
+	commit          = flag.Bool("commit", false, "write the replay back to the database (default is a dry run)")
+)
+
+func main() {
+	flag.Parse()
+
+	cfg := config.Load(viper.New(), *configPath)
+
+	engine, err := rating.New(orString(*algorithm, cfg.Rating.Algorithm), rating.Options{
+		EloK:          orFloat(*eloK, cfg.Rating.EloK),
+		Glicko2Tau:    orFloat(*glicko2Tau, cfg.Rating.Glicko2Tau),
+		TrueSkillBeta: orFloat(*trueSkillBeta, cfg.Rating.TrueSkillBeta),
+		TrueSkillTau:  orFloat(*trueSkillTau, cfg.Rating.TrueSkillTau),
+
+		TrueSkillDrawProbability: orFloat(*trueSkillDraw, cfg.Rating.TrueSkillDrawProbability),
+	})
+	if err != nil {
+		logger.Fatal("[Rerate] - Failed to configure rating engine. %v", err)
+	}
+
+	excluded, err := loadExcludedUsers(*excludeUsers, *excludeFile)
+	if err != nil {
+		logger.Fatal("[Rerate] - Failed to read excluded users. %v", err)
+	}
+
+	db, err := api.NewDatabaseConnection(cfg.Database, false)
+	if err != nil {
+		logger.Fatal("[Rerate] - Failed to connect to database. %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	torroRepo := repository.NewTorroRepo(db)
+	replayRepo := repository.NewReplayRepo(db)
+
+	torrons, err := torroRepo.List(ctx)
+	if err != nil {
+		logger.Fatal("[Rerate] - Failed to list torrons. %v", err)
+	}
+
+	compute := func(votes []*domain.ReplayVote) *domain.Replay {
+		return replay.Run(engine, torrons, votes, replay.Options{
+			ExcludeUsers:    excluded,
+			ExcludeCampaign: *excludeCampaign,
+		})
+	}
+
+	var result *domain.Replay
+	if *commit {
+		result, err = replayRepo.Rebuild(ctx, compute)
+		if err != nil {
+			logger.Fatal("[Rerate] - Failed to commit replay. %v", err)
+		}
+	} else {
+		votes, err := replayRepo.ListVotes(ctx)
+		if err != nil {
+			logger.Fatal("[Rerate] - Failed to list votes. %v", err)
+		}
+		result = compute(votes)
+	}
+
+	fmt.Printf("Replayed %d votes with %s (%d applied, %d excluded, %d from quarantined users)\n\n",
+		len(result.Results), engine.Name(), result.Applied, result.Skipped, result.Quarantined)
+	printDiff(replay.RankDiff(torrons, result))
+
+	if !*commit {
+		fmt.Printf("\nDry run: nothing was written. Pass -commit to apply.\n")
+		return
+	}
+
+	fmt.Printf("\nCommitted: %d torrons, %d results and %d user snapshots rewritten.\n",
+		len(result.Torrons), len(result.Results), len(result.Snapshots))
+}
+
+// printDiff prints one right-aligned table per class, torrons in their new
+// order, with their live rank and how far they moved.
+func printDiff(diff []replay.RankChange) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	class := ""
+	for _, c := range diff {
+		if c.Class != class {
+			if class != "" {
+				fmt.Fprintln(w, "\t\t\t\t\t\t")
+			}
+			class = c.Class
+			fmt.Fprintf(w, "class %s\tnew\tlive\tmoved\tnew rating\tlive rating\t\n", class)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%+d\t%.1f\t%.1f\t\n",
+			c.Name, c.NewRank, c.LiveRank, c.Moved(), c.NewRating, c.LiveRating)
+	}
+	w.Flush()
+}
+
+// loadExcludedUsers merges the comma-separated list and the one-id-per-line
+// file into a set. Blank lines and lines starting with # are ignored.
+func loadExcludedUsers(list, file string) (map[string]bool, error) {
+	excluded := make(map[string]bool)
+	for _, id := range strings.Split(list, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			excluded[id] = true
+		}
+	}
+
+	if file == "" {
+		return excluded, nil
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		excluded[line] = true
+	}
+
+	return excluded, scanner.Err()
+}
+
+func orString(v, def string) string {
+	if v == "" {
+		return def
+	}
+	return v
+}
+
+func orFloat(v, def float64) float64 {
+	if v <= 0 {
+		return def
+	}
+	return v
+}
@@ -3,7 +3,6 @@ package api
 import (
 	"context"
 	"database/sql"
-	"errors"
 	"fmt"
 	"time"
 
@@ -11,16 +10,19 @@ import (
 	"github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/golang-migrate/migrate/v4/source/iofs"
-	"github.com/google/uuid"
 	_ "github.com/lib/pq"
 	"github.com/oxtoacart/bpool"
 
 	torrons "github.com/krtffl/torro"
+	"github.com/krtffl/torro/internal/catalog"
 	"github.com/krtffl/torro/internal/config"
 	"github.com/krtffl/torro/internal/domain"
 	"github.com/krtffl/torro/internal/http"
 	"github.com/krtffl/torro/internal/logger"
+	"github.com/krtffl/torro/internal/matchmaking"
+	"github.com/krtffl/torro/internal/rating"
 	"github.com/krtffl/torro/internal/repository"
+	"github.com/krtffl/torro/internal/trust"
 )
 
 type Torrons struct {
@@ -54,15 +56,66 @@ func New(c *config.Config, runMigrations bool) *Torrons {
 	userEloRepo := repository.NewUserEloSnapshotRepo(db)
 	campaignRepo := repository.NewCampaignRepo(db)
 	bracketRepo := repository.NewBracketRepo(db)
+	bracketPredictionRepo := repository.NewBracketPredictionRepo(db)
 	adventVoteRepo := repository.NewAdventVoteRepo(db)
 	friendCircleRepo := repository.NewFriendCircleRepo(db)
 	pressStatsRepo := repository.NewPressStatsRepo(db)
 	wrappedStatsRepo := repository.NewWrappedStatsRepo(db)
 	personaRepo := repository.NewPersonaRepo(db)
+	strengthRepo := repository.NewStrengthRepo(db)
+	fraudRepo := repository.NewFraudRepo(db)
+	replayRepo := repository.NewReplayRepo(db)
+	voteUndoRepo := repository.NewVoteUndoRepo(db)
+	campaignRatingRepo := repository.NewCampaignRatingRepo(db)
+	rankingSubmissionRepo := repository.NewRankingSubmissionRepo(db)
+	trustRepo := repository.NewTrustRepo(db)
+	reasonRepo := repository.NewReasonRepo(db)
+	similarityRepo := repository.NewSimilarityRepo(db)
+	tasteTwinRepo := repository.NewTasteTwinRepo(db)
+
+	ratingEngine, err := rating.New(c.Rating.Algorithm, rating.Options{
+		EloK:          c.Rating.EloK,
+		Glicko2Tau:    c.Rating.Glicko2Tau,
+		TrueSkillBeta: c.Rating.TrueSkillBeta,
+		TrueSkillTau:  c.Rating.TrueSkillTau,
+
+		TrueSkillDrawProbability: c.Rating.TrueSkillDrawProbability,
+	})
+	if err != nil {
+		logger.Fatal("[API - New] - "+
+			"Failed to configure rating engine. %v", err)
+	}
+	logger.Info("[API - New] - Using the %s rating engine", ratingEngine.Name())
 
-	if err := CheckPairingsCreated(db, paringRepo, torroRepo, classRepo); err != nil {
+	pairingSelector, err := matchmaking.New(c.Pairing.Selector, paringRepo, matchmaking.Options{
+		AdaptiveRatio:  c.Pairing.AdaptiveRatio,
+		PersonalWeight: c.Pairing.PersonalWeight,
+	})
+	if err != nil {
+		logger.Fatal("[API - New] - "+
+			"Failed to configure pairing selector. %v", err)
+	}
+	logger.Info("[API - New] - Using the %s pairing selector", pairingSelector.Name())
+
+	voteWeighting, err := trust.New(c.Trust.Policy, trust.Options{
+		MinWeight:      c.Trust.MinWeight,
+		FullAgeDays:    c.Trust.FullAgeDays,
+		FullVotes:      c.Trust.FullVotes,
+		FullStreak:     c.Trust.FullStreak,
+		AgeShare:       c.Trust.AgeShare,
+		VotesShare:     c.Trust.VotesShare,
+		AgreementShare: c.Trust.AgreementShare,
+		StreakShare:    c.Trust.StreakShare,
+	})
+	if err != nil {
 		logger.Fatal("[API - New] - "+
-			"Failed to check pairings. %v", err)
+			"Failed to configure vote weighting. %v", err)
+	}
+	logger.Info("[API - New] - Using the %s vote weighting", voteWeighting.Name())
+
+	if err := ReconcilePairings(paringRepo, torroRepo, classRepo); err != nil {
+		logger.Fatal("[API - New] - "+
+			"Failed to reconcile pairings. %v", err)
 	}
 
 	handler := http.NewHandler(
@@ -76,12 +129,27 @@ func New(c *config.Config, runMigrations bool) *Torrons {
 		userEloRepo,
 		campaignRepo,
 		bracketRepo,
+		bracketPredictionRepo,
 		adventVoteRepo,
 		friendCircleRepo,
 		pressStatsRepo,
 		wrappedStatsRepo,
 		personaRepo,
+		strengthRepo,
+		fraudRepo,
+		replayRepo,
+		voteUndoRepo,
+		campaignRatingRepo,
+		rankingSubmissionRepo,
+		trustRepo,
+		reasonRepo,
+		similarityRepo,
+		tasteTwinRepo,
+		ratingEngine,
+		pairingSelector,
+		voteWeighting,
 		c.AdminToken,
+		c.Bracket.GranFinalRunnersUp,
 	)
 
 	if c.AdminToken == "" {
@@ -125,198 +193,23 @@ func (t *Torrons) Shutdown() {
 	logger.Info("[API - Shutdown] Shutdown complete")
 }
 
-// createGlobalPairings creates strategic pairings for the global category
-// Instead of all O(n²) combinations, this creates O(n*k) pairings where:
-// - Each torron is compared with top torrons from other categories
-// - This allows ELO to converge to a global ranking efficiently
-func createGlobalPairings(
-	ctx context.Context,
-	tx *sql.Tx,
-	torroRep domain.TorroRepo,
-	globalClassId string,
-) (int, error) {
-	// Get torrons from each category (1-4), sorted by rating (best first)
-	categories := []string{"1", "2", "3", "4"}
-	torronsByCategory := make(map[string][]*domain.Torro)
-
-	for _, categoryId := range categories {
-		torrons, err := torroRep.ListByClass(ctx, categoryId)
-		if err != nil {
-			return 0, err
-		}
-		// Filter out discontinued torrons
-		var activeTorrons []*domain.Torro
-		for _, t := range torrons {
-			if !t.Discontinued {
-				activeTorrons = append(activeTorrons, t)
-			}
-		}
-		torronsByCategory[categoryId] = activeTorrons
-	}
-
-	pairingsCreated := 0
-	pairingsMap := make(map[string]bool) // Prevent duplicates
-
-	// Strategy: Each torron competes with representatives from other categories
-	// For thorough comparison, pair each torron with:
-	// - Top 5 from each other category (if available)
-	// - This ensures cross-category comparisons while keeping count manageable
-	const pairingsPerCategory = 5
-
-	for categoryId, torrons := range torronsByCategory {
-		for _, torron := range torrons {
-			// Pair this torron with top torrons from OTHER categories
-			for otherCategoryId, otherTorrons := range torronsByCategory {
-				if otherCategoryId == categoryId {
-					continue // Skip same category
-				}
-
-				// Pair with top N torrons from this other category
-				limit := pairingsPerCategory
-				if len(otherTorrons) < limit {
-					limit = len(otherTorrons)
-				}
-
-				for i := 0; i < limit; i++ {
-					otherTorron := otherTorrons[i]
-
-					// Create pairing key to prevent duplicates (order-independent)
-					var pairingKey string
-					if torron.Id < otherTorron.Id {
-						pairingKey = torron.Id + ":" + otherTorron.Id
-					} else {
-						pairingKey = otherTorron.Id + ":" + torron.Id
-					}
-
-					// Skip if already created
-					if pairingsMap[pairingKey] {
-						continue
-					}
-
-					pairing := domain.Pairing{
-						Torro1: torron.Id,
-						Torro2: otherTorron.Id,
-						Class:  globalClassId,
-					}
-
-					inserted, err := insertPairingTx(ctx, tx, &pairing)
-					if err != nil {
-						return 0, fmt.Errorf("create global pairing (%s vs %s): %w",
-							torron.Id, otherTorron.Id, err)
-					}
-
-					pairingsMap[pairingKey] = true
-					if inserted {
-						pairingsCreated++
-					}
-				}
-			}
-		}
-	}
-
-	return pairingsCreated, nil
-}
-
-// insertPairingTx inserts a single pairing within a transaction, silently
-// skipping it if the same matchup (order-independent) already exists for this
-// class - see migration idx_pairings_unique_matchup. This makes concurrent
-// seeding across two instances that both raced to seed the same class safe:
-// whichever loses the row-level conflict just no-ops instead of erroring or
-// double-inserting. Returns whether a row was actually inserted, so callers
-// can report an accurate created-count even when some inserts are skipped.
-func insertPairingTx(ctx context.Context, tx *sql.Tx, p *domain.Pairing) (bool, error) {
-	var id string
-	err := tx.QueryRowContext(ctx,
-		`INSERT INTO "Pairings" ("Id", "Torro1", "Torro2", "Class")
-		 VALUES ($1, $2, $3, $4)
-		 ON CONFLICT (LEAST("Torro1", "Torro2"), GREATEST("Torro1", "Torro2"), "Class") DO NOTHING
-		 RETURNING "Id"`,
-		uuid.NewString(), p.Torro1, p.Torro2, p.Class,
-	).Scan(&id)
-	if errors.Is(err, sql.ErrNoRows) {
-		return false, nil // matchup already exists - not an error
-	}
-	if err != nil {
-		return false, err
-	}
-	return true, nil
-}
-
-// createAllPairs seeds every unordered pair of a regular class's torrons within
-// the given transaction.
-func createAllPairs(ctx context.Context, tx *sql.Tx, torroRep domain.TorroRepo, classId string) (int, error) {
-	t, err := torroRep.ListByClass(ctx, classId)
-	if err != nil {
-		return 0, err
-	}
-
-	created := 0
-	for i := 0; i < len(t); i++ {
-		for j := i + 1; j < len(t); j++ {
-			pairing := domain.Pairing{Torro1: t[i].Id, Torro2: t[j].Id, Class: classId}
-			inserted, err := insertPairingTx(ctx, tx, &pairing)
-			if err != nil {
-				return 0, fmt.Errorf("create pairing (%s vs %s): %w", t[i].Id, t[j].Id, err)
-			}
-			if inserted {
-				created++
-			}
-		}
-	}
-	return created, nil
-}
-
-func CheckPairingsCreated(
-	db *sql.DB,
+// ReconcilePairings brings every class's pairings in line with the torró
+// catalog (see internal/catalog) and logs what changed. Run at startup, so
+// torrons added, moved or discontinued since the last boot are picked up;
+// admins can also trigger it with POST /admin/pairings/reconcile.
+func ReconcilePairings(
 	pairingRep domain.PairingRepo,
 	torroRep domain.TorroRepo,
 	classRep domain.ClassRepo,
 ) error {
-	ctx := context.Background()
-	classes, err := classRep.List(ctx)
+	summaries, err := catalog.Reconcile(context.Background(), classRep, torroRep, pairingRep)
 	if err != nil {
 		return err
 	}
 
-	for _, c := range classes {
-		count, err := pairingRep.CountClass(ctx, c.Id)
-		if err != nil {
-			return err
-		}
-
-		if count >= 1 {
-			logger.Info("[API - New] - %s - %d pairings already created", c.Name, count)
-			continue
-		}
-
-		logger.Info("[API - New] - %s - Creating pairings", c.Name)
-
-		// Seed a class's pairings inside a single transaction: if any insert
-		// fails the whole class rolls back, instead of leaving a permanently
-		// partial set that the count<1 guard above would then treat as "done".
-		// One commit per class is also far faster than the previous per-row
-		// autocommit (thousands of round-trips).
-		tx, err := db.Begin()
-		if err != nil {
-			return err
-		}
-
-		var created int
-		if c.Id == "5" {
-			// Global (cross-category) uses the smart pairing strategy.
-			created, err = createGlobalPairings(ctx, tx, torroRep, c.Id)
-		} else {
-			created, err = createAllPairs(ctx, tx, torroRep, c.Id)
-		}
-		if err != nil {
-			_ = tx.Rollback()
-			return err
-		}
-		if err := tx.Commit(); err != nil {
-			return err
-		}
-
-		logger.Info("[API - New] - %s - Created %d pairings", c.Name, created)
+	for _, s := range summaries {
+		logger.Info("[API - New] - %s - %d pairings: %d inserted, %d revived, %d retired",
+			s.ClassName, s.Desired, s.Inserted, s.Revived, s.Retired)
 	}
 
 	return nil
@@ -0,0 +1,132 @@
+// Package catalog keeps the Pairings table in step with the torró catalog.
+// It works out the matchups each class should offer from the current
+// torrons and has the repository insert, revive or retire pairings to
+// match. A renamed torró keeps its id, so only additions, class changes,
+// discontinuations and (for the global class) rating moves change the
+// desired set.
+package catalog
+
+import (
+	"context"
+	"sort"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+// GlobalClassId is the cross-category class ("Els Àrbitres"), whose
+// pairings are built from the other classes' torrons rather than its own.
+const GlobalClassId = "5"
+
+// globalPairingsPerClass is how many of each other class's top-rated
+// torrons every torró meets in the global class. Pairing everything with
+// everything across classes would be O(n²) duels; the leaders are enough
+// for the ratings to converge on a global order.
+const globalPairingsPerClass = 5
+
+// DesiredPairings returns the matchups classId should offer given every
+// torró in the catalog: each pair of its active torrons, or for
+// GlobalClassId each active torró against the globalPairingsPerClass
+// highest-rated active torrons of every other class. Discontinued torrons
+// take part in none. The result is deterministic for a given input.
+func DesiredPairings(classId string, torrons []*domain.Torro) []*domain.Pairing {
+	if classId == GlobalClassId {
+		return desiredGlobalPairings(torrons)
+	}
+
+	var members []*domain.Torro
+	for _, t := range torrons {
+		if t.Class == classId && !t.Discontinued {
+			members = append(members, t)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].Id < members[j].Id })
+
+	pairings := make([]*domain.Pairing, 0, len(members)*(len(members)-1)/2)
+	for i := 0; i < len(members); i++ {
+		for j := i + 1; j < len(members); j++ {
+			pairings = append(pairings, &domain.Pairing{
+				Torro1: members[i].Id,
+				Torro2: members[j].Id,
+				Class:  classId,
+			})
+		}
+	}
+	return pairings
+}
+
+func desiredGlobalPairings(torrons []*domain.Torro) []*domain.Pairing {
+	byClass := make(map[string][]*domain.Torro)
+	for _, t := range torrons {
+		if t.Class != GlobalClassId && !t.Discontinued {
+			byClass[t.Class] = append(byClass[t.Class], t)
+		}
+	}
+
+	classIds := make([]string, 0, len(byClass))
+	for classId, members := range byClass {
+		classIds = append(classIds, classId)
+		// Best first; ties (every torró at the start of a season) broken
+		// by id so the same catalog always yields the same set.
+		sort.Slice(members, func(i, j int) bool {
+			if members[i].Rating != members[j].Rating {
+				return members[i].Rating > members[j].Rating
+			}
+			return members[i].Id < members[j].Id
+		})
+	}
+	sort.Strings(classIds)
+
+	var pairings []*domain.Pairing
+	seen := make(map[string]bool)
+	for _, classId := range classIds {
+		for _, t := range byClass[classId] {
+			for _, otherClassId := range classIds {
+				if otherClassId == classId {
+					continue
+				}
+				leaders := byClass[otherClassId]
+				for _, leader := range leaders[:min(globalPairingsPerClass, len(leaders))] {
+					p := &domain.Pairing{Torro1: t.Id, Torro2: leader.Id, Class: GlobalClassId}
+					if seen[p.Key()] {
+						continue
+					}
+					seen[p.Key()] = true
+					pairings = append(pairings, p)
+				}
+			}
+		}
+	}
+	return pairings
+}
+
+// Reconcile brings every class's pairings in line with the catalog and
+// returns a summary per class, in class order.
+func Reconcile(
+	ctx context.Context,
+	classRepo domain.ClassRepo,
+	torroRepo domain.TorroRepo,
+	pairingRepo domain.PairingRepo,
+) ([]*domain.PairingReconciliation, error) {
+	classes, err := classRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	torrons, err := torroRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]*domain.PairingReconciliation, 0, len(classes))
+	for _, c := range classes {
+		summary, err := pairingRepo.Reconcile(ctx, c.Id, DesiredPairings(c.Id, torrons))
+		if err != nil {
+			return nil, err
+		}
+		summary.ClassName = c.Name
+		summaries = append(summaries, summary)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].ClassId < summaries[j].ClassId })
+	return summaries, nil
+}
@@ -0,0 +1,88 @@
+package catalog
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+func keys(pairings []*domain.Pairing) map[string]bool {
+	set := make(map[string]bool, len(pairings))
+	for _, p := range pairings {
+		set[p.Key()] = true
+	}
+	return set
+}
+
+func TestDesiredPairingsClass(t *testing.T) {
+	torrons := []*domain.Torro{
+		{Id: "c", Class: "1"},
+		{Id: "a", Class: "1"},
+		{Id: "b", Class: "1"},
+		{Id: "x", Class: "1", Discontinued: true},
+		{Id: "z", Class: "2"},
+	}
+
+	got := DesiredPairings("1", torrons)
+	if len(got) != 3 {
+		t.Fatalf("got %d pairings, want 3", len(got))
+	}
+	want := []string{"1:a:b", "1:a:c", "1:b:c"}
+	for i, p := range got {
+		if p.Key() != want[i] {
+			t.Errorf("pairing %d = %s, want %s", i, p.Key(), want[i])
+		}
+	}
+
+	if got := DesiredPairings("3", torrons); len(got) != 0 {
+		t.Errorf("empty class got %d pairings", len(got))
+	}
+}
+
+func TestDesiredPairingsGlobal(t *testing.T) {
+	var torrons []*domain.Torro
+	// Two classes of six, each rated 1550 down to 1500 by index.
+	for _, class := range []string{"a", "b"} {
+		for i := 0; i < 6; i++ {
+			classId := "1"
+			if class == "b" {
+				classId = "2"
+			}
+			torrons = append(torrons, &domain.Torro{
+				Id:     fmt.Sprintf("%s%d", class, i),
+				Class:  classId,
+				Rating: float64(1550 - 10*i),
+			})
+		}
+	}
+	torrons = append(torrons,
+		&domain.Torro{Id: "b9", Class: "2", Rating: 1600, Discontinued: true},
+		&domain.Torro{Id: "g0", Class: GlobalClassId, Rating: 1700},
+	)
+
+	// Every torró meets the other class's top five, so the only matchup
+	// missing is between the two sixth-placed torrons.
+	got := keys(DesiredPairings(GlobalClassId, torrons))
+	if len(got) != 35 {
+		t.Fatalf("got %d global pairings, want 35", len(got))
+	}
+	if got["5:a5:b5"] {
+		t.Error("the two bottom torrons were paired")
+	}
+	for k := range got {
+		if strings.Contains(k, "b9") || strings.Contains(k, "g0") {
+			t.Errorf("%s involves a discontinued or global-class torró", k)
+		}
+	}
+
+	// a5 climbs to the top of its class, pushing a4 out of the top five:
+	// now a4 and b5 are the pair left out.
+	torrons[5].Rating = 1600
+	got = keys(DesiredPairings(GlobalClassId, torrons))
+	if len(got) != 35 || !got["5:a5:b5"] || got["5:a4:b5"] {
+		t.Errorf("after a5 climbs: %d pairings, a5-b5 %v, a4-b5 %v; want 35, true, false",
+			len(got), got["5:a5:b5"], got["5:a4:b5"])
+	}
+}
@@ -49,6 +49,80 @@ type Database struct {
 	SSLMode  string `mapstructure:"ssl"      yaml:"ssl"`
 }
 
+// Rating selects and tunes the rating engine applied to every Phase 1 vote
+// (see internal/rating). Parameters left at zero fall back to the engine's
+// own defaults.
+type Rating struct {
+	// Algorithm is one of "elo" (the default), "glicko2" or "trueskill".
+	Algorithm string `mapstructure:"algorithm" yaml:"algorithm"`
+
+	// EloK is the Elo K-factor.
+	EloK float64 `mapstructure:"elo_k" yaml:"elo_k"`
+
+	// Glicko2Tau is the Glicko-2 system constant bounding how fast a
+	// torró's volatility can change.
+	Glicko2Tau float64 `mapstructure:"glicko2_tau" yaml:"glicko2_tau"`
+
+	// TrueSkillBeta and TrueSkillTau are TrueSkill's performance noise and
+	// dynamics factor, on the same 1500-centred scale as the ratings.
+	TrueSkillBeta float64 `mapstructure:"trueskill_beta" yaml:"trueskill_beta"`
+	TrueSkillTau  float64 `mapstructure:"trueskill_tau"  yaml:"trueskill_tau"`
+
+	// TrueSkillDrawProbability is TrueSkill's expected share of "can't
+	// decide" votes between two evenly matched torrons.
+	TrueSkillDrawProbability float64 `mapstructure:"trueskill_draw_probability" yaml:"trueskill_draw_probability"`
+}
+
+// Pairing selects and tunes how the vote screen picks the next Phase 1 duel
+// (see internal/matchmaking).
+type Pairing struct {
+	// Selector is one of "coverage", "adaptive" (the default) or "random".
+	Selector string `mapstructure:"selector" yaml:"selector"`
+
+	// AdaptiveRatio is the share of duels the adaptive selector picks by
+	// score; the rest stay uniformly random. Unset means the selector's
+	// default.
+	AdaptiveRatio *float64 `mapstructure:"adaptive_ratio" yaml:"adaptive_ratio"`
+
+	// PersonalWeight is how strongly a signed-in user's own snapshot gaps
+	// steer the adaptive selector, from 0 (global needs only) to 1. Unset
+	// means the selector's default.
+	PersonalWeight *float64 `mapstructure:"personal_weight" yaml:"personal_weight"`
+}
+
+// Trust selects and tunes how much each vote moves the global ratings (see
+// internal/trust). Parameters left unset, and thresholds left at zero, fall
+// back to the policy's own defaults.
+type Trust struct {
+	// Policy is one of "trust" or "none" (the default), where every vote
+	// counts in full.
+	Policy string `mapstructure:"policy" yaml:"policy"`
+
+	// MinWeight is what a vote by a user with no trust yet counts for,
+	// from 0 to 1.
+	MinWeight *float64 `mapstructure:"min_weight" yaml:"min_weight"`
+
+	// FullAgeDays, FullVotes and FullStreak are the account age, vote
+	// count and longest streak at which each earns its full share.
+	FullAgeDays float64 `mapstructure:"full_age_days" yaml:"full_age_days"`
+	FullVotes   float64 `mapstructure:"full_votes"    yaml:"full_votes"`
+	FullStreak  float64 `mapstructure:"full_streak"   yaml:"full_streak"`
+
+	// The shares are how much age, vote count, agreement with the
+	// community and streak count towards the score, relative to each other.
+	// A share of 0 leaves its component out.
+	AgeShare       *float64 `mapstructure:"age_share"       yaml:"age_share"`
+	VotesShare     *float64 `mapstructure:"votes_share"     yaml:"votes_share"`
+	AgreementShare *float64 `mapstructure:"agreement_share" yaml:"agreement_share"`
+	StreakShare    *float64 `mapstructure:"streak_share"    yaml:"streak_share"`
+}
+
+type Bracket struct {
+	// GranFinalRunnersUp sends each class bracket's runner-up to the Gran
+	// Final alongside its champion
+	GranFinalRunnersUp bool `mapstructure:"gran_final_runners_up" yaml:"gran_final_runners_up"`
+}
+
 type Config struct {
 	// Port is the port the HTTP server will listen to
 	Port uint `mapstructure:"port" yaml:"port"`
@@ -77,6 +151,18 @@ type Config struct {
 	// that excludes your clients to effectively trust no forwarding headers.
 	TrustedProxies []string `mapstructure:"trusted_proxies" yaml:"trusted_proxies"`
 
+	// Rating selects the rating engine used for Phase 1 votes
+	Rating Rating `mapstructure:"rating" yaml:"rating"`
+
+	// Pairing selects how the next Phase 1 duel is picked
+	Pairing Pairing `mapstructure:"pairing" yaml:"pairing"`
+
+	// Trust selects how much each vote counts in the global ratings
+	Trust Trust `mapstructure:"trust" yaml:"trust"`
+
+	// Bracket configures Phase 2's Gran Final meta-bracket
+	Bracket Bracket `mapstructure:"bracket" yaml:"bracket"`
+
 	// Database contains the configuration to connect to the
 	// database instance
 	Database Database `mapstructure:"database" yaml:"database"`
@@ -161,6 +247,20 @@ func overrideWithEnvVars(config *Config) {
 	if key := secretEnv("INDEXNOW_KEY"); key != "" {
 		config.IndexNowKey = key
 	}
+	if algorithm := os.Getenv("RATING_ALGORITHM"); algorithm != "" {
+		config.Rating.Algorithm = algorithm
+	}
+	if selector := os.Getenv("PAIRING_SELECTOR"); selector != "" {
+		config.Pairing.Selector = selector
+	}
+	if policy := os.Getenv("VOTE_WEIGHTING"); policy != "" {
+		config.Trust.Policy = policy
+	}
+	if runnersUp := os.Getenv("GRAN_FINAL_RUNNERS_UP"); runnersUp != "" {
+		if b, err := strconv.ParseBool(runnersUp); err == nil {
+			config.Bracket.GranFinalRunnersUp = b
+		}
+	}
 	if proxies := os.Getenv("TRUSTED_PROXIES"); proxies != "" {
 		var list []string
 		for _, p := range strings.Split(proxies, ",") {
@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/spf13/viper"
 )
 
 func TestSecretEnv(t *testing.T) {
@@ -42,3 +44,25 @@ func TestSecretEnv(t *testing.T) {
 		}
 	})
 }
+
+// TestLoadHonoursZeroWeights checks a weight set to 0 in the config file
+// reaches the selector and the trust policy as 0, not as unset.
+func TestLoadHonoursZeroWeights(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	yaml := "pairing:\n  personal_weight: 0\ntrust:\n  min_weight: 0\n"
+	if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+		t.Fatalf("failed to write test config file: %v", err)
+	}
+
+	cfg := Load(viper.New(), path)
+
+	if w := cfg.Pairing.PersonalWeight; w == nil || *w != 0 {
+		t.Errorf("Pairing.PersonalWeight = %v, want 0", w)
+	}
+	if w := cfg.Trust.MinWeight; w == nil || *w != 0 {
+		t.Errorf("Trust.MinWeight = %v, want 0", w)
+	}
+	if cfg.Pairing.AdaptiveRatio != nil {
+		t.Errorf("Pairing.AdaptiveRatio = %v, want unset", *cfg.Pairing.AdaptiveRatio)
+	}
+}
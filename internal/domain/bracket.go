@@ -19,6 +19,54 @@ const (
 	BracketStatusCompleted  = "completed"
 )
 
+// Bracket formats. A single-elimination bracket knocks a torró out on its
+// first defeat. A double-elimination one drops it to the losers side
+// instead, where a second defeat knocks it out; the two sides' champions
+// meet in the grand final. A Swiss bracket knocks no one out: it plays a
+// fixed number of rounds pairing torrons on equal scores, and the final
+// standings name the champion. A groups-then-knockout bracket draws its
+// torrons into round-robin groups first; the top of each group table goes
+// through to a single-elimination tree.
+const (
+	BracketFormatSingleElimination = "single_elimination"
+	BracketFormatDoubleElimination = "double_elimination"
+	BracketFormatSwiss             = "swiss"
+	BracketFormatGroupsKnockout    = "groups_knockout"
+)
+
+// Bracket sides. Single-elimination and Swiss matches, and the knockout of
+// a groups-then-knockout bracket, are all on the winners side; its group
+// stage is on the group side.
+const (
+	BracketSideWinners    = "winners"
+	BracketSideLosers     = "losers"
+	BracketSideGrandFinal = "grand_final"
+	BracketSideGroup      = "group"
+)
+
+// Bracket kinds. A class bracket is played by one class's torrons; the Gran
+// Final is the Global class's meta-bracket, played by the champions (and
+// optionally runners-up) of a campaign's class brackets once they have all
+// completed.
+const (
+	BracketKindClass     = "class"
+	BracketKindGranFinal = "gran_final"
+)
+
+// Bracket seeding strategies: how a bracket's field is chosen and ordered
+// 1..N. Rating seeds by the live global Torro.Rating, CampaignRating by the
+// global rating a torró finished (or stands at) in one campaign, Strength
+// by the batch Bradley–Terry fit (see TorroStrength). Random draws the
+// field from a published seed, and Manual takes an admin's seed list as
+// given.
+const (
+	BracketSeedingRating         = "rating"
+	BracketSeedingCampaignRating = "campaign_rating"
+	BracketSeedingStrength       = "strength"
+	BracketSeedingRandom         = "random"
+	BracketSeedingManual         = "manual"
+)
+
 // BracketMatchStatus constants
 const (
 	BracketMatchStatusPending   = "pending"
@@ -38,38 +86,133 @@ const DefaultBracketSize = 8
 // exceeds any realistic torró category.
 const MaxBracketSize = 128
 
-// Bracket represents a single-elimination knockout tournament for one class
-// within one campaign, seeded from Phase 1 ELO ratings.
+// Bracket represents a Phase 2 tournament for one class within one
+// campaign, seeded from Phase 1 as Seeding records. Kind is one of the
+// BracketKind* constants, Format one of the
+// BracketFormat* constants; GrandFinalReset only matters to a
+// double-elimination bracket (see IsDoubleElimination). Rounds is how many
+// rounds a Swiss bracket plays, or how many matchdays the group stage of a
+// groups-then-knockout bracket lasts, and 0 for the knockout formats, whose
+// rounds follow from Size. A Swiss bracket's Size is its number of entries;
+// a groups-then-knockout one's is the size of its knockout tree.
 type Bracket struct {
-	Id           string  `db:"Id"           json:"id"`
-	CampaignId   string  `db:"CampaignId"   json:"campaign_id"`
-	ClassId      string  `db:"ClassId"      json:"class_id"`
-	Size         int     `db:"Size"         json:"size"`
-	CurrentRound int     `db:"CurrentRound" json:"current_round"`
-	Status       string  `db:"Status"       json:"status"`
-	ChampionId   *string `db:"ChampionId"   json:"champion_id,omitempty"`
-	CreatedAt    string  `db:"CreatedAt"    json:"created_at"`
-	CompletedAt  *string `db:"CompletedAt"  json:"completed_at,omitempty"`
+	Id              string  `db:"Id"              json:"id"`
+	CampaignId      string  `db:"CampaignId"      json:"campaign_id"`
+	ClassId         string  `db:"ClassId"         json:"class_id"`
+	Kind            string  `db:"Kind"            json:"kind"`
+	Size            int     `db:"Size"            json:"size"`
+	Format          string  `db:"Format"          json:"format"`
+	GrandFinalReset bool    `db:"GrandFinalReset" json:"grand_final_reset"`
+	Rounds          int     `db:"Rounds"          json:"rounds,omitempty"`
+	CurrentRound    int     `db:"CurrentRound"    json:"current_round"`
+	Status          string  `db:"Status"          json:"status"`
+	ChampionId      *string `db:"ChampionId"      json:"champion_id,omitempty"`
+	CreatedAt       string  `db:"CreatedAt"       json:"created_at"`
+	CompletedAt     *string `db:"CompletedAt"     json:"completed_at,omitempty"`
+
+	Seeding BracketSeeding `db:"-" json:"seeding"`
+}
+
+// BracketSeeding is how a bracket was seeded: its strategy (one of the
+// BracketSeeding* constants) and the inputs that reproduce its seeds.
+type BracketSeeding struct {
+	Strategy string `json:"strategy"`
+	BracketSeedingInputs
+}
+
+// BracketSeedingInputs are a seeding strategy's inputs, stored with the
+// bracket as they were used. CampaignId is the campaign a CampaignRating
+// seeding rated by, RandomSeed the published seed of a Random draw, Seeds
+// a Manual seed list (torró ids, best first) and FittedAt when the
+// strength fit a Strength seeding read was run. Exclude lists torrons kept
+// out of the bracket whatever the strategy.
+type BracketSeedingInputs struct {
+	CampaignId string   `json:"campaign_id,omitempty"`
+	RandomSeed *int64   `json:"random_seed,omitempty"`
+	Seeds      []string `json:"seeds,omitempty"`
+	Exclude    []string `json:"exclude,omitempty"`
+	FittedAt   string   `json:"fitted_at,omitempty"`
+}
+
+// IsGranFinal reports whether the bracket is a campaign's Gran Final
+// meta-bracket rather than a class bracket.
+func (b *Bracket) IsGranFinal() bool {
+	return b.Kind == BracketKindGranFinal
+}
+
+// IsDoubleElimination reports whether the bracket has a losers side. A
+// bracket with no Format predates formats and is single-elimination.
+func (b *Bracket) IsDoubleElimination() bool {
+	return b.Format == BracketFormatDoubleElimination
+}
+
+// IsSwiss reports whether the bracket is played in the Swiss system.
+func (b *Bracket) IsSwiss() bool {
+	return b.Format == BracketFormatSwiss
+}
+
+// IsGroupsKnockout reports whether the bracket opens with a group stage.
+func (b *Bracket) IsGroupsKnockout() bool {
+	return b.Format == BracketFormatGroupsKnockout
+}
+
+// GroupStageRounds returns how many rounds are played before the knockout
+// tree: the group stage's matchdays, 0 for every other format.
+func (b *Bracket) GroupStageRounds() int {
+	if b.IsGroupsKnockout() {
+		return b.Rounds
+	}
+	return 0
+}
+
+// BracketGroup is one round-robin group of a groups-then-knockout
+// bracket. Position orders the groups from 0; Name is what the overview
+// shows ("A", "B", ...).
+type BracketGroup struct {
+	Id        string `db:"Id"        json:"id"`
+	BracketId string `db:"BracketId" json:"bracket_id"`
+	Position  int    `db:"Position"  json:"position"`
+	Name      string `db:"Name"      json:"name"`
+}
+
+// BracketRound is the schedule of one round of a bracket created with
+// deadlines: it opens at OpensAt and is closed, whatever its votes, at
+// ClosesAt. A round can open early, when the one before it is voted on
+// every match before its own deadline.
+type BracketRound struct {
+	BracketId string `db:"BracketId" json:"bracket_id"`
+	Round     int    `db:"Round"     json:"round"`
+	OpensAt   string `db:"OpensAt"   json:"opens_at"`
+	ClosesAt  string `db:"ClosesAt"  json:"closes_at"`
 }
 
 // BracketEntry is one seeded participant in a bracket. Seeds are assigned
-// 1..N by descending Phase 1 rating at bracket-creation time.
+// 1..N at bracket-creation time by the bracket's Seeding; SeedRating is
+// the score it was ranked by (its global or campaign rating, or its fitted
+// strength), or its global rating for a random or manual seeding. GroupId
+// is the group it was drawn into, nil outside a groups-then-knockout
+// bracket.
 type BracketEntry struct {
 	Id         string  `db:"Id"         json:"id"`
 	BracketId  string  `db:"BracketId"  json:"bracket_id"`
 	TorronId   string  `db:"TorronId"   json:"torron_id"`
 	Seed       int     `db:"Seed"       json:"seed"`
 	SeedRating float64 `db:"SeedRating" json:"seed_rating"`
+	GroupId    *string `db:"GroupId"    json:"group_id,omitempty"`
 }
 
-// BracketMatch is a single knockout match: (Round, Slot) uniquely identify
-// its position in the bracket. Torro2Id is nil for a bye (Torro1Id
-// auto-advances with no vote needed). WinnerId is nil until the match is
-// decided.
+// BracketMatch is a single knockout match: (Round, Side, Slot) uniquely
+// identify its position in the bracket. Round is bracket-wide, so in a
+// double-elimination bracket a winners-side and a losers-side match can be
+// played in the same round. GroupId is set on group-side matches only.
+// Torro2Id is nil for a bye (Torro1Id auto-advances with no vote needed).
+// WinnerId is nil until the match is decided.
 type BracketMatch struct {
 	Id        string  `db:"Id"        json:"id"`
 	BracketId string  `db:"BracketId" json:"bracket_id"`
 	Round     int     `db:"Round"     json:"round"`
+	Side      string  `db:"Side"      json:"side"`
+	GroupId   *string `db:"GroupId"   json:"group_id,omitempty"`
 	Slot      int     `db:"Slot"      json:"slot"`
 	Torro1Id  string  `db:"Torro1Id"  json:"torro1_id"`
 	Torro2Id  *string `db:"Torro2Id"  json:"torro2_id,omitempty"`
@@ -83,6 +226,20 @@ func (m *BracketMatch) IsBye() bool {
 	return m.Torro2Id == nil
 }
 
+// LoserId returns the competitor who lost a decided match, nil for a bye
+// or an undecided match.
+func (m *BracketMatch) LoserId() *string {
+	if m.WinnerId == nil || m.Torro2Id == nil {
+		return nil
+	}
+	if *m.WinnerId == m.Torro1Id {
+		loser := *m.Torro2Id
+		return &loser
+	}
+	loser := m.Torro1Id
+	return &loser
+}
+
 // BracketMatchVote is a single user's vote for a single match. The
 // (MatchId, UserId) pair is unique at the DB level, enforcing "at most one
 // vote per user per match".
@@ -95,8 +252,9 @@ type BracketMatchVote struct {
 }
 
 // BracketRepo defines data access for the whole Phase 2 knockout schema
-// (brackets, their seeded entries, their matches and the per-match vote
-// log). It is intentionally one interface across four tables, mirroring
+// (brackets, their round schedules, their groups, their seeded entries,
+// their matches and the per-match vote log). It is intentionally one
+// interface across six tables, mirroring
 // how the "bracket" concept is owned by a single domain/repository file.
 type BracketRepo interface {
 	// -- Brackets --
@@ -115,12 +273,29 @@ type BracketRepo interface {
 	// class, regardless of campaign. Used by the bracket overview page.
 	GetLatestByClass(ctx context.Context, classId string) (*Bracket, error)
 
+	// GetLatestGranFinal retrieves the most recently created Gran Final
+	// meta-bracket, regardless of campaign.
+	GetLatestGranFinal(ctx context.Context) (*Bracket, error)
+
 	// UpdateRound advances a bracket's current round pointer.
 	UpdateRound(ctx context.Context, id string, round int) error
 
 	// Complete marks a bracket as completed with its champion.
 	Complete(ctx context.Context, id string, championId string) error
 
+	// -- Bracket rounds (schedule) --
+
+	// ListRounds lists a bracket's round schedule, ordered by round. It is
+	// empty for a bracket created without deadlines.
+	ListRounds(ctx context.Context, bracketId string) ([]*BracketRound, error)
+
+	// GetRound retrieves the schedule of one round of a bracket.
+	GetRound(ctx context.Context, bracketId string, round int) (*BracketRound, error)
+
+	// ListOverdueBrackets lists the in-progress brackets whose current
+	// round closed at or before now (RFC 3339).
+	ListOverdueBrackets(ctx context.Context, now string) ([]*Bracket, error)
+
 	// -- Bracket entries (seeding) --
 
 	// CreateEntry adds a seeded participant to a bracket.
@@ -130,6 +305,14 @@ type BracketRepo interface {
 	// seed ascending.
 	ListEntries(ctx context.Context, bracketId string) ([]*BracketEntry, error)
 
+	// -- Bracket groups --
+
+	// CreateGroup adds a round-robin group to a bracket.
+	CreateGroup(ctx context.Context, group *BracketGroup) (*BracketGroup, error)
+
+	// ListGroups lists a bracket's groups, ordered by position.
+	ListGroups(ctx context.Context, bracketId string) ([]*BracketGroup, error)
+
 	// -- Bracket matches --
 
 	// CreateMatch creates a single match.
@@ -138,12 +321,12 @@ type BracketRepo interface {
 	// GetMatch retrieves a match by ID.
 	GetMatch(ctx context.Context, id string) (*BracketMatch, error)
 
-	// ListMatchesByRound lists all matches for a bracket round, ordered by
-	// slot ascending.
+	// ListMatchesByRound lists all matches for a bracket round, both sides
+	// of it, ordered by side then slot ascending.
 	ListMatchesByRound(ctx context.Context, bracketId string, round int) ([]*BracketMatch, error)
 
 	// ListMatches lists every match ever played in a bracket, ordered by
-	// round then slot. Used to render past-round results.
+	// round, side then slot. Used to render past-round results.
 	ListMatches(ctx context.Context, bracketId string) ([]*BracketMatch, error)
 
 	// ListOpenMatchesForUser lists the still-open (pending) matches of a
@@ -168,6 +351,10 @@ type BracketRepo interface {
 	// Torrons with zero votes are simply absent from the map.
 	CountVotesByTorron(ctx context.Context, matchId string) (map[string]int, error)
 
+	// CountVotesByBracket tallies the votes of every match of a bracket,
+	// keyed by match ID then torron ID. Matches without votes are absent.
+	CountVotesByBracket(ctx context.Context, bracketId string) (map[string]map[string]int, error)
+
 	// -- Transaction methods --
 	// The vote+advance flow is a read-then-write that needs the same
 	// consistency guarantees as Phase 1's result handler, so every method
@@ -178,12 +365,18 @@ type BracketRepo interface {
 	UpdateRoundTx(tx *sql.Tx, ctx context.Context, id string, round int) error
 	CompleteTx(tx *sql.Tx, ctx context.Context, id string, championId string) error
 
+	CreateRoundTx(tx *sql.Tx, ctx context.Context, round *BracketRound) (*BracketRound, error)
+
+	CreateGroupTx(tx *sql.Tx, ctx context.Context, group *BracketGroup) (*BracketGroup, error)
+	ListGroupsTx(tx *sql.Tx, ctx context.Context, bracketId string) ([]*BracketGroup, error)
+
 	CreateEntryTx(tx *sql.Tx, ctx context.Context, entry *BracketEntry) (*BracketEntry, error)
 	ListEntriesTx(tx *sql.Tx, ctx context.Context, bracketId string) ([]*BracketEntry, error)
 
 	CreateMatchTx(tx *sql.Tx, ctx context.Context, match *BracketMatch) (*BracketMatch, error)
 	GetMatchTx(tx *sql.Tx, ctx context.Context, id string) (*BracketMatch, error)
 	ListMatchesByRoundTx(tx *sql.Tx, ctx context.Context, bracketId string, round int) ([]*BracketMatch, error)
+	ListMatchesTx(tx *sql.Tx, ctx context.Context, bracketId string) ([]*BracketMatch, error)
 	SetMatchWinnerTx(tx *sql.Tx, ctx context.Context, matchId string, winnerId string) error
 
 	CreateVoteTx(tx *sql.Tx, ctx context.Context, vote *BracketMatchVote) (*BracketMatchVote, error)
@@ -0,0 +1,82 @@
+package domain
+
+import "context"
+
+// Bracket pick'em.
+//
+// Before a single-elimination bracket's first round opens, a user can
+// predict the whole tree: a winner for every match slot through to the
+// champion. A prediction is never stored with a score. It is scored
+// against the bracket's decided matches whenever it is read, so a winner
+// counts as soon as SetMatchWinner records it. A bye is nobody's call and
+// scores nothing.
+
+// BracketPrediction is one user's prediction for one bracket (added in
+// migration 000037). ChampionId is the final's pick, kept alongside the
+// picks so leaderboards can show it without walking the tree.
+type BracketPrediction struct {
+	Id         string `db:"Id"         json:"id"`
+	BracketId  string `db:"BracketId"  json:"bracket_id"`
+	UserId     string `db:"UserId"     json:"user_id"`
+	ChampionId string `db:"ChampionId" json:"champion_id"`
+	CreatedAt  string `db:"CreatedAt"  json:"created_at"`
+	UpdatedAt  string `db:"UpdatedAt"  json:"updated_at"`
+}
+
+// BracketPredictionPick is the torró a prediction has winning the
+// winners-side match at (Round, Slot).
+type BracketPredictionPick struct {
+	PredictionId string `db:"PredictionId" json:"prediction_id"`
+	Round        int    `db:"Round"        json:"round"`
+	Slot         int    `db:"Slot"         json:"slot"`
+	TorronId     string `db:"TorronId"     json:"torron_id"`
+}
+
+// PredictionPoints is what a correct pick in round scores: 1 in round 1,
+// doubling every round, so each round is worth as much as the first one.
+func PredictionPoints(round int) int {
+	return 1 << (round - 1)
+}
+
+// BracketPredictionScore is how one prediction is doing so far: Points
+// from its Correct picks, out of the Decided matches it picked. Users are
+// anonymous, so UserId never leaves the server.
+type BracketPredictionScore struct {
+	Rank         int    `json:"rank"`
+	UserId       string `json:"-"`
+	ChampionId   string `json:"champion_id"`
+	ChampionName string `json:"champion_name"`
+	Points       int    `json:"points"`
+	Correct      int    `json:"correct"`
+	Decided      int    `json:"decided"`
+}
+
+// Accuracy returns Correct as a percentage of Decided, 0 before any match
+// is decided.
+func (s *BracketPredictionScore) Accuracy() int {
+	if s.Decided == 0 {
+		return 0
+	}
+	return s.Correct * 100 / s.Decided
+}
+
+type BracketPredictionRepo interface {
+	// Save stores a user's prediction for a bracket with its picks,
+	// replacing every pick of an earlier one. It fails with a validation
+	// error once the bracket's predictions are locked.
+	Save(ctx context.Context, prediction *BracketPrediction, picks []*BracketPredictionPick) (*BracketPrediction, error)
+
+	// GetForUser retrieves a user's prediction for a bracket and its
+	// picks, ordered by round and slot.
+	GetForUser(ctx context.Context, bracketId string, userId string) (*BracketPrediction, []*BracketPredictionPick, error)
+
+	// Leaderboard ranks a bracket's predictions by points, then by
+	// correct picks (equal ones share a rank, the earliest submitted
+	// listed first), capped to limit. A non-empty circleId keeps only that
+	// friend circle's members. Quarantined users are left out.
+	Leaderboard(ctx context.Context, bracketId string, circleId string, limit int) ([]*BracketPredictionScore, error)
+
+	// ScoreForUser scores a user's prediction for a bracket. Rank is left
+	// at 0.
+	ScoreForUser(ctx context.Context, bracketId string, userId string) (*BracketPredictionScore, error)
+}
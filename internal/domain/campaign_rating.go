@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// RatingSeason names the campaign the live ratings (Torrons.Rating and
+// UserEloSnapshots) belong to, added in migration 000027. StartedAt is when
+// they were last reset: the rating replay and the strength fit only read
+// Results cast since then. CampaignId is nil until a campaign has adopted
+// the live ratings.
+type RatingSeason struct {
+	CampaignId *string
+	StartedAt  time.Time
+}
+
+// CampaignRating is one torró's global rating in one campaign: archived for
+// a past campaign, live for the current season's. Rank and ClassRank are
+// its positions across every class and within its own.
+type CampaignRating struct {
+	CampaignId string  `json:"campaign_id"`
+	TorroId    string  `json:"torro_id"`
+	Name       string  `json:"name"`
+	ClassId    string  `json:"class_id"`
+	Rating     float64 `json:"rating"`
+	Deviation  float64 `json:"rating_deviation"`
+	Volatility float64 `json:"rating_volatility"`
+	Votes      int     `json:"votes"`
+	Rank       int     `json:"rank"`
+	ClassRank  int     `json:"class_rank"`
+}
+
+// RatingComparison is one torró's standing in a campaign against the one
+// before it. The Previous* fields are zero, and New is set, for a torró
+// that was not rated in the earlier campaign. RankChange and
+// ClassRankChange are positive when the torró climbed.
+type RatingComparison struct {
+	TorroId   string  `json:"torro_id"`
+	Name      string  `json:"name"`
+	ClassId   string  `json:"class_id"`
+	Rating    float64 `json:"rating"`
+	Rank      int     `json:"rank"`
+	ClassRank int     `json:"class_rank"`
+
+	PreviousRating    float64 `json:"previous_rating,omitempty"`
+	PreviousRank      int     `json:"previous_rank,omitempty"`
+	PreviousClassRank int     `json:"previous_class_rank,omitempty"`
+	RankChange        int     `json:"rank_change"`
+	ClassRankChange   int     `json:"class_rank_change"`
+	New               bool    `json:"new"`
+}
+
+// CompareCampaignRatings lines up every torró rated in current with its
+// standing in previous, in current's rank order.
+func CompareCampaignRatings(current, previous []*CampaignRating) []*RatingComparison {
+	before := make(map[string]*CampaignRating, len(previous))
+	for _, r := range previous {
+		before[r.TorroId] = r
+	}
+
+	comparisons := make([]*RatingComparison, 0, len(current))
+	for _, r := range current {
+		c := &RatingComparison{
+			TorroId:   r.TorroId,
+			Name:      r.Name,
+			ClassId:   r.ClassId,
+			Rating:    r.Rating,
+			Rank:      r.Rank,
+			ClassRank: r.ClassRank,
+		}
+		if p, ok := before[r.TorroId]; ok {
+			c.PreviousRating = p.Rating
+			c.PreviousRank = p.Rank
+			c.PreviousClassRank = p.ClassRank
+			c.RankChange = p.Rank - r.Rank
+			c.ClassRankChange = p.ClassRank - r.ClassRank
+		} else {
+			c.New = true
+		}
+		comparisons = append(comparisons, c)
+	}
+
+	sort.SliceStable(comparisons, func(i, j int) bool {
+		return comparisons[i].Rank < comparisons[j].Rank
+	})
+	return comparisons
+}
+
+type CampaignRatingRepo interface {
+	// GetSeason returns the current rating season.
+	GetSeason(ctx context.Context) (*RatingSeason, error)
+
+	// Rollover starts campaignId's rating season, holding off votes while
+	// it runs. If the live ratings belong to another campaign, they are
+	// archived under it and every torró and personal snapshot starts over
+	// from initial; with no campaign yet, campaignId simply adopts them.
+	// It reports whether an archive and reset happened, and is a no-op if
+	// campaignId's season has already started.
+	Rollover(ctx context.Context, campaignId string, initial RatingState) (bool, error)
+
+	// List returns a campaign's global ratings by rank: the live ones for
+	// the current season's campaign, archived ones otherwise (none for a
+	// campaign that never had a season). The live season leaves
+	// discontinued torrons out.
+	List(ctx context.Context, campaignId string) ([]*CampaignRating, error)
+
+	// ListForUser returns a user's personal ratings in a campaign, ranked
+	// within that user's own snapshots; Votes is their vote count on the
+	// torró.
+	ListForUser(ctx context.Context, campaignId string, userId string) ([]*CampaignRating, error)
+
+	// PreviousCampaignId returns the latest campaign from before year that
+	// has archived ratings.
+	PreviousCampaignId(ctx context.Context, year int) (string, error)
+}
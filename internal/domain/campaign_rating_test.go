@@ -0,0 +1,34 @@
+package domain
+
+import "testing"
+
+func TestCompareCampaignRatings(t *testing.T) {
+	previous := []*CampaignRating{
+		{TorroId: "a", Rating: 1600, Rank: 1, ClassRank: 1},
+		{TorroId: "b", Rating: 1550, Rank: 2, ClassRank: 1},
+		{TorroId: "c", Rating: 1400, Rank: 3, ClassRank: 2},
+	}
+	current := []*CampaignRating{
+		{TorroId: "d", Rating: 1520, Rank: 3, ClassRank: 2},
+		{TorroId: "c", Rating: 1580, Rank: 1, ClassRank: 1},
+		{TorroId: "a", Rating: 1560, Rank: 2, ClassRank: 2},
+	}
+
+	got := CompareCampaignRatings(current, previous)
+	if len(got) != 3 {
+		t.Fatalf("got %d comparisons, want 3", len(got))
+	}
+
+	if got[0].TorroId != "c" || got[1].TorroId != "a" || got[2].TorroId != "d" {
+		t.Fatalf("order = %s %s %s, want c a d", got[0].TorroId, got[1].TorroId, got[2].TorroId)
+	}
+	if c := got[0]; c.RankChange != 2 || c.ClassRankChange != 1 || c.PreviousRating != 1400 || c.New {
+		t.Errorf("c = %+v, want up 2 overall and 1 in class", c)
+	}
+	if a := got[1]; a.RankChange != -1 || a.ClassRankChange != -1 || a.PreviousRank != 1 {
+		t.Errorf("a = %+v, want down 1 from first", a)
+	}
+	if d := got[2]; !d.New || d.PreviousRank != 0 || d.RankChange != 0 {
+		t.Errorf("d = %+v, want new", d)
+	}
+}
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// FraudSignals are one user's raw voting patterns, as the fraud analyzer
+// (internal/fraud) reads them from Results. Counts, not ratios, so the
+// analyzer decides how much evidence is enough.
+type FraudSignals struct {
+	UserId string
+
+	// Votes is every duel-screen Result the user cast (ranking votes are
+	// not scored); Decided leaves out draws.
+	Votes   int
+	Decided int
+
+	// LeftPicks is how many decided votes went to the pairing's Torro1,
+	// the torró shown on the left of the vote screen.
+	LeftPicks int
+
+	// FastVotes is how many votes came less than a second after the user's
+	// previous one.
+	FastVotes int
+
+	// FavouriteWins is how many decided votes the user's most-picked torró
+	// won.
+	FavouriteWins int
+
+	// IpSiblings is how many other users were first seen from the same IP
+	// within a day of this one.
+	IpSiblings int
+
+	Quarantined bool
+
+	// Released is true once an admin has lifted a quarantine on the user,
+	// which exempts them from the analyzer from then on.
+	Released bool
+}
+
+// FraudAssessment is the analyzer's verdict on one user: the normalised
+// signals behind it, a combined Score from 0 to 1, and a readable reason for
+// every signal that contributed.
+type FraudAssessment struct {
+	UserId         string   `json:"user_id"`
+	Votes          int      `json:"votes"`
+	LeftShare      float64  `json:"left_share"`
+	FastShare      float64  `json:"fast_share"`
+	FavouriteShare float64  `json:"favourite_share"`
+	IpSiblings     int      `json:"ip_siblings"`
+	Score          float64  `json:"score"`
+	Reasons        []string `json:"reasons"`
+	Quarantined    bool     `json:"quarantined"`
+	Released       bool     `json:"released"`
+}
+
+// QuarantinedUser is one row of the admin fraud report's quarantine list.
+type QuarantinedUser struct {
+	UserId        string    `json:"user_id"`
+	FraudScore    float64   `json:"fraud_score"`
+	QuarantinedAt time.Time `json:"quarantined_at"`
+	VoteCount     int       `json:"vote_count"`
+}
+
+type FraudRepo interface {
+	// ListSignals tallies FraudSignals for every user with at least
+	// minVotes Results.
+	ListSignals(ctx context.Context, minVotes int) ([]*FraudSignals, error)
+
+	// ListQuarantined returns every currently quarantined user, most
+	// recently quarantined first.
+	ListQuarantined(ctx context.Context) ([]*QuarantinedUser, error)
+
+	// Quarantine flags a user and records the score that flagged them. It
+	// reports false, changing nothing, for a user who is already quarantined
+	// or whom an admin has released.
+	Quarantine(ctx context.Context, userId string, score float64) (bool, error)
+
+	// Release lifts a user's quarantine and exempts them from future
+	// analyzer runs.
+	Release(ctx context.Context, userId string) error
+}
@@ -1,14 +1,123 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type Pairing struct {
 	Id     string `db:"Id"`
 	Torro1 string `db:"Torro1"`
 	Torro2 string `db:"Torro2"`
 	Class  string `db:"Class"`
+
+	// RetiredAt is set once pairing reconciliation has taken the matchup
+	// out of the running (added in migration 000028). A retired pairing
+	// keeps its Results but is never served.
+	RetiredAt *time.Time `db:"RetiredAt"`
+}
+
+// Key identifies the matchup regardless of which torró is Torro1: the same
+// order-independent identity idx_pairings_unique_matchup enforces.
+func (p *Pairing) Key() string {
+	if p.Torro1 < p.Torro2 {
+		return p.Class + ":" + p.Torro1 + ":" + p.Torro2
+	}
+	return p.Class + ":" + p.Torro2 + ":" + p.Torro1
+}
+
+// PairingReconciliation summarises one class's pairing reconciliation:
+// how many matchups the catalog calls for, and how many were inserted,
+// brought back from retirement and retired to get there.
+type PairingReconciliation struct {
+	ClassId   string `json:"class_id"`
+	ClassName string `json:"class_name"`
+	Desired   int    `json:"desired"`
+	Inserted  int    `json:"inserted"`
+	Revived   int    `json:"revived"`
+	Retired   int    `json:"retired"`
+}
+
+// Changed reports whether reconciliation touched any pairing.
+func (r *PairingReconciliation) Changed() bool {
+	return r.Inserted > 0 || r.Revived > 0 || r.Retired > 0
+}
+
+// Pairing selector names, as selected by the "pairing.selector" config
+// value and reported by PairingSelector.Name.
+const (
+	PairingSelectorRandom   = "random"
+	PairingSelectorAdaptive = "adaptive"
+	PairingSelectorCoverage = "coverage"
+)
+
+// PairingCandidate is a pairing plus what is currently known about it: both
+// torrons' global rating state, how many votes it has collected overall, and
+// how much the requesting user has already seen of it.
+type PairingCandidate struct {
+	Pairing *Pairing
+
+	// State1 and State2 are the global rating states of Torro1 and Torro2.
+	State1 RatingState
+	State2 RatingState
+
+	// Votes is the number of Results recorded on this pairing by anyone.
+	Votes int
+
+	// UserVotes is how many of those Results the requesting user cast, and
+	// UserTorro1Votes/UserTorro2Votes are the VoteCount of the user's own
+	// snapshots of each torró (how often it has appeared in their duels).
+	UserVotes       int
+	UserTorro1Votes int
+	UserTorro2Votes int
+
+	// UserSeen is how many times the vote screen has served this pairing
+	// to the user, and UserLastSeen when it last did (zero if never).
+	UserSeen     int
+	UserLastSeen time.Time
 }
 
+// Covered reports whether the user has already seen or voted on the
+// pairing.
+func (c *PairingCandidate) Covered() bool {
+	return c.UserSeen > 0 || c.UserVotes > 0
+}
+
+// PairingCoverage is how much of one class's pairing pool a user has been
+// shown, as reported on /stats and /api/user/stats.
+type PairingCoverage struct {
+	ClassId string `json:"class_id"`
+	Seen    int    `json:"seen"`
+	Total   int    `json:"total"`
+
+	// Passes is how many complete cycles through the class the user has
+	// made: the serve count of their least-seen pairing.
+	Passes int `json:"passes"`
+}
+
+// Percentage returns Seen as a share of Total, from 0 to 100.
+func (c *PairingCoverage) Percentage() int {
+	if c.Total == 0 {
+		return 0
+	}
+	return c.Seen * 100 / c.Total
+}
+
+// PairingSelector decides which duel to serve next on the vote screen.
+// Implementations live in internal/matchmaking.
+type PairingSelector interface {
+	// Name returns the selector's PairingSelector* identifier.
+	Name() string
+
+	// Next returns the next pairing to show userId (empty for an anonymous
+	// session) in classId. excludeId, when set, is the pairing just voted
+	// on; it is only served again if the class has no other pairing.
+	Next(ctx context.Context, classId, userId, excludeId string) (*Pairing, error)
+}
+
+// PairingRepo reads and writes the Pairings table. Every listing, count
+// and random draw only sees pairings that are not retired; Get returns one
+// either way, with RetiredAt set.
 type PairingRepo interface {
 	Get(ctx context.Context, id string) (*Pairing, error)
 	List(ctx context.Context) ([]*Pairing, error)
@@ -28,7 +137,25 @@ type PairingRepo interface {
 	// stable "pairing of the day" across requests and replicas.
 	GetDeterministic(ctx context.Context, classId string, seed int64) (*Pairing, error)
 
+	// ListCandidates returns every pairing in the class along with the
+	// signals a PairingSelector scores it on. userId may be empty for an
+	// anonymous session, in which case the per-user counts are all zero.
+	ListCandidates(ctx context.Context, classId, userId string) ([]*PairingCandidate, error)
+
+	// MarkSeen records that the vote screen just served pairingId to
+	// userId, for the coverage selector.
+	MarkSeen(ctx context.Context, userId, pairingId string) error
+
+	// CoverageByUser returns userId's coverage of every class's pairings.
+	CoverageByUser(ctx context.Context, userId string) ([]*PairingCoverage, error)
+
 	Count(ctx context.Context) (int, error)
 	CountClass(ctx context.Context, classId string) (int, error)
 	Create(ctx context.Context, pairing *Pairing) (*Pairing, error)
+
+	// Reconcile makes classId's active pairings exactly the desired
+	// matchups, in one transaction: missing ones are inserted, retired
+	// ones revived, and any other active pairing retired. Nothing is
+	// deleted. The returned summary leaves ClassName empty.
+	Reconcile(ctx context.Context, classId string, desired []*Pairing) (*PairingReconciliation, error)
 }
@@ -0,0 +1,96 @@
+package domain
+
+import "sort"
+
+// ProvisionalPriorVotes is how many votes' worth of weight the global
+// rating carries as the prior of a provisional personal rating: a torró
+// the user has voted on this many times is half theirs, half everyone's.
+const ProvisionalPriorVotes = 5
+
+// How much a provisional leaderboard can be trusted, from the user's votes
+// against the threshold that unlocks the real one.
+const (
+	ConfidenceLow    = "low"
+	ConfidenceMedium = "medium"
+	ConfidenceHigh   = "high"
+)
+
+// ProvisionalEntry is one torró on a provisional personal leaderboard.
+// Rating blends PersonalRating with GlobalRating, Weight being the share
+// of the former; PersonalRating is nil for a torró the user has never
+// voted on, which is ranked on GlobalRating alone.
+type ProvisionalEntry struct {
+	TorronId       string   `json:"torron_id"`
+	TorronName     string   `json:"torron_name"`
+	TorronImage    string   `json:"torron_image"`
+	Rating         float64  `json:"rating"`
+	Weight         float64  `json:"weight"`
+	PersonalRating *float64 `json:"personal_rating"`
+	GlobalRating   float64  `json:"global_rating"`
+	VoteCount      int      `json:"vote_count"`
+	Rank           int      `json:"rank"`
+}
+
+// BlendRating shrinks a personal rating built from votes votes towards the
+// global one, returning the blend and the weight the personal rating got:
+// votes / (votes + ProvisionalPriorVotes).
+func BlendRating(personal, global float64, votes int) (float64, float64) {
+	if votes <= 0 {
+		return global, 0
+	}
+	weight := float64(votes) / float64(votes+ProvisionalPriorVotes)
+	return weight*personal + (1-weight)*global, weight
+}
+
+// ProvisionalRanking ranks torrons on the user's blended ratings, best
+// first, tied ratings sharing a rank as SQL's RANK() does. torrons are the
+// ones in scope, already filtered; snapshots are the user's.
+func ProvisionalRanking(torrons []*Torro, snapshots []*UserEloSnapshot) []*ProvisionalEntry {
+	byTorro := make(map[string]*UserEloSnapshot, len(snapshots))
+	for _, s := range snapshots {
+		byTorro[s.TorronId] = s
+	}
+
+	entries := make([]*ProvisionalEntry, 0, len(torrons))
+	for _, t := range torrons {
+		entry := &ProvisionalEntry{
+			TorronId:     t.Id,
+			TorronName:   t.Name,
+			TorronImage:  t.Image,
+			Rating:       t.Rating,
+			GlobalRating: t.Rating,
+		}
+		if s := byTorro[t.Id]; s != nil && s.VoteCount > 0 {
+			personal := s.Rating
+			entry.PersonalRating = &personal
+			entry.VoteCount = s.VoteCount
+			entry.Rating, entry.Weight = BlendRating(s.Rating, t.Rating, s.VoteCount)
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Rating > entries[j].Rating
+	})
+	for i, e := range entries {
+		e.Rank = i + 1
+		if i > 0 && e.Rating == entries[i-1].Rating {
+			e.Rank = entries[i-1].Rank
+		}
+	}
+	return entries
+}
+
+// ProvisionalConfidence rates a provisional leaderboard built from votes
+// votes where minVotes unlock the real one: low under a third of the way,
+// medium until the threshold, high from it on.
+func ProvisionalConfidence(votes, minVotes int) string {
+	switch {
+	case votes >= minVotes:
+		return ConfidenceHigh
+	case votes*3 >= minVotes:
+		return ConfidenceMedium
+	default:
+		return ConfidenceLow
+	}
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBlendRating(t *testing.T) {
+	tests := []struct {
+		name       string
+		votes      int
+		wantRating float64
+		wantWeight float64
+	}{
+		{"no votes", 0, 1500, 0},
+		{"one vote", 1, 1500 + 300.0/6, 1.0 / 6},
+		{"as many as the prior", ProvisionalPriorVotes, 1650, 0.5},
+		{"many votes", 95, 1500 + 300*0.95, 0.95},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rating, weight := BlendRating(1800, 1500, tt.votes)
+			if math.Abs(rating-tt.wantRating) > 1e-9 || math.Abs(weight-tt.wantWeight) > 1e-9 {
+				t.Errorf("BlendRating(1800, 1500, %d) = %.2f, %.3f, want %.2f, %.3f",
+					tt.votes, rating, weight, tt.wantRating, tt.wantWeight)
+			}
+		})
+	}
+}
+
+func TestProvisionalRanking(t *testing.T) {
+	torrons := []*Torro{
+		{Id: "a", Rating: 1600},
+		{Id: "b", Rating: 1500},
+		{Id: "c", Rating: 1500},
+		{Id: "d", Rating: 1400},
+	}
+	// One vote barely moves d off its global rating; many votes lift b to the top.
+	snapshots := []*UserEloSnapshot{
+		{TorronId: "b", Rating: 1800, VoteCount: 20},
+		{TorronId: "d", Rating: 1900, VoteCount: 1},
+		{TorronId: "c", Rating: 1700, VoteCount: 0},
+	}
+
+	entries := ProvisionalRanking(torrons, snapshots)
+
+	var order []string
+	for _, e := range entries {
+		order = append(order, e.TorronId)
+	}
+	if got := order[0] + order[1] + order[2] + order[3]; got != "bacd" {
+		t.Fatalf("order = %v, want b a c d", order)
+	}
+	if b := entries[0]; b.PersonalRating == nil || *b.PersonalRating != 1800 || b.Weight != 0.8 || b.GlobalRating != 1500 {
+		t.Errorf("b = %+v, want personal 1800 weighted 0.8 over global 1500", b)
+	}
+	if c := entries[2]; c.PersonalRating != nil || c.Weight != 0 || c.Rating != 1500 {
+		t.Errorf("c = %+v, want the global rating alone: a snapshot without votes is no evidence", c)
+	}
+}
+
+func TestProvisionalRankingTies(t *testing.T) {
+	entries := ProvisionalRanking([]*Torro{{Id: "a", Rating: 1500}, {Id: "b", Rating: 1500}, {Id: "c", Rating: 1400}}, nil)
+	if entries[0].Rank != 1 || entries[1].Rank != 1 || entries[2].Rank != 3 {
+		t.Errorf("ranks = %d %d %d, want 1 1 3", entries[0].Rank, entries[1].Rank, entries[2].Rank)
+	}
+}
+
+func TestProvisionalConfidence(t *testing.T) {
+	for votes, want := range map[int]string{0: ConfidenceLow, 9: ConfidenceLow, 10: ConfidenceMedium, 29: ConfidenceMedium, 30: ConfidenceHigh} {
+		if got := ProvisionalConfidence(votes, 30); got != want {
+			t.Errorf("ProvisionalConfidence(%d, 30) = %s, want %s", votes, got, want)
+		}
+	}
+}
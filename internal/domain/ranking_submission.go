@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// How many torrons a ranking vote orders at once.
+const (
+	RankingMinSize = 3
+	RankingMaxSize = 4
+)
+
+// RankingSubmission is one "rank these torrons" vote (added in migration
+// 000029): the user put TorroIds in order, best first. It is recorded as
+// the pairwise Results it implies, each carrying the submission's Id.
+type RankingSubmission struct {
+	Id         string    `db:"Id"         json:"id"`
+	UserId     *string   `db:"UserId"     json:"user_id,omitempty"`
+	ClassId    string    `db:"ClassId"    json:"class_id"`
+	CampaignId *string   `db:"CampaignId" json:"campaign_id,omitempty"`
+	TorroIds   []string  `db:"TorroIds"   json:"torro_ids"`
+	CreatedAt  time.Time `db:"CreatedAt"  json:"created_at"`
+}
+
+// RankedDuel is one pairwise outcome a ranking implies.
+type RankedDuel struct {
+	Winner string
+	Loser  string
+}
+
+// ValidateRanking checks that order names between RankingMinSize and
+// RankingMaxSize distinct torrons.
+func ValidateRanking(order []string) error {
+	if len(order) < RankingMinSize || len(order) > RankingMaxSize {
+		return fmt.Errorf("%s: a ranking orders between %d and %d torrons, got %d",
+			ValidationError, RankingMinSize, RankingMaxSize, len(order))
+	}
+
+	seen := make(map[string]bool, len(order))
+	for _, id := range order {
+		if id == "" || seen[id] {
+			return fmt.Errorf("%s: a ranking can't repeat or leave out a torró", ValidationError)
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// Duels returns every pairwise outcome the ranking implies: each torró
+// beats all those ranked below it. They come in the order they are
+// applied, the leader's duels first, so 3 torrons give 3 duels and 4 give
+// 6.
+func (s *RankingSubmission) Duels() []RankedDuel {
+	duels := make([]RankedDuel, 0, len(s.TorroIds)*(len(s.TorroIds)-1)/2)
+	for i := 0; i < len(s.TorroIds); i++ {
+		for j := i + 1; j < len(s.TorroIds); j++ {
+			duels = append(duels, RankedDuel{Winner: s.TorroIds[i], Loser: s.TorroIds[j]})
+		}
+	}
+	return duels
+}
+
+type RankingSubmissionRepo interface {
+	// Transaction method
+	CreateTx(tx *sql.Tx, ctx context.Context, submission *RankingSubmission) (*RankingSubmission, error)
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRankingSubmissionDuels(t *testing.T) {
+	s := &RankingSubmission{TorroIds: []string{"a", "b", "c", "d"}}
+
+	want := []RankedDuel{
+		{Winner: "a", Loser: "b"},
+		{Winner: "a", Loser: "c"},
+		{Winner: "a", Loser: "d"},
+		{Winner: "b", Loser: "c"},
+		{Winner: "b", Loser: "d"},
+		{Winner: "c", Loser: "d"},
+	}
+	if got := s.Duels(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Duels() = %v, want %v", got, want)
+	}
+
+	s.TorroIds = []string{"c", "a", "b"}
+	if got := len(s.Duels()); got != 3 {
+		t.Errorf("a ranking of 3 implies %d duels, want 3", got)
+	}
+}
+
+func TestValidateRanking(t *testing.T) {
+	tests := []struct {
+		name    string
+		order   []string
+		wantErr bool
+	}{
+		{name: "three", order: []string{"a", "b", "c"}},
+		{name: "four", order: []string{"a", "b", "c", "d"}},
+		{name: "too few", order: []string{"a", "b"}, wantErr: true},
+		{name: "too many", order: []string{"a", "b", "c", "d", "e"}, wantErr: true},
+		{name: "repeated", order: []string{"a", "b", "a"}, wantErr: true},
+		{name: "blank", order: []string{"a", "", "c"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateRanking(tt.order); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateRanking(%v) = %v, wantErr %v", tt.order, err, tt.wantErr)
+			}
+		})
+	}
+}
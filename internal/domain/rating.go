@@ -0,0 +1,68 @@
+package domain
+
+// Rating algorithm names, as selected by the "rating.algorithm" config value
+// (see internal/config) and reported by RatingEngine.Name.
+const (
+	RatingAlgorithmElo       = "elo"
+	RatingAlgorithmGlicko2   = "glicko2"
+	RatingAlgorithmTrueSkill = "trueskill"
+)
+
+// Default rating state given to a torró (or a user's personal snapshot of
+// one) that has never been voted on. These mirror the column defaults in the
+// "Torrons" and "UserEloSnapshots" tables, so a freshly inserted row and a
+// replay from scratch start from the same place.
+const (
+	DefaultRating           = 1500
+	DefaultRatingDeviation  = 350
+	DefaultRatingVolatility = 0.06
+)
+
+// RatingState is the full per-item state a RatingEngine reads and writes.
+// Rating is the point estimate every leaderboard sorts by. Deviation and
+// Volatility only carry meaning for the deviation-aware engines: Elo leaves
+// them untouched, Glicko-2 uses both (RD and sigma), and TrueSkill uses
+// Deviation as its sigma and ignores Volatility.
+type RatingState struct {
+	Rating     float64 `json:"rating"`
+	Deviation  float64 `json:"rating_deviation"`
+	Volatility float64 `json:"rating_volatility"`
+}
+
+// RatingEngine is the pluggable rating model applied to every Phase 1 duel,
+// both to the global Torro rating and to the voter's own UserEloSnapshot.
+// Implementations live in internal/rating and must be pure and safe for
+// concurrent use: the vote transaction handles locking and persistence.
+type RatingEngine interface {
+	// Name returns the engine's RatingAlgorithm* identifier.
+	Name() string
+
+	// Initial returns the state of an item that has never been rated.
+	Initial() RatingState
+
+	// Update returns the new states of a and b after a single duel between
+	// them. scoreA is a's actual score: 1 if a won, 0 if b won, 0.5 for a
+	// draw.
+	Update(a, b RatingState, scoreA float64) (RatingState, RatingState)
+}
+
+// WeightedUpdate runs a duel through engine and keeps only weight (0 to 1)
+// of the change it makes to each side. For Elo that is exactly the update
+// with K scaled by weight; for the deviation-aware engines the deviation
+// and volatility move only part of the way too, so a low-weight vote is
+// also less of a confidence boost.
+func WeightedUpdate(engine RatingEngine, a, b RatingState, scoreA, weight float64) (RatingState, RatingState) {
+	newA, newB := engine.Update(a, b, scoreA)
+	if weight == 1 {
+		return newA, newB
+	}
+	return scaleRatingChange(a, newA, weight), scaleRatingChange(b, newB, weight)
+}
+
+func scaleRatingChange(before, after RatingState, weight float64) RatingState {
+	return RatingState{
+		Rating:     before.Rating + weight*(after.Rating-before.Rating),
+		Deviation:  before.Deviation + weight*(after.Deviation-before.Deviation),
+		Volatility: before.Volatility + weight*(after.Volatility-before.Volatility),
+	}
+}
@@ -0,0 +1,139 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ReasonTag is one entry of the "why?" vocabulary a voter can tag a decided
+// duel with (added in migration 000031). Tags are data, not code: Position
+// orders them in the picker, and a retired tag is kept with Active unset so
+// its past votes still count.
+type ReasonTag struct {
+	Id       string `db:"Id"       json:"id"`
+	Label    string `db:"Label"    json:"label"`
+	Position int    `db:"Position" json:"position"`
+	Active   bool   `db:"Active"   json:"active"`
+}
+
+// ReasonCount is how many votes carried a tag.
+type ReasonCount struct {
+	TagId string `json:"tag"`
+	Label string `json:"label"`
+	Count int    `json:"count"`
+}
+
+// ReasonShare is a tag's share of a set of reasons, for display.
+type ReasonShare struct {
+	TagId      string `json:"tag"`
+	Label      string `json:"label"`
+	Count      int    `json:"count"`
+	Percentage int    `json:"percentage"`
+}
+
+// TorroReasons is what a torró wins and loses its duels for.
+type TorroReasons struct {
+	TorroId string         `json:"torro_id"`
+	Wins    []*ReasonCount `json:"wins"`
+	Losses  []*ReasonCount `json:"losses"`
+}
+
+// ReasonExportRow is one torró and tag with how many of the torró's wins
+// and losses carried it.
+type ReasonExportRow struct {
+	TorroId   string
+	TorroName string
+	ClassId   string
+	TagId     string
+	TagLabel  string
+	Wins      int
+	Losses    int
+}
+
+// ValidateReasons checks that tagIds are distinct, active tags of the
+// vocabulary. An empty selection is valid: it clears a vote's reasons.
+func ValidateReasons(tagIds []string, vocabulary []*ReasonTag) error {
+	active := make(map[string]bool, len(vocabulary))
+	for _, t := range vocabulary {
+		active[t.Id] = t.Active
+	}
+
+	seen := make(map[string]bool, len(tagIds))
+	for _, id := range tagIds {
+		if !active[id] {
+			return fmt.Errorf("%s: unknown reason %q", ValidationError, id)
+		}
+		if seen[id] {
+			return fmt.Errorf("%s: reason %q given twice", ValidationError, id)
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// TopReasons returns the limit most frequent reasons in counts, most
+// frequent first (ties by label), each with its rounded share of all of
+// them. A limit of 0 or less keeps them all.
+func TopReasons(counts []*ReasonCount, limit int) []ReasonShare {
+	total := 0
+	for _, c := range counts {
+		total += c.Count
+	}
+	if total == 0 {
+		return nil
+	}
+
+	sorted := make([]*ReasonCount, 0, len(counts))
+	for _, c := range counts {
+		if c.Count > 0 {
+			sorted = append(sorted, c)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Count != sorted[j].Count {
+			return sorted[i].Count > sorted[j].Count
+		}
+		return sorted[i].Label < sorted[j].Label
+	})
+	if limit > 0 && len(sorted) > limit {
+		sorted = sorted[:limit]
+	}
+
+	shares := make([]ReasonShare, len(sorted))
+	for i, c := range sorted {
+		shares[i] = ReasonShare{
+			TagId:      c.TagId,
+			Label:      c.Label,
+			Count:      c.Count,
+			Percentage: int(math.Round(100 * float64(c.Count) / float64(total))),
+		}
+	}
+	return shares
+}
+
+type ReasonRepo interface {
+	// ListTags returns the active vocabulary by Position.
+	ListTags(ctx context.Context) ([]*ReasonTag, error)
+
+	// ListForResult returns the tag ids a vote carries.
+	ListForResult(ctx context.Context, resultId string) ([]string, error)
+
+	// SetForResult replaces the reasons of userId's decided vote resultId
+	// with tagIds. The error is a NotFound one when the vote isn't theirs
+	// and a Validation one when it was a draw.
+	SetForResult(ctx context.Context, resultId, userId string, tagIds []string) error
+
+	// ListForTorro counts the reasons of every decided vote torroId won or
+	// lost, over all tags ever used.
+	ListForTorro(ctx context.Context, torroId string) (*TorroReasons, error)
+
+	// Breakdown counts every vote reason by tag.
+	Breakdown(ctx context.Context) ([]*ReasonCount, error)
+
+	// Export counts, for every torró and tag, the wins and losses that
+	// carried it, by torró name then tag position. Pairs with no reasons
+	// are left out.
+	Export(ctx context.Context) ([]*ReasonExportRow, error)
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidateReasons(t *testing.T) {
+	vocabulary := []*ReasonTag{
+		{Id: "textura", Label: "Textura", Active: true},
+		{Id: "dolcor", Label: "Dolçor", Active: true},
+		{Id: "preu", Label: "Preu", Active: false},
+	}
+
+	tests := []struct {
+		name    string
+		tags    []string
+		wantErr bool
+	}{
+		{"none", nil, false},
+		{"one", []string{"textura"}, false},
+		{"several", []string{"dolcor", "textura"}, false},
+		{"unknown", []string{"color"}, true},
+		{"retired", []string{"preu"}, true},
+		{"repeated", []string{"textura", "textura"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateReasons(tt.tags, vocabulary)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateReasons(%v) error = %v, wantErr %v", tt.tags, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTopReasons(t *testing.T) {
+	counts := []*ReasonCount{
+		{TagId: "aspecte", Label: "Aspecte", Count: 1},
+		{TagId: "textura", Label: "Textura", Count: 5},
+		{TagId: "intensitat", Label: "Intensitat", Count: 0},
+		{TagId: "dolcor", Label: "Dolçor", Count: 2},
+		{TagId: "ingredients", Label: "Ingredients", Count: 2},
+	}
+
+	got := TopReasons(counts, 3)
+	want := []ReasonShare{
+		{TagId: "textura", Label: "Textura", Count: 5, Percentage: 50},
+		{TagId: "dolcor", Label: "Dolçor", Count: 2, Percentage: 20},
+		{TagId: "ingredients", Label: "Ingredients", Count: 2, Percentage: 20},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TopReasons() = %+v, want %+v", got, want)
+	}
+
+	if got := TopReasons(counts, 0); len(got) != 4 {
+		t.Errorf("TopReasons(limit 0) kept %d reasons, want the 4 used ones", len(got))
+	}
+	if got := TopReasons([]*ReasonCount{{TagId: "textura", Count: 0}}, 3); got != nil {
+		t.Errorf("TopReasons() of unused tags = %+v, want nil", got)
+	}
+}
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Where a Recommendation's predicted rating came from, most personal first.
+const (
+	// RecommendationCollaborative predicts from the user's own snapshots
+	// of torrons that other users rate alike (TorroSimilarity).
+	RecommendationCollaborative = "collaborative"
+
+	// RecommendationCatalog predicts from the user's snapshots of torrons
+	// that share the candidate's class and ingredients, for torrons too
+	// new or too little rated to have similarities yet.
+	RecommendationCatalog = "catalog"
+
+	// RecommendationPopular falls back to the community rating, for users
+	// with no snapshots to go on.
+	RecommendationPopular = "popular"
+)
+
+// TorroSimilarity is how alike users who have rated both torrons feel
+// about them (see internal/recommend), from -1 to 1. Torro1 < Torro2.
+type TorroSimilarity struct {
+	Torro1     string    `json:"torro1"`
+	Torro2     string    `json:"torro2"`
+	Similarity float64   `json:"similarity"`
+	CoRaters   int       `json:"co_raters"`
+	FittedAt   time.Time `json:"fitted_at"`
+}
+
+// Recommendation is a torró the user hasn't rated yet with the personal
+// rating they are predicted to give it. Because is the rated torró that
+// contributed most to the prediction ("because you liked X"), empty for a
+// RecommendationPopular one.
+type Recommendation struct {
+	TorroId   string  `json:"torro_id"`
+	Name      string  `json:"name"`
+	Image     string  `json:"image"`
+	ClassId   string  `json:"class_id"`
+	Predicted float64 `json:"predicted_rating"`
+	Basis     string  `json:"basis"`
+
+	BecauseId   string `json:"because_id,omitempty"`
+	BecauseName string `json:"because_name,omitempty"`
+}
+
+type SimilarityRepo interface {
+	// ListRatings returns every snapshot of users with at least two, the
+	// only ones that can tie two torrons together. Only UserId, TorronId
+	// and Rating are set.
+	ListRatings(ctx context.Context) ([]*UserEloSnapshot, error)
+
+	// List returns the latest fit.
+	List(ctx context.Context) ([]*TorroSimilarity, error)
+
+	// Replace swaps the stored fit for a new one in a single transaction.
+	Replace(ctx context.Context, similarities []*TorroSimilarity) error
+}
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ReplayVote is one Results row as the offline re-rating replay reads it:
+// who played whom, how it ended, who voted and when.
+type ReplayVote struct {
+	ResultId   string
+	Torro1     string
+	Torro2     string
+	Winner     *string
+	Outcome    string
+	UserId     *string
+	CampaignId *string
+	Timestamp  time.Time
+
+	// Quarantined is true when the voter is currently quarantined by the
+	// fraud analyzer: the vote still counts towards their personal
+	// snapshots but not towards any global rating.
+	Quarantined bool
+
+	// Weight is the share of a full global rating update the vote was cast
+	// with (see VoteWeighting), which the replay applies again.
+	Weight float64
+}
+
+// ScoreTorro1 returns the vote's score from Torro1's point of view, as fed
+// to RatingEngine.Update.
+func (v *ReplayVote) ScoreTorro1() float64 {
+	switch {
+	case v.Outcome == ResultOutcomeDraw || v.Winner == nil:
+		return 0.5
+	case *v.Winner == v.Torro1:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// ReplayedResult holds the before/after ratings a replay computed for one
+// Results row, to be written back over the stored columns.
+type ReplayedResult struct {
+	ResultId string
+	Rat1Bef  float64
+	Rat2Bef  float64
+	Rat1Aft  float64
+	Rat2Aft  float64
+}
+
+// Replay is the outcome of re-running the whole Results history through a
+// rating engine: every torró's final state, every Result's rewritten
+// before/after ratings, and every user's rebuilt personal snapshots.
+type Replay struct {
+	Torrons   map[string]RatingState
+	Results   []ReplayedResult
+	Snapshots []*UserEloSnapshot
+
+	// Applied and Skipped count the votes that moved ratings and the ones
+	// left out by the replay's exclusions. Quarantined counts the votes from
+	// quarantined users, which moved only their personal snapshots.
+	Applied     int
+	Skipped     int
+	Quarantined int
+}
+
+type ReplayRepo interface {
+	// ListVotes returns every Results row of the current rating season
+	// (see RatingSeason) in the order it was cast.
+	ListVotes(ctx context.Context) ([]*ReplayVote, error)
+
+	// Commit writes a replay back in a single transaction: torró ratings,
+	// the Results before/after columns and a full rebuild of
+	// UserEloSnapshots, dropping every pending VoteUndo. It refuses to
+	// commit if the season's Results have changed since the replay read
+	// them.
+	Commit(ctx context.Context, replay *Replay) error
+
+	// Rebuild reads the votes, runs compute over them and commits the
+	// result, all in one transaction that holds off new votes throughout,
+	// so it cannot lose a race with them the way ListVotes then Commit can.
+	Rebuild(ctx context.Context, compute func(votes []*ReplayVote) *Replay) (*Replay, error)
+}
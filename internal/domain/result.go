@@ -3,6 +3,15 @@ package domain
 import (
 	"context"
 	"database/sql"
+	"time"
+)
+
+// Result outcomes (added in migration 000022). A win names the preferred
+// torró in Winner; a draw is the duel screen's "can't decide" answer, leaves
+// Winner nil and scores both torrons 0.5 in the rating engine.
+const (
+	ResultOutcomeWin  = "win"
+	ResultOutcomeDraw = "draw"
 )
 
 type Result struct {
@@ -10,7 +19,7 @@ type Result struct {
 	Pairing string  `db:"Pairing"`
 	Rat1Bef float64 `db:"Torro1RatingBefore"`
 	Rat2Bef float64 `db:"Torro2RatingBefore"`
-	Winner  string  `db:"Winner"`
+	Winner  *string `db:"Winner"`
 	Rat1Aft float64 `db:"Torro1RatingAfter"`
 	Rat2Aft float64 `db:"Torro2RatingAfter"`
 
@@ -18,10 +27,59 @@ type Result struct {
 	UserId     *string `db:"UserId"     json:"user_id,omitempty"`
 	Timestamp  string  `db:"Timestamp"  json:"timestamp"`
 	CampaignId *string `db:"CampaignId" json:"campaign_id,omitempty"`
+
+	// Outcome is one of the ResultOutcome* constants (added in migration
+	// 000022).
+	Outcome string `db:"Outcome" json:"outcome"`
+
+	// SubmissionId is the RankingSubmission the duel was implied by, when
+	// it came from a ranking vote rather than the duel screen (added in
+	// migration 000029).
+	SubmissionId *string `db:"SubmissionId" json:"submission_id,omitempty"`
+
+	// Weight is the share of a full global rating update the vote was
+	// applied with, from 0 to 1, from the voter's trust (added in migration
+	// 000030). Nil records a full vote; a policy's deliberate 0 is stored
+	// as 0.
+	Weight *float64 `db:"Weight" json:"weight"`
+}
+
+// IsDraw reports whether the voter couldn't pick between the two torrons.
+func (r *Result) IsDraw() bool {
+	return r.Outcome == ResultOutcomeDraw
+}
+
+// Rating history bucket sizes: how finely ListRatingHistory downsamples a
+// torró's votes.
+const (
+	RatingHistoryHour = "hour"
+	RatingHistoryDay  = "day"
+)
+
+// RatingHistoryPoint is one bucket of a torró's rating over time, read back
+// from the before/after ratings every Result records. Open is the rating
+// going into the bucket's first vote and Rating the one its last vote left.
+type RatingHistoryPoint struct {
+	Time   time.Time `json:"time"`
+	Open   float64   `json:"open"`
+	Rating float64   `json:"rating"`
+	Votes  int       `json:"votes"`
+}
+
+// RatingHistoryFilter narrows ListRatingHistory. Bucket is one of the
+// RatingHistory* constants; a nil CampaignId covers every vote.
+type RatingHistoryFilter struct {
+	Bucket     string
+	CampaignId *string
 }
 
 type ResultRepo interface {
 	Create(ctx context.Context, result *Result) (*Result, error)
+
+	// ListRatingHistory returns a torró's rating history, oldest bucket
+	// first, with one point per bucket the torró was voted in.
+	ListRatingHistory(ctx context.Context, torroId string, filter RatingHistoryFilter) ([]*RatingHistoryPoint, error)
+
 	// Transaction method
 	CreateTx(tx *sql.Tx, ctx context.Context, result *Result) (*Result, error)
 }
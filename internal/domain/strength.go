@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// TorroStrength is one torró's batch Bradley–Terry fit (see
+// internal/strength): a maximum-likelihood strength over every Result of
+// the current rating season, on
+// the same 1500-centred scale as Rating, with its 95% interval. Unlike
+// Rating it does not depend on the order votes arrived in.
+type TorroStrength struct {
+	TorroId  string  `json:"torro_id"`
+	ClassId  string  `json:"-"`
+	Strength float64 `json:"strength"`
+	Lower    float64 `json:"lower"`
+	Upper    float64 `json:"upper"`
+	StdError float64 `json:"std_error"`
+
+	// Comparisons is how many decided or drawn duels the fit saw for the
+	// torró.
+	Comparisons int `json:"comparisons"`
+
+	// Rank and ClassRank are the torró's positions by Strength across every
+	// class and within its own, or 0 for a discontinued torró (fitted, but
+	// no longer ranked).
+	Rank      int `json:"rank"`
+	ClassRank int `json:"class_rank"`
+
+	// SeparableFromNext and SeparableFromNextInClass report whether the
+	// torró is ahead of the next-ranked torró, overall and within its
+	// class, at the 95% level.
+	SeparableFromNext        bool `json:"separable_from_next"`
+	SeparableFromNextInClass bool `json:"separable_from_next_in_class"`
+
+	FittedAt time.Time `json:"fitted_at"`
+}
+
+// Margin is the half-width of the 95% interval, in rating points.
+func (s *TorroStrength) Margin() float64 {
+	return (s.Upper - s.Lower) / 2
+}
+
+// PairRecord is the head-to-head tally between two torrons over every
+// Result, as the batch fit reads it. Wins1 and Wins2 are decided votes for
+// Torro1 and Torro2 respectively.
+type PairRecord struct {
+	Torro1 string
+	Torro2 string
+	Wins1  int
+	Wins2  int
+	Draws  int
+}
+
+// LeaderSeparation answers "is #1 really ahead of #2?" for one ranked
+// list of strengths.
+type LeaderSeparation struct {
+	LeaderId     string `json:"leader_id"`
+	LeaderName   string `json:"leader_name"`
+	RunnerUpId   string `json:"runner_up_id"`
+	RunnerUpName string `json:"runner_up_name"`
+
+	// Separable is true when the leader's strength exceeds the runner-up's
+	// at the 95% level.
+	Separable bool `json:"separable"`
+}
+
+type StrengthRepo interface {
+	// ListPairRecords tallies every Result of the current rating season by
+	// the pair of torrons involved, except those cast by quarantined users.
+	ListPairRecords(ctx context.Context) ([]*PairRecord, error)
+
+	// List returns the latest fit, one row per fitted torró.
+	List(ctx context.Context) ([]*TorroStrength, error)
+
+	// Replace swaps the stored fit for a new one in a single transaction.
+	Replace(ctx context.Context, strengths []*TorroStrength) error
+}
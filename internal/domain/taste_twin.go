@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"context"
+	"math"
+)
+
+// TasteTwins is how a user's personal ratings compare with everyone
+// else's (see internal/taste). It holds aggregates only: no other user is
+// identified, and a pick is only shown once several twins agree on it.
+type TasteTwins struct {
+	// Compared is how many users share enough rated torrons with this one
+	// to be compared at all.
+	Compared int `json:"compared"`
+
+	// Twins is how many of them rank torrons much like this user does.
+	Twins int `json:"twins"`
+
+	// Closest are the closest twins' matches, best first.
+	Closest []*TasteMatch `json:"closest"`
+
+	// Opposite is the compared user who ranks torrons least like this one,
+	// nil when nobody disagrees at all.
+	Opposite *TasteMatch `json:"opposite,omitempty"`
+
+	// Picks are torrons the user hasn't rated that their twins rank
+	// highly, the most agreed on first.
+	Picks []*TwinPick `json:"picks"`
+}
+
+// TasteMatch is how alike one anonymous user ranks the torrons both have
+// rated: Correlation is the rank correlation, from -1 (opposite order) to
+// 1 (the same order), over SharedTorrons torrons.
+type TasteMatch struct {
+	Correlation   float64 `json:"correlation"`
+	SharedTorrons int     `json:"shared_torrons"`
+}
+
+// Percentage returns Correlation from 0 to 100, 0 for any disagreement.
+func (m *TasteMatch) Percentage() int {
+	return int(math.Round(max(0, m.Correlation) * 100))
+}
+
+// TwinPick is a torró the user hasn't rated that Twins of their taste
+// twins have among their favourites.
+type TwinPick struct {
+	TorroId string `json:"torro_id"`
+	Name    string `json:"name"`
+	Image   string `json:"image"`
+	Twins   int    `json:"twins"`
+}
+
+type TasteTwinRepo interface {
+	// ListOverlapping returns every snapshot of the users, other than
+	// userId and quarantined ones, who have rated at least minOverlap of
+	// the torrons userId has. Only UserId, TorronId and Rating are set.
+	ListOverlapping(ctx context.Context, userId string, minOverlap int) ([]*UserEloSnapshot, error)
+}
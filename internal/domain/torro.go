@@ -28,6 +28,27 @@ type Torro struct {
 	IsNew2025       bool     `db:"IsNew2025"       json:"is_new_2025"`
 	Discontinued    bool     `db:"Discontinued"    json:"discontinued"`
 	YearAdded       int      `db:"YearAdded"       json:"year_added"`
+
+	// Rating-engine state beyond the point estimate (added in migration
+	// 000021). Only the deviation-aware engines move these; see RatingState.
+	RatingDeviation  float64 `db:"RatingDeviation"  json:"rating_deviation,omitempty"`
+	RatingVolatility float64 `db:"RatingVolatility" json:"rating_volatility,omitempty"`
+}
+
+// RatingState returns the torró's full rating-engine state.
+func (t *Torro) RatingState() RatingState {
+	return RatingState{
+		Rating:     t.Rating,
+		Deviation:  t.RatingDeviation,
+		Volatility: t.RatingVolatility,
+	}
+}
+
+// SetRatingState stores an engine's output back onto the torró.
+func (t *Torro) SetRatingState(state RatingState) {
+	t.Rating = state.Rating
+	t.RatingDeviation = state.Deviation
+	t.RatingVolatility = state.Volatility
 }
 
 // TorroFilter holds optional dietary attribute filters used when listing
@@ -46,13 +67,15 @@ func (f TorroFilter) IsEmpty() bool {
 
 type TorroRepo interface {
 	Get(ctx context.Context, id string) (*Torro, error)
+	// List returns every torró, discontinued ones included with
+	// Discontinued set.
 	List(ctx context.Context) ([]*Torro, error)
 	ListByClass(ctx context.Context, classId string) ([]*Torro, error)
 	// ListFiltered lists torrons optionally scoped to a class and filtered by
 	// dietary attributes. An empty classId returns torrons across all
 	// classes. Results are ordered by rating (descending).
 	ListFiltered(ctx context.Context, classId string, filter TorroFilter) ([]*Torro, error)
-	Update(ctx context.Context, id string, rating float64) (*Torro, error)
+	Update(ctx context.Context, id string, state RatingState) (*Torro, error)
 
 	// TopNByClass returns the top N active (non-discontinued) torrons in a
 	// class ordered by Rating descending. Used to seed a Phase 2 bracket
@@ -62,5 +85,5 @@ type TorroRepo interface {
 
 	// Transaction methods
 	GetTx(tx *sql.Tx, ctx context.Context, id string) (*Torro, error)
-	UpdateTx(tx *sql.Tx, ctx context.Context, id string, rating float64) (*Torro, error)
+	UpdateTx(tx *sql.Tx, ctx context.Context, id string, state RatingState) (*Torro, error)
 }
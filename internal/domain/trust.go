@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Vote weighting policies, as selected by the "trust.policy" config value
+// (see internal/config) and reported by VoteWeighting.Name.
+const (
+	VoteWeightingNone  = "none"
+	VoteWeightingTrust = "trust"
+)
+
+// TrustSignals is what a user's trust score is computed from.
+type TrustSignals struct {
+	UserId    string
+	FirstSeen time.Time
+
+	// Votes is the user's VoteCount and LongestStreak their best run of
+	// consecutive voting days.
+	Votes         int
+	LongestStreak int
+
+	// Comparable is how many of the user's decided votes were between
+	// torrons rated differently at the time; Agreed is how many of those
+	// went to the higher-rated one, i.e. agreed with the community.
+	Comparable int
+	Agreed     int
+}
+
+// VoteWeighting decides how much one vote moves the global ratings.
+// Implementations live in internal/trust. A user's personal snapshots
+// always take the full update: the weighting only protects the community
+// ranking.
+type VoteWeighting interface {
+	// Name returns the policy's VoteWeighting* identifier.
+	Name() string
+
+	// Score returns a user's trust score at now, from 0 (a brand new
+	// cookie) to 1.
+	Score(s *TrustSignals, now time.Time) float64
+
+	// Weight returns the share of a full rating update a vote by user
+	// counts for, from above 0 to 1. user is nil for an anonymous vote.
+	Weight(user *User) float64
+}
+
+type TrustRepo interface {
+	// ListSignals tallies TrustSignals for every user who has voted.
+	ListSignals(ctx context.Context) ([]*TrustSignals, error)
+
+	// SaveScores stores each user's trust score, keyed by user id.
+	SaveScores(ctx context.Context, scores map[string]float64) error
+}
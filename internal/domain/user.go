@@ -20,11 +20,42 @@ type User struct {
 	CurrentStreak int     `db:"CurrentStreak" json:"current_streak"`
 	LongestStreak int     `db:"LongestStreak" json:"longest_streak"`
 	LastVoteDate  *string `db:"LastVoteDate"  json:"last_vote_date,omitempty"`
+
+	// Quarantined is set by the vote-fraud analyzer (added in migration
+	// 000025, see internal/fraud). A quarantined user's votes still update
+	// their personal snapshots but no longer move global ratings.
+	Quarantined bool `db:"Quarantined" json:"-"`
+
+	// TrustScore is the user's trust score from 0 to 1, recomputed
+	// periodically (added in migration 000030, see internal/trust). It
+	// decides how much their votes move the global ratings.
+	TrustScore float64 `db:"TrustScore" json:"-"`
+
+	// IpHash is a truncated SHA-256 of the client IP the user was created
+	// from, written once on Create. Never the IP itself.
+	IpHash *string `db:"IpHash" json:"-"`
 }
 
 // ClassVotesMap is a helper type for working with the ClassVotes JSONB field
 type ClassVotesMap map[string]int
 
+// SeasonVoteCount returns how many votes the user has cast this rating
+// season: the sum of ClassVotes, which a season rollover resets along with
+// the personal snapshots, unlike the all-time VoteCount. It is what the
+// Global class's personal leaderboard unlocks on. Unparseable ClassVotes
+// count as none.
+func (u *User) SeasonVoteCount() int {
+	var classVotes ClassVotesMap
+	if len(u.ClassVotes) == 0 || json.Unmarshal(u.ClassVotes, &classVotes) != nil {
+		return 0
+	}
+	total := 0
+	for _, n := range classVotes {
+		total += n
+	}
+	return total
+}
+
 // UserRepo defines the interface for user data access
 type UserRepo interface {
 	// Get retrieves a user by ID
@@ -45,7 +76,7 @@ type UserRepo interface {
 	// UpdateLastSeen updates the user's last seen timestamp
 	UpdateLastSeen(ctx context.Context, userId string) error
 
-	// Transaction methods
+	// Transaction methods. GetTx locks the user's row.
 	GetTx(tx *sql.Tx, ctx context.Context, id string) (*User, error)
 	IncrementVoteCountTx(tx *sql.Tx, ctx context.Context, userId string, classId string) error
 
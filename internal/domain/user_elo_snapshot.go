@@ -14,6 +14,27 @@ type UserEloSnapshot struct {
 	Rating      float64 `db:"Rating"      json:"rating"`
 	VoteCount   int     `db:"VoteCount"   json:"vote_count"`
 	LastUpdated string  `db:"LastUpdated" json:"last_updated"`
+
+	// Rating-engine state beyond the point estimate (added in migration
+	// 000021), mirroring Torro.RatingDeviation/RatingVolatility.
+	RatingDeviation  float64 `db:"RatingDeviation"  json:"rating_deviation"`
+	RatingVolatility float64 `db:"RatingVolatility" json:"rating_volatility"`
+}
+
+// RatingState returns the snapshot's full rating-engine state.
+func (s *UserEloSnapshot) RatingState() RatingState {
+	return RatingState{
+		Rating:     s.Rating,
+		Deviation:  s.RatingDeviation,
+		Volatility: s.RatingVolatility,
+	}
+}
+
+// SetRatingState stores an engine's output back onto the snapshot.
+func (s *UserEloSnapshot) SetRatingState(state RatingState) {
+	s.Rating = state.Rating
+	s.RatingDeviation = state.Deviation
+	s.RatingVolatility = state.Volatility
 }
 
 // UserLeaderboardEntry represents a torron with its user-specific rating
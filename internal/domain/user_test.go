@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSeasonVoteCount(t *testing.T) {
+	tests := []struct {
+		name       string
+		classVotes string
+		want       int
+	}{
+		{name: "no votes yet", classVotes: "", want: 0},
+		{name: "reset by a rollover", classVotes: "{}", want: 0},
+		{name: "sums every class", classVotes: `{"1": 30, "2": 15, "5": 6}`, want: 51},
+		{name: "unparseable", classVotes: "[1, 2]", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &User{VoteCount: 80, ClassVotes: json.RawMessage(tt.classVotes)}
+			if got := user.SeasonVoteCount(); got != tt.want {
+				t.Errorf("SeasonVoteCount() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// VoteUndo is everything a user's latest Phase 1 vote overwrote (added in
+// migration 000026), kept so the duel screen's undo toast can reverse it
+// exactly. Each vote replaces its user's previous VoteUndo, so only the
+// latest vote is ever undoable.
+type VoteUndo struct {
+	UserId   string
+	ResultId string
+	CastAt   time.Time
+
+	// ClassId is the class whose ClassVotes counter the vote incremented.
+	ClassId string
+
+	// AdventDate is the AdventVotes date the vote recorded, when it was
+	// also the day's advent duel.
+	AdventDate *string
+
+	// PairingId, Torro1, Torro2 and the before/after ratings are read back
+	// from the Result and its Pairing, never stored twice.
+	PairingId string
+	Torro1    string
+	Torro2    string
+	Rat1Bef   float64
+	Rat2Bef   float64
+	Rat1Aft   float64
+	Rat2Aft   float64
+
+	// Torro1Before and Torro2Before are both torrons' full rating state
+	// before the vote. Their Rating is the Result's RatingBefore.
+	Torro1Before RatingState
+	Torro2Before RatingState
+
+	// Snapshot1Before and Snapshot2Before are the user's snapshots of
+	// Torro1 and Torro2 before the vote; a VoteCount of 0 means the vote
+	// created the snapshot.
+	Snapshot1Before UserEloSnapshot
+	Snapshot2Before UserEloSnapshot
+
+	PrevCurrentStreak int
+	PrevLongestStreak int
+	PrevLastVoteDate  *string
+}
+
+// Expired reports whether the grace window for undoing the vote has passed.
+func (u *VoteUndo) Expired(window time.Duration, now time.Time) bool {
+	return now.Sub(u.CastAt) > window
+}
+
+// UndoRating returns a torró's rating state with one vote taken back out.
+// current is the torró's state now, before its state before the vote and
+// after the Rating the vote left it at. If no other vote has moved the
+// torró since, it goes back to before exactly; otherwise only the vote's
+// own rating change is subtracted, keeping everything later votes did.
+func UndoRating(current, before RatingState, after float64) RatingState {
+	if current.Rating == after {
+		return before
+	}
+	current.Rating -= after - before.Rating
+	return current
+}
+
+type VoteUndoRepo interface {
+	// Get returns the user's latest undoable vote.
+	Get(ctx context.Context, userId string) (*VoteUndo, error)
+
+	// Transaction methods. GetTx locks the user's VoteUndos row.
+	GetTx(tx *sql.Tx, ctx context.Context, userId string) (*VoteUndo, error)
+	SaveTx(tx *sql.Tx, ctx context.Context, undo *VoteUndo) error
+
+	// RevertTx reverses every side effect of the vote except the global
+	// torró ratings, which the caller restores under its own row locks:
+	// it restores the user's vote counts, streak and snapshots, deletes
+	// the advent vote if any and the Result itself, and forgets the undo.
+	RevertTx(tx *sql.Tx, ctx context.Context, undo *VoteUndo) error
+
+	// ForgetTx drops the user's pending undo, for a vote that moves the
+	// same counters and snapshots without being undoable itself: reverting
+	// the earlier vote past it would restore state it has since changed.
+	ForgetTx(tx *sql.Tx, ctx context.Context, userId string) error
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUndoRating(t *testing.T) {
+	before := RatingState{Rating: 1500, Deviation: 200, Volatility: 0.06}
+
+	tests := []struct {
+		name    string
+		current RatingState
+		after   float64
+		want    RatingState
+	}{
+		{
+			name:    "untouched since the vote goes back exactly",
+			current: RatingState{Rating: 1516, Deviation: 190, Volatility: 0.059},
+			after:   1516,
+			want:    before,
+		},
+		{
+			name:    "later votes keep their own change",
+			current: RatingState{Rating: 1530, Deviation: 180, Volatility: 0.058},
+			after:   1516,
+			want:    RatingState{Rating: 1514, Deviation: 180, Volatility: 0.058},
+		},
+		{
+			name:    "a losing vote is added back",
+			current: RatingState{Rating: 1470, Deviation: 180, Volatility: 0.058},
+			after:   1484,
+			want:    RatingState{Rating: 1486, Deviation: 180, Volatility: 0.058},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UndoRating(tt.current, before, tt.after); got != tt.want {
+				t.Errorf("UndoRating() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVoteUndoExpired(t *testing.T) {
+	castAt := time.Date(2025, 12, 1, 10, 0, 0, 0, time.UTC)
+	u := &VoteUndo{CastAt: castAt}
+
+	if u.Expired(10*time.Second, castAt.Add(10*time.Second)) {
+		t.Error("expired at the very end of the window")
+	}
+	if !u.Expired(10*time.Second, castAt.Add(11*time.Second)) {
+		t.Error("not expired after the window")
+	}
+}
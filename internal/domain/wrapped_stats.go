@@ -42,7 +42,7 @@ type WrappedDuelStats struct {
 // section.
 type BracketPathStat struct {
 	// HasVoted is false if the user never voted in this bracket at all;
-	// every other field is then a zero value.
+	// the vote fields below are then zero values.
 	HasVoted bool
 
 	RoundsVoted    int
@@ -55,12 +55,24 @@ type BracketPathStat struct {
 	// MatchedChampion is meaningful only if HasChampion: did the user
 	// pick the champion in any of their voted matches.
 	MatchedChampion bool
+
+	// HasPrediction is whether the user made a pick'em prediction for the
+	// bracket (see BracketPredictionRepo), voted or not; the prediction
+	// fields below are zero values without one.
+	HasPrediction     bool
+	PredictionPoints  int
+	PredictionDecided int // decided matches the prediction picked, byes left out
+	PredictionCorrect int // subset of PredictionDecided it called right
+	// PredictedChampion is meaningful only if HasChampion: did the
+	// prediction name the champion.
+	PredictedChampion bool
 }
 
 // WrappedStatsRepo provides the read-only cross-source aggregation behind
 // a user's personal "Torrorèndum Wrapped" recap. Like PressStatsRepo, it
 // only reads from existing Phase 1 (Results/Pairings/Torrons) and Phase 2
-// (BracketMatches/BracketMatchVotes) tables - no new schema is needed.
+// (BracketMatches/BracketMatchVotes/BracketPredictions) tables - no new
+// schema is needed.
 type WrappedStatsRepo interface {
 	// DuelStats returns, for one user, their most contested duel (closest
 	// global vote split among pairings they voted on) and their most
@@ -73,8 +85,9 @@ type WrappedStatsRepo interface {
 	// error) if the user has no eligible pairings yet.
 	DuelStats(ctx context.Context, userId string, minTotalVotes int) (*WrappedDuelStats, error)
 
-	// BracketPath summarizes a user's participation in one bracket.
-	// Returns a BracketPathStat with HasVoted false (not an error) if the
-	// user never voted in this bracket.
+	// BracketPath summarizes a user's participation in one bracket: their
+	// votes and their pick'em prediction. Returns a BracketPathStat with
+	// HasVoted and HasPrediction false (not an error) if the user did
+	// neither.
 	BracketPath(ctx context.Context, userId string, bracketId string) (*BracketPathStat, error)
 }
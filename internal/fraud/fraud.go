@@ -0,0 +1,101 @@
+// Package fraud scores users on voting patterns that scripted or
+// ballot-stuffing voters leave behind in Results, so the worst can be
+// quarantined: their votes stop moving global ratings while their own
+// personal rankings keep working.
+//
+// Each signal is mapped to a strength from 0 (looks human) to 1 (looks
+// scripted), weighted by how much it proves on its own, and combined as a
+// noisy-OR: the score is the chance that at least one signal is real. The
+// weights are set so that a side bias or a sub-second cadence can quarantine
+// a user alone, while a favourite torró or a crowded IP, each of which an
+// honest voter can show, only does in combination with another signal.
+package fraud
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+const (
+	// DefaultThreshold is the score at or above which the analyzer
+	// quarantines a user.
+	DefaultThreshold = 0.7
+
+	// DefaultMinVotes is how many votes a user needs before they are
+	// scored at all; below it the shares are too noisy to read.
+	DefaultMinVotes = 20
+)
+
+const (
+	sideWeight      = 0.75
+	cadenceWeight   = 0.8
+	favouriteWeight = 0.6
+	ipWeight        = 0.5
+)
+
+// Assess scores one user's signals.
+func Assess(s *domain.FraudSignals) *domain.FraudAssessment {
+	a := &domain.FraudAssessment{
+		UserId:      s.UserId,
+		Votes:       s.Votes,
+		IpSiblings:  s.IpSiblings,
+		Quarantined: s.Quarantined,
+		Released:    s.Released,
+		Reasons:     []string{},
+	}
+	if s.Decided > 0 {
+		a.LeftShare = float64(s.LeftPicks) / float64(s.Decided)
+		a.FavouriteShare = float64(s.FavouriteWins) / float64(s.Decided)
+	}
+	if s.Votes > 1 {
+		a.FastShare = float64(s.FastVotes) / float64(s.Votes-1)
+	}
+
+	clean := 1.0
+	add := func(weight, strength float64, reason string) {
+		if strength <= 0 {
+			return
+		}
+		clean *= 1 - weight*strength
+		a.Reasons = append(a.Reasons, reason)
+	}
+
+	// An honest voter's left share over 20+ decided votes stays within
+	// about ten points of half; an always-left script sits at 100%.
+	if s.Decided > 0 {
+		add(sideWeight, clamp((math.Abs(a.LeftShare-0.5)-0.1)/0.4),
+			fmt.Sprintf("picks the left torró in %.0f%% of decided votes", 100*a.LeftShare))
+	}
+
+	// Reading two torrons takes longer than a second. An occasional
+	// double-tap is forgiven; half the votes that fast is a script.
+	add(cadenceWeight, clamp((a.FastShare-0.05)/0.45),
+		fmt.Sprintf("%.0f%% of votes came under a second after the previous one", 100*a.FastShare))
+
+	// With a class's worth of torrons, any one shows up in roughly a tenth
+	// of duels, so one torró winning a quarter of them means its duels are
+	// being sought out.
+	add(favouriteWeight, clamp((a.FavouriteShare-0.25)/0.35),
+		fmt.Sprintf("one torró won %.0f%% of decided votes", 100*a.FavouriteShare))
+
+	// A household or an office shares an IP; a dozen fresh identities from
+	// one within a day is someone clearing cookies.
+	add(ipWeight, clamp(float64(s.IpSiblings-3)/7),
+		fmt.Sprintf("%d other new users from the same IP within a day", s.IpSiblings))
+
+	a.Score = 1 - clean
+	return a
+}
+
+// Flagged reports whether an assessment should put its user in
+// quarantine: scored at or above threshold, not there already and never
+// released by an admin.
+func Flagged(a *domain.FraudAssessment, threshold float64) bool {
+	return a.Score >= threshold && !a.Quarantined && !a.Released
+}
+
+func clamp(x float64) float64 {
+	return math.Max(0, math.Min(1, x))
+}
@@ -0,0 +1,88 @@
+package fraud
+
+import (
+	"testing"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+func TestAssess(t *testing.T) {
+	tests := []struct {
+		name    string
+		signals domain.FraudSignals
+		flagged bool
+		reasons int
+	}{
+		{
+			name:    "honest voter",
+			signals: domain.FraudSignals{Votes: 60, Decided: 55, LeftPicks: 29, FastVotes: 1, FavouriteWins: 8, IpSiblings: 1},
+			flagged: false,
+			reasons: 0,
+		},
+		{
+			name:    "always left",
+			signals: domain.FraudSignals{Votes: 40, Decided: 40, LeftPicks: 40, FavouriteWins: 6},
+			flagged: true,
+			reasons: 1,
+		},
+		{
+			name:    "sub-second cadence",
+			signals: domain.FraudSignals{Votes: 101, Decided: 100, LeftPicks: 52, FastVotes: 90, FavouriteWins: 9},
+			flagged: true,
+			reasons: 1,
+		},
+		{
+			name:    "favourite torró alone",
+			signals: domain.FraudSignals{Votes: 30, Decided: 30, LeftPicks: 15, FavouriteWins: 30},
+			flagged: false,
+			reasons: 1,
+		},
+		{
+			name:    "crowded IP alone",
+			signals: domain.FraudSignals{Votes: 30, Decided: 30, LeftPicks: 15, FavouriteWins: 4, IpSiblings: 40},
+			flagged: false,
+			reasons: 1,
+		},
+		{
+			name:    "favourite torró from a crowded IP",
+			signals: domain.FraudSignals{Votes: 30, Decided: 30, LeftPicks: 15, FavouriteWins: 30, IpSiblings: 40},
+			flagged: true,
+			reasons: 2,
+		},
+		{
+			name:    "only draws",
+			signals: domain.FraudSignals{Votes: 25},
+			flagged: false,
+			reasons: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := Assess(&tt.signals)
+			if a.Score < 0 || a.Score > 1 {
+				t.Fatalf("score %.3f out of [0, 1]", a.Score)
+			}
+			if got := Flagged(a, DefaultThreshold); got != tt.flagged {
+				t.Errorf("flagged = %v (score %.3f), want %v", got, a.Score, tt.flagged)
+			}
+			if len(a.Reasons) != tt.reasons {
+				t.Errorf("reasons = %q, want %d of them", a.Reasons, tt.reasons)
+			}
+		})
+	}
+}
+
+func TestFlaggedSkipsQuarantinedAndReleased(t *testing.T) {
+	signals := domain.FraudSignals{Votes: 40, Decided: 40, LeftPicks: 40}
+
+	signals.Quarantined = true
+	if Flagged(Assess(&signals), DefaultThreshold) {
+		t.Error("an already quarantined user was flagged again")
+	}
+
+	signals.Quarantined, signals.Released = false, true
+	if Flagged(Assess(&signals), DefaultThreshold) {
+		t.Error("a user released by an admin was flagged again")
+	}
+}
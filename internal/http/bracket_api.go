@@ -0,0 +1,264 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"math/bits"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+
+	"github.com/krtffl/torro/internal/domain"
+	"github.com/krtffl/torro/internal/logger"
+	"github.com/krtffl/torro/internal/sharecard"
+	"github.com/krtffl/torro/internal/tournament"
+)
+
+// BracketAPIResponse is a bracket as GET /api/bracket/{bracketId} returns
+// it: the bracket itself (with its seeding), its seeded entries, its
+// groups (groups then knockout only) and every match it has played or
+// will play, each with its vote tally.
+type BracketAPIResponse struct {
+	Bracket *domain.Bracket        `json:"bracket"`
+	Entries []BracketAPIEntry      `json:"entries"`
+	Groups  []*domain.BracketGroup `json:"groups,omitempty"`
+	Matches []BracketAPIMatch      `json:"matches"`
+}
+
+// BracketAPIEntry is a seeded entry with its torró's name.
+type BracketAPIEntry struct {
+	*domain.BracketEntry
+	Name string `json:"name"`
+}
+
+// BracketAPIMatch is a match with its votes by torró ID, a competitor
+// nobody has voted for at 0.
+type BracketAPIMatch struct {
+	*domain.BracketMatch
+	Votes map[string]int `json:"votes"`
+}
+
+// bracketDetail is everything a bracket's API and tree are built from.
+type bracketDetail struct {
+	bracket *domain.Bracket
+	entries []*domain.BracketEntry
+	groups  []*domain.BracketGroup
+	matches []*domain.BracketMatch
+	votes   map[string]map[string]int // match ID -> torró ID -> votes
+	names   map[string]string         // torró ID -> name
+}
+
+// loadBracketDetail reads a bracket with its entries, groups, matches, vote
+// tallies and entrants' names.
+func (h *Handler) loadBracketDetail(ctx context.Context, bracketId string) (*bracketDetail, error) {
+	bracket, err := h.bracketRepo.Get(ctx, bracketId)
+	if err != nil {
+		return nil, err
+	}
+	detail := &bracketDetail{bracket: bracket, names: make(map[string]string)}
+
+	if detail.entries, err = h.bracketRepo.ListEntries(ctx, bracket.Id); err != nil {
+		return nil, err
+	}
+	if bracket.IsGroupsKnockout() {
+		if detail.groups, err = h.bracketRepo.ListGroups(ctx, bracket.Id); err != nil {
+			return nil, err
+		}
+	}
+	if detail.matches, err = h.bracketRepo.ListMatches(ctx, bracket.Id); err != nil {
+		return nil, err
+	}
+	if detail.votes, err = h.bracketRepo.CountVotesByBracket(ctx, bracket.Id); err != nil {
+		return nil, err
+	}
+
+	getTorro := h.torroFetcher(ctx)
+	for _, e := range detail.entries {
+		torro, err := getTorro(e.TorronId)
+		if err != nil {
+			return nil, err
+		}
+		detail.names[torro.Id] = torro.Name
+	}
+	return detail, nil
+}
+
+// bracketAPI handles GET /api/bracket/{bracketId}.
+func (h *Handler) bracketAPI(w http.ResponseWriter, r *http.Request) {
+	logger.Info("[Handler - BracketAPI] Incoming request")
+
+	bracketId := chi.URLParam(r, "bracketId")
+	detail, err := h.loadBracketDetail(r.Context(), bracketId)
+	if err != nil {
+		logger.Error("[Handler - BracketAPI] Couldn't load bracket %s. %v", bracketId, err)
+		renderBracketError(w, r, err)
+		return
+	}
+
+	response := BracketAPIResponse{
+		Bracket: detail.bracket,
+		Entries: make([]BracketAPIEntry, 0, len(detail.entries)),
+		Groups:  detail.groups,
+		Matches: make([]BracketAPIMatch, 0, len(detail.matches)),
+	}
+	for _, e := range detail.entries {
+		response.Entries = append(response.Entries, BracketAPIEntry{BracketEntry: e, Name: detail.names[e.TorronId]})
+	}
+	for _, m := range detail.matches {
+		votes := map[string]int{m.Torro1Id: detail.votes[m.Id][m.Torro1Id]}
+		if m.Torro2Id != nil {
+			votes[*m.Torro2Id] = detail.votes[m.Id][*m.Torro2Id]
+		}
+		response.Matches = append(response.Matches, BracketAPIMatch{BracketMatch: m, Votes: votes})
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, response)
+}
+
+// bracketTree handles GET /bracket/{bracketId}/tree.svg and tree.png: the
+// bracket's knockout tree, winners highlighted, as a vector image or as a
+// shareable card. Registered without the extension (see /share/card in
+// server.go); middleware.URLFormat hands it over instead. A Swiss bracket
+// has no tree.
+func (h *Handler) bracketTree(w http.ResponseWriter, r *http.Request) {
+	logger.Info("[Handler - BracketTree] Incoming request")
+
+	format, _ := r.Context().Value(middleware.URLFormatCtxKey).(string)
+	if format != "svg" && format != "png" {
+		render.Render(w, r, domain.ErrNotFound(
+			fmt.Errorf("%s: the tree comes as tree.svg or tree.png", domain.NotFoundError)))
+		return
+	}
+
+	ctx := r.Context()
+	bracketId := chi.URLParam(r, "bracketId")
+	detail, err := h.loadBracketDetail(ctx, bracketId)
+	if err != nil {
+		logger.Error("[Handler - BracketTree] Couldn't load bracket %s. %v", bracketId, err)
+		renderBracketError(w, r, err)
+		return
+	}
+	if detail.bracket.IsSwiss() {
+		render.Render(w, r, domain.ErrBadRequest(
+			fmt.Errorf("%s: a Swiss bracket has no knockout tree", domain.ValidationError)))
+		return
+	}
+
+	data := bracketTreeData(detail)
+	data.ClassName = h.bracketClassName(ctx, detail.bracket.ClassId)
+
+	if format == "svg" {
+		w.Header().Set("Content-Type", "image/svg+xml")
+		w.Header().Set("Cache-Control", "public, max-age=60")
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write(sharecard.BracketTreeSVG(data)); err != nil {
+			logger.Error("[Handler - BracketTree] Couldn't write response. %v", err)
+		}
+		return
+	}
+
+	// Cap concurrent renders (each allocates a large RGBA and pegs a core):
+	// shed load with 503 rather than piling up when the cap is saturated.
+	if !sharecard.TryAcquireRenderSlot(ctx) {
+		logger.Warn("[Handler - BracketTree] Render slots saturated; shedding request.")
+		w.Header().Set("Retry-After", "1")
+		http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	defer sharecard.ReleaseRenderSlot()
+
+	png, err := sharecard.RenderBracketTree(data)
+	if err != nil {
+		logger.Error("[Handler - BracketTree] Couldn't render card. %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "public, max-age=60")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(png); err != nil {
+		logger.Error("[Handler - BracketTree] Couldn't write response. %v", err)
+	}
+}
+
+// bracketTreeData lays a knockout bracket's winners side out as a tree,
+// round by round with every slot in place - the ones nobody has reached
+// yet empty - followed by a double-elimination bracket's grand final and
+// its reset. The losers side stays on the overview page. A
+// groups-then-knockout bracket's tree is its knockout, empty until the
+// group stage is over.
+func bracketTreeData(detail *bracketDetail) sharecard.BracketTreeData {
+	bracket := detail.bracket
+	seeds := seedMap(detail.entries)
+
+	var data sharecard.BracketTreeData
+	if bracket.ChampionId != nil {
+		data.Champion = detail.names[*bracket.ChampionId]
+	}
+
+	winnersRounds := bits.Len(uint(bracket.Size)) - 1
+	grandFinals := 0
+	for _, m := range detail.matches {
+		if m.Side == domain.BracketSideGrandFinal {
+			grandFinals = max(grandFinals, tournament.SideRound(bracket.Size, m))
+		}
+	}
+
+	winners := roundLabels(bracket, domain.BracketSideWinners, winnersRounds)
+	for i, label := range winners {
+		data.Rounds = append(data.Rounds, sharecard.BracketTreeRound{
+			Label:   label,
+			Matches: make([]sharecard.BracketTreeMatch, bracket.Size>>(i+1)),
+		})
+	}
+	for _, label := range roundLabels(bracket, domain.BracketSideGrandFinal, grandFinals) {
+		data.Rounds = append(data.Rounds, sharecard.BracketTreeRound{Label: label, Matches: make([]sharecard.BracketTreeMatch, 1)})
+	}
+
+	for _, m := range detail.matches {
+		column := -1
+		switch {
+		case m.Side == domain.BracketSideGrandFinal:
+			column = winnersRounds + tournament.SideRound(bracket.Size, m) - 1
+		case m.Side == domain.BracketSideWinners || m.Side == "":
+			column = m.Round - bracket.GroupStageRounds() - 1
+		}
+		if column < 0 || column >= len(data.Rounds) || m.Slot < 0 || m.Slot >= len(data.Rounds[column].Matches) {
+			continue
+		}
+
+		slot := func(torroId string) sharecard.BracketTreeSlot {
+			return sharecard.BracketTreeSlot{
+				Name:  detail.names[torroId],
+				Seed:  seeds[torroId],
+				Votes: detail.votes[m.Id][torroId],
+				Won:   m.WinnerId != nil && *m.WinnerId == torroId,
+			}
+		}
+		match := sharecard.BracketTreeMatch{Top: slot(m.Torro1Id), Bye: m.IsBye()}
+		if m.Torro2Id != nil {
+			match.Bottom = slot(*m.Torro2Id)
+		}
+		data.Rounds[column].Matches[m.Slot] = match
+	}
+	return data
+}
+
+// roundLabels returns the overview's labels for the first rounds rounds of
+// a side of bracket ("Ronda 1", ..., "Gran Final").
+func roundLabels(bracket *domain.Bracket, side string, rounds int) []string {
+	byRound := make(map[int][]BracketMatchView, rounds)
+	for round := 1; round <= rounds; round++ {
+		byRound[round] = nil
+	}
+
+	var labels []string
+	for _, view := range bracketRoundViews(bracket, side, byRound) {
+		labels = append(labels, view.Label)
+	}
+	return labels
+}
@@ -0,0 +1,68 @@
+package http
+
+import (
+	"testing"
+
+	"github.com/krtffl/torro/internal/domain"
+	"github.com/krtffl/torro/internal/tournament"
+)
+
+func TestBracketTreeData(t *testing.T) {
+	b, c, d := "b", "c", "d"
+	detail := &bracketDetail{
+		bracket: &domain.Bracket{Size: 4, Format: domain.BracketFormatDoubleElimination},
+		entries: []*domain.BracketEntry{
+			{TorronId: "a", Seed: 1}, {TorronId: "b", Seed: 2}, {TorronId: "c", Seed: 3}, {TorronId: "d", Seed: 4},
+		},
+		matches: []*domain.BracketMatch{
+			{Id: "m1", Round: 1, Side: domain.BracketSideWinners, Slot: 0, Torro1Id: "a", Torro2Id: &d, WinnerId: &d},
+			{Id: "m2", Round: 1, Side: domain.BracketSideWinners, Slot: 1, Torro1Id: "b", Torro2Id: &c, WinnerId: &b},
+			{Id: "l1", Round: 2, Side: domain.BracketSideLosers, Slot: 0, Torro1Id: "a", Torro2Id: &c},
+			{Id: "gf", Round: tournament.DoubleEliminationRounds(4), Side: domain.BracketSideGrandFinal, Torro1Id: "d"},
+		},
+		votes: map[string]map[string]int{"m1": {"a": 2, "d": 5}, "m2": {"b": 4}},
+		names: map[string]string{"a": "Alacant", "b": "Xixona", "c": "Coco", "d": "Gema"},
+	}
+
+	data := bracketTreeData(detail)
+	var labels []string
+	for _, round := range data.Rounds {
+		labels = append(labels, round.Label)
+	}
+	if len(labels) != 3 || labels[0] != "Ronda 1" || labels[1] != "Final de guanyadors" || labels[2] != "Gran Final" {
+		t.Fatalf("tree columns %q, want Ronda 1, the winners final and the grand final", labels)
+	}
+
+	first := data.Rounds[0].Matches
+	if len(first) != 2 || first[0].Top.Name != "Alacant" || first[0].Top.Votes != 2 || first[0].Top.Won ||
+		!first[0].Bottom.Won || first[0].Bottom.Seed != 4 || first[1].Bottom.Votes != 0 || !first[1].Top.Won {
+		t.Errorf("first round %+v", first)
+	}
+	if winnersFinal := data.Rounds[1].Matches; len(winnersFinal) != 1 || winnersFinal[0].Top.Name != "" {
+		t.Errorf("the unplayed winners final is %+v, want an empty place", winnersFinal)
+	}
+	if final := data.Rounds[2].Matches[0]; final.Top.Name != "Gema" || !final.Bye || final.Decided() {
+		t.Errorf("the grand final is %+v", final)
+	}
+	for _, round := range data.Rounds {
+		for _, m := range round.Matches {
+			if m.Top.Name == "Coco" && m.Bottom.Name == "Alacant" {
+				t.Error("the losers side made it into the tree")
+			}
+		}
+	}
+
+	// A groups-then-knockout bracket's knockout starts after its three group
+	// rounds.
+	groups := &bracketDetail{
+		bracket: &domain.Bracket{Size: 2, Format: domain.BracketFormatGroupsKnockout, Rounds: 3},
+		matches: []*domain.BracketMatch{
+			{Id: "g", Round: 1, Side: domain.BracketSideGroup, Torro1Id: "a", Torro2Id: &b},
+			{Id: "k", Round: 4, Side: domain.BracketSideWinners, Torro1Id: "a", Torro2Id: &b},
+		},
+		names: map[string]string{"a": "Alacant", "b": "Xixona"},
+	}
+	if data := bracketTreeData(groups); len(data.Rounds) != 1 || data.Rounds[0].Matches[0].Bottom.Name != "Xixona" {
+		t.Errorf("groups then knockout tree %+v", data.Rounds)
+	}
+}
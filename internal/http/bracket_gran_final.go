@@ -0,0 +1,161 @@
+package http
+
+import (
+	"context"
+	"strings"
+
+	"github.com/krtffl/torro/internal/catalog"
+	"github.com/krtffl/torro/internal/domain"
+	"github.com/krtffl/torro/internal/logger"
+	"github.com/krtffl/torro/internal/tournament"
+)
+
+// createGranFinalIfReady creates a campaign's Gran Final - the Global
+// class's meta-bracket - once every other class has a completed bracket in
+// it. Its entries are the class champions, and the runners-up too with
+// granFinalRunnersUp, seeded by their Phase 1 ratings into a
+// single-elimination tree played like any other bracket. It returns nil
+// without creating anything while a class bracket is missing or still in
+// play, and when the Gran Final already exists, including when a
+// concurrent call created it first.
+func (h *Handler) createGranFinalIfReady(ctx context.Context, campaignId string) (*domain.Bracket, error) {
+	if existing, err := h.bracketRepo.GetByCampaignAndClass(ctx, campaignId, catalog.GlobalClassId); err == nil && existing != nil {
+		return nil, nil
+	}
+
+	classes, err := h.classRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var finalists []string
+	seen := make(map[string]bool)
+	for _, class := range classes {
+		if class.Id == catalog.GlobalClassId {
+			continue
+		}
+		bracket, err := h.bracketRepo.GetByCampaignAndClass(ctx, campaignId, class.Id)
+		if err != nil {
+			if strings.Contains(err.Error(), string(domain.NotFoundError)) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		if bracket.Status != domain.BracketStatusCompleted || bracket.ChampionId == nil {
+			return nil, nil
+		}
+
+		classFinalists := []string{*bracket.ChampionId}
+		if h.granFinalRunnersUp {
+			entries, err := h.bracketRepo.ListEntries(ctx, bracket.Id)
+			if err != nil {
+				return nil, err
+			}
+			matches, err := h.bracketRepo.ListMatches(ctx, bracket.Id)
+			if err != nil {
+				return nil, err
+			}
+			if runnerUp := bracketRunnerUp(bracket, entries, matches); runnerUp != "" {
+				classFinalists = append(classFinalists, runnerUp)
+			}
+		}
+		for _, id := range classFinalists {
+			if !seen[id] {
+				seen[id] = true
+				finalists = append(finalists, id)
+			}
+		}
+	}
+	if len(finalists) < 2 {
+		return nil, nil
+	}
+
+	getTorro := h.torroFetcher(ctx)
+	field := make([]*domain.Torro, 0, len(finalists))
+	ratings := make(map[string]float64, len(finalists))
+	for _, id := range finalists {
+		torro, err := getTorro(id)
+		if err != nil {
+			return nil, err
+		}
+		field = append(field, torro)
+		ratings[torro.Id] = torro.Rating
+	}
+	seeded := tournament.SeedByScore(field, ratings)
+
+	size := 2
+	for size < len(seeded) {
+		size *= 2
+	}
+
+	bracket, err := h.createSeededBracket(ctx, &domain.Bracket{
+		CampaignId:   campaignId,
+		ClassId:      catalog.GlobalClassId,
+		Kind:         domain.BracketKindGranFinal,
+		Size:         size,
+		Format:       domain.BracketFormatSingleElimination,
+		CurrentRound: 1,
+		Seeding:      domain.BracketSeeding{Strategy: domain.BracketSeedingRating},
+	}, bracketOptions{}, seeded, ratings)
+	if err != nil {
+		if strings.Contains(err.Error(), string(domain.DuplicateKeyError)) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return bracket, nil
+}
+
+// startGranFinalAfter creates the Gran Final of bracket's campaign when
+// bracket, a class bracket, has just completed as the last one standing.
+// Its transaction has committed by then, so a failure here is only
+// logged; the bracket scheduler tries again on its next run.
+func (h *Handler) startGranFinalAfter(ctx context.Context, bracket *domain.Bracket) {
+	if bracket.Status != domain.BracketStatusCompleted || bracket.IsGranFinal() {
+		return
+	}
+
+	granFinal, err := h.createGranFinalIfReady(ctx, bracket.CampaignId)
+	if err != nil {
+		logger.Error("[Gran Final] Couldn't create the Gran Final of campaign %s. %v", bracket.CampaignId, err)
+		return
+	}
+	if granFinal != nil {
+		logger.Info("[Gran Final] Bracket %s completed the last class of campaign %s; created Gran Final %s",
+			bracket.Id, bracket.CampaignId, granFinal.Id)
+	}
+}
+
+// bracketRunnerUp returns the torró a completed bracket's champion beat to
+// the title: second in a Swiss bracket's standings, or else the loser of
+// the last round's decisive match - the final, or a double-elimination
+// grand final's reset. It is empty when there is none, as when the final
+// was a bye.
+func bracketRunnerUp(bracket *domain.Bracket, entries []*domain.BracketEntry, matches []*domain.BracketMatch) string {
+	if bracket.ChampionId == nil {
+		return ""
+	}
+	if bracket.IsSwiss() {
+		standings := tournament.SwissStandings(entries, matches)
+		if len(standings) < 2 {
+			return ""
+		}
+		return standings[1].TorroId
+	}
+
+	lastRound := 0
+	for _, m := range matches {
+		if m.Side != domain.BracketSideGroup {
+			lastRound = max(lastRound, m.Round)
+		}
+	}
+	for _, m := range matches {
+		if m.Round == lastRound && m.Side != domain.BracketSideGroup &&
+			m.WinnerId != nil && *m.WinnerId == *bracket.ChampionId {
+			if loser := m.LoserId(); loser != nil {
+				return *loser
+			}
+		}
+	}
+	return ""
+}
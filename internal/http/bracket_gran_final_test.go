@@ -0,0 +1,114 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/krtffl/torro/internal/catalog"
+	"github.com/krtffl/torro/internal/domain"
+)
+
+// granFinalBracketRepo is a minimal stand-in for domain.BracketRepo, used
+// only by createGranFinalIfReady's lookups of the campaign's brackets
+// (embedded-nil-interface trick, same as fakeBracketRepo).
+type granFinalBracketRepo struct {
+	domain.BracketRepo
+	brackets map[string]*domain.Bracket // classId -> the campaign's bracket, absent = none
+}
+
+func (f *granFinalBracketRepo) GetByCampaignAndClass(ctx context.Context, campaignId string, classId string) (*domain.Bracket, error) {
+	if b, ok := f.brackets[classId]; ok {
+		return b, nil
+	}
+	return nil, fmt.Errorf("%s: no bracket for class %s", domain.NotFoundError, classId)
+}
+
+func TestCreateGranFinalIfReadyWaitsForEveryClass(t *testing.T) {
+	champion := "a"
+	completed := func(classId string) *domain.Bracket {
+		return &domain.Bracket{ClassId: classId, Status: domain.BracketStatusCompleted, ChampionId: &champion}
+	}
+	classes := &fakeClassRepo{classes: []*domain.Class{{Id: "1"}, {Id: "2"}, {Id: catalog.GlobalClassId}}}
+
+	for name, brackets := range map[string]map[string]*domain.Bracket{
+		"a class without a bracket": {"1": completed("1")},
+		"a class bracket in play": {
+			"1": completed("1"),
+			"2": {ClassId: "2", Status: domain.BracketStatusInProgress},
+		},
+		"the Gran Final already created": {
+			"1": completed("1"), "2": completed("2"),
+			catalog.GlobalClassId: {Kind: domain.BracketKindGranFinal},
+		},
+	} {
+		// No database: each case must stop before creating anything.
+		h := &Handler{classRepo: classes, bracketRepo: &granFinalBracketRepo{brackets: brackets}}
+		granFinal, err := h.createGranFinalIfReady(context.Background(), "campaign")
+		if err != nil || granFinal != nil {
+			t.Errorf("with %s, createGranFinalIfReady = %+v, %v; want nothing created", name, granFinal, err)
+		}
+	}
+}
+
+func TestBracketRunnerUp(t *testing.T) {
+	a, b, c, d := "a", "b", "c", "d"
+	match := func(round int, side string, torro1 string, torro2, winner *string) *domain.BracketMatch {
+		return &domain.BracketMatch{Round: round, Side: side, Torro1Id: torro1, Torro2Id: torro2, WinnerId: winner}
+	}
+	entries := []*domain.BracketEntry{{TorronId: a, Seed: 1}, {TorronId: b, Seed: 2}, {TorronId: c, Seed: 3}, {TorronId: d, Seed: 4}}
+
+	tests := map[string]struct {
+		bracket *domain.Bracket
+		matches []*domain.BracketMatch
+		want    string
+	}{
+		"single elimination": {
+			bracket: &domain.Bracket{ChampionId: &c},
+			matches: []*domain.BracketMatch{
+				match(1, domain.BracketSideWinners, a, &d, &a),
+				match(1, domain.BracketSideWinners, b, &c, &c),
+				match(2, domain.BracketSideWinners, a, &c, &c),
+			},
+			want: a,
+		},
+		"double elimination won in the reset": {
+			bracket: &domain.Bracket{Format: domain.BracketFormatDoubleElimination, ChampionId: &b},
+			matches: []*domain.BracketMatch{
+				match(4, domain.BracketSideLosers, b, &c, &b),
+				match(5, domain.BracketSideGrandFinal, a, &b, &b),
+				match(6, domain.BracketSideGrandFinal, a, &b, &b),
+			},
+			want: a,
+		},
+		"groups then knockout": {
+			bracket: &domain.Bracket{Format: domain.BracketFormatGroupsKnockout, Rounds: 3, ChampionId: &d},
+			matches: []*domain.BracketMatch{
+				match(3, domain.BracketSideGroup, a, &b, &a),
+				match(4, domain.BracketSideWinners, d, &b, &d),
+			},
+			want: b,
+		},
+		"a final that was a bye": {
+			bracket: &domain.Bracket{ChampionId: &a},
+			matches: []*domain.BracketMatch{match(1, domain.BracketSideWinners, a, nil, &a)},
+			want:    "",
+		},
+		"Swiss": {
+			bracket: &domain.Bracket{Format: domain.BracketFormatSwiss, ChampionId: &a},
+			matches: []*domain.BracketMatch{
+				match(1, domain.BracketSideWinners, a, &d, &a),
+				match(1, domain.BracketSideWinners, b, &c, &c),
+				match(2, domain.BracketSideWinners, a, &c, &a),
+				match(2, domain.BracketSideWinners, b, &d, &b),
+			},
+			want: c,
+		},
+	}
+
+	for name, tt := range tests {
+		if got := bracketRunnerUp(tt.bracket, entries, tt.matches); got != tt.want {
+			t.Errorf("%s: runner-up %q, want %q", name, got, tt.want)
+		}
+	}
+}
@@ -7,21 +7,26 @@ import (
 	"fmt"
 	"math/big"
 	"math/bits"
+	mathrand "math/rand/v2"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 
+	"github.com/krtffl/torro/internal/catalog"
 	"github.com/krtffl/torro/internal/domain"
 	"github.com/krtffl/torro/internal/logger"
+	"github.com/krtffl/torro/internal/tournament"
 )
 
 // Phase 2 - The knockout.
 //
-// This file is the HTTP surface for the single-elimination bracket that
-// sits on top of Phase 1's open season. It is a deliberately different
+// This file is the HTTP surface for the Phase 2 bracket (single or double
+// elimination, Swiss, or groups then knockout; see domain.BracketFormat*)
+// that sits on top of Phase 1's open season. It is a deliberately different
 // mechanic (see internal/domain/bracket.go): one vote per user per match,
 // a round is resolved by tally rather than an ELO nudge, and none of this
 // ever touches Torro.Rating. Do not reuse this file's helpers from the
@@ -43,6 +48,8 @@ type BracketTorroView struct {
 type BracketMatchView struct {
 	Id        string
 	Round     int
+	Side      string // domain.BracketSide*
+	GroupName string // group-side matches only
 	Slot      int
 	Status    string
 	IsBye     bool
@@ -59,24 +66,70 @@ type BracketMatchView struct {
 	OnConfirmedPath bool
 }
 
-// BracketRoundView groups matches by round for the overview page.
+// BracketRoundView groups matches by round for the overview page. Round
+// counts within the round's side of the bracket.
 type BracketRoundView struct {
 	Round     int
+	Label     string // "Ronda 2", "Gran Final", ...
 	IsFinal   bool
 	IsCurrent bool
 	Matches   []BracketMatchView
 }
 
-// BracketOverviewContent holds data for the bracket overview page.
+// BracketStandingView is one line of a Swiss bracket's standings.
+type BracketStandingView struct {
+	Rank     int
+	Torro    BracketTorroView
+	Score    int
+	Wins     int
+	Losses   int
+	Byes     int
+	Buchholz int
+}
+
+// BracketGroupStandingView is one line of a group table. Qualifies marks
+// the places that go through to the knockout.
+type BracketGroupStandingView struct {
+	Rank      int
+	Torro     BracketTorroView
+	Played    int
+	Wins      int
+	Losses    int
+	Qualifies bool
+}
+
+// BracketGroupView is one group of a groups-then-knockout bracket: its
+// table and its matches.
+type BracketGroupView struct {
+	Name    string
+	Table   []BracketGroupStandingView
+	Matches []BracketMatchView
+}
+
+// BracketOverviewContent holds data for the bracket overview page. Rounds
+// is the winners side, the whole tree of a single-elimination bracket and
+// every round of a Swiss one; a double-elimination one also fills in
+// LosersRounds and GrandFinal (the grand final and its reset, if played),
+// a Swiss one its Standings, and a groups-then-knockout one its Groups,
+// Rounds being the knockout that follows them.
 type BracketOverviewContent struct {
-	HX            bool
-	ClassId       string
-	ClassName     string
-	BracketExists bool
-	Bracket       *domain.Bracket
-	Rounds        []BracketRoundView
-	Champion      *BracketTorroView
-	TotalRounds   int
+	HX                bool
+	ClassId           string
+	ClassName         string
+	BracketExists     bool
+	Bracket           *domain.Bracket
+	DoubleElimination bool
+	Swiss             bool
+	GroupsKnockout    bool
+	GroupStageCurrent bool
+	Predictions       bool // the bracket takes pick'em predictions
+	Rounds            []BracketRoundView
+	LosersRounds      []BracketRoundView
+	GrandFinal        []BracketRoundView
+	Standings         []BracketStandingView
+	Groups            []BracketGroupView
+	Champion          *BracketTorroView
+	TotalRounds       int
 }
 
 // BracketVoteContent holds data for the bracket voting card.
@@ -88,6 +141,11 @@ type BracketVoteContent struct {
 	ChampionName  string
 	NoMoreMatches bool
 	Match         *BracketMatchView
+	Countdown     *BracketCountdownView // nil for a round with no deadline
+	// OpensAt is set while round 1 hasn't opened yet, Predictions when
+	// the bracket takes pick'em predictions meanwhile.
+	OpensAt     string
+	Predictions bool
 }
 
 // bracketOverview handles GET /bracket/{classId}: current round, every
@@ -98,20 +156,10 @@ func (h *Handler) bracketOverview(w http.ResponseWriter, r *http.Request) {
 	classId := chi.URLParam(r, "classId")
 	ctx := r.Context()
 
-	className := classId
-	if classes, err := h.classRepo.List(ctx); err == nil {
-		for _, c := range classes {
-			if c.Id == classId {
-				className = c.Name
-				break
-			}
-		}
-	}
-
 	content := BracketOverviewContent{
 		HX:        isHX(r),
 		ClassId:   classId,
-		ClassName: className,
+		ClassName: h.bracketClassName(ctx, classId),
 	}
 
 	bracket, err := h.bracketRepo.GetLatestByClass(ctx, classId)
@@ -122,7 +170,22 @@ func (h *Handler) bracketOverview(w http.ResponseWriter, r *http.Request) {
 	}
 	content.BracketExists = true
 	content.Bracket = bracket
+	content.DoubleElimination = bracket.IsDoubleElimination()
 	content.TotalRounds = bits.Len(uint(bracket.Size)) - 1
+	content.Swiss = bracket.IsSwiss()
+	content.GroupsKnockout = bracket.IsGroupsKnockout()
+	content.Predictions = takesPredictions(bracket)
+	lives := 1
+	switch {
+	case content.DoubleElimination:
+		content.TotalRounds = tournament.DoubleEliminationRounds(bracket.Size)
+		lives = 2
+	case content.Swiss:
+		content.TotalRounds = bracket.Rounds
+	case content.GroupsKnockout:
+		content.TotalRounds += bracket.GroupStageRounds()
+		content.GroupStageCurrent = bracket.CurrentRound <= bracket.GroupStageRounds()
+	}
 
 	entries, err := h.bracketRepo.ListEntries(ctx, bracket.Id)
 	if err != nil {
@@ -140,10 +203,47 @@ func (h *Handler) bracketOverview(w http.ResponseWriter, r *http.Request) {
 	}
 
 	getTorro := h.torroFetcher(ctx)
-	alive := confirmedPathWinners(matches)
+	var groups []*domain.BracketGroup
+	groupNames := make(map[string]string)
+	if content.GroupsKnockout {
+		groups, err = h.bracketRepo.ListGroups(ctx, bracket.Id)
+		if err != nil {
+			logger.Error("[Handler - BracketOverview] Couldn't list groups for bracket %s. %v", bracket.Id, err)
+			render.Render(w, r, domain.ErrInternal(err))
+			return
+		}
+		for _, g := range groups {
+			groupNames[g.Id] = g.Name
+		}
+	}
 
-	roundsByNumber := make(map[int][]BracketMatchView)
-	maxRound := 0
+	// Group matches knock no one out, so only the knockout's count
+	// towards the confirmed path.
+	var knockoutMatches []*domain.BracketMatch
+	for _, m := range matches {
+		if m.Side != domain.BracketSideGroup {
+			knockoutMatches = append(knockoutMatches, m)
+		}
+	}
+
+	alive := confirmedPathWinners(knockoutMatches, lives)
+	if content.Swiss {
+		// Nobody is knocked out of a Swiss bracket, so there is no thread
+		// to follow until the standings name a champion.
+		alive = map[string]bool{}
+	}
+	if bracket.ChampionId != nil {
+		// Once there is a champion, theirs is the only thread left, even
+		// for a double-elimination runner-up who only lost once.
+		alive = map[string]bool{*bracket.ChampionId: true}
+	}
+
+	groupMatches := make(map[string][]BracketMatchView)
+	sides := map[string]map[int][]BracketMatchView{
+		domain.BracketSideWinners:    {},
+		domain.BracketSideLosers:     {},
+		domain.BracketSideGrandFinal: {},
+	}
 	for _, m := range matches {
 		view, err := buildMatchView(m, seedByTorro, getTorro)
 		if err != nil {
@@ -158,19 +258,55 @@ func (h *Handler) bracketOverview(w http.ResponseWriter, r *http.Request) {
 			}
 			view.OnConfirmedPath = alive[winnerId]
 		}
-		roundsByNumber[m.Round] = append(roundsByNumber[m.Round], view)
-		if m.Round > maxRound {
-			maxRound = m.Round
+
+		if m.GroupId != nil {
+			view.GroupName = groupNames[*m.GroupId]
+			groupMatches[*m.GroupId] = append(groupMatches[*m.GroupId], view)
+			continue
 		}
+
+		round := m.Round - bracket.GroupStageRounds()
+		if content.DoubleElimination {
+			round = tournament.SideRound(bracket.Size, m)
+		}
+		if sides[view.Side] == nil {
+			logger.Warn("[Handler - BracketOverview] Match %s is on unknown side %q", m.Id, view.Side)
+			continue
+		}
+		sides[view.Side][round] = append(sides[view.Side][round], view)
 	}
 
-	for round := 1; round <= maxRound; round++ {
-		content.Rounds = append(content.Rounds, BracketRoundView{
-			Round:     round,
-			IsFinal:   round == content.TotalRounds,
-			IsCurrent: round == bracket.CurrentRound && bracket.Status == domain.BracketStatusInProgress,
-			Matches:   roundsByNumber[round],
-		})
+	content.Rounds = bracketRoundViews(bracket, domain.BracketSideWinners, sides[domain.BracketSideWinners])
+	content.LosersRounds = bracketRoundViews(bracket, domain.BracketSideLosers, sides[domain.BracketSideLosers])
+	content.GrandFinal = bracketRoundViews(bracket, domain.BracketSideGrandFinal, sides[domain.BracketSideGrandFinal])
+
+	if content.GroupsKnockout {
+		content.Groups, err = h.groupViews(bracket, groups, entries, matches, groupMatches, getTorro)
+		if err != nil {
+			logger.Error("[Handler - BracketOverview] Couldn't build group tables for bracket %s. %v", bracket.Id, err)
+			render.Render(w, r, domain.ErrInternal(err))
+			return
+		}
+	}
+
+	if content.Swiss {
+		for _, st := range tournament.SwissStandings(entries, matches) {
+			torro, err := getTorro(st.TorroId)
+			if err != nil {
+				logger.Error("[Handler - BracketOverview] Couldn't get torro %s. %v", st.TorroId, err)
+				render.Render(w, r, domain.ErrInternal(err))
+				return
+			}
+			content.Standings = append(content.Standings, BracketStandingView{
+				Rank:     st.Rank,
+				Torro:    BracketTorroView{Id: torro.Id, Name: torro.Name, Image: torro.Image, Seed: st.Seed},
+				Score:    st.Score,
+				Wins:     st.Wins,
+				Losses:   st.Losses,
+				Byes:     st.Byes,
+				Buchholz: st.Buchholz,
+			})
+		}
 	}
 
 	if bracket.ChampionId != nil {
@@ -187,6 +323,19 @@ func (h *Handler) bracketOverview(w http.ResponseWriter, r *http.Request) {
 	h.renderBracketOverview(w, r, content)
 }
 
+// bracketClassName returns a class's name for a bracket page, its id when
+// the class can't be found.
+func (h *Handler) bracketClassName(ctx context.Context, classId string) string {
+	if classes, err := h.classRepo.List(ctx); err == nil {
+		for _, c := range classes {
+			if c.Id == classId {
+				return c.Name
+			}
+		}
+	}
+	return classId
+}
+
 func (h *Handler) renderBracketOverview(w http.ResponseWriter, r *http.Request, content BracketOverviewContent) {
 	buf := h.bpool.Get()
 	defer h.bpool.Put(buf)
@@ -248,6 +397,24 @@ func (h *Handler) serveBracketVoteCard(w http.ResponseWriter, r *http.Request, c
 		return
 	}
 
+	now := time.Now().UTC()
+	waiting, opensAt, err := h.roundWaiting(ctx, bracket, now)
+	if err != nil {
+		logger.Error("[Handler - BracketVote] Couldn't check the opening of bracket %s. %v", bracket.Id, err)
+		render.Render(w, r, domain.ErrInternal(err))
+		return
+	}
+	if waiting {
+		content.OpensAt = bracketTimeLabel(opensAt)
+		content.Predictions = takesPredictions(bracket)
+		h.renderBracketVotePage(w, r, content)
+		return
+	}
+
+	if round, err := h.bracketRepo.GetRound(ctx, bracket.Id, bracket.CurrentRound); err == nil {
+		content.Countdown = newBracketCountdown(round, now)
+	}
+
 	openMatches, err := h.bracketRepo.ListOpenMatchesForUser(ctx, bracket.Id, bracket.CurrentRound, userId)
 	if err != nil {
 		logger.Error("[Handler - BracketVote] Couldn't list open matches for bracket %s. %v", bracket.Id, err)
@@ -282,6 +449,16 @@ func (h *Handler) serveBracketVoteCard(w http.ResponseWriter, r *http.Request, c
 		return
 	}
 
+	if match.GroupId != nil {
+		if groups, err := h.bracketRepo.ListGroups(ctx, bracket.Id); err == nil {
+			for _, g := range groups {
+				if g.Id == *match.GroupId {
+					view.GroupName = g.Name
+				}
+			}
+		}
+	}
+
 	content.Match = &view
 	h.renderBracketVotePage(w, r, content)
 }
@@ -363,6 +540,41 @@ func (h *Handler) bracketMatchVote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// A groups-then-knockout bracket creates every matchday pending up
+	// front; only the current one is open, behind its opening and deadline.
+	if match.Round != bracket.CurrentRound {
+		render.Render(w, r, domain.ErrBadRequest(
+			fmt.Errorf("%s: this match isn't open for voting yet", domain.ValidationError)))
+		return
+	}
+
+	now := time.Now().UTC()
+	waiting, opensAt, err := h.roundWaiting(ctx, bracket, now)
+	if err != nil {
+		logger.Error("[Handler - BracketMatchVote] Couldn't check the opening of bracket %s. %v", bracket.Id, err)
+		render.Render(w, r, domain.ErrInternal(err))
+		return
+	}
+	if waiting {
+		render.Render(w, r, domain.ErrBadRequest(
+			fmt.Errorf("%s: voting opens at %s", domain.ValidationError, opensAt.Format(time.RFC3339))))
+		return
+	}
+
+	// Past its deadline a round only waits for the scheduler to close it;
+	// a vote landing in between would be counted after the fact.
+	closed, err := h.roundClosed(ctx, bracket, now)
+	if err != nil {
+		logger.Error("[Handler - BracketMatchVote] Couldn't check the deadline of bracket %s. %v", bracket.Id, err)
+		render.Render(w, r, domain.ErrInternal(err))
+		return
+	}
+	if closed {
+		render.Render(w, r, domain.ErrBadRequest(
+			fmt.Errorf("%s: voting on round %d has closed", domain.ValidationError, bracket.CurrentRound)))
+		return
+	}
+
 	_, err = h.bracketRepo.CreateVoteTx(tx, ctx, &domain.BracketMatchVote{
 		MatchId:  matchId,
 		UserId:   userId,
@@ -387,9 +599,10 @@ func (h *Handler) bracketMatchVote(w http.ResponseWriter, r *http.Request) {
 	// can't mean 100% turnout the way Phase 1's min-vote thresholds do.
 	// We treat a round as fully voted once every match in it has received
 	// at least one vote (byes don't count, they're already decided). This
-	// keeps the knockout moving without a cron job, at the cost of a
-	// single slow/ignored match blocking the whole round - that's exactly
-	// what the explicit force-advance endpoint is for.
+	// keeps the knockout moving between deadlines, at the cost of a single
+	// slow/ignored match blocking the whole round - that's what a round's
+	// deadline (see runBracketScheduler) and the explicit force-advance
+	// endpoint are for.
 	if err := h.checkAndAdvanceIfRoundFullyVoted(tx, ctx, bracket); err != nil {
 		logger.Error("[Handler - BracketMatchVote] Couldn't check/advance round for bracket %s. %v", bracket.Id, err)
 		render.Render(w, r, domain.ErrInternal(err))
@@ -401,6 +614,7 @@ func (h *Handler) bracketMatchVote(w http.ResponseWriter, r *http.Request) {
 		render.Render(w, r, domain.ErrInternal(err))
 		return
 	}
+	h.startGranFinalAfter(ctx, bracket)
 
 	h.serveBracketVoteCard(w, r, bracket.ClassId, userId)
 }
@@ -431,25 +645,33 @@ func (h *Handler) checkAndAdvanceIfRoundFullyVoted(tx *sql.Tx, ctx context.Conte
 		}
 	}
 
-	entries, err := h.bracketRepo.ListEntriesTx(tx, ctx, bracket.Id)
-	if err != nil {
+	previousRound := bracket.CurrentRound
+	if err := h.resolveAndCascadeTx(tx, ctx, bracket); err != nil {
 		return err
 	}
-	seedByTorro := seedMap(entries)
 
-	if err := h.resolvePendingMatchesInRound(tx, ctx, bracket, seedByTorro); err != nil {
+	logger.Info("[Handler - BracketMatchVote] Round %d of bracket %s fully voted, now at round %d (status=%s)",
+		previousRound, bracket.Id, bracket.CurrentRound, bracket.Status)
+
+	return nil
+}
+
+// resolveAndCascadeTx closes the bracket's current round whatever its
+// votes: every still-pending match goes to decideMatchWinner, then the
+// bracket cascades on. It is how a round ends, whether by its last vote,
+// an admin's force-advance or its deadline (see closeRound).
+func (h *Handler) resolveAndCascadeTx(tx *sql.Tx, ctx context.Context, bracket *domain.Bracket) error {
+	entries, err := h.bracketRepo.ListEntriesTx(tx, ctx, bracket.Id)
+	if err != nil {
 		return err
 	}
 
-	previousRound := bracket.CurrentRound
-	if _, err := h.cascadeAdvance(tx, ctx, bracket); err != nil {
+	if err := h.resolvePendingMatchesInRound(tx, ctx, bracket, seedMap(entries)); err != nil {
 		return err
 	}
 
-	logger.Info("[Handler - BracketMatchVote] Round %d of bracket %s fully voted, now at round %d (status=%s)",
-		previousRound, bracket.Id, bracket.CurrentRound, bracket.Status)
-
-	return nil
+	_, err = h.cascadeAdvance(tx, ctx, bracket)
+	return err
 }
 
 // resolvePendingMatchesInRound tallies votes for every still-pending match
@@ -490,7 +712,26 @@ func (h *Handler) resolvePendingMatchesInRound(tx *sql.Tx, ctx context.Context,
 // far fewer torrons than the requested bracket size), stopping either when
 // a round has at least one match that genuinely needs votes, or when the
 // bracket is completed. Returns whether the bracket was completed.
+//
+// Double-elimination and Swiss brackets are planned by the tournament
+// package instead, and a groups-then-knockout bracket plays out its group
+// stage first; see cascadeAdvanceDoubleElimination, cascadeAdvanceSwiss and
+// cascadeAdvanceGroups.
 func (h *Handler) cascadeAdvance(tx *sql.Tx, ctx context.Context, bracket *domain.Bracket) (bool, error) {
+	switch {
+	case bracket.IsDoubleElimination():
+		return h.cascadeAdvanceDoubleElimination(tx, ctx, bracket)
+	case bracket.IsSwiss():
+		return h.cascadeAdvanceSwiss(tx, ctx, bracket)
+	case bracket.IsGroupsKnockout():
+		return h.cascadeAdvanceGroups(tx, ctx, bracket)
+	}
+	return h.cascadeAdvanceKnockout(tx, ctx, bracket)
+}
+
+// cascadeAdvanceKnockout is cascadeAdvance for a single-elimination tree,
+// which in a groups-then-knockout bracket starts after the group stage.
+func (h *Handler) cascadeAdvanceKnockout(tx *sql.Tx, ctx context.Context, bracket *domain.Bracket) (bool, error) {
 	for {
 		roundMatches, err := h.bracketRepo.ListMatchesByRoundTx(tx, ctx, bracket.Id, bracket.CurrentRound)
 		if err != nil {
@@ -505,7 +746,7 @@ func (h *Handler) cascadeAdvance(tx *sql.Tx, ctx context.Context, bracket *domai
 		}
 
 		// Number of matches this round would have if every seed showed up.
-		slotCount := bracket.Size >> uint(bracket.CurrentRound)
+		slotCount := bracket.Size >> uint(bracket.CurrentRound-bracket.GroupStageRounds())
 		if slotCount <= 1 {
 			// This round IS the Gran Final.
 			if len(roundMatches) != 1 || roundMatches[0].WinnerId == nil {
@@ -587,25 +828,402 @@ func (h *Handler) cascadeAdvance(tx *sql.Tx, ctx context.Context, bracket *domai
 	}
 }
 
-// bracketCreate handles POST /bracket/{classId}/create?size={n}.
+// cascadeAdvanceDoubleElimination is cascadeAdvance for a double-elimination
+// bracket: once every match of the current round is decided, it plans and
+// creates the next round on both sides, cascading through rounds of byes,
+// until a round needs votes or the grand final has a champion.
+func (h *Handler) cascadeAdvanceDoubleElimination(tx *sql.Tx, ctx context.Context, bracket *domain.Bracket) (bool, error) {
+	for {
+		matches, err := h.bracketRepo.ListMatchesTx(tx, ctx, bracket.Id)
+		if err != nil {
+			return false, err
+		}
+
+		for _, m := range matches {
+			if m.Round == bracket.CurrentRound && m.Status == domain.BracketMatchStatusPending {
+				return false, nil
+			}
+		}
+
+		next, championId, err := tournament.NextDoubleElimination(bracket, matches)
+		if err != nil {
+			return false, err
+		}
+
+		if championId != "" {
+			if err := h.bracketRepo.CompleteTx(tx, ctx, bracket.Id, championId); err != nil {
+				return false, err
+			}
+			bracket.Status = domain.BracketStatusCompleted
+			bracket.ChampionId = &championId
+			return true, nil
+		}
+
+		createdPending, err := h.createMatchesTx(tx, ctx, next)
+		if err != nil {
+			return false, err
+		}
+
+		nextRound := bracket.CurrentRound + 1
+		if err := h.bracketRepo.UpdateRoundTx(tx, ctx, bracket.Id, nextRound); err != nil {
+			return false, err
+		}
+		bracket.CurrentRound = nextRound
+
+		if createdPending {
+			return false, nil
+		}
+	}
+}
+
+// cascadeAdvanceSwiss is cascadeAdvance for a Swiss bracket: once every
+// match of the current round is decided, it pairs the next round from the
+// standings, or, after the last round, crowns the top of the standings.
+func (h *Handler) cascadeAdvanceSwiss(tx *sql.Tx, ctx context.Context, bracket *domain.Bracket) (bool, error) {
+	for {
+		matches, err := h.bracketRepo.ListMatchesTx(tx, ctx, bracket.Id)
+		if err != nil {
+			return false, err
+		}
+
+		for _, m := range matches {
+			if m.Round == bracket.CurrentRound && m.Status == domain.BracketMatchStatusPending {
+				return false, nil
+			}
+		}
+
+		entries, err := h.bracketRepo.ListEntriesTx(tx, ctx, bracket.Id)
+		if err != nil {
+			return false, err
+		}
+
+		if bracket.CurrentRound >= bracket.Rounds {
+			standings := tournament.SwissStandings(entries, matches)
+			if len(standings) == 0 {
+				return false, fmt.Errorf("%s: bracket %s has no entries", domain.ValidationError, bracket.Id)
+			}
+			championId := standings[0].TorroId
+			if err := h.bracketRepo.CompleteTx(tx, ctx, bracket.Id, championId); err != nil {
+				return false, err
+			}
+			bracket.Status = domain.BracketStatusCompleted
+			bracket.ChampionId = &championId
+			return true, nil
+		}
+
+		nextRound := bracket.CurrentRound + 1
+		createdPending, err := h.createMatchesTx(tx, ctx,
+			tournament.PairSwissRound(bracket.Id, nextRound, entries, matches))
+		if err != nil {
+			return false, err
+		}
+
+		if err := h.bracketRepo.UpdateRoundTx(tx, ctx, bracket.Id, nextRound); err != nil {
+			return false, err
+		}
+		bracket.CurrentRound = nextRound
+
+		if createdPending {
+			return false, nil
+		}
+	}
+}
+
+// cascadeAdvanceGroups is cascadeAdvance for a groups-then-knockout
+// bracket. Every group match was created up front, so through the group
+// stage a decided round just moves on to the next matchday; after the last
+// one, the group tables draw the knockout's first round and the bracket
+// carries on as a single-elimination tree.
+func (h *Handler) cascadeAdvanceGroups(tx *sql.Tx, ctx context.Context, bracket *domain.Bracket) (bool, error) {
+	groupRounds := bracket.GroupStageRounds()
+	for bracket.CurrentRound <= groupRounds {
+		roundMatches, err := h.bracketRepo.ListMatchesByRoundTx(tx, ctx, bracket.Id, bracket.CurrentRound)
+		if err != nil {
+			return false, err
+		}
+		for _, m := range roundMatches {
+			if m.Status == domain.BracketMatchStatusPending {
+				return false, nil
+			}
+		}
+
+		if bracket.CurrentRound == groupRounds {
+			if err := h.createKnockoutFromGroups(tx, ctx, bracket); err != nil {
+				return false, err
+			}
+		}
+
+		nextRound := bracket.CurrentRound + 1
+		if err := h.bracketRepo.UpdateRoundTx(tx, ctx, bracket.Id, nextRound); err != nil {
+			return false, err
+		}
+		bracket.CurrentRound = nextRound
+	}
+
+	return h.cascadeAdvanceKnockout(tx, ctx, bracket)
+}
+
+// createKnockoutFromGroups ranks every group, then lays the qualifiers out
+// as the first round of the knockout tree, played straight after the
+// group stage: group winners as the top seeds, and group mates kept apart
+// where a swap allows it.
+func (h *Handler) createKnockoutFromGroups(tx *sql.Tx, ctx context.Context, bracket *domain.Bracket) error {
+	groups, err := h.bracketRepo.ListGroupsTx(tx, ctx, bracket.Id)
+	if err != nil {
+		return err
+	}
+	entries, err := h.bracketRepo.ListEntriesTx(tx, ctx, bracket.Id)
+	if err != nil {
+		return err
+	}
+	matches, err := h.bracketRepo.ListMatchesTx(tx, ctx, bracket.Id)
+	if err != nil {
+		return err
+	}
+
+	groupOf := make(map[string]string, len(entries))
+	tables := make([][]*tournament.GroupStanding, 0, len(groups))
+	for _, g := range groups {
+		members, groupMatches := groupMembersAndMatches(g.Id, entries, matches)
+		for _, e := range members {
+			groupOf[e.TorronId] = g.Id
+		}
+		tables = append(tables, tournament.GroupStandings(members, groupMatches))
+	}
+
+	advance := bracket.Size / max(len(groups), 1)
+	field := tournament.KnockoutField(tables, advance)
+	if len(field) != bracket.Size {
+		return fmt.Errorf("%s: bracket %s sends %d torrons through to a knockout of %d",
+			domain.ValidationError, bracket.Id, len(field), bracket.Size)
+	}
+
+	order := standardSeedOrder(bracket.Size)
+	pairs := make([][2]string, bracket.Size/2)
+	for slot := range pairs {
+		pairs[slot] = [2]string{field[order[2*slot]-1], field[order[2*slot+1]-1]}
+	}
+	tournament.SeparateGroupMates(pairs, groupOf)
+
+	round := bracket.GroupStageRounds() + 1
+	next := make([]*domain.BracketMatch, 0, len(pairs))
+	for slot, pair := range pairs {
+		torro2Id := pair[1]
+		next = append(next, &domain.BracketMatch{
+			BracketId: bracket.Id,
+			Round:     round,
+			Side:      domain.BracketSideWinners,
+			Slot:      slot,
+			Torro1Id:  pair[0],
+			Torro2Id:  &torro2Id,
+			Status:    domain.BracketMatchStatusPending,
+		})
+	}
+	_, err = h.createMatchesTx(tx, ctx, next)
+	return err
+}
+
+// groupMembersAndMatches picks one group's entries and matches out of a
+// bracket's.
+func groupMembersAndMatches(groupId string, entries []*domain.BracketEntry, matches []*domain.BracketMatch) ([]*domain.BracketEntry, []*domain.BracketMatch) {
+	var members []*domain.BracketEntry
+	for _, e := range entries {
+		if e.GroupId != nil && *e.GroupId == groupId {
+			members = append(members, e)
+		}
+	}
+	var groupMatches []*domain.BracketMatch
+	for _, m := range matches {
+		if m.GroupId != nil && *m.GroupId == groupId {
+			groupMatches = append(groupMatches, m)
+		}
+	}
+	return members, groupMatches
+}
+
+// groupViews builds the group tables of the overview page, each with its
+// matches in the order they are played.
+func (h *Handler) groupViews(bracket *domain.Bracket, groups []*domain.BracketGroup, entries []*domain.BracketEntry,
+	matches []*domain.BracketMatch, groupMatches map[string][]BracketMatchView, getTorro func(string) (*domain.Torro, error)) ([]BracketGroupView, error) {
+	advance := bracket.Size / max(len(groups), 1)
+
+	views := make([]BracketGroupView, 0, len(groups))
+	for _, g := range groups {
+		members, played := groupMembersAndMatches(g.Id, entries, matches)
+		view := BracketGroupView{Name: g.Name, Matches: groupMatches[g.Id]}
+		for _, st := range tournament.GroupStandings(members, played) {
+			torro, err := getTorro(st.TorroId)
+			if err != nil {
+				return nil, err
+			}
+			view.Table = append(view.Table, BracketGroupStandingView{
+				Rank:      st.Rank,
+				Torro:     BracketTorroView{Id: torro.Id, Name: torro.Name, Image: torro.Image, Seed: st.Seed},
+				Played:    st.Played,
+				Wins:      st.Wins,
+				Losses:    st.Losses,
+				Qualifies: st.Rank <= advance,
+			})
+		}
+		views = append(views, view)
+	}
+	return views, nil
+}
+
+// createMatchesTx stores a planned round's matches and reports whether any
+// of them still needs votes.
+func (h *Handler) createMatchesTx(tx *sql.Tx, ctx context.Context, matches []*domain.BracketMatch) (bool, error) {
+	pending := false
+	for _, match := range matches {
+		if _, err := h.bracketRepo.CreateMatchTx(tx, ctx, match); err != nil {
+			return false, err
+		}
+		if match.Status == domain.BracketMatchStatusPending {
+			pending = true
+		}
+	}
+	return pending, nil
+}
+
+// bracketOptions are the admin's choices for a new bracket.
+type bracketOptions struct {
+	Size            int
+	Format          string // domain.BracketFormat*, empty for single elimination
+	GrandFinalReset bool
+	Rounds          int // Swiss only; 0 for tournament.DefaultSwissRounds
+	Groups          int // groups then knockout only, like GroupSize and Advance
+	GroupSize       int
+	Advance         int
+	Opens           time.Time     // round 1's opening; zero to open it right away
+	Closes          time.Time     // last round's deadline; zero for no schedule
+	Every           time.Duration // each scheduled round's length
+	Seeding         domain.BracketSeeding
+}
+
+// Group stage defaults: four groups of four, the top two of each going
+// through to a knockout of eight.
+const (
+	defaultBracketGroups    = 4
+	defaultBracketGroupSize = 4
+	defaultBracketAdvance   = 2
+)
+
+// bracketCreate handles
+// POST /bracket/{classId}/create?size={n}&format={format}&reset={bool}&rounds={n}
+// &groups={n}&group_size={n}&advance={n}&opens={time}&closes={time}&every={duration}.
+// format is single_elimination (the default), double_elimination, swiss or
+// groups_knockout; reset (default true) only applies to double elimination,
+// rounds to Swiss, and groups, group_size and advance to groups then
+// knockout, whose size follows from them. A Swiss bracket's size caps its
+// field rather than padding it with byes, and defaults to the whole class.
+// closes (RFC 3339) gives the bracket deadlines: its rounds are scheduled
+// back to back, every long (default 24h), the last one closing at closes,
+// and the scheduler closes each one at its deadline. opens (RFC 3339, with
+// closes) holds round 1 back until then, leaving time for pick'em
+// predictions (see bracketPredict). The optional JSON body picks the
+// seeding (see decodeBracketSeeding and seedField): its strategy - rating,
+// campaign_rating, strength, random or manual - with its campaign_id,
+// random_seed or seeds, and the torrons to exclude. The bracket records
+// it, filled in, under seeding.
+//
 // Gated by Handler.RequireAdminToken - see its route registration in server.go.
 func (h *Handler) bracketCreate(w http.ResponseWriter, r *http.Request) {
 	logger.Info("[Handler - BracketCreate] Incoming request")
 
 	classId := chi.URLParam(r, "classId")
-
-	size := domain.DefaultBracketSize
-	if sizeParam := r.URL.Query().Get("size"); sizeParam != "" {
+	query := r.URL.Query()
+
+	opts := bracketOptions{
+		Size:            domain.DefaultBracketSize,
+		Format:          query.Get("format"),
+		GrandFinalReset: true,
+	}
+	switch opts.Format {
+	case domain.BracketFormatSwiss:
+		opts.Size = domain.MaxBracketSize
+	case domain.BracketFormatGroupsKnockout:
+		opts.Size = 0
+		opts.Groups = defaultBracketGroups
+		opts.GroupSize = defaultBracketGroupSize
+		opts.Advance = defaultBracketAdvance
+	}
+	if sizeParam := query.Get("size"); sizeParam != "" {
 		parsed, err := strconv.Atoi(sizeParam)
 		if err != nil {
 			render.Render(w, r, domain.ErrBadRequest(
 				fmt.Errorf("%s: size must be an integer", domain.ValidationError)))
 			return
 		}
-		size = parsed
+		opts.Size = parsed
 	}
+	if closesParam := query.Get("closes"); closesParam != "" {
+		parsed, err := time.Parse(time.RFC3339, closesParam)
+		if err != nil {
+			render.Render(w, r, domain.ErrBadRequest(
+				fmt.Errorf("%s: closes must be an RFC 3339 time", domain.ValidationError)))
+			return
+		}
+		opts.Closes = parsed
+		opts.Every = defaultBracketRoundLength
+	}
+	if opensParam := query.Get("opens"); opensParam != "" {
+		parsed, err := time.Parse(time.RFC3339, opensParam)
+		if err != nil || opts.Closes.IsZero() {
+			render.Render(w, r, domain.ErrBadRequest(
+				fmt.Errorf("%s: opens must be an RFC 3339 time, and comes with closes", domain.ValidationError)))
+			return
+		}
+		opts.Opens = parsed
+	}
+	if everyParam := query.Get("every"); everyParam != "" {
+		parsed, err := time.ParseDuration(everyParam)
+		if err != nil || opts.Closes.IsZero() {
+			render.Render(w, r, domain.ErrBadRequest(
+				fmt.Errorf("%s: every must be a duration such as 24h, and comes with closes", domain.ValidationError)))
+			return
+		}
+		opts.Every = parsed
+	}
+	if resetParam := query.Get("reset"); resetParam != "" {
+		parsed, err := strconv.ParseBool(resetParam)
+		if err != nil {
+			render.Render(w, r, domain.ErrBadRequest(
+				fmt.Errorf("%s: reset must be true or false", domain.ValidationError)))
+			return
+		}
+		opts.GrandFinalReset = parsed
+	}
+	for _, p := range []struct {
+		param  string
+		target *int
+	}{
+		{"rounds", &opts.Rounds},
+		{"groups", &opts.Groups},
+		{"group_size", &opts.GroupSize},
+		{"advance", &opts.Advance},
+	} {
+		param, target := p.param, p.target
+		value := query.Get(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			render.Render(w, r, domain.ErrBadRequest(
+				fmt.Errorf("%s: %s must be an integer", domain.ValidationError, param)))
+			return
+		}
+		*target = parsed
+	}
+
+	seeding, err := decodeBracketSeeding(w, r)
+	if err != nil {
+		render.Render(w, r, domain.ErrBadRequest(err))
+		return
+	}
+	opts.Seeding = seeding
 
-	bracket, err := h.seedAndCreateBracket(r.Context(), classId, size)
+	bracket, err := h.seedAndCreateBracket(r.Context(), classId, opts)
 	if err != nil {
 		logger.Error("[Handler - BracketCreate] Couldn't create bracket for class %s. %v", classId, err)
 		renderBracketError(w, r, err)
@@ -616,14 +1234,54 @@ func (h *Handler) bracketCreate(w http.ResponseWriter, r *http.Request) {
 	render.JSON(w, r, bracket)
 }
 
-// seedAndCreateBracket seeds the top-N torrons of a class by Phase 1
-// rating (N = min(size, active torrons in class)) and generates round-1
+// seedAndCreateBracket seeds N torrons of a class by opts.Seeding (N =
+// min(size, active torrons seeded), see seedField) and generates round-1
 // matches using standard single-elimination seeding (1v8, 4v5, 2v7, 3v6
 // for a field of 8, generalized to any power-of-two size). If N isn't a
-// power of two, the missing top seeds are byes that auto-advance.
-func (h *Handler) seedAndCreateBracket(ctx context.Context, classId string, size int) (*domain.Bracket, error) {
-	if !isPowerOfTwo(size) {
-		return nil, fmt.Errorf("%s: bracket size must be a power of two (got %d)", domain.ValidationError, size)
+// power of two, the missing top seeds are byes that auto-advance. A
+// double-elimination bracket's round 1 is the same: its winners side. A
+// Swiss bracket takes any N, and its round 1 is paired by
+// tournament.PairSwissRound. A groups-then-knockout bracket seeds
+// groups x group size torrons, draws them into groups and creates every
+// group match up front (see drawGroupsTx).
+func (h *Handler) seedAndCreateBracket(ctx context.Context, classId string, opts bracketOptions) (*domain.Bracket, error) {
+	size := opts.Size
+	format := opts.Format
+	if format == "" {
+		format = domain.BracketFormatSingleElimination
+	}
+	if format != domain.BracketFormatGroupsKnockout && (opts.Groups != 0 || opts.GroupSize != 0 || opts.Advance != 0) {
+		return nil, fmt.Errorf("%s: groups, group_size and advance only apply to a groups-then-knockout bracket", domain.ValidationError)
+	}
+	switch format {
+	case domain.BracketFormatSingleElimination, domain.BracketFormatDoubleElimination:
+		if !isPowerOfTwo(size) {
+			return nil, fmt.Errorf("%s: bracket size must be a power of two (got %d)", domain.ValidationError, size)
+		}
+		if opts.Rounds != 0 {
+			return nil, fmt.Errorf("%s: rounds only applies to a Swiss bracket", domain.ValidationError)
+		}
+	case domain.BracketFormatSwiss:
+	case domain.BracketFormatGroupsKnockout:
+		if size != 0 || opts.Rounds != 0 {
+			return nil, fmt.Errorf("%s: a groups-then-knockout bracket takes groups, group_size and advance instead of size and rounds",
+				domain.ValidationError)
+		}
+		if opts.Groups < 2 || opts.GroupSize < 2 || opts.GroupSize > tournament.MaxGroupSize {
+			return nil, fmt.Errorf("%s: a groups-then-knockout bracket needs at least 2 groups of 2 to %d torrons (got %d groups of %d)",
+				domain.ValidationError, tournament.MaxGroupSize, opts.Groups, opts.GroupSize)
+		}
+		if opts.Advance < 1 || opts.Advance >= opts.GroupSize {
+			return nil, fmt.Errorf("%s: between 1 and %d torrons per group can go through (got %d)",
+				domain.ValidationError, opts.GroupSize-1, opts.Advance)
+		}
+		if !isPowerOfTwo(opts.Groups * opts.Advance) {
+			return nil, fmt.Errorf("%s: groups x advance must make a power-of-two knockout (got %d x %d)",
+				domain.ValidationError, opts.Groups, opts.Advance)
+		}
+		size = opts.Groups * opts.GroupSize
+	default:
+		return nil, fmt.Errorf("%s: unknown bracket format %q", domain.ValidationError, format)
 	}
 	// isPowerOfTwo(1) is true (2^0), but a single-slot bracket has no matches
 	// to play - reject it with its own clear message rather than letting it
@@ -636,17 +1294,30 @@ func (h *Handler) seedAndCreateBracket(ctx context.Context, classId string, size
 			domain.ValidationError, domain.MaxBracketSize, size)
 	}
 
+	if format == domain.BracketFormatDoubleElimination && size < tournament.MinDoubleEliminationSize {
+		return nil, fmt.Errorf("%s: a double-elimination bracket needs a size of at least %d (got %d)",
+			domain.ValidationError, tournament.MinDoubleEliminationSize, size)
+	}
+
 	campaign, err := h.campaignRepo.GetActive(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("%s: no active campaign to attach a bracket to", domain.ValidationError)
 	}
 
+	// The Global class has no torrons of its own: its bracket is the Gran
+	// Final, built from the class champions (see createGranFinalIfReady).
+	if classId == catalog.GlobalClassId {
+		return nil, fmt.Errorf("%s: the Global class's bracket is the Gran Final, created once every class bracket completes",
+			domain.ValidationError)
+	}
+
 	if existing, err := h.bracketRepo.GetByCampaignAndClass(ctx, campaign.Id, classId); err == nil && existing != nil {
 		return nil, fmt.Errorf(
 			"%s: a bracket already exists for class %s in the active campaign", domain.ValidationError, classId)
 	}
 
-	topTorrons, err := h.torroRepo.TopNByClass(ctx, classId, size)
+	seeding := opts.Seeding
+	topTorrons, seedRatings, err := h.seedField(ctx, classId, campaign, &seeding, size)
 	if err != nil {
 		return nil, err
 	}
@@ -655,37 +1326,113 @@ func (h *Handler) seedAndCreateBracket(ctx context.Context, classId string, size
 			"%s: class %s needs at least 2 active torrons to start a bracket", domain.ValidationError, classId)
 	}
 
+	rounds := 0
+	if format == domain.BracketFormatGroupsKnockout {
+		// Every group must still have someone to knock out when the last
+		// pot comes up short.
+		if len(topTorrons) < opts.Groups*(opts.Advance+1) {
+			return nil, fmt.Errorf("%s: %d groups sending %d through need at least %d active torrons (class %s has %d)",
+				domain.ValidationError, opts.Groups, opts.Advance, opts.Groups*(opts.Advance+1), classId, len(topTorrons))
+		}
+		size = opts.Groups * opts.Advance
+		rounds = tournament.GroupStageMatchdays(len(topTorrons), opts.Groups)
+	}
+	if format == domain.BracketFormatSwiss {
+		// A Swiss bracket is exactly as big as its field.
+		size = len(topTorrons)
+		if size < tournament.MinSwissSize {
+			return nil, fmt.Errorf("%s: a Swiss bracket needs at least %d active torrons (class %s has %d)",
+				domain.ValidationError, tournament.MinSwissSize, classId, size)
+		}
+		rounds = opts.Rounds
+		if rounds == 0 {
+			rounds = tournament.DefaultSwissRounds(size)
+		}
+		if rounds < 1 || rounds > tournament.MaxSwissRounds(size) {
+			return nil, fmt.Errorf("%s: a Swiss bracket of %d torrons plays between 1 and %d rounds (got %d)",
+				domain.ValidationError, size, tournament.MaxSwissRounds(size), rounds)
+		}
+	}
+
+	return h.createSeededBracket(ctx, &domain.Bracket{
+		CampaignId:      campaign.Id,
+		ClassId:         classId,
+		Kind:            domain.BracketKindClass,
+		Size:            size,
+		Format:          format,
+		GrandFinalReset: opts.GrandFinalReset,
+		Rounds:          rounds,
+		CurrentRound:    1,
+		Seeding:         seeding,
+	}, opts, topTorrons, seedRatings)
+}
+
+// createSeededBracket stores bracket with topTorrons as its entries, seeded
+// 1..N in order with their seedRatings, its round schedule and its first
+// matches (see seedAndCreateBracket), all in one transaction.
+func (h *Handler) createSeededBracket(ctx context.Context, bracket *domain.Bracket, opts bracketOptions,
+	topTorrons []*domain.Torro, seedRatings map[string]float64) (*domain.Bracket, error) {
 	tx, err := h.db.Begin()
 	if err != nil {
 		return nil, err
 	}
 	defer tx.Rollback()
 
-	bracket := &domain.Bracket{
-		CampaignId:   campaign.Id,
-		ClassId:      classId,
-		Size:         size,
-		CurrentRound: 1,
-	}
 	bracket, err = h.bracketRepo.CreateTx(tx, ctx, bracket)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := h.createRoundScheduleTx(tx, ctx, bracket, opts, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+
+	seeded := make([]*domain.BracketEntry, 0, len(topTorrons))
 	for i, t := range topTorrons {
-		_, err := h.bracketRepo.CreateEntryTx(tx, ctx, &domain.BracketEntry{
+		seeded = append(seeded, &domain.BracketEntry{
 			BracketId:  bracket.Id,
 			TorronId:   t.Id,
 			Seed:       i + 1,
-			SeedRating: t.Rating,
+			SeedRating: seedRatings[t.Id],
 		})
+	}
+
+	// Groups are drawn before the entries are stored, so each entry goes in
+	// with its group.
+	var groups []*domain.BracketGroup
+	var drawn [][]*domain.BracketEntry
+	if bracket.IsGroupsKnockout() {
+		groups, drawn, err = h.drawGroupsTx(tx, ctx, bracket, seeded, opts.Groups)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	if err := h.createRoundOneMatches(tx, ctx, bracket, topTorrons); err != nil {
-		return nil, err
+	entries := make([]*domain.BracketEntry, 0, len(seeded))
+	for _, e := range seeded {
+		entry, err := h.bracketRepo.CreateEntryTx(tx, ctx, e)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	switch {
+	case bracket.IsSwiss():
+		if _, err := h.createMatchesTx(tx, ctx, tournament.PairSwissRound(bracket.Id, 1, entries, nil)); err != nil {
+			return nil, err
+		}
+	case bracket.IsGroupsKnockout():
+		// Every group match is known from the draw, so the whole group
+		// stage is created up front.
+		matches, _ := tournament.GroupStageMatches(bracket.Id, groups, drawn)
+		if _, err := h.createMatchesTx(tx, ctx, matches); err != nil {
+			return nil, err
+		}
+	default:
+		if err := h.createRoundOneMatches(tx, ctx, bracket, topTorrons); err != nil {
+			return nil, err
+		}
 	}
 
 	// A tiny/sparse field can resolve entirely via byes before a single
@@ -701,6 +1448,33 @@ func (h *Handler) seedAndCreateBracket(ctx context.Context, classId string, size
 	return bracket, nil
 }
 
+// drawGroupsTx creates a groups-then-knockout bracket's groups and draws
+// its seeded entries into them, pot by pot, setting each entry's GroupId.
+// It returns the groups and their members in group order.
+func (h *Handler) drawGroupsTx(tx *sql.Tx, ctx context.Context, bracket *domain.Bracket,
+	seeded []*domain.BracketEntry, groupCount int) ([]*domain.BracketGroup, [][]*domain.BracketEntry, error) {
+	drawn := tournament.DrawGroups(seeded, groupCount, mathrand.Shuffle)
+
+	groups := make([]*domain.BracketGroup, 0, groupCount)
+	for position, members := range drawn {
+		group, err := h.bracketRepo.CreateGroupTx(tx, ctx, &domain.BracketGroup{
+			BracketId: bracket.Id,
+			Position:  position,
+			Name:      tournament.GroupName(position),
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		groups = append(groups, group)
+
+		for _, e := range members {
+			groupId := group.Id
+			e.GroupId = &groupId
+		}
+	}
+	return groups, drawn, nil
+}
+
 // createRoundOneMatches lays out round 1 using the standard bracket seed
 // order. topTorrons must already be sorted by seed (1..N, best first).
 func (h *Handler) createRoundOneMatches(tx *sql.Tx, ctx context.Context, bracket *domain.Bracket, topTorrons []*domain.Torro) error {
@@ -723,6 +1497,7 @@ func (h *Handler) createRoundOneMatches(tx *sql.Tx, ctx context.Context, bracket
 		match := &domain.BracketMatch{
 			BracketId: bracket.Id,
 			Round:     1,
+			Side:      domain.BracketSideWinners,
 			Slot:      slot,
 		}
 
@@ -785,22 +1560,8 @@ func (h *Handler) bracketAdvance(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	entries, err := h.bracketRepo.ListEntriesTx(tx, ctx, bracket.Id)
-	if err != nil {
-		logger.Error("[Handler - BracketAdvance] Couldn't list entries for bracket %s. %v", bracket.Id, err)
-		render.Render(w, r, domain.ErrInternal(err))
-		return
-	}
-	seedByTorro := seedMap(entries)
-
-	if err := h.resolvePendingMatchesInRound(tx, ctx, bracket, seedByTorro); err != nil {
-		logger.Error("[Handler - BracketAdvance] Couldn't resolve round %d. %v", bracket.CurrentRound, err)
-		render.Render(w, r, domain.ErrInternal(err))
-		return
-	}
-
-	if _, err := h.cascadeAdvance(tx, ctx, bracket); err != nil {
-		logger.Error("[Handler - BracketAdvance] Couldn't advance bracket %s. %v", bracket.Id, err)
+	if err := h.resolveAndCascadeTx(tx, ctx, bracket); err != nil {
+		logger.Error("[Handler - BracketAdvance] Couldn't advance bracket %s from round %d. %v", bracket.Id, bracket.CurrentRound, err)
 		render.Render(w, r, domain.ErrInternal(err))
 		return
 	}
@@ -810,6 +1571,7 @@ func (h *Handler) bracketAdvance(w http.ResponseWriter, r *http.Request) {
 		render.Render(w, r, domain.ErrInternal(err))
 		return
 	}
+	h.startGranFinalAfter(ctx, bracket)
 
 	render.Status(r, http.StatusOK)
 	render.JSON(w, r, bracket)
@@ -841,7 +1603,7 @@ func isPowerOfTwo(n int) bool {
 }
 
 // torroBySeed returns the torró at the given 1-indexed seed within a
-// seed-ordered slice (as returned by TorroRepo.TopNByClass), and whether
+// seed-ordered slice (as returned by Handler.seedField), and whether
 // that seed actually exists (it may not, if the class has fewer active
 // torrons than the requested bracket size).
 func torroBySeed(seedOrdered []*domain.Torro, seed int) (*domain.Torro, bool) {
@@ -868,26 +1630,22 @@ func totalVotes(votes map[string]int) int {
 }
 
 // confirmedPathWinners returns the set of torró IDs still alive in this
-// bracket - winners of every match they've played so far. A decided match
-// sits on the "confirmed" desktop-tree path exactly when its own winner is
-// in this set; the moment a torró loses a later match, every earlier match
-// that produced them reverts to neutral, since their thread is cut.
-func confirmedPathWinners(matches []*domain.BracketMatch) map[string]bool {
-	eliminated := make(map[string]bool)
+// bracket - winners who haven't yet lost lives matches (1 in single
+// elimination, 2 in double). A decided match sits on the "confirmed"
+// desktop-tree path exactly when its own winner is in this set; the moment
+// a torró is knocked out, every earlier match that produced them reverts to
+// neutral, since their thread is cut.
+func confirmedPathWinners(matches []*domain.BracketMatch, lives int) map[string]bool {
+	defeats := make(map[string]int)
 	for _, m := range matches {
-		if m.WinnerId == nil || m.Torro2Id == nil {
-			continue // undecided, or a bye with no loser
+		if loser := m.LoserId(); loser != nil {
+			defeats[*loser]++
 		}
-		loser := m.Torro1Id
-		if *m.WinnerId == m.Torro1Id {
-			loser = *m.Torro2Id
-		}
-		eliminated[loser] = true
 	}
 
 	alive := make(map[string]bool)
 	for _, m := range matches {
-		if m.WinnerId == nil || eliminated[*m.WinnerId] {
+		if m.WinnerId == nil || defeats[*m.WinnerId] >= lives {
 			continue
 		}
 		alive[*m.WinnerId] = true
@@ -895,6 +1653,51 @@ func confirmedPathWinners(matches []*domain.BracketMatch) map[string]bool {
 	return alive
 }
 
+// bracketRoundViews orders one side's matches, keyed by their round within
+// the side, into labelled rounds.
+func bracketRoundViews(bracket *domain.Bracket, side string, byRound map[int][]BracketMatchView) []BracketRoundView {
+	maxRound := 0
+	for round := range byRound {
+		maxRound = max(maxRound, round)
+	}
+
+	winnersRounds := bits.Len(uint(bracket.Size)) - 1
+	double := bracket.IsDoubleElimination()
+
+	var rounds []BracketRoundView
+	for round := 1; round <= maxRound; round++ {
+		view := BracketRoundView{Round: round, Label: fmt.Sprintf("Ronda %d", round), Matches: byRound[round]}
+
+		// The bracket-wide round this side's round is played in.
+		played := round + bracket.GroupStageRounds()
+		switch {
+		case side == domain.BracketSideGrandFinal:
+			played = tournament.DoubleEliminationRounds(bracket.Size) + round - 1
+			view.IsFinal = true
+			view.Label = "Gran Final"
+			if round > 1 {
+				view.Label = "Gran Final - desempat"
+			}
+		case bracket.IsSwiss():
+			// Every Swiss round is just a round; the standings decide.
+		case side == domain.BracketSideLosers:
+			played = round + 1
+			if round == tournament.LosersRounds(bracket.Size) {
+				view.Label = "Final de perdedors"
+			}
+		case round == winnersRounds && double:
+			view.Label = "Final de guanyadors"
+		case round == winnersRounds:
+			view.IsFinal = true
+			view.Label = "Gran Final"
+		}
+		view.IsCurrent = played == bracket.CurrentRound && bracket.Status == domain.BracketStatusInProgress
+
+		rounds = append(rounds, view)
+	}
+	return rounds
+}
+
 // decideMatchWinner tallies a match's votes and returns the winning
 // torró's ID. A tie (including 0-0, which happens when force-advancing an
 // untouched match) is broken by the lower original seed number - i.e. the
@@ -939,9 +1742,15 @@ func buildMatchView(m *domain.BracketMatch, seedByTorro map[string]int, getTorro
 		return BracketMatchView{}, err
 	}
 
+	side := m.Side
+	if side == "" {
+		side = domain.BracketSideWinners
+	}
+
 	view := BracketMatchView{
 		Id:     m.Id,
 		Round:  m.Round,
+		Side:   side,
 		Slot:   m.Slot,
 		Status: m.Status,
 		IsBye:  m.IsBye(),
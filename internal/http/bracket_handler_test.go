@@ -0,0 +1,215 @@
+package http
+
+import (
+	"html/template"
+	"strings"
+	"testing"
+
+	torrons "github.com/krtffl/torro"
+	"github.com/krtffl/torro/internal/domain"
+)
+
+func TestBracketRoundViewsLabels(t *testing.T) {
+	byRound := func(rounds int) map[int][]BracketMatchView {
+		m := make(map[int][]BracketMatchView)
+		for r := 1; r <= rounds; r++ {
+			m[r] = []BracketMatchView{{Round: r}}
+		}
+		return m
+	}
+	labels := func(views []BracketRoundView) string {
+		var out []string
+		for _, v := range views {
+			out = append(out, v.Label)
+		}
+		return strings.Join(out, " / ")
+	}
+
+	single := &domain.Bracket{Size: 8, CurrentRound: 2, Status: domain.BracketStatusInProgress}
+	views := bracketRoundViews(single, domain.BracketSideWinners, byRound(3))
+	if got, want := labels(views), "Ronda 1 / Ronda 2 / Gran Final"; got != want {
+		t.Errorf("single elimination labels = %q, want %q", got, want)
+	}
+	if !views[1].IsCurrent || views[2].IsCurrent || !views[2].IsFinal {
+		t.Errorf("single elimination current/final flags wrong: %+v", views)
+	}
+
+	double := &domain.Bracket{
+		Size:         8,
+		Format:       domain.BracketFormatDoubleElimination,
+		CurrentRound: 3,
+		Status:       domain.BracketStatusInProgress,
+	}
+	if got, want := labels(bracketRoundViews(double, domain.BracketSideWinners, byRound(3))), "Ronda 1 / Ronda 2 / Final de guanyadors"; got != want {
+		t.Errorf("winners side labels = %q, want %q", got, want)
+	}
+	losers := bracketRoundViews(double, domain.BracketSideLosers, byRound(4))
+	if got, want := labels(losers), "Ronda 1 / Ronda 2 / Ronda 3 / Final de perdedors"; got != want {
+		t.Errorf("losers side labels = %q, want %q", got, want)
+	}
+	// Losers round 2 is played alongside winners round 3.
+	if !losers[1].IsCurrent {
+		t.Errorf("losers round 2 should be current in bracket round 3")
+	}
+	if got, want := labels(bracketRoundViews(double, domain.BracketSideGrandFinal, byRound(2))), "Gran Final / Gran Final - desempat"; got != want {
+		t.Errorf("grand final labels = %q, want %q", got, want)
+	}
+}
+
+func TestConfirmedPathWinnersLives(t *testing.T) {
+	decided := func(a, b, winner string) *domain.BracketMatch {
+		return &domain.BracketMatch{Torro1Id: a, Torro2Id: &b, WinnerId: &winner, Status: domain.BracketMatchStatusCompleted}
+	}
+	matches := []*domain.BracketMatch{
+		decided("1", "4", "1"),
+		decided("2", "3", "3"),
+		decided("1", "3", "1"),
+		decided("4", "2", "2"), // losers side: 2 survives its first defeat
+	}
+
+	if alive := confirmedPathWinners(matches, 1); !alive["1"] || alive["2"] || alive["3"] {
+		t.Errorf("single elimination alive = %v, want only 1", alive)
+	}
+	if alive := confirmedPathWinners(matches, 2); !alive["1"] || !alive["2"] || !alive["3"] {
+		t.Errorf("double elimination alive = %v, want 1, 2 and 3", alive)
+	}
+}
+
+func TestBracketTemplateDoubleElimination(t *testing.T) {
+	tmpls, err := template.New("").Funcs(templateFuncs).ParseFS(torrons.Public, "public/templates/*.html")
+	if err != nil {
+		t.Fatalf("failed to parse templates: %v", err)
+	}
+
+	match := BracketMatchView{
+		Id:     "m",
+		Torro1: BracketTorroView{Id: "1", Name: "Jijona", Seed: 1},
+		Torro2: &BracketTorroView{Id: "2", Name: "Alacant", Seed: 2},
+	}
+	content := BracketOverviewContent{
+		HX:                true,
+		ClassId:           "c",
+		ClassName:         "Clàssics",
+		BracketExists:     true,
+		DoubleElimination: true,
+		Bracket: &domain.Bracket{
+			Size:            4,
+			Format:          domain.BracketFormatDoubleElimination,
+			GrandFinalReset: true,
+			CurrentRound:    4,
+			Status:          domain.BracketStatusInProgress,
+		},
+		Rounds:       []BracketRoundView{{Round: 1, Label: "Ronda 1", Matches: []BracketMatchView{match}}},
+		LosersRounds: []BracketRoundView{{Round: 1, Label: "Final de perdedors", Matches: []BracketMatchView{match}}},
+		GrandFinal:   []BracketRoundView{{Round: 1, Label: "Gran Final", IsFinal: true, IsCurrent: true, Matches: []BracketMatchView{match}}},
+		TotalRounds:  4,
+	}
+
+	var sb strings.Builder
+	if err := tmpls.ExecuteTemplate(&sb, "bracket.html", content); err != nil {
+		t.Fatalf("failed to render bracket.html: %v", err)
+	}
+	out := sb.String()
+	for _, want := range []string{"Quadre de guanyadors", "Quadre de perdedors", "Final de perdedors", "es juga un desempat"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("bracket.html is missing %q", want)
+		}
+	}
+
+	// A single-elimination bracket renders its one tree only.
+	content.DoubleElimination = false
+	content.LosersRounds, content.GrandFinal = nil, nil
+	sb.Reset()
+	if err := tmpls.ExecuteTemplate(&sb, "bracket.html", content); err != nil {
+		t.Fatalf("failed to render bracket.html: %v", err)
+	}
+	if strings.Contains(sb.String(), "Quadre de perdedors") {
+		t.Error("single-elimination bracket.html renders a losers side")
+	}
+}
+
+func TestBracketTemplateSwissStandings(t *testing.T) {
+	tmpls, err := template.New("").Funcs(templateFuncs).ParseFS(torrons.Public, "public/templates/*.html")
+	if err != nil {
+		t.Fatalf("failed to parse templates: %v", err)
+	}
+
+	content := BracketOverviewContent{
+		HX:            true,
+		ClassId:       "c",
+		ClassName:     "Clàssics",
+		BracketExists: true,
+		Swiss:         true,
+		Bracket: &domain.Bracket{
+			Size:         5,
+			Format:       domain.BracketFormatSwiss,
+			Rounds:       3,
+			CurrentRound: 2,
+			Status:       domain.BracketStatusInProgress,
+		},
+		Rounds: bracketRoundViews(&domain.Bracket{Size: 5, Format: domain.BracketFormatSwiss, CurrentRound: 2},
+			domain.BracketSideWinners, map[int][]BracketMatchView{1: nil, 2: nil}),
+		Standings: []BracketStandingView{
+			{Rank: 1, Torro: BracketTorroView{Id: "1", Name: "Jijona", Seed: 1}, Score: 2, Wins: 1, Byes: 1, Buchholz: 1},
+			{Rank: 2, Torro: BracketTorroView{Id: "2", Name: "Alacant", Seed: 2}, Score: 1, Wins: 1, Losses: 1, Buchholz: 2},
+		},
+		TotalRounds: 3,
+	}
+
+	var sb strings.Builder
+	if err := tmpls.ExecuteTemplate(&sb, "bracket.html", content); err != nil {
+		t.Fatalf("failed to render bracket.html: %v", err)
+	}
+	out := sb.String()
+	for _, want := range []string{"Classificació", "Jijona", "1 lliure", "BH 2", "Ronda 2 de 3"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("bracket.html is missing %q", want)
+		}
+	}
+	if strings.Contains(out, "Gran Final") {
+		t.Error("a Swiss bracket has no Gran Final round")
+	}
+}
+
+func TestBracketTemplateGroupTables(t *testing.T) {
+	tmpls, err := template.New("").Funcs(templateFuncs).ParseFS(torrons.Public, "public/templates/*.html")
+	if err != nil {
+		t.Fatalf("failed to parse templates: %v", err)
+	}
+
+	bracket := &domain.Bracket{
+		Size:         4,
+		Format:       domain.BracketFormatGroupsKnockout,
+		Rounds:       3,
+		CurrentRound: 4,
+		Status:       domain.BracketStatusInProgress,
+	}
+	content := BracketOverviewContent{
+		HX:             true,
+		ClassId:        "c",
+		ClassName:      "Clàssics",
+		BracketExists:  true,
+		GroupsKnockout: true,
+		Bracket:        bracket,
+		Groups: []BracketGroupView{{
+			Name: "A",
+			Table: []BracketGroupStandingView{
+				{Rank: 1, Torro: BracketTorroView{Id: "1", Name: "Jijona", Seed: 1}, Played: 3, Wins: 3, Qualifies: true},
+				{Rank: 2, Torro: BracketTorroView{Id: "5", Name: "Alacant", Seed: 5}, Played: 3, Wins: 1, Losses: 2},
+			},
+		}},
+		Rounds:      bracketRoundViews(bracket, domain.BracketSideWinners, map[int][]BracketMatchView{1: nil}),
+		TotalRounds: 5,
+	}
+
+	var sb strings.Builder
+	if err := tmpls.ExecuteTemplate(&sb, "bracket.html", content); err != nil {
+		t.Fatalf("failed to render bracket.html: %v", err)
+	}
+	out := sb.String()
+	for _, want := range []string{"Fase de grups", "Grup A", "is-qualified", "3 J &middot; 3V", "Fase eliminatòria", "ronda actual", "Ronda 4 de 5"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("bracket.html is missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,458 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"math/bits"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+
+	"github.com/krtffl/torro/internal/domain"
+	"github.com/krtffl/torro/internal/logger"
+	"github.com/krtffl/torro/internal/tournament"
+)
+
+// Bracket pick'em: a user fills in a single-elimination bracket's whole
+// tree before its round 1 opens (see domain.BracketPrediction), and is
+// scored as the real matches are decided.
+
+// predictionLeaderboardSize caps a prediction leaderboard.
+const predictionLeaderboardSize = 100
+
+// BracketPredictionSlotView is one match of the prediction form: the
+// torrons that can win it and the one the prediction picked. Decided and
+// Correct follow the real match once it is played.
+type BracketPredictionSlotView struct {
+	Field    string // form field, "pick-{round}-{slot}"
+	Label    string
+	Options  []BracketTorroView
+	PickedId string
+	Picked   *BracketTorroView
+	Decided  bool
+	Correct  bool
+}
+
+// BracketPredictionRoundView is one round of the prediction form, with
+// what a correct pick in it is worth.
+type BracketPredictionRoundView struct {
+	Round  int
+	Label  string
+	Points int
+	Slots  []BracketPredictionSlotView
+}
+
+// BracketPredictContent holds data for the prediction page. Open is true
+// while predictions are taken, until LocksAt; afterwards the page shows the
+// user's prediction and its Score, nil when they made none.
+type BracketPredictContent struct {
+	HX            bool
+	ClassId       string
+	ClassName     string
+	BracketExists bool
+	Unsupported   bool // not a single-elimination bracket
+	Open          bool
+	LocksAt       string
+	Saved         bool
+	Rounds        []BracketPredictionRoundView
+	Champion      *BracketTorroView
+	Score         *domain.BracketPredictionScore
+}
+
+// BracketPredictionEntryView is one line of a prediction leaderboard.
+// Predictors are anonymous: only the viewer's own line is told apart.
+type BracketPredictionEntryView struct {
+	Rank         int
+	IsYou        bool
+	ChampionName string
+	Points       int
+	Correct      int
+	Decided      int
+	Accuracy     int
+}
+
+// BracketCircleView is one of the viewer's friend circles, as the
+// leaderboard's switcher names it. Circles have no names of their own.
+type BracketCircleView struct {
+	Id    string
+	Label string
+}
+
+// BracketPredictionsContent holds data for a prediction leaderboard,
+// global or, with CircleId, scoped to one of the viewer's friend circles.
+type BracketPredictionsContent struct {
+	HX            bool
+	ClassId       string
+	ClassName     string
+	BracketExists bool
+	Unsupported   bool
+	CircleId      string
+	NotMember     bool
+	Circles       []BracketCircleView
+	Entries       []BracketPredictionEntryView
+}
+
+// takesPredictions reports whether a bracket has a tree to predict: only
+// single elimination does, a bracket with no Format included.
+func takesPredictions(bracket *domain.Bracket) bool {
+	return bracket.Format == "" || bracket.Format == domain.BracketFormatSingleElimination
+}
+
+// bracketPredict handles GET /bracket/{classId}/predict: the prediction
+// form while predictions are open, the user's prediction and its score
+// once they are locked.
+func (h *Handler) bracketPredict(w http.ResponseWriter, r *http.Request) {
+	logger.Info("[Handler - BracketPredict] Incoming request")
+
+	classId := chi.URLParam(r, "classId")
+	userId := GetUserIDFromContext(r.Context())
+	if userId == "" {
+		logger.Error("[Handler - BracketPredict] No user ID in context")
+		render.Render(w, r, domain.ErrInternal(fmt.Errorf("%s: missing user context", domain.ValidationError)))
+		return
+	}
+
+	content, _, _, err := h.bracketPredictContent(r.Context(), classId, userId, time.Now().UTC())
+	if err != nil {
+		logger.Error("[Handler - BracketPredict] Couldn't load the prediction for class %s. %v", classId, err)
+		render.Render(w, r, domain.ErrInternal(err))
+		return
+	}
+	content.HX = isHX(r)
+	h.renderBracketPredict(w, r, content)
+}
+
+// bracketPredictSubmit handles POST /bracket/{classId}/predict: stores the
+// user's prediction (form values "pick-{round}-{slot}", a torró id each),
+// replacing the one they made before, as long as round 1 hasn't opened.
+// Picks for matches with a single possible winner can be left out.
+func (h *Handler) bracketPredictSubmit(w http.ResponseWriter, r *http.Request) {
+	logger.Info("[Handler - BracketPredictSubmit] Incoming request")
+
+	classId := chi.URLParam(r, "classId")
+	userId := GetUserIDFromContext(r.Context())
+	if userId == "" {
+		logger.Warn("[Handler - BracketPredictSubmit] No user ID in context")
+		render.Render(w, r, domain.ErrBadRequest(fmt.Errorf("%s: missing user context", domain.ValidationError)))
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		render.Render(w, r, domain.ErrBadRequest(
+			fmt.Errorf("%s: couldn't read the prediction. %v", domain.ValidationError, err)))
+		return
+	}
+	picks, err := parsePredictionPicks(r.PostForm)
+	if err != nil {
+		render.Render(w, r, domain.ErrBadRequest(err))
+		return
+	}
+
+	ctx := r.Context()
+	content, bracket, candidates, err := h.bracketPredictContent(ctx, classId, userId, time.Now().UTC())
+	switch {
+	case err != nil:
+		logger.Error("[Handler - BracketPredictSubmit] Couldn't load the prediction for class %s. %v", classId, err)
+		render.Render(w, r, domain.ErrInternal(err))
+		return
+	case !content.BracketExists:
+		render.Render(w, r, domain.ErrNotFound(
+			fmt.Errorf("%s: there is no bracket for this class", domain.NotFoundError)))
+		return
+	case content.Unsupported:
+		render.Render(w, r, domain.ErrBadRequest(
+			fmt.Errorf("%s: pick'em predictions only apply to a single-elimination bracket", domain.ValidationError)))
+		return
+	case !content.Open:
+		render.Render(w, r, domain.ErrBadRequest(
+			fmt.Errorf("%s: predictions locked when round 1 opened", domain.ValidationError)))
+		return
+	}
+
+	checked, championId, err := tournament.CheckPrediction(candidates, picks)
+	if err != nil {
+		render.Render(w, r, domain.ErrBadRequest(err))
+		return
+	}
+
+	_, err = h.bracketPredictionRepo.Save(ctx, &domain.BracketPrediction{
+		BracketId:  bracket.Id,
+		UserId:     userId,
+		ChampionId: championId,
+	}, checked)
+	if err != nil {
+		logger.Error("[Handler - BracketPredictSubmit] Couldn't save the prediction for bracket %s. %v", bracket.Id, err)
+		renderBracketError(w, r, err)
+		return
+	}
+
+	content, _, _, err = h.bracketPredictContent(ctx, classId, userId, time.Now().UTC())
+	if err != nil {
+		logger.Error("[Handler - BracketPredictSubmit] Couldn't reload the prediction for class %s. %v", classId, err)
+		render.Render(w, r, domain.ErrInternal(err))
+		return
+	}
+	content.HX = isHX(r)
+	content.Saved = true
+	h.renderBracketPredict(w, r, content)
+}
+
+// parsePredictionPicks reads the "pick-{round}-{slot}" form values, empty
+// ones left out.
+func parsePredictionPicks(form map[string][]string) ([]*domain.BracketPredictionPick, error) {
+	var picks []*domain.BracketPredictionPick
+	for field, values := range form {
+		position, ok := strings.CutPrefix(field, "pick-")
+		if !ok || len(values) == 0 || values[0] == "" {
+			continue
+		}
+		roundParam, slotParam, _ := strings.Cut(position, "-")
+		round, roundErr := strconv.Atoi(roundParam)
+		slot, slotErr := strconv.Atoi(slotParam)
+		if roundErr != nil || slotErr != nil {
+			return nil, fmt.Errorf("%s: %s isn't a match of the bracket", domain.ValidationError, field)
+		}
+		picks = append(picks, &domain.BracketPredictionPick{Round: round, Slot: slot, TorronId: values[0]})
+	}
+	return picks, nil
+}
+
+// bracketPredictContent loads the prediction page of a class's latest
+// bracket for a user, along with the bracket and the torrons that can win
+// each of its matches (see tournament.PredictionCandidates). Predictions
+// lock when round 1 opens (see firstRoundOpensAt), so a bracket created
+// without an opening in the future never takes any.
+func (h *Handler) bracketPredictContent(ctx context.Context, classId string, userId string, now time.Time) (
+	BracketPredictContent, *domain.Bracket, [][][]string, error,
+) {
+	content := BracketPredictContent{ClassId: classId, ClassName: h.bracketClassName(ctx, classId)}
+
+	bracket, err := h.bracketRepo.GetLatestByClass(ctx, classId)
+	if err != nil {
+		// No bracket for this class yet - an empty state, like the overview.
+		return content, nil, nil, nil
+	}
+	content.BracketExists = true
+	if !takesPredictions(bracket) {
+		content.Unsupported = true
+		return content, bracket, nil, nil
+	}
+
+	locksAt, err := h.firstRoundOpensAt(ctx, bracket)
+	if err != nil {
+		return content, nil, nil, err
+	}
+	content.LocksAt = bracketTimeLabel(locksAt)
+	content.Open = now.Before(locksAt) && bracket.CurrentRound == 1 && bracket.Status == domain.BracketStatusInProgress
+
+	matches, err := h.bracketRepo.ListMatches(ctx, bracket.Id)
+	if err != nil {
+		return content, nil, nil, err
+	}
+	entries, err := h.bracketRepo.ListEntries(ctx, bracket.Id)
+	if err != nil {
+		return content, nil, nil, err
+	}
+
+	var roundOne []*domain.BracketMatch
+	played := make(map[[2]int]*domain.BracketMatch)
+	for _, m := range matches {
+		if m.Round == 1 {
+			roundOne = append(roundOne, m)
+		}
+		if m.WinnerId != nil && !m.IsBye() {
+			played[[2]int{m.Round, m.Slot}] = m
+		}
+	}
+	candidates := tournament.PredictionCandidates(bracket.Size, roundOne)
+
+	picked := make(map[[2]int]string)
+	prediction, picks, err := h.bracketPredictionRepo.GetForUser(ctx, bracket.Id, userId)
+	switch {
+	case err == nil:
+		for _, p := range picks {
+			picked[[2]int{p.Round, p.Slot}] = p.TorronId
+		}
+		content.Score, err = h.bracketPredictionRepo.ScoreForUser(ctx, bracket.Id, userId)
+		if err != nil {
+			return content, nil, nil, err
+		}
+	case !strings.Contains(err.Error(), string(domain.NotFoundError)):
+		return content, nil, nil, err
+	}
+
+	seedByTorro := seedMap(entries)
+	getTorro := h.torroFetcher(ctx)
+	torroView := func(id string) (*BracketTorroView, error) {
+		t, err := getTorro(id)
+		if err != nil {
+			return nil, err
+		}
+		return &BracketTorroView{Id: t.Id, Name: t.Name, Image: t.Image, Seed: seedByTorro[t.Id]}, nil
+	}
+
+	totalRounds := bits.Len(uint(bracket.Size)) - 1
+	for r, slots := range candidates {
+		round := r + 1
+		view := BracketPredictionRoundView{Round: round, Label: fmt.Sprintf("Ronda %d", round), Points: domain.PredictionPoints(round)}
+		if round == totalRounds {
+			view.Label = "Gran Final"
+		}
+		for slot, reach := range slots {
+			if len(reach) == 0 {
+				continue
+			}
+			slotView := BracketPredictionSlotView{
+				Field:    fmt.Sprintf("pick-%d-%d", round, slot),
+				Label:    fmt.Sprintf("%s, matx %d", view.Label, slot+1),
+				PickedId: picked[[2]int{round, slot}],
+			}
+			for _, id := range reach {
+				option, err := torroView(id)
+				if err != nil {
+					return content, nil, nil, err
+				}
+				slotView.Options = append(slotView.Options, *option)
+			}
+			if slotView.PickedId != "" {
+				if slotView.Picked, err = torroView(slotView.PickedId); err != nil {
+					return content, nil, nil, err
+				}
+				if m := played[[2]int{round, slot}]; m != nil {
+					slotView.Decided = true
+					slotView.Correct = *m.WinnerId == slotView.PickedId
+				}
+			}
+			view.Slots = append(view.Slots, slotView)
+		}
+		content.Rounds = append(content.Rounds, view)
+	}
+
+	if prediction != nil {
+		if content.Champion, err = torroView(prediction.ChampionId); err != nil {
+			return content, nil, nil, err
+		}
+	}
+
+	return content, bracket, candidates, nil
+}
+
+func (h *Handler) renderBracketPredict(w http.ResponseWriter, r *http.Request, content BracketPredictContent) {
+	buf := h.bpool.Get()
+	defer h.bpool.Put(buf)
+
+	if err := h.template.ExecuteTemplate(buf, "bracket-predict-page", content); err != nil {
+		logger.Error("[Handler - BracketPredict] Couldn't execute template. %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		if execErr := h.template.ExecuteTemplate(w, "error.html", Content{}); execErr != nil {
+			logger.Error("[Handler - BracketPredict] Failed to render error page. %v", execErr)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	buf.WriteTo(w)
+}
+
+// bracketPredictions handles GET /bracket/{classId}/predictions?circle={id}:
+// the prediction leaderboard of a class's latest bracket, across everyone
+// or, with circle, among that friend circle's members. Like
+// friendsLeaderboard, only a member gets to see a circle's.
+func (h *Handler) bracketPredictions(w http.ResponseWriter, r *http.Request) {
+	logger.Info("[Handler - BracketPredictions] Incoming request")
+
+	classId := chi.URLParam(r, "classId")
+	circleId := r.URL.Query().Get("circle")
+	ctx := r.Context()
+
+	userId := GetUserIDFromContext(ctx)
+	if userId == "" {
+		logger.Error("[Handler - BracketPredictions] No user ID in context")
+		render.Render(w, r, domain.ErrInternal(fmt.Errorf("%s: missing user context", domain.ValidationError)))
+		return
+	}
+
+	content := BracketPredictionsContent{
+		HX:        isHX(r),
+		ClassId:   classId,
+		ClassName: h.bracketClassName(ctx, classId),
+		CircleId:  circleId,
+	}
+
+	circles, err := h.friendCircleRepo.ListForUser(ctx, userId)
+	if err != nil {
+		logger.Error("[Handler - BracketPredictions] Couldn't list the circles of user %s. %v", userId, err)
+		render.Render(w, r, domain.ErrInternal(err))
+		return
+	}
+	for i, c := range circles {
+		content.Circles = append(content.Circles, BracketCircleView{Id: c.Id, Label: fmt.Sprintf("Cercle %d", i+1)})
+	}
+
+	if circleId != "" {
+		isMember, err := h.friendCircleRepo.IsMember(ctx, circleId, userId)
+		if err != nil {
+			logger.Error("[Handler - BracketPredictions] Couldn't check membership. %v", err)
+			render.Render(w, r, domain.ErrInternal(err))
+			return
+		}
+		if !isMember {
+			content.NotMember = true
+			h.renderBracketPredictions(w, r, content)
+			return
+		}
+	}
+
+	bracket, err := h.bracketRepo.GetLatestByClass(ctx, classId)
+	if err != nil {
+		h.renderBracketPredictions(w, r, content)
+		return
+	}
+	content.BracketExists = true
+	if !takesPredictions(bracket) {
+		content.Unsupported = true
+		h.renderBracketPredictions(w, r, content)
+		return
+	}
+
+	scores, err := h.bracketPredictionRepo.Leaderboard(ctx, bracket.Id, circleId, predictionLeaderboardSize)
+	if err != nil {
+		logger.Error("[Handler - BracketPredictions] Couldn't rank the predictions for bracket %s. %v", bracket.Id, err)
+		render.Render(w, r, domain.ErrInternal(err))
+		return
+	}
+	for _, s := range scores {
+		content.Entries = append(content.Entries, BracketPredictionEntryView{
+			Rank:         s.Rank,
+			IsYou:        s.UserId == userId,
+			ChampionName: s.ChampionName,
+			Points:       s.Points,
+			Correct:      s.Correct,
+			Decided:      s.Decided,
+			Accuracy:     s.Accuracy(),
+		})
+	}
+
+	h.renderBracketPredictions(w, r, content)
+}
+
+func (h *Handler) renderBracketPredictions(w http.ResponseWriter, r *http.Request, content BracketPredictionsContent) {
+	buf := h.bpool.Get()
+	defer h.bpool.Put(buf)
+
+	if err := h.template.ExecuteTemplate(buf, "bracket-predictions-page", content); err != nil {
+		logger.Error("[Handler - BracketPredictions] Couldn't execute template. %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		if execErr := h.template.ExecuteTemplate(w, "error.html", Content{}); execErr != nil {
+			logger.Error("[Handler - BracketPredictions] Failed to render error page. %v", execErr)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	buf.WriteTo(w)
+}
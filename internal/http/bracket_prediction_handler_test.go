@@ -0,0 +1,94 @@
+package http
+
+import (
+	"html/template"
+	"net/url"
+	"strings"
+	"testing"
+
+	torrons "github.com/krtffl/torro"
+	"github.com/krtffl/torro/internal/domain"
+)
+
+func TestParsePredictionPicks(t *testing.T) {
+	picks, err := parsePredictionPicks(url.Values{
+		"pick-1-0": {"a"},
+		"pick-2-1": {"b"},
+		"pick-1-3": {""},
+		"other":    {"c"},
+	})
+	if err != nil {
+		t.Fatalf("parsePredictionPicks: %v", err)
+	}
+	if len(picks) != 2 {
+		t.Fatalf("parsed %d picks, want 2 with the empty one left out", len(picks))
+	}
+	for _, p := range picks {
+		if (p.Round == 1 && (p.Slot != 0 || p.TorronId != "a")) || (p.Round == 2 && (p.Slot != 1 || p.TorronId != "b")) {
+			t.Errorf("pick = round %d, slot %d, %s", p.Round, p.Slot, p.TorronId)
+		}
+	}
+
+	if _, err := parsePredictionPicks(url.Values{"pick-final": {"a"}}); err == nil {
+		t.Error("a pick for no match was accepted")
+	}
+}
+
+func TestBracketPredictTemplates(t *testing.T) {
+	tmpls, err := template.New("").Funcs(templateFuncs).ParseFS(torrons.Public, "public/templates/*.html")
+	if err != nil {
+		t.Fatalf("failed to parse templates: %v", err)
+	}
+
+	torroA := BracketTorroView{Id: "a", Name: "Torró A", Seed: 1}
+	torroB := BracketTorroView{Id: "b", Name: "Torró B", Seed: 2}
+	open := BracketPredictContent{
+		HX: true, ClassId: "c", ClassName: "Classe", BracketExists: true, Open: true, LocksAt: "02/01/2027 12:00 UTC",
+		Rounds: []BracketPredictionRoundView{{
+			Round: 1, Label: "Gran Final", Points: 1,
+			Slots: []BracketPredictionSlotView{{Field: "pick-1-0", Label: "Gran Final, matx 1", Options: []BracketTorroView{torroA, torroB}, PickedId: "b"}},
+		}},
+	}
+	var sb strings.Builder
+	if err := tmpls.ExecuteTemplate(&sb, "bracket-predict-page", open); err != nil {
+		t.Fatalf("failed to render the prediction form: %v", err)
+	}
+	for _, want := range []string{`name="pick-1-0"`, `<option value="b" selected>`, "02/01/2027 12:00 UTC", "Desa el pronòstic"} {
+		if !strings.Contains(sb.String(), want) {
+			t.Errorf("prediction form is missing %q", want)
+		}
+	}
+
+	locked := open
+	locked.Open = false
+	locked.Champion = &torroB
+	locked.Score = &domain.BracketPredictionScore{Points: 1, Correct: 1, Decided: 1}
+	locked.Rounds[0].Slots[0].Picked = &torroB
+	locked.Rounds[0].Slots[0].Decided = true
+	locked.Rounds[0].Slots[0].Correct = true
+	sb.Reset()
+	if err := tmpls.ExecuteTemplate(&sb, "bracket-predict-page", locked); err != nil {
+		t.Fatalf("failed to render a locked prediction: %v", err)
+	}
+	if strings.Contains(sb.String(), "<select") || !strings.Contains(sb.String(), "1 de 1 encerts") {
+		t.Error("a locked prediction still shows its form, or not its score")
+	}
+
+	leaderboard := BracketPredictionsContent{
+		HX: true, ClassId: "c", ClassName: "Classe", BracketExists: true,
+		Circles: []BracketCircleView{{Id: "circle", Label: "Cercle 1"}},
+		Entries: []BracketPredictionEntryView{
+			{Rank: 1, ChampionName: "Torró A", Points: 6, Correct: 4, Decided: 5, Accuracy: 80},
+			{Rank: 2, IsYou: true, ChampionName: "Torró B", Points: 3, Correct: 3, Decided: 5, Accuracy: 60},
+		},
+	}
+	sb.Reset()
+	if err := tmpls.ExecuteTemplate(&sb, "bracket-predictions-page", leaderboard); err != nil {
+		t.Fatalf("failed to render the prediction leaderboard: %v", err)
+	}
+	for _, want := range []string{"Pronosticador anònim", "Tu", "(80%)", "?circle=circle", "Cercle 1"} {
+		if !strings.Contains(sb.String(), want) {
+			t.Errorf("prediction leaderboard is missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package http
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/bits"
+	"strings"
+	"time"
+
+	"github.com/krtffl/torro/internal/domain"
+	"github.com/krtffl/torro/internal/logger"
+	"github.com/krtffl/torro/internal/tournament"
+)
+
+// bracketSchedulerInterval is how often the scheduler looks for bracket
+// rounds past their deadline. A round closing up to a minute late is fine;
+// a vote cast in that minute is already turned away (see
+// bracketMatchVote).
+const bracketSchedulerInterval = time.Minute
+
+// defaultBracketRoundLength is how long each round of a scheduled bracket
+// lasts when the admin doesn't say: one round a day.
+const defaultBracketRoundLength = 24 * time.Hour
+
+// runBracketScheduler loops until ctx is cancelled, closing every bracket
+// round past its deadline a minute after boot and then every
+// bracketSchedulerInterval, and creating the active campaign's Gran Final
+// if its class brackets are done and it's still missing.
+func (h *Handler) runBracketScheduler(ctx context.Context) {
+	timer := time.NewTimer(time.Minute)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		runCtx, cancel := context.WithTimeout(ctx, time.Minute)
+		if n, err := h.closeOverdueRounds(runCtx, time.Now().UTC()); err != nil {
+			logger.Warn("[Bracket Scheduler] Couldn't close overdue rounds. %v", err)
+		} else if n > 0 {
+			logger.Info("[Bracket Scheduler] Closed %d overdue rounds", n)
+		}
+		// The Gran Final is created as the last class bracket completes;
+		// this catches one whose creation failed then.
+		if campaign, err := h.campaignRepo.GetActive(runCtx); err == nil {
+			if _, err := h.createGranFinalIfReady(runCtx, campaign.Id); err != nil {
+				logger.Warn("[Bracket Scheduler] Couldn't create the Gran Final of campaign %s. %v", campaign.Id, err)
+			}
+		}
+		cancel()
+
+		timer.Reset(bracketSchedulerInterval)
+	}
+}
+
+// closeOverdueRounds closes the current round of every bracket whose
+// deadline has passed by now, returning how many it closed. A bracket
+// whose next round is overdue too (the scheduler was down) catches up one
+// round per run.
+func (h *Handler) closeOverdueRounds(ctx context.Context, now time.Time) (int, error) {
+	overdue, err := h.bracketRepo.ListOverdueBrackets(ctx, now.Format(time.RFC3339))
+	if err != nil {
+		return 0, err
+	}
+
+	closed := 0
+	for _, b := range overdue {
+		ok, err := h.closeRound(ctx, b.Id, b.CurrentRound)
+		if err != nil {
+			logger.Warn("[Bracket Scheduler] Couldn't close round %d of bracket %s. %v", b.CurrentRound, b.Id, err)
+			continue
+		}
+		if ok {
+			closed++
+		}
+	}
+	return closed, nil
+}
+
+// closeRound resolves round of a bracket with decideMatchWinner and
+// cascades, like a forced advance. It reports false without touching the
+// bracket when the round was closed in the meantime, by its last vote or
+// by an admin.
+func (h *Handler) closeRound(ctx context.Context, bracketId string, round int) (bool, error) {
+	tx, err := h.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	bracket, err := h.bracketRepo.GetTx(tx, ctx, bracketId)
+	if err != nil {
+		return false, err
+	}
+	if bracket.Status != domain.BracketStatusInProgress || bracket.CurrentRound != round {
+		return false, nil
+	}
+
+	if err := h.resolveAndCascadeTx(tx, ctx, bracket); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	h.startGranFinalAfter(ctx, bracket)
+
+	logger.Info("[Bracket Scheduler] Round %d of bracket %s reached its deadline, now at round %d (status=%s)",
+		round, bracket.Id, bracket.CurrentRound, bracket.Status)
+	return true, nil
+}
+
+// roundClosed reports whether the bracket's current round is past its
+// deadline. A bracket without a schedule never closes by the clock.
+func (h *Handler) roundClosed(ctx context.Context, bracket *domain.Bracket, now time.Time) (bool, error) {
+	round, err := h.bracketRepo.GetRound(ctx, bracket.Id, bracket.CurrentRound)
+	if err != nil {
+		if strings.Contains(err.Error(), string(domain.NotFoundError)) {
+			return false, nil
+		}
+		return false, err
+	}
+	closesAt, err := time.Parse(time.RFC3339, round.ClosesAt)
+	if err != nil {
+		return false, err
+	}
+	return !now.Before(closesAt), nil
+}
+
+// scheduledRounds returns how many rounds a bracket's schedule covers:
+// every round it can play, a grand final reset included, so that the last
+// one closes at the admin's deadline even when the reset is played.
+func scheduledRounds(bracket *domain.Bracket) int {
+	switch {
+	case bracket.IsSwiss():
+		return bracket.Rounds
+	case bracket.IsDoubleElimination():
+		rounds := tournament.DoubleEliminationRounds(bracket.Size)
+		if bracket.GrandFinalReset {
+			rounds++
+		}
+		return rounds
+	}
+	return bracket.GroupStageRounds() + bits.Len(uint(bracket.Size)) - 1
+}
+
+// planRoundSchedule lays out rounds back to back so that the last one
+// closes at closes, each lasting every; round 1 opens at opens, whatever
+// its length. It fails when round 1 would close before it opens.
+func planRoundSchedule(bracketId string, opens, closes time.Time, every time.Duration, rounds int) ([]*domain.BracketRound, error) {
+	if every <= 0 {
+		return nil, fmt.Errorf("%s: every must be a positive duration (got %s)", domain.ValidationError, every)
+	}
+	if rounds < 1 {
+		return nil, nil
+	}
+
+	firstClose := closes.Add(-time.Duration(rounds-1) * every)
+	if !firstClose.After(opens) {
+		return nil, fmt.Errorf("%s: %d rounds of %s closing at %s leave round 1 closing at %s, before it opens at %s",
+			domain.ValidationError, rounds, every, closes.Format(time.RFC3339), firstClose.Format(time.RFC3339),
+			opens.Format(time.RFC3339))
+	}
+
+	schedule := make([]*domain.BracketRound, 0, rounds)
+	for round := 1; round <= rounds; round++ {
+		closesAt := firstClose.Add(time.Duration(round-1) * every)
+		schedule = append(schedule, &domain.BracketRound{
+			BracketId: bracketId,
+			Round:     round,
+			OpensAt:   opens.UTC().Format(time.RFC3339),
+			ClosesAt:  closesAt.UTC().Format(time.RFC3339),
+		})
+		opens = closesAt
+	}
+	return schedule, nil
+}
+
+// createRoundScheduleTx stores a new bracket's round schedule, if the
+// admin gave it a deadline. Round 1 opens now unless the admin set a later
+// opening.
+func (h *Handler) createRoundScheduleTx(tx *sql.Tx, ctx context.Context, bracket *domain.Bracket, opts bracketOptions, now time.Time) error {
+	if opts.Closes.IsZero() {
+		return nil
+	}
+
+	opens := now
+	if opts.Opens.After(now) {
+		opens = opts.Opens
+	}
+	schedule, err := planRoundSchedule(bracket.Id, opens, opts.Closes, opts.Every, scheduledRounds(bracket))
+	if err != nil {
+		return err
+	}
+	for _, round := range schedule {
+		if _, err := h.bracketRepo.CreateRoundTx(tx, ctx, round); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// firstRoundOpensAt returns when a bracket's round 1 opens: its scheduled
+// opening, or the bracket's creation for one without a schedule. Only
+// round 1 can be waiting to open; every later round opens as soon as the
+// one before it closes.
+func (h *Handler) firstRoundOpensAt(ctx context.Context, bracket *domain.Bracket) (time.Time, error) {
+	opensAt := bracket.CreatedAt
+	round, err := h.bracketRepo.GetRound(ctx, bracket.Id, 1)
+	switch {
+	case err == nil:
+		opensAt = round.OpensAt
+	case !strings.Contains(err.Error(), string(domain.NotFoundError)):
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339, opensAt)
+}
+
+// roundWaiting reports whether a bracket is still waiting for its round 1
+// to open.
+func (h *Handler) roundWaiting(ctx context.Context, bracket *domain.Bracket, now time.Time) (bool, time.Time, error) {
+	if bracket.CurrentRound != 1 || bracket.Status != domain.BracketStatusInProgress {
+		return false, time.Time{}, nil
+	}
+	opensAt, err := h.firstRoundOpensAt(ctx, bracket)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+	return now.Before(opensAt), opensAt, nil
+}
+
+// BracketCountdownView is the time left to vote in a bracket's current
+// round, as the vote page shows it.
+type BracketCountdownView struct {
+	Days     int
+	Hours    int
+	Minutes  int
+	ClosesAt string
+}
+
+// newBracketCountdown returns the countdown to a round's deadline, nil
+// when the round has no deadline or it has already passed.
+func newBracketCountdown(round *domain.BracketRound, now time.Time) *BracketCountdownView {
+	if round == nil {
+		return nil
+	}
+	closesAt, err := time.Parse(time.RFC3339, round.ClosesAt)
+	if err != nil {
+		return nil
+	}
+	left := closesAt.Sub(now)
+	if left <= 0 {
+		return nil
+	}
+	return &BracketCountdownView{
+		Days:     int(left.Hours() / 24),
+		Hours:    int(left.Hours()) % 24,
+		Minutes:  int(left.Minutes()) % 60,
+		ClosesAt: bracketTimeLabel(closesAt),
+	}
+}
+
+// bracketTimeLabel is how bracket pages show a deadline or an opening.
+func bracketTimeLabel(t time.Time) string {
+	return t.UTC().Format("02/01/2006 15:04") + " UTC"
+}
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"html/template"
+	"strings"
+	"testing"
+	"time"
+
+	torrons "github.com/krtffl/torro"
+	"github.com/krtffl/torro/internal/domain"
+)
+
+func TestPlanRoundScheduleEndsAtDeadline(t *testing.T) {
+	now := time.Date(2026, time.December, 30, 18, 0, 0, 0, time.UTC)
+	closes := time.Date(2027, time.January, 6, 23, 59, 0, 0, time.UTC)
+
+	schedule, err := planRoundSchedule("b", now, closes, 24*time.Hour, 3)
+	if err != nil {
+		t.Fatalf("planRoundSchedule: %v", err)
+	}
+	want := []struct{ opens, closes string }{
+		{"2026-12-30T18:00:00Z", "2027-01-04T23:59:00Z"},
+		{"2027-01-04T23:59:00Z", "2027-01-05T23:59:00Z"},
+		{"2027-01-05T23:59:00Z", "2027-01-06T23:59:00Z"},
+	}
+	if len(schedule) != len(want) {
+		t.Fatalf("schedule has %d rounds, want %d", len(schedule), len(want))
+	}
+	for i, round := range schedule {
+		if round.Round != i+1 || round.OpensAt != want[i].opens || round.ClosesAt != want[i].closes {
+			t.Errorf("round %d = %s to %s, want %s to %s", round.Round, round.OpensAt, round.ClosesAt, want[i].opens, want[i].closes)
+		}
+	}
+
+	// Nine daily rounds can't all fit before the deadline.
+	if _, err := planRoundSchedule("b", now, closes, 24*time.Hour, 9); err == nil {
+		t.Error("a schedule whose round 1 is already closed was accepted")
+	}
+	// Holding round 1 back for predictions only shortens it.
+	opens := time.Date(2027, time.January, 2, 12, 0, 0, 0, time.UTC)
+	schedule, err = planRoundSchedule("b", opens, closes, 24*time.Hour, 3)
+	if err != nil {
+		t.Fatalf("planRoundSchedule with a later opening: %v", err)
+	}
+	if schedule[0].OpensAt != "2027-01-02T12:00:00Z" || schedule[0].ClosesAt != "2027-01-04T23:59:00Z" {
+		t.Errorf("round 1 = %s to %s, want 2027-01-02T12:00:00Z to 2027-01-04T23:59:00Z", schedule[0].OpensAt, schedule[0].ClosesAt)
+	}
+	if _, err := planRoundSchedule("b", closes, closes, 24*time.Hour, 1); err == nil {
+		t.Error("a round 1 opening at its own deadline was accepted")
+	}
+	if _, err := planRoundSchedule("b", now, closes, 0, 3); err == nil {
+		t.Error("a zero round length was accepted")
+	}
+}
+
+func TestScheduledRounds(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		bracket domain.Bracket
+		want    int
+	}{
+		{"single elimination of 8", domain.Bracket{Size: 8}, 3},
+		{"double elimination of 8 with reset", domain.Bracket{Size: 8, Format: domain.BracketFormatDoubleElimination, GrandFinalReset: true}, 7},
+		{"double elimination of 8", domain.Bracket{Size: 8, Format: domain.BracketFormatDoubleElimination}, 6},
+		{"Swiss", domain.Bracket{Size: 20, Format: domain.BracketFormatSwiss, Rounds: 5}, 5},
+		{"groups then knockout of 8", domain.Bracket{Size: 8, Format: domain.BracketFormatGroupsKnockout, Rounds: 3}, 6},
+	} {
+		if got := scheduledRounds(&tt.bracket); got != tt.want {
+			t.Errorf("%s: %d rounds, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBracketVoteCountdown(t *testing.T) {
+	now := time.Date(2027, time.January, 4, 10, 30, 0, 0, time.UTC)
+	round := &domain.BracketRound{Round: 2, ClosesAt: "2027-01-05T23:59:00Z"}
+
+	countdown := newBracketCountdown(round, now)
+	if countdown == nil || countdown.Days != 1 || countdown.Hours != 13 || countdown.Minutes != 29 {
+		t.Fatalf("countdown = %+v, want 1 d 13 h 29 min", countdown)
+	}
+	if newBracketCountdown(round, now.Add(48*time.Hour)) != nil {
+		t.Error("a past deadline still counts down")
+	}
+
+	tmpls, err := template.New("").Funcs(templateFuncs).ParseFS(torrons.Public, "public/templates/*.html")
+	if err != nil {
+		t.Fatalf("failed to parse templates: %v", err)
+	}
+	var sb strings.Builder
+	content := BracketVoteContent{HX: true, ClassId: "c", BracketExists: true, NoMoreMatches: true, Countdown: countdown}
+	if err := tmpls.ExecuteTemplate(&sb, "bracket-vote-page", content); err != nil {
+		t.Fatalf("failed to render the bracket vote page: %v", err)
+	}
+	for _, want := range []string{"La ronda tanca en", "1 d 13 h 29 min", "05/01/2027 23:59 UTC"} {
+		if !strings.Contains(sb.String(), want) {
+			t.Errorf("bracket vote page is missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	mathrand "math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/krtffl/torro/internal/domain"
+	"github.com/krtffl/torro/internal/tournament"
+)
+
+// maxBracketSeedingBody bounds the JSON body of POST /bracket/{classId}/create.
+// A seed list of MaxBracketSize torró ids takes a few KB.
+const maxBracketSeedingBody = 64 << 10
+
+// decodeBracketSeeding reads the optional JSON body of
+// POST /bracket/{classId}/create, a domain.BracketSeeding such as
+// {"strategy": "manual", "seeds": [...], "exclude": [...]}. An empty body
+// seeds by global rating.
+func decodeBracketSeeding(w http.ResponseWriter, r *http.Request) (domain.BracketSeeding, error) {
+	var seeding domain.BracketSeeding
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBracketSeedingBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&seeding); err != nil && !errors.Is(err, io.EOF) {
+		return seeding, fmt.Errorf("%s: the body must be a JSON seeding (%v)", domain.ValidationError, err)
+	}
+	return seeding, nil
+}
+
+// checkBracketSeeding defaults an empty strategy to global rating and
+// rejects inputs the strategy doesn't take. FittedAt is never the
+// caller's: seedField records it.
+func checkBracketSeeding(seeding *domain.BracketSeeding) error {
+	if seeding.Strategy == "" {
+		seeding.Strategy = domain.BracketSeedingRating
+	}
+	seeding.FittedAt = ""
+
+	switch seeding.Strategy {
+	case domain.BracketSeedingRating, domain.BracketSeedingCampaignRating, domain.BracketSeedingStrength,
+		domain.BracketSeedingRandom, domain.BracketSeedingManual:
+	default:
+		return fmt.Errorf("%s: unknown seeding strategy %q", domain.ValidationError, seeding.Strategy)
+	}
+
+	if seeding.CampaignId != "" && seeding.Strategy != domain.BracketSeedingCampaignRating {
+		return fmt.Errorf("%s: campaign_id only applies to campaign_rating seeding", domain.ValidationError)
+	}
+	if seeding.RandomSeed != nil && seeding.Strategy != domain.BracketSeedingRandom {
+		return fmt.Errorf("%s: random_seed only applies to random seeding", domain.ValidationError)
+	}
+	if len(seeding.Seeds) > 0 && seeding.Strategy != domain.BracketSeedingManual {
+		return fmt.Errorf("%s: seeds only apply to manual seeding", domain.ValidationError)
+	}
+	if len(seeding.Seeds) == 0 && seeding.Strategy == domain.BracketSeedingManual {
+		return fmt.Errorf("%s: manual seeding needs a seed list", domain.ValidationError)
+	}
+	return nil
+}
+
+// seedField picks and orders the torrons a bracket of class classId seeds,
+// at most size of them, by seeding's strategy (see checkBracketSeeding):
+// the class's active torrons less the excluded ones, ranked by score, drawn
+// or listed. It fills in the inputs that make the seeding reproducible -
+// the campaign rated by when none was given (the active one), a drawn
+// random seed, the strength fit's time - and returns each seed's
+// BracketEntry.SeedRating alongside.
+func (h *Handler) seedField(ctx context.Context, classId string, campaign *domain.Campaign,
+	seeding *domain.BracketSeeding, size int) ([]*domain.Torro, map[string]float64, error) {
+	if err := checkBracketSeeding(seeding); err != nil {
+		return nil, nil, err
+	}
+	if len(seeding.Seeds) > size {
+		return nil, nil, fmt.Errorf("%s: a seed list of %d torrons doesn't fit a bracket of %d",
+			domain.ValidationError, len(seeding.Seeds), size)
+	}
+
+	torrons, err := h.torroRepo.List(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	var field []*domain.Torro
+	ratings := make(map[string]float64)
+	for _, t := range torrons {
+		if t.Class == classId && !t.Discontinued {
+			field = append(field, t)
+			ratings[t.Id] = t.Rating
+		}
+	}
+	field, err = tournament.ExcludeSeeds(field, seeding.Exclude)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	seedRatings := ratings
+	var seeded []*domain.Torro
+	switch seeding.Strategy {
+	case domain.BracketSeedingRating:
+		seeded = tournament.SeedByScore(field, ratings)
+
+	case domain.BracketSeedingCampaignRating:
+		if seeding.CampaignId == "" {
+			seeding.CampaignId = campaign.Id
+		}
+		campaignRatings, err := h.campaignRatingRepo.List(ctx, seeding.CampaignId)
+		if err != nil {
+			return nil, nil, err
+		}
+		seedRatings = make(map[string]float64)
+		for _, cr := range campaignRatings {
+			if cr.ClassId == classId {
+				seedRatings[cr.TorroId] = cr.Rating
+			}
+		}
+		if len(seedRatings) == 0 {
+			return nil, nil, fmt.Errorf("%s: campaign %s has no ratings for class %s",
+				domain.ValidationError, seeding.CampaignId, classId)
+		}
+		seeded = tournament.SeedByScore(field, seedRatings)
+
+	case domain.BracketSeedingStrength:
+		strengths, err := h.strengthRepo.List(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		seedRatings = make(map[string]float64)
+		var fittedAt time.Time
+		for _, s := range strengths {
+			if s.ClassId == classId {
+				seedRatings[s.TorroId] = s.Strength
+				if s.FittedAt.After(fittedAt) {
+					fittedAt = s.FittedAt
+				}
+			}
+		}
+		if len(seedRatings) == 0 {
+			return nil, nil, fmt.Errorf("%s: there is no strength fit for class %s yet", domain.ValidationError, classId)
+		}
+		seeding.FittedAt = fittedAt.UTC().Format(time.RFC3339)
+		seeded = tournament.SeedByScore(field, seedRatings)
+
+	case domain.BracketSeedingRandom:
+		if seeding.RandomSeed == nil {
+			seed := mathrand.Int64()
+			seeding.RandomSeed = &seed
+		}
+		seeded = tournament.SeedByDraw(field, *seeding.RandomSeed)
+
+	case domain.BracketSeedingManual:
+		seeded, err = tournament.SeedByList(field, seeding.Seeds)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if len(seeded) > size {
+		seeded = seeded[:size]
+	}
+	return seeded, seedRatings, nil
+}
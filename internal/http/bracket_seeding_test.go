@@ -0,0 +1,57 @@
+package http
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+func TestDecodeBracketSeeding(t *testing.T) {
+	decode := func(body string) (domain.BracketSeeding, error) {
+		r := httptest.NewRequest("POST", "/bracket/1/create", strings.NewReader(body))
+		return decodeBracketSeeding(httptest.NewRecorder(), r)
+	}
+
+	seeding, err := decode(`{"strategy": "random", "random_seed": 7, "exclude": ["x"]}`)
+	if err != nil {
+		t.Fatalf("decodeBracketSeeding: %v", err)
+	}
+	if seeding.Strategy != domain.BracketSeedingRandom || seeding.RandomSeed == nil || *seeding.RandomSeed != 7 ||
+		len(seeding.Exclude) != 1 {
+		t.Errorf("decoded %+v", seeding)
+	}
+
+	if seeding, err := decode(""); err != nil || seeding.Strategy != "" {
+		t.Errorf("an empty body decoded to %+v, %v", seeding, err)
+	}
+	for _, body := range []string{`{"strategy": "manual", "seed": ["a"]}`, `["a", "b"]`, `{"random_seed": "7"}`} {
+		if _, err := decode(body); err == nil {
+			t.Errorf("the body %s was accepted", body)
+		}
+	}
+}
+
+func TestCheckBracketSeeding(t *testing.T) {
+	seeding := domain.BracketSeeding{BracketSeedingInputs: domain.BracketSeedingInputs{FittedAt: "2026-12-01T00:00:00Z"}}
+	if err := checkBracketSeeding(&seeding); err != nil {
+		t.Fatalf("checkBracketSeeding: %v", err)
+	}
+	if seeding.Strategy != domain.BracketSeedingRating || seeding.FittedAt != "" {
+		t.Errorf("the default seeding is %+v, want rating with no fit time", seeding)
+	}
+
+	seed := int64(7)
+	for name, bad := range map[string]domain.BracketSeeding{
+		"an unknown strategy":       {Strategy: "elo"},
+		"a campaign to rate by":     {Strategy: domain.BracketSeedingStrength, BracketSeedingInputs: domain.BracketSeedingInputs{CampaignId: "c"}},
+		"a random seed":             {Strategy: domain.BracketSeedingManual, BracketSeedingInputs: domain.BracketSeedingInputs{Seeds: []string{"a"}, RandomSeed: &seed}},
+		"a seed list":               {Strategy: domain.BracketSeedingRandom, BracketSeedingInputs: domain.BracketSeedingInputs{Seeds: []string{"a"}}},
+		"no seed list for a manual": {Strategy: domain.BracketSeedingManual},
+	} {
+		if err := checkBracketSeeding(&bad); err == nil {
+			t.Errorf("a seeding with %s was accepted", name)
+		}
+	}
+}
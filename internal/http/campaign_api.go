@@ -7,6 +7,7 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 
+	"github.com/krtffl/torro/internal/domain"
 	"github.com/krtffl/torro/internal/logger"
 )
 
@@ -32,6 +33,10 @@ type GlobalLeaderboardEntry struct {
 	Image      string  `json:"image"`
 	Rating     float64 `json:"rating"`
 	ClassName  string  `json:"class_name"`
+
+	// Strength is the torró's batch Bradley–Terry fit with its 95%
+	// interval, omitted until the first fit has run.
+	Strength *domain.TorroStrength `json:"strength,omitempty"`
 }
 
 // handleCountdown returns countdown information for the active campaign
@@ -199,8 +204,19 @@ func (h *Handler) handleGlobalLeaderboard(w http.ResponseWriter, r *http.Request
 		entries = append(entries, entry)
 	}
 
+	// Like the public ranking pages, the fit only adds to the live
+	// ratings: without one the response still goes out.
+	strengths, err := h.loadStrengthView(r.Context())
+	if err != nil {
+		logger.Warn("[Global Leaderboard] Couldn't load torró strengths: %v", err)
+	}
+	for i := range entries {
+		entries[i].Strength = strengths.get(entries[i].TorronId)
+	}
+
 	response := map[string]interface{}{
 		"entries":       entries,
+		"leader":        strengths.leader(""),
 		"total_entries": len(entries),
 		"timestamp":     time.Now().UTC().Format(time.RFC3339),
 	}
@@ -282,10 +298,19 @@ func (h *Handler) handleClassLeaderboard(w http.ResponseWriter, r *http.Request)
 		entries = append(entries, entry)
 	}
 
+	strengths, err := h.loadStrengthView(r.Context())
+	if err != nil {
+		logger.Warn("[Class Leaderboard] Couldn't load torró strengths: %v", err)
+	}
+	for i := range entries {
+		entries[i].Strength = strengths.get(entries[i].TorronId)
+	}
+
 	response := map[string]interface{}{
 		"class_id":      classId,
 		"class_name":    className,
 		"entries":       entries,
+		"leader":        strengths.leader(classId),
 		"total_entries": len(entries),
 		"timestamp":     time.Now().UTC().Format(time.RFC3339),
 	}
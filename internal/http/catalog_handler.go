@@ -0,0 +1,41 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	"github.com/krtffl/torro/internal/catalog"
+	"github.com/krtffl/torro/internal/domain"
+	"github.com/krtffl/torro/internal/logger"
+)
+
+// PairingReconcileResponse reports a pairing reconciliation an admin
+// triggered, one summary per class.
+type PairingReconcileResponse struct {
+	Classes []*domain.PairingReconciliation `json:"classes"`
+}
+
+// reconcilePairings handles POST /admin/pairings/reconcile: brings every
+// class's pairings in line with the current catalog without waiting for a
+// restart, e.g. right after torrons are added or discontinued.
+func (h *Handler) reconcilePairings(w http.ResponseWriter, r *http.Request) {
+	logger.Info("[Handler - ReconcilePairings] Incoming request")
+
+	summaries, err := catalog.Reconcile(r.Context(), h.classRepo, h.torroRepo, h.pairingRepo)
+	if err != nil {
+		logger.Error("[Handler - ReconcilePairings] Couldn't reconcile pairings. %v", err)
+		render.Render(w, r, domain.ErrInternal(err))
+		return
+	}
+
+	for _, s := range summaries {
+		if s.Changed() {
+			logger.Info("[Handler - ReconcilePairings] %s - %d pairings: %d inserted, %d revived, %d retired",
+				s.ClassName, s.Desired, s.Inserted, s.Revived, s.Retired)
+		}
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, PairingReconcileResponse{Classes: summaries})
+}
@@ -3,6 +3,7 @@ package http
 import (
 	"net/http"
 
+	"github.com/krtffl/torro/internal/domain"
 	"github.com/krtffl/torro/internal/logger"
 )
 
@@ -50,6 +51,7 @@ type CategoryPageContent struct {
 	HX           bool
 	Page         CategoryPage
 	Entries      []LeaderboardEntry
+	Leader       *domain.LeaderSeparation
 	TotalVotes   int
 	UpdatedAt    string
 	UpdatedAtISO string
@@ -69,9 +71,10 @@ func (h *Handler) categoryPageHandler(page CategoryPage) http.HandlerFunc {
 		}
 
 		var entries []LeaderboardEntry
+		var leader *domain.LeaderSeparation
 		for _, c := range content.Categories {
 			if c.Class.Id == page.ClassId {
-				entries = c.Entries
+				entries, leader = c.Entries, c.Leader
 				break
 			}
 		}
@@ -83,6 +86,7 @@ func (h *Handler) categoryPageHandler(page CategoryPage) http.HandlerFunc {
 			HX:           isHX(r),
 			Page:         page,
 			Entries:      entries,
+			Leader:       leader,
 			TotalVotes:   content.TotalVotes,
 			UpdatedAt:    content.UpdatedAt,
 			UpdatedAtISO: content.UpdatedAtISO,
@@ -0,0 +1,227 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+
+	"github.com/krtffl/torro/internal/domain"
+	"github.com/krtffl/torro/internal/fraud"
+	"github.com/krtffl/torro/internal/logger"
+	"github.com/krtffl/torro/internal/replay"
+)
+
+// fraudAnalyzeInterval is how often the vote-fraud analyzer rescans
+// Results. The rate limiter already slows scripted voting down, so an hour
+// is soon enough to catch what got through.
+const fraudAnalyzeInterval = time.Hour
+
+// fraudReportLimit caps how many suspects the admin report lists.
+const fraudReportLimit = 200
+
+// FraudReport is the admin fraud report: every quarantined user, and the
+// highest-scoring users the analyzer has not quarantined (below the
+// threshold, or released by an admin).
+type FraudReport struct {
+	Threshold   float64                   `json:"threshold"`
+	MinVotes    int                       `json:"min_votes"`
+	Quarantined []*domain.QuarantinedUser `json:"quarantined"`
+	Suspects    []*domain.FraudAssessment `json:"suspects"`
+}
+
+// FraudReplayResponse reports a global rating replay an admin action
+// triggered.
+type FraudReplayResponse struct {
+	NewlyQuarantined []string `json:"newly_quarantined,omitempty"`
+	ReleasedUserId   string   `json:"released_user_id,omitempty"`
+	Applied          int      `json:"applied"`
+	QuarantinedVotes int      `json:"quarantined_votes"`
+}
+
+// runFraudAnalyzer loops until ctx is cancelled, scanning for suspicious
+// voters a few minutes after boot and then every fraudAnalyzeInterval.
+func (h *Handler) runFraudAnalyzer(ctx context.Context) {
+	timer := time.NewTimer(5 * time.Minute)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		// A replay rewrites every Result, so it gets more room than the
+		// strength fit.
+		runCtx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+		if _, _, err := h.analyzeFraud(runCtx, false); err != nil {
+			logger.Warn("[Fraud] Couldn't analyze votes. %v", err)
+		}
+		cancel()
+
+		timer.Reset(fraudAnalyzeInterval)
+	}
+}
+
+// analyzeFraud quarantines every newly flagged user and, if there were any
+// (or forceReplay is set), replays the global ratings without their votes.
+// The replay is nil when none ran.
+func (h *Handler) analyzeFraud(ctx context.Context, forceReplay bool) ([]string, *domain.Replay, error) {
+	signals, err := h.fraudRepo.ListSignals(ctx, fraud.DefaultMinVotes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var quarantined []string
+	for _, s := range signals {
+		a := fraud.Assess(s)
+		if !fraud.Flagged(a, fraud.DefaultThreshold) {
+			continue
+		}
+
+		ok, err := h.fraudRepo.Quarantine(ctx, a.UserId, a.Score)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok {
+			logger.Info("[Fraud] Quarantined user %s (score %.2f: %v)", a.UserId, a.Score, a.Reasons)
+			quarantined = append(quarantined, a.UserId)
+		}
+	}
+
+	if len(quarantined) == 0 && !forceReplay {
+		return nil, nil, nil
+	}
+
+	result, err := h.replayRatings(ctx)
+	if err != nil {
+		return quarantined, nil, err
+	}
+	return quarantined, result, nil
+}
+
+// replayRatings rebuilds every global rating from Results, leaving out
+// quarantined users' votes, then refits the strengths so the ranking pages
+// stop counting those votes right away rather than at the next scheduled
+// fit.
+func (h *Handler) replayRatings(ctx context.Context) (*domain.Replay, error) {
+	torrons, err := h.torroRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := h.replayRepo.Rebuild(ctx, func(votes []*domain.ReplayVote) *domain.Replay {
+		return replay.Run(h.ratingEngine, torrons, votes, replay.Options{})
+	})
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("[Fraud] Replayed ratings: %d votes applied, %d from quarantined users",
+		result.Applied, result.Quarantined)
+
+	if err := h.refitStrengths(ctx); err != nil {
+		logger.Warn("[Fraud] Couldn't refit torró strengths after the replay. %v", err)
+	}
+
+	return result, nil
+}
+
+// fraudReport handles GET /admin/fraud/report.
+func (h *Handler) fraudReport(w http.ResponseWriter, r *http.Request) {
+	logger.Info("[Handler - FraudReport] Incoming request")
+
+	quarantined, err := h.fraudRepo.ListQuarantined(r.Context())
+	if err != nil {
+		logger.Error("[Handler - FraudReport] Couldn't list quarantined users. %v", err)
+		render.Render(w, r, domain.ErrInternal(err))
+		return
+	}
+
+	signals, err := h.fraudRepo.ListSignals(r.Context(), fraud.DefaultMinVotes)
+	if err != nil {
+		logger.Error("[Handler - FraudReport] Couldn't list fraud signals. %v", err)
+		render.Render(w, r, domain.ErrInternal(err))
+		return
+	}
+
+	suspects := []*domain.FraudAssessment{}
+	for _, s := range signals {
+		if a := fraud.Assess(s); a.Score > 0 && !a.Quarantined {
+			suspects = append(suspects, a)
+		}
+	}
+	sort.Slice(suspects, func(i, j int) bool {
+		return suspects[i].Score > suspects[j].Score
+	})
+	if len(suspects) > fraudReportLimit {
+		suspects = suspects[:fraudReportLimit]
+	}
+
+	if quarantined == nil {
+		quarantined = []*domain.QuarantinedUser{}
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, FraudReport{
+		Threshold:   fraud.DefaultThreshold,
+		MinVotes:    fraud.DefaultMinVotes,
+		Quarantined: quarantined,
+		Suspects:    suspects,
+	})
+}
+
+// fraudAnalyze handles POST /admin/fraud/analyze: an on-demand analyzer
+// run. It always replays, so it also retries a replay a release left
+// undone.
+func (h *Handler) fraudAnalyze(w http.ResponseWriter, r *http.Request) {
+	logger.Info("[Handler - FraudAnalyze] Incoming request")
+
+	quarantined, result, err := h.analyzeFraud(r.Context(), true)
+	if err != nil {
+		logger.Error("[Handler - FraudAnalyze] Couldn't analyze votes. %v", err)
+		render.Render(w, r, domain.ErrInternal(err))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, FraudReplayResponse{
+		NewlyQuarantined: quarantined,
+		Applied:          result.Applied,
+		QuarantinedVotes: result.Quarantined,
+	})
+}
+
+// fraudRelease handles POST /admin/fraud/users/{userId}/release: lifts a
+// quarantine, for good, and replays the global ratings with the user's
+// votes counted again.
+func (h *Handler) fraudRelease(w http.ResponseWriter, r *http.Request) {
+	logger.Info("[Handler - FraudRelease] Incoming request")
+
+	userId := chi.URLParam(r, "userId")
+	if err := h.fraudRepo.Release(r.Context(), userId); err != nil {
+		logger.Error("[Handler - FraudRelease] Couldn't release user %s. %v", userId, err)
+		render.Render(w, r, domain.ErrFromRepo(err))
+		return
+	}
+	logger.Info("[Handler - FraudRelease] Released user %s from quarantine", userId)
+
+	result, err := h.replayRatings(r.Context())
+	if err != nil {
+		// The release itself stands; POST /admin/fraud/analyze retries
+		// the replay.
+		logger.Error("[Handler - FraudRelease] Released user %s but couldn't replay ratings. %v", userId, err)
+		render.Render(w, r, domain.ErrInternal(err))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, FraudReplayResponse{
+		ReleasedUserId:   userId,
+		Applied:          result.Applied,
+		QuarantinedVotes: result.Quarantined,
+	})
+}
@@ -1,11 +1,13 @@
 package http
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"html/template"
 	"net/http"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -53,14 +55,6 @@ func lastSeasonYear() int {
 	return now.Year() - 1
 }
 
-// K is the K-factor for ELO rating calculations
-// Value of 42 provides:
-// - Fast convergence for new items (larger rating changes)
-// - Balanced sensitivity for established items
-// - Standard K-factor is 32 for masters, 40 for beginners
-// - 42 chosen for this system's moderate volatility needs
-const K = 42
-
 type Content struct {
 	Torrons  []*domain.Torro
 	Pairing  *domain.Pairing
@@ -89,26 +83,59 @@ type Content struct {
 	ProgressDegrees    float64
 	ResultsUnlocked    bool
 	Category           string
+
+	// Undo offers to take back the vote that led to this pairing (see
+	// undoVote); Undone confirms a vote was just taken back. Both only ever
+	// appear on the HTMX pairing partial.
+	Undo   *VoteUndoToast
+	Undone bool
+
+	// Reasons offers to tag the vote that led to this pairing with why it
+	// was won (see voteReasons). Only decided votes of a known user get it.
+	Reasons *ReasonPicker
 }
 
 type Handler struct {
-	db               *sql.DB
-	template         *template.Template
-	bpool            *bpool.BufferPool
-	pairingRepo      domain.PairingRepo
-	torroRepo        domain.TorroRepo
-	classRepo        domain.ClassRepo
-	resultRepo       domain.ResultRepo
-	userRepo         domain.UserRepo
-	userEloRepo      domain.UserEloSnapshotRepo
-	campaignRepo     domain.CampaignRepo
-	bracketRepo      domain.BracketRepo
-	adventVoteRepo   domain.AdventVoteRepo
-	friendCircleRepo domain.FriendCircleRepo
-	pressStatsRepo   domain.PressStatsRepo
-	wrappedStatsRepo domain.WrappedStatsRepo
-	personaRepo      domain.PersonaRepo
-	adminToken       string
+	db                    *sql.DB
+	template              *template.Template
+	bpool                 *bpool.BufferPool
+	pairingRepo           domain.PairingRepo
+	torroRepo             domain.TorroRepo
+	classRepo             domain.ClassRepo
+	resultRepo            domain.ResultRepo
+	userRepo              domain.UserRepo
+	userEloRepo           domain.UserEloSnapshotRepo
+	campaignRepo          domain.CampaignRepo
+	bracketRepo           domain.BracketRepo
+	bracketPredictionRepo domain.BracketPredictionRepo
+	adventVoteRepo        domain.AdventVoteRepo
+	friendCircleRepo      domain.FriendCircleRepo
+	pressStatsRepo        domain.PressStatsRepo
+	wrappedStatsRepo      domain.WrappedStatsRepo
+	personaRepo           domain.PersonaRepo
+	strengthRepo          domain.StrengthRepo
+	fraudRepo             domain.FraudRepo
+	replayRepo            domain.ReplayRepo
+	voteUndoRepo          domain.VoteUndoRepo
+	campaignRatingRepo    domain.CampaignRatingRepo
+	rankingSubmissionRepo domain.RankingSubmissionRepo
+	trustRepo             domain.TrustRepo
+	reasonRepo            domain.ReasonRepo
+	similarityRepo        domain.SimilarityRepo
+	tasteTwinRepo         domain.TasteTwinRepo
+	ratingEngine          domain.RatingEngine
+	pairingSelector       domain.PairingSelector
+	voteWeighting         domain.VoteWeighting
+	adminToken            string
+
+	// granFinalRunnersUp sends each class bracket's runner-up to the Gran
+	// Final alongside its champion (see createGranFinalIfReady).
+	granFinalRunnersUp bool
+
+	// seasonCampaign caches the campaign this process last saw owning the
+	// live ratings, so votes only hit RatingSeason when the active
+	// campaign changes (see syncRatingSeason).
+	seasonCampaign atomic.Pointer[string]
 }
 
 func NewHandler(
@@ -122,12 +149,27 @@ func NewHandler(
 	userEloRepo domain.UserEloSnapshotRepo,
 	campaignRepo domain.CampaignRepo,
 	bracketRepo domain.BracketRepo,
+	bracketPredictionRepo domain.BracketPredictionRepo,
 	adventVoteRepo domain.AdventVoteRepo,
 	friendCircleRepo domain.FriendCircleRepo,
 	pressStatsRepo domain.PressStatsRepo,
 	wrappedStatsRepo domain.WrappedStatsRepo,
 	personaRepo domain.PersonaRepo,
+	strengthRepo domain.StrengthRepo,
+	fraudRepo domain.FraudRepo,
+	replayRepo domain.ReplayRepo,
+	voteUndoRepo domain.VoteUndoRepo,
+	campaignRatingRepo domain.CampaignRatingRepo,
+	rankingSubmissionRepo domain.RankingSubmissionRepo,
+	trustRepo domain.TrustRepo,
+	reasonRepo domain.ReasonRepo,
+	similarityRepo domain.SimilarityRepo,
+	tasteTwinRepo domain.TasteTwinRepo,
+	ratingEngine domain.RatingEngine,
+	pairingSelector domain.PairingSelector,
+	voteWeighting domain.VoteWeighting,
 	adminToken string,
+	granFinalRunnersUp bool,
 ) *Handler {
 	tmpls, err := template.New("").Funcs(templateFuncs).ParseFS(torrons.Public, "public/templates/*.html")
 	if err != nil {
@@ -135,23 +177,38 @@ func NewHandler(
 	}
 
 	return &Handler{
-		db:               db,
-		template:         tmpls,
-		bpool:            bpool,
-		pairingRepo:      pairingRep,
-		torroRepo:        torroRepo,
-		classRepo:        classRepo,
-		resultRepo:       resultRepo,
-		userRepo:         userRepo,
-		userEloRepo:      userEloRepo,
-		campaignRepo:     campaignRepo,
-		bracketRepo:      bracketRepo,
-		adventVoteRepo:   adventVoteRepo,
-		friendCircleRepo: friendCircleRepo,
-		pressStatsRepo:   pressStatsRepo,
-		wrappedStatsRepo: wrappedStatsRepo,
-		personaRepo:      personaRepo,
-		adminToken:       adminToken,
+		db:                    db,
+		template:              tmpls,
+		bpool:                 bpool,
+		pairingRepo:           pairingRep,
+		torroRepo:             torroRepo,
+		classRepo:             classRepo,
+		resultRepo:            resultRepo,
+		userRepo:              userRepo,
+		userEloRepo:           userEloRepo,
+		campaignRepo:          campaignRepo,
+		bracketRepo:           bracketRepo,
+		bracketPredictionRepo: bracketPredictionRepo,
+		adventVoteRepo:        adventVoteRepo,
+		friendCircleRepo:      friendCircleRepo,
+		pressStatsRepo:        pressStatsRepo,
+		wrappedStatsRepo:      wrappedStatsRepo,
+		personaRepo:           personaRepo,
+		strengthRepo:          strengthRepo,
+		fraudRepo:             fraudRepo,
+		replayRepo:            replayRepo,
+		voteUndoRepo:          voteUndoRepo,
+		campaignRatingRepo:    campaignRatingRepo,
+		rankingSubmissionRepo: rankingSubmissionRepo,
+		trustRepo:             trustRepo,
+		reasonRepo:            reasonRepo,
+		similarityRepo:        similarityRepo,
+		tasteTwinRepo:         tasteTwinRepo,
+		ratingEngine:          ratingEngine,
+		pairingSelector:       pairingSelector,
+		voteWeighting:         voteWeighting,
+		adminToken:            adminToken,
+		granFinalRunnersUp:    granFinalRunnersUp,
 	}
 }
 
@@ -215,14 +272,15 @@ func (h *Handler) vote(w http.ResponseWriter, r *http.Request) {
 
 	classId := chi.URLParam(r, "id")
 
-	p, err := h.pairingRepo.GetRandom(r.Context(), classId)
+	p, err := h.pairingSelector.Next(r.Context(), classId, GetUserIDFromContext(r.Context()), "")
 	if err != nil {
-		logger.Error("[Handler - Vote] Couldn't get random pairing. %v", err)
+		logger.Error("[Handler - Vote] Couldn't select pairing. %v", err)
 		// A nonexistent class (or one with no pairings) surfaces as a
 		// not-found repo error -> 404, not a 500.
 		render.Render(w, r, domain.ErrFromRepo(err))
 		return
 	}
+	h.markPairingSeen(r, p.Id)
 
 	t1, err := h.torroRepo.Get(r.Context(), p.Torro1)
 	if err != nil {
@@ -261,10 +319,11 @@ func (h *Handler) vote(w http.ResponseWriter, r *http.Request) {
 			streakAtRisk = currentStreak > 0 &&
 				(user.LastVoteDate == nil || !strings.HasPrefix(*user.LastVoteDate, today))
 
-			// The Global class "5" unlocks on total votes (like stats/leaderboard);
-			// every other class unlocks on its own per-class count.
+			// The Global class "5" unlocks on total votes this season (like
+			// stats/leaderboard); every other class unlocks on its own
+			// per-class count.
 			if classId == "5" {
-				voteCount = user.VoteCount
+				voteCount = user.SeasonVoteCount()
 			}
 		}
 		if classId != "5" {
@@ -316,6 +375,19 @@ func (h *Handler) vote(w http.ResponseWriter, r *http.Request) {
 	buf.WriteTo(w)
 }
 
+// markPairingSeen records that the current user was just served pairingId,
+// which is what the coverage selector schedules on. Coverage is a nicety,
+// so a failure is logged and the duel is served regardless.
+func (h *Handler) markPairingSeen(r *http.Request, pairingId string) {
+	userId := GetUserIDFromContext(r.Context())
+	if userId == "" {
+		return
+	}
+	if err := h.pairingRepo.MarkSeen(r.Context(), userId, pairingId); err != nil {
+		logger.Warn("[Handler - Vote] Couldn't record pairing %s as seen. %v", pairingId, err)
+	}
+}
+
 func (h *Handler) result(w http.ResponseWriter, r *http.Request) {
 	logger.Info("[Handler - Result] Incoming request")
 
@@ -330,6 +402,10 @@ func (h *Handler) result(w http.ResponseWriter, r *http.Request) {
 	// random pairing).
 	isAdvent := r.URL.Query().Get("advent") == "true"
 
+	// isDraw is the "can't decide" button: no winner, both torrons scored
+	// 0.5. The id parameter is ignored for a draw.
+	isDraw := r.URL.Query().Get("outcome") == domain.ResultOutcomeDraw
+
 	// Get user ID from context (set by UserMiddleware)
 	userId := GetUserIDFromContext(r.Context())
 	if userId == "" {
@@ -346,8 +422,16 @@ func (h *Handler) result(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// A page loaded before reconciliation retired the matchup (one of the
+	// torrons was discontinued) can still post to it.
+	if p.RetiredAt != nil {
+		render.Render(w, r, domain.ErrConflict(
+			fmt.Errorf("%s: this matchup is no longer being voted on", domain.ValidationError)))
+		return
+	}
+
 	// Validate that the winner ID matches one of the torros in the pairing
-	if winnerId != p.Torro1 && winnerId != p.Torro2 {
+	if !isDraw && winnerId != p.Torro1 && winnerId != p.Torro2 {
 		logger.Error("[Handler - Result] Invalid winner ID %s for pairing %s (expected %s or %s)",
 			winnerId, pairingId, p.Torro1, p.Torro2)
 		render.Render(w, r, domain.ErrBadRequest(
@@ -365,6 +449,10 @@ func (h *Handler) result(w http.ResponseWriter, r *http.Request) {
 	var campaignIdPtr *string
 	if campaign, err := h.campaignRepo.GetActive(r.Context()); err == nil {
 		campaignIdPtr = &campaign.Id
+		// The first vote of a new campaign starts its rating season, so it
+		// lands on fresh ratings rather than last year's. Also before the
+		// transaction: a rollover locks the same rows this vote will.
+		h.syncRatingSeason(r.Context(), campaign.Id)
 	} else {
 		logger.Debug("[Handler - Result] No active campaign to tag this vote with. %v", err)
 	}
@@ -400,98 +488,65 @@ func (h *Handler) result(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Calculate new ELO ratings based on match result
-	new1, new2 := UpdateRatings(t1.Rating, t2.Rating, winnerId == t1.Id, K)
-
-	// Prepare user ID pointer for result record (nullable)
-	var userIdPtr *string
-	if userId != "" {
-		userIdPtr = &userId
-	}
-
-	// Create result record within transaction
-	_, err = h.resultRepo.CreateTx(tx, r.Context(), &domain.Result{
-		Pairing:    pairingId,
-		Rat1Bef:    t1.Rating,
-		Rat2Bef:    t2.Rating,
-		Winner:     winnerId,
-		Rat1Aft:    new1,
-		Rat2Aft:    new2,
-		UserId:     userIdPtr, // Track which user cast this vote
-		CampaignId: campaignIdPtr,
-	})
-	if err != nil {
-		logger.Error("[Handler - Result] Couldn't create result. %v", err)
-		render.Render(w, r, domain.ErrInternal(err))
-		return
+	// Score the duel from t1's point of view. A draw leaves the result
+	// without a winner.
+	score1 := 0.0
+	switch {
+	case isDraw:
+		score1 = 0.5
+	case winnerId == t1.Id:
+		score1 = 1
 	}
 
-	// Update user vote count if user tracking is enabled
-	if userId != "" && p.Class != "" {
-		if err := h.userRepo.IncrementVoteCountTx(tx, r.Context(), userId, p.Class); err != nil {
-			logger.Error("[Handler - Result] Couldn't increment user vote count. %v", err)
-			render.Render(w, r, domain.ErrInternal(err))
-			return
-		}
-
-		// Update the user's voting streak (any vote in any class counts).
-		// This is a general engagement metric, independent of per-class counts.
-		if err := h.userRepo.UpdateStreakTx(tx, r.Context(), userId); err != nil {
-			logger.Error("[Handler - Result] Couldn't update user streak. %v", err)
-			render.Render(w, r, domain.ErrInternal(err))
-			return
-		}
-
-		// Calculate and update personalized ELO ratings for this user
-		// Get or create user ELO snapshots for both torrons
-		userElo1, err := h.userEloRepo.GetOrCreateTx(tx, r.Context(), userId, t1.Id)
-		if err != nil {
-			logger.Error("[Handler - Result] Couldn't get user ELO for torron 1. %v", err)
-			render.Render(w, r, domain.ErrInternal(err))
-			return
-		}
-
-		userElo2, err := h.userEloRepo.GetOrCreateTx(tx, r.Context(), userId, t2.Id)
+	// Read after the torró locks so a replay that quarantined the user has
+	// finished by now. GetTx also locks the user's row, so the streak read
+	// here is exactly the one this vote overwrites (see VoteUndo below).
+	var user *domain.User
+	if userId != "" {
+		user, err = h.userRepo.GetTx(tx, r.Context(), userId)
 		if err != nil {
-			logger.Error("[Handler - Result] Couldn't get user ELO for torron 2. %v", err)
-			render.Render(w, r, domain.ErrInternal(err))
-			return
-		}
-
-		// Calculate new personalized ELO ratings (same formula as global)
-		userNew1, userNew2 := UpdateRatings(userElo1.Rating, userElo2.Rating, winnerId == t1.Id, K)
-
-		// Update user ELO snapshots
-		userElo1.Rating = userNew1
-		userElo1.VoteCount++
-		if _, err := h.userEloRepo.UpdateTx(tx, r.Context(), userElo1); err != nil {
-			logger.Error("[Handler - Result] Couldn't update user ELO for torron 1. %v", err)
-			render.Render(w, r, domain.ErrInternal(err))
-			return
-		}
-
-		userElo2.Rating = userNew2
-		userElo2.VoteCount++
-		if _, err := h.userEloRepo.UpdateTx(tx, r.Context(), userElo2); err != nil {
-			logger.Error("[Handler - Result] Couldn't update user ELO for torron 2. %v", err)
+			logger.Error("[Handler - Result] Couldn't get user %s. %v", userId, err)
 			render.Render(w, r, domain.ErrInternal(err))
 			return
 		}
 	}
 
-	// Update global ratings within transaction
-	_, err = h.torroRepo.UpdateTx(tx, r.Context(), t1.Id, new1)
+	// Both torrons' state before the vote, for the undo; applyDuelTx moves
+	// t1 and t2 on.
+	before1, before2 := t1.RatingState(), t2.RatingState()
+
+	applied, err := h.applyDuelTx(tx, r.Context(), duelVote{
+		pairing:    p,
+		t1:         t1,
+		t2:         t2,
+		score1:     score1,
+		user:       user,
+		campaignId: campaignIdPtr,
+	})
 	if err != nil {
-		logger.Error("[Handler - Result] Couldn't update rating: %s. %v", t1.Id, err)
+		logger.Error("[Handler - Result] Couldn't record vote. %v", err)
 		render.Render(w, r, domain.ErrInternal(err))
 		return
 	}
 
-	_, err = h.torroRepo.UpdateTx(tx, r.Context(), t2.Id, new2)
-	if err != nil {
-		logger.Error("[Handler - Result] Couldn't update rating: %s. %v", t2.Id, err)
-		render.Render(w, r, domain.ErrInternal(err))
-		return
+	// undo records what this vote overwrote, so the voter can take it back
+	// within voteUndoWindow (see undoVote). Only a tracked vote in a class
+	// touches the user's counters and snapshots, so only those are undoable.
+	var undo *domain.VoteUndo
+	if applied.tracked {
+		undo = &domain.VoteUndo{
+			UserId:            userId,
+			ResultId:          applied.result.Id,
+			CastAt:            time.Now().UTC(),
+			ClassId:           p.Class,
+			Torro1Before:      before1,
+			Torro2Before:      before2,
+			Snapshot1Before:   applied.snapshot1Before,
+			Snapshot2Before:   applied.snapshot2Before,
+			PrevCurrentStreak: user.CurrentStreak,
+			PrevLongestStreak: user.LongestStreak,
+			PrevLastVoteDate:  user.LastVoteDate,
+		}
 	}
 
 	// If this is today's featured advent duel, record it within the same
@@ -518,6 +573,17 @@ func (h *Handler) result(w http.ResponseWriter, r *http.Request) {
 			render.Render(w, r, domain.ErrFromRepo(err))
 			return
 		}
+		if undo != nil {
+			undo.AdventDate = &voteDate
+		}
+	}
+
+	if undo != nil {
+		if err := h.voteUndoRepo.SaveTx(tx, r.Context(), undo); err != nil {
+			logger.Error("[Handler - Result] Couldn't save undo state. %v", err)
+			render.Render(w, r, domain.ErrInternal(err))
+			return
+		}
 	}
 
 	// Commit transaction (makes all changes visible atomically)
@@ -548,12 +614,13 @@ func (h *Handler) result(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	newP, err := h.pairingRepo.GetRandomExcluding(r.Context(), p.Class, pairingId)
+	newP, err := h.pairingSelector.Next(r.Context(), p.Class, userId, pairingId)
 	if err != nil {
-		logger.Error("[Handler - Result] Couldn't get random pairing. %v", err)
+		logger.Error("[Handler - Result] Couldn't select next pairing. %v", err)
 		render.Render(w, r, domain.ErrInternal(err))
 		return
 	}
+	h.markPairingSeen(r, newP.Id)
 
 	newt1, err := h.torroRepo.Get(r.Context(), newP.Torro1)
 	if err != nil {
@@ -575,11 +642,22 @@ func (h *Handler) result(w http.ResponseWriter, r *http.Request) {
 	buf := h.bpool.Get()
 	defer h.bpool.Put(buf)
 
-	if err := h.template.ExecuteTemplate(buf, "pairing.html", Content{
+	content := Content{
 		Pairing: newP,
 		Torrons: []*domain.Torro{newt1, newt2},
 		HX:      isHX(r),
-	}); err != nil {
+	}
+	if undo != nil {
+		content.Undo = &VoteUndoToast{
+			ResultId: undo.ResultId,
+			Seconds:  int(voteUndoWindow / time.Second),
+		}
+		if applied.result.Winner != nil {
+			content.Reasons = h.newReasonPicker(r.Context(), undo.ResultId, nil)
+		}
+	}
+
+	if err := h.template.ExecuteTemplate(buf, "pairing.html", content); err != nil {
 		logger.Error("[Handler - Result] Couldn't execute template. %v", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		if execErr := h.template.ExecuteTemplate(w, "error.html", Content{}); execErr != nil {
@@ -592,6 +670,133 @@ func (h *Handler) result(w http.ResponseWriter, r *http.Request) {
 	buf.WriteTo(w)
 }
 
+// duelVote is one duel outcome to record inside a vote transaction. t1 and
+// t2 are the pairing's Torro1 and Torro2, already locked by the caller;
+// score1 is t1's score (1, 0.5 or 0). user is the voter, locked too, or
+// nil for an anonymous session.
+type duelVote struct {
+	pairing      *domain.Pairing
+	t1, t2       *domain.Torro
+	score1       float64
+	user         *domain.User
+	campaignId   *string
+	submissionId *string
+}
+
+// appliedDuel is what applyDuelTx recorded. tracked says whether the duel
+// counted towards the user's vote counts, streak and personal snapshots,
+// which the snapshots before it are then the state of.
+type appliedDuel struct {
+	result          *domain.Result
+	tracked         bool
+	snapshot1Before domain.UserEloSnapshot
+	snapshot2Before domain.UserEloSnapshot
+}
+
+// applyDuelTx records one duel outcome through the configured rating
+// engine (Elo, Glicko-2 or TrueSkill): its Result, the user's vote counts,
+// streak and personal snapshots, and both torrons' global ratings. d.t1 and
+// d.t2 are left holding their new state, so several duels applied in one
+// transaction build on each other as separate votes would.
+//
+// A user the fraud analyzer has quarantined still gets a Result and their
+// personal snapshots, but the global ratings stay put (the Result records
+// equal before and after ratings, as a replay would).
+func (h *Handler) applyDuelTx(tx *sql.Tx, ctx context.Context, d duelVote) (*appliedDuel, error) {
+	t1, t2 := d.t1, d.t2
+	// The global ratings take only the voter's trust weight of the update;
+	// it is stored on the Result so a replay applies the same one.
+	weight := h.voteWeighting.Weight(d.user)
+	new1, new2 := domain.WeightedUpdate(h.ratingEngine, t1.RatingState(), t2.RatingState(), d.score1, weight)
+	if d.user != nil && d.user.Quarantined {
+		new1, new2 = t1.RatingState(), t2.RatingState()
+	}
+
+	outcome := domain.ResultOutcomeWin
+	var winner *string
+	switch d.score1 {
+	case 1:
+		winner = &t1.Id
+	case 0:
+		winner = &t2.Id
+	default:
+		outcome = domain.ResultOutcomeDraw
+	}
+
+	var userId *string
+	if d.user != nil {
+		userId = &d.user.Id
+	}
+
+	created, err := h.resultRepo.CreateTx(tx, ctx, &domain.Result{
+		Pairing:      d.pairing.Id,
+		Rat1Bef:      t1.Rating,
+		Rat2Bef:      t2.Rating,
+		Winner:       winner,
+		Outcome:      outcome,
+		Rat1Aft:      new1.Rating,
+		Rat2Aft:      new2.Rating,
+		UserId:       userId,
+		CampaignId:   d.campaignId,
+		SubmissionId: d.submissionId,
+		Weight:       &weight,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating result: %w", err)
+	}
+	applied := &appliedDuel{result: created}
+
+	if d.user != nil && d.pairing.Class != "" {
+		if err := h.userRepo.IncrementVoteCountTx(tx, ctx, d.user.Id, d.pairing.Class); err != nil {
+			return nil, fmt.Errorf("incrementing user vote count: %w", err)
+		}
+
+		// Any vote in any class counts towards the streak.
+		if err := h.userRepo.UpdateStreakTx(tx, ctx, d.user.Id); err != nil {
+			return nil, fmt.Errorf("updating user streak: %w", err)
+		}
+
+		userElo1, err := h.userEloRepo.GetOrCreateTx(tx, ctx, d.user.Id, t1.Id)
+		if err != nil {
+			return nil, fmt.Errorf("getting user ELO for torron %s: %w", t1.Id, err)
+		}
+		userElo2, err := h.userEloRepo.GetOrCreateTx(tx, ctx, d.user.Id, t2.Id)
+		if err != nil {
+			return nil, fmt.Errorf("getting user ELO for torron %s: %w", t2.Id, err)
+		}
+		applied.tracked = true
+		applied.snapshot1Before = *userElo1
+		applied.snapshot2Before = *userElo2
+
+		// Personalized ratings move through the same engine as the global
+		// ones.
+		userNew1, userNew2 := h.ratingEngine.Update(userElo1.RatingState(), userElo2.RatingState(), d.score1)
+
+		userElo1.SetRatingState(userNew1)
+		userElo1.VoteCount++
+		if _, err := h.userEloRepo.UpdateTx(tx, ctx, userElo1); err != nil {
+			return nil, fmt.Errorf("updating user ELO for torron %s: %w", t1.Id, err)
+		}
+
+		userElo2.SetRatingState(userNew2)
+		userElo2.VoteCount++
+		if _, err := h.userEloRepo.UpdateTx(tx, ctx, userElo2); err != nil {
+			return nil, fmt.Errorf("updating user ELO for torron %s: %w", t2.Id, err)
+		}
+	}
+
+	if _, err := h.torroRepo.UpdateTx(tx, ctx, t1.Id, new1); err != nil {
+		return nil, fmt.Errorf("updating rating of %s: %w", t1.Id, err)
+	}
+	if _, err := h.torroRepo.UpdateTx(tx, ctx, t2.Id, new2); err != nil {
+		return nil, fmt.Errorf("updating rating of %s: %w", t2.Id, err)
+	}
+	t1.SetRatingState(new1)
+	t2.SetRatingState(new2)
+
+	return applied, nil
+}
+
 // renderErrorPage writes the branded 500 page. Shared by handlers whose
 // error path has nothing page-specific to add beyond their own log line.
 // (Several older handlers still inline this same block; fold them into this
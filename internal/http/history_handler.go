@@ -1,11 +1,14 @@
 package http
 
 import (
+	"context"
 	"database/sql"
 	"net/http"
 	"strconv"
 	"time"
 
+	"github.com/lib/pq"
+
 	"github.com/krtffl/torro/internal/logger"
 )
 
@@ -18,12 +21,23 @@ type VoteHistory struct {
 	WinnerId     string
 	IsWinner1    bool
 	IsWinner2    bool
+	IsDraw       bool // "can't decide" vote: no winner
+	// SubmissionId is set for a ranking vote, which Ranking then lists best
+	// first instead of a duel.
+	SubmissionId string
+	Ranking      []HistoryRankedTorro
 	CategoryName string
 	CategoryIcon string
 	Timestamp    time.Time
 	TimeAgo      string
 }
 
+// HistoryRankedTorro is one torró of a ranking vote in the history.
+type HistoryRankedTorro struct {
+	Name  string
+	Image string
+}
+
 // HistoryContent holds data for history page template
 type HistoryContent struct {
 	HX             bool
@@ -76,31 +90,41 @@ func (h *Handler) history(w http.ResponseWriter, r *http.Request) {
 	var query string
 	var args []interface{}
 
+	// A ranking vote's duels show as one row, for its RankingSubmission;
+	// the torrons it ordered are loaded below.
 	baseQuery := `
-		SELECT
-			t1."Id" as torron1_id,
-			t1."Name" as torron1_name,
-			t1."Image" as torron1_image,
-			t2."Id" as torron2_id,
-			t2."Name" as torron2_name,
-			t2."Image" as torron2_image,
-			r."Winner",
-			r."Timestamp",
-			c."Name" as category_name,
-			c."Id" as category_id
-		FROM "Results" r
-		INNER JOIN "Pairings" p ON r."Pairing" = p."Id"
-		INNER JOIN "Torrons" t1 ON p."Torro1" = t1."Id"
-		INNER JOIN "Torrons" t2 ON p."Torro2" = t2."Id"
-		INNER JOIN "Classes" c ON p."Class" = c."Id"
-		WHERE r."UserId" = $1
+		SELECT * FROM (
+			SELECT
+				'' as submission_id,
+				t1."Id" as torron1_id,
+				t1."Name" as torron1_name,
+				t1."Image" as torron1_image,
+				t2."Id" as torron2_id,
+				t2."Name" as torron2_name,
+				t2."Image" as torron2_image,
+				r."Winner",
+				r."Timestamp",
+				c."Name" as category_name,
+				c."Id" as category_id
+			FROM "Results" r
+			INNER JOIN "Pairings" p ON r."Pairing" = p."Id"
+			INNER JOIN "Torrons" t1 ON p."Torro1" = t1."Id"
+			INNER JOIN "Torrons" t2 ON p."Torro2" = t2."Id"
+			INNER JOIN "Classes" c ON p."Class" = c."Id"
+			WHERE r."UserId" = $1 AND r."SubmissionId" IS NULL
+			UNION ALL
+			SELECT s."Id", '', '', '', '', '', '', NULL, s."CreatedAt", c."Name", c."Id"
+			FROM "RankingSubmissions" s
+			INNER JOIN "Classes" c ON s."ClassId" = c."Id"
+			WHERE s."UserId" = $1
+		) v
 	`
 
 	if filterCategory != "all" {
-		query = baseQuery + " AND p.\"Class\" = $2 ORDER BY r.\"Timestamp\" DESC LIMIT $3 OFFSET $4"
+		query = baseQuery + " WHERE v.category_id = $2 ORDER BY v.\"Timestamp\" DESC LIMIT $3 OFFSET $4"
 		args = []interface{}{userId, filterCategory, limit + 1, offset} // +1 to check if there are more
 	} else {
-		query = baseQuery + " ORDER BY r.\"Timestamp\" DESC LIMIT $2 OFFSET $3"
+		query = baseQuery + " ORDER BY v.\"Timestamp\" DESC LIMIT $2 OFFSET $3"
 		args = []interface{}{userId, limit + 1, offset}
 	}
 
@@ -118,15 +142,17 @@ func (h *Handler) history(w http.ResponseWriter, r *http.Request) {
 		var timestamp sql.NullTime
 		var categoryId string
 		var torron1Id, torron2Id string
+		var winnerId sql.NullString
 
 		err := rows.Scan(
+			&vote.SubmissionId,
 			&torron1Id,
 			&vote.Torron1Name,
 			&vote.Torron1Image,
 			&torron2Id,
 			&vote.Torron2Name,
 			&vote.Torron2Image,
-			&vote.WinnerId,
+			&winnerId,
 			&timestamp,
 			&vote.CategoryName,
 			&categoryId,
@@ -141,9 +167,14 @@ func (h *Handler) history(w http.ResponseWriter, r *http.Request) {
 			vote.TimeAgo = getTimeAgo(timestamp.Time)
 		}
 
-		// Set winner flags by comparing winner ID with torron IDs
-		vote.IsWinner1 = vote.WinnerId == torron1Id
-		vote.IsWinner2 = vote.WinnerId == torron2Id
+		// Set winner flags by comparing winner ID with torron IDs. A draw
+		// has a NULL winner and matches neither; a ranking has neither.
+		if vote.SubmissionId == "" {
+			vote.WinnerId = winnerId.String
+			vote.IsDraw = !winnerId.Valid
+			vote.IsWinner1 = vote.WinnerId == torron1Id
+			vote.IsWinner2 = vote.WinnerId == torron2Id
+		}
 
 		// Get category icon
 		vote.CategoryIcon = getCategoryIcon(categoryId)
@@ -162,6 +193,12 @@ func (h *Handler) history(w http.ResponseWriter, r *http.Request) {
 		votes = votes[:limit] // Trim to actual limit
 	}
 
+	if err := h.loadHistoryRankings(r.Context(), votes); err != nil {
+		logger.Error("[Handler - History] Couldn't load rankings. %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
 	// Get all categories for filter
 	classes, err := h.classRepo.List(r.Context())
 	if err != nil {
@@ -242,3 +279,44 @@ func getCategoryIcon(categoryId string) string {
 	}
 	return icon
 }
+
+// loadHistoryRankings fills in the ordered torrons of every ranking vote
+// among votes.
+func (h *Handler) loadHistoryRankings(ctx context.Context, votes []VoteHistory) error {
+	index := make(map[string]int)
+	var ids []string
+	for i, vote := range votes {
+		if vote.SubmissionId != "" {
+			index[vote.SubmissionId] = i
+			ids = append(ids, vote.SubmissionId)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	rows, err := h.db.QueryContext(ctx, `
+		SELECT s."Id", t."Name", t."Image"
+		FROM "RankingSubmissions" s
+		CROSS JOIN LATERAL unnest(s."TorroIds") WITH ORDINALITY AS u("TorroId", "Position")
+		INNER JOIN "Torrons" t ON t."Id" = u."TorroId"
+		WHERE s."Id" = ANY($1)
+		ORDER BY s."Id", u."Position"`,
+		pq.Array(ids),
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var submissionId string
+		var torro HistoryRankedTorro
+		if err := rows.Scan(&submissionId, &torro.Name, &torro.Image); err != nil {
+			return err
+		}
+		vote := &votes[index[submissionId]]
+		vote.Ranking = append(vote.Ranking, torro)
+	}
+	return rows.Err()
+}
@@ -59,21 +59,32 @@ import (
 	"encoding/json"
 	"fmt"
 	"html/template"
+	"io"
+	"math"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
 	"github.com/google/uuid"
 	_ "github.com/lib/pq"
 	"github.com/oxtoacart/bpool"
 
 	torrons "github.com/krtffl/torro"
+	"github.com/krtffl/torro/internal/catalog"
 	"github.com/krtffl/torro/internal/domain"
+	"github.com/krtffl/torro/internal/matchmaking"
+	"github.com/krtffl/torro/internal/rating"
+	"github.com/krtffl/torro/internal/replay"
 	"github.com/krtffl/torro/internal/repository"
+	"github.com/krtffl/torro/internal/tournament"
+	"github.com/krtffl/torro/internal/trust"
 )
 
 // getEnvOrDefault reads an environment variable, falling back to a default
@@ -249,16 +260,26 @@ func TestIntegration_VoteCasting(t *testing.T) {
 	}
 
 	h := &Handler{
-		db:           db,
-		template:     newIntegrationTemplate(t),
-		bpool:        bpool.NewBufferPool(8),
-		pairingRepo:  pairingRepo,
-		torroRepo:    torroRepo,
-		classRepo:    classRepo,
-		resultRepo:   resultRepo,
-		userRepo:     userRepo,
-		userEloRepo:  userEloRepo,
-		campaignRepo: campaignRepo,
+		db:                    db,
+		template:              newIntegrationTemplate(t),
+		bpool:                 bpool.NewBufferPool(8),
+		pairingRepo:           pairingRepo,
+		torroRepo:             torroRepo,
+		classRepo:             classRepo,
+		resultRepo:            resultRepo,
+		userRepo:              userRepo,
+		userEloRepo:           userEloRepo,
+		campaignRepo:          campaignRepo,
+		voteUndoRepo:          repository.NewVoteUndoRepo(db),
+		campaignRatingRepo:    repository.NewCampaignRatingRepo(db),
+		rankingSubmissionRepo: repository.NewRankingSubmissionRepo(db),
+		reasonRepo:            repository.NewReasonRepo(db),
+		similarityRepo:        repository.NewSimilarityRepo(db),
+		tasteTwinRepo:         repository.NewTasteTwinRepo(db),
+		ratingEngine:          rating.NewElo(rating.DefaultEloK),
+		voteWeighting:         trust.NewNone(),
+
+		pairingSelector: matchmaking.NewRandom(pairingRepo),
 	}
 
 	// torro1 wins: winnerId is passed as the *query string* "id" param,
@@ -275,7 +296,7 @@ func TestIntegration_VoteCasting(t *testing.T) {
 		t.Fatalf("status = %d, want %d; body: %s", rec.Code, http.StatusOK, rec.Body.String())
 	}
 
-	wantNew1, wantNew2 := UpdateRatings(1500, 1500, true, K)
+	wantNew1, wantNew2 := rating.UpdateRatings(1500, 1500, true, rating.DefaultEloK)
 
 	// Assert the Results row: winner, before/after ratings, and -- this is
 	// the regression this test specifically guards -- that UserId actually
@@ -393,16 +414,26 @@ func TestIntegration_VoteCasting_NoActiveCampaign(t *testing.T) {
 	}
 
 	h := &Handler{
-		db:           db,
-		template:     newIntegrationTemplate(t),
-		bpool:        bpool.NewBufferPool(8),
-		pairingRepo:  pairingRepo,
-		torroRepo:    torroRepo,
-		classRepo:    classRepo,
-		resultRepo:   resultRepo,
-		userRepo:     userRepo,
-		userEloRepo:  userEloRepo,
-		campaignRepo: campaignRepo, // no Campaigns rows exist - GetActive will error
+		db:                    db,
+		template:              newIntegrationTemplate(t),
+		bpool:                 bpool.NewBufferPool(8),
+		pairingRepo:           pairingRepo,
+		torroRepo:             torroRepo,
+		classRepo:             classRepo,
+		resultRepo:            resultRepo,
+		userRepo:              userRepo,
+		userEloRepo:           userEloRepo,
+		campaignRepo:          campaignRepo, // no Campaigns rows exist - GetActive will error
+		voteUndoRepo:          repository.NewVoteUndoRepo(db),
+		campaignRatingRepo:    repository.NewCampaignRatingRepo(db),
+		rankingSubmissionRepo: repository.NewRankingSubmissionRepo(db),
+		reasonRepo:            repository.NewReasonRepo(db),
+		similarityRepo:        repository.NewSimilarityRepo(db),
+		tasteTwinRepo:         repository.NewTasteTwinRepo(db),
+		ratingEngine:          rating.NewElo(rating.DefaultEloK),
+		voteWeighting:         trust.NewNone(),
+
+		pairingSelector: matchmaking.NewRandom(pairingRepo),
 	}
 
 	target := fmt.Sprintf("/pairings/%s/vote?id=%s", pairing.Id, torro1Id)
@@ -427,240 +458,2466 @@ func TestIntegration_VoteCasting_NoActiveCampaign(t *testing.T) {
 	}
 }
 
-// -- Full bracket lifecycle (bracket_handler.go) --
-
-func TestIntegration_BracketLifecycle(t *testing.T) {
+// TestIntegration_VoteCasting_Draw covers the "can't decide" button: the
+// Results row is stored with no winner and a "draw" outcome, and two evenly
+// rated torrons keep their rating.
+func TestIntegration_VoteCasting_Draw(t *testing.T) {
 	db := setupIntegrationDB(t)
 	ctx := context.Background()
 
+	pairingRepo := repository.NewPairingRepo(db)
 	torroRepo := repository.NewTorroRepo(db)
-	classRepo := repository.NewClassRepo(db)
-	campaignRepo := repository.NewCampaignRepo(db)
-	bracketRepo := repository.NewBracketRepo(db)
 	userRepo := repository.NewUserRepo(db)
 
-	// Torrons.Class is never literally "5" (the real "Global" pseudo-class
-	// convention) in production; a plain, non-Global class ID like this one
-	// is exactly what the rest of the app uses for every real category, so
-	// there's nothing special to work around here.
-	classId := insertTestClass(t, db, "Bracket Lifecycle Test Class")
-
-	// Seed 4 torrons with distinct ratings so TopNByClass's seed order is
-	// deterministic: seed 1 (highest) .. seed 4 (lowest).
-	seed1Id := insertTestTorro(t, db, classId, "Seed 1", 1600)
-	seed2Id := insertTestTorro(t, db, classId, "Seed 2", 1550)
-	seed3Id := insertTestTorro(t, db, classId, "Seed 3", 1500)
-	seed4Id := insertTestTorro(t, db, classId, "Seed 4", 1450)
+	classId := insertTestClass(t, db, "Draw Test Class")
+	torro1Id := insertTestTorro(t, db, classId, "Torró A", 1500)
+	torro2Id := insertTestTorro(t, db, classId, "Torró B", 1500)
 
-	now := time.Now().UTC()
-	campaign, err := campaignRepo.Create(ctx, &domain.Campaign{
-		Name:      "Bracket Lifecycle Test Campaign",
-		StartDate: now.Add(-1 * time.Hour).Format(time.RFC3339),
-		EndDate:   now.Add(1 * time.Hour).Format(time.RFC3339),
-		Year:      now.Year(),
-		Status:    domain.CampaignStatusActive,
+	pairing, err := pairingRepo.Create(ctx, &domain.Pairing{
+		Torro1: torro1Id,
+		Torro2: torro2Id,
+		Class:  classId,
 	})
 	if err != nil {
-		t.Fatalf("failed to create test campaign: %v", err)
+		t.Fatalf("failed to create test pairing: %v", err)
 	}
 
-	voterA, err := userRepo.Create(ctx, &domain.User{Id: uuid.NewString()})
-	if err != nil {
-		t.Fatalf("failed to create voter A: %v", err)
-	}
-	voterB, err := userRepo.Create(ctx, &domain.User{Id: uuid.NewString()})
+	user, err := userRepo.Create(ctx, &domain.User{Id: uuid.NewString()})
 	if err != nil {
-		t.Fatalf("failed to create voter B: %v", err)
+		t.Fatalf("failed to create test user: %v", err)
 	}
 
 	h := &Handler{
-		db:           db,
-		template:     newIntegrationTemplate(t),
-		bpool:        bpool.NewBufferPool(8),
-		torroRepo:    torroRepo,
-		classRepo:    classRepo,
-		campaignRepo: campaignRepo,
-		bracketRepo:  bracketRepo,
-	}
-
-	// -- 1. bracketCreate: seed a size-4 bracket --
-
-	createTarget := fmt.Sprintf("/bracket/%s/create?size=4", classId)
-	createReq := newIntegrationRequest(http.MethodPost, createTarget, map[string]string{"classId": classId}, "")
-	createRec := httptest.NewRecorder()
+		db:                    db,
+		template:              newIntegrationTemplate(t),
+		bpool:                 bpool.NewBufferPool(8),
+		pairingRepo:           pairingRepo,
+		torroRepo:             torroRepo,
+		classRepo:             repository.NewClassRepo(db),
+		resultRepo:            repository.NewResultRepo(db),
+		userRepo:              userRepo,
+		userEloRepo:           repository.NewUserEloSnapshotRepo(db),
+		campaignRepo:          repository.NewCampaignRepo(db),
+		voteUndoRepo:          repository.NewVoteUndoRepo(db),
+		campaignRatingRepo:    repository.NewCampaignRatingRepo(db),
+		rankingSubmissionRepo: repository.NewRankingSubmissionRepo(db),
+		reasonRepo:            repository.NewReasonRepo(db),
+		similarityRepo:        repository.NewSimilarityRepo(db),
+		tasteTwinRepo:         repository.NewTasteTwinRepo(db),
+		ratingEngine:          rating.NewElo(rating.DefaultEloK),
+		voteWeighting:         trust.NewNone(),
+
+		pairingSelector: matchmaking.NewRandom(pairingRepo),
+	}
+
+	target := fmt.Sprintf("/pairings/%s/vote?outcome=draw", pairing.Id)
+	req := newIntegrationRequest(http.MethodPost, target, map[string]string{"id": pairing.Id}, user.Id)
+	rec := httptest.NewRecorder()
 
-	h.bracketCreate(createRec, createReq)
+	h.result(rec, req)
 
-	if createRec.Code != http.StatusCreated {
-		t.Fatalf("bracketCreate status = %d, want %d; body: %s", createRec.Code, http.StatusCreated, createRec.Body.String())
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d; body: %s", rec.Code, http.StatusOK, rec.Body.String())
 	}
 
-	var bracket domain.Bracket
-	if err := json.Unmarshal(createRec.Body.Bytes(), &bracket); err != nil {
-		t.Fatalf("failed to decode bracketCreate response: %v", err)
+	var (
+		winner           sql.NullString
+		outcome          string
+		rat1Aft, rat2Aft float64
+	)
+	if err := db.QueryRowContext(ctx,
+		`SELECT "Winner", "Outcome", "Torro1RatingAfter", "Torro2RatingAfter"
+		 FROM "Results" WHERE "Pairing" = $1`,
+		pairing.Id,
+	).Scan(&winner, &outcome, &rat1Aft, &rat2Aft); err != nil {
+		t.Fatalf("failed to read back the Results row: %v", err)
 	}
 
-	if bracket.ClassId != classId {
-		t.Errorf("bracket.ClassId = %q, want %q", bracket.ClassId, classId)
-	}
-	if bracket.CampaignId != campaign.Id {
-		t.Errorf("bracket.CampaignId = %q, want %q", bracket.CampaignId, campaign.Id)
+	if winner.Valid {
+		t.Errorf("Results.Winner = %q, want NULL for a draw", winner.String)
 	}
-	if bracket.Size != 4 {
-		t.Errorf("bracket.Size = %d, want 4", bracket.Size)
+	if outcome != domain.ResultOutcomeDraw {
+		t.Errorf("Results.Outcome = %q, want %q", outcome, domain.ResultOutcomeDraw)
 	}
-	if bracket.CurrentRound != 1 {
-		t.Errorf("bracket.CurrentRound = %d, want 1", bracket.CurrentRound)
+	if !floatsClose(rat1Aft, 1500) || !floatsClose(rat2Aft, 1500) {
+		t.Errorf("after ratings = (%v, %v), want (1500, 1500)", rat1Aft, rat2Aft)
 	}
-	if bracket.Status != domain.BracketStatusInProgress {
-		t.Errorf("bracket.Status = %q, want %q", bracket.Status, domain.BracketStatusInProgress)
+}
+
+// TestIntegration_VoteCasting_Quarantined covers a vote from a user the
+// fraud analyzer has quarantined: the Result is stored and the user's own
+// snapshot moves, but the global ratings stay where they were.
+func TestIntegration_VoteCasting_Quarantined(t *testing.T) {
+	db := setupIntegrationDB(t)
+	ctx := context.Background()
+
+	pairingRepo := repository.NewPairingRepo(db)
+	torroRepo := repository.NewTorroRepo(db)
+	userRepo := repository.NewUserRepo(db)
+	userEloRepo := repository.NewUserEloSnapshotRepo(db)
+
+	classId := insertTestClass(t, db, "Quarantine Test Class")
+	torro1Id := insertTestTorro(t, db, classId, "Torró A", 1500)
+	torro2Id := insertTestTorro(t, db, classId, "Torró B", 1500)
+
+	pairing, err := pairingRepo.Create(ctx, &domain.Pairing{
+		Torro1: torro1Id,
+		Torro2: torro2Id,
+		Class:  classId,
+	})
+	if err != nil {
+		t.Fatalf("failed to create test pairing: %v", err)
 	}
 
-	entries, err := bracketRepo.ListEntries(ctx, bracket.Id)
+	user, err := userRepo.Create(ctx, &domain.User{Id: uuid.NewString()})
 	if err != nil {
-		t.Fatalf("failed to list bracket entries: %v", err)
+		t.Fatalf("failed to create test user: %v", err)
 	}
-	if len(entries) != 4 {
-		t.Fatalf("len(entries) = %d, want 4", len(entries))
+	if ok, err := repository.NewFraudRepo(db).Quarantine(ctx, user.Id, 0.9); err != nil || !ok {
+		t.Fatalf("failed to quarantine test user: ok=%v err=%v", ok, err)
 	}
-	seedByTorro := make(map[string]int, len(entries))
-	for _, e := range entries {
-		seedByTorro[e.TorronId] = e.Seed
+
+	h := &Handler{
+		db:                    db,
+		template:              newIntegrationTemplate(t),
+		bpool:                 bpool.NewBufferPool(8),
+		pairingRepo:           pairingRepo,
+		torroRepo:             torroRepo,
+		classRepo:             repository.NewClassRepo(db),
+		resultRepo:            repository.NewResultRepo(db),
+		userRepo:              userRepo,
+		userEloRepo:           userEloRepo,
+		campaignRepo:          repository.NewCampaignRepo(db),
+		voteUndoRepo:          repository.NewVoteUndoRepo(db),
+		campaignRatingRepo:    repository.NewCampaignRatingRepo(db),
+		rankingSubmissionRepo: repository.NewRankingSubmissionRepo(db),
+		reasonRepo:            repository.NewReasonRepo(db),
+		similarityRepo:        repository.NewSimilarityRepo(db),
+		tasteTwinRepo:         repository.NewTasteTwinRepo(db),
+		ratingEngine:          rating.NewElo(rating.DefaultEloK),
+		voteWeighting:         trust.NewNone(),
+
+		pairingSelector: matchmaking.NewRandom(pairingRepo),
 	}
-	if seedByTorro[seed1Id] != 1 || seedByTorro[seed2Id] != 2 || seedByTorro[seed3Id] != 3 || seedByTorro[seed4Id] != 4 {
-		t.Fatalf("unexpected seeding: %+v", seedByTorro)
+
+	target := fmt.Sprintf("/pairings/%s/vote?id=%s", pairing.Id, torro1Id)
+	req := newIntegrationRequest(http.MethodPost, target, map[string]string{"id": pairing.Id}, user.Id)
+	rec := httptest.NewRecorder()
+
+	h.result(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d; body: %s", rec.Code, http.StatusOK, rec.Body.String())
 	}
 
-	round1Matches, err := bracketRepo.ListMatchesByRound(ctx, bracket.Id, 1)
-	if err != nil {
-		t.Fatalf("failed to list round 1 matches: %v", err)
+	var rat1Aft, rat2Aft float64
+	if err := db.QueryRowContext(ctx,
+		`SELECT "Torro1RatingAfter", "Torro2RatingAfter" FROM "Results" WHERE "Pairing" = $1`,
+		pairing.Id,
+	).Scan(&rat1Aft, &rat2Aft); err != nil {
+		t.Fatalf("failed to read back the Results row: %v", err)
 	}
-	if len(round1Matches) != 2 {
-		t.Fatalf("len(round1Matches) = %d, want 2", len(round1Matches))
+	if !floatsClose(rat1Aft, 1500) || !floatsClose(rat2Aft, 1500) {
+		t.Errorf("after ratings = (%v, %v), want (1500, 1500) for a quarantined vote", rat1Aft, rat2Aft)
 	}
 
-	// Standard seeding for a field of 4 is 1v4, 2v3.
-	matchOf := func(matches []*domain.BracketMatch, a, b string) *domain.BracketMatch {
-		for _, m := range matches {
-			t2 := ""
-			if m.Torro2Id != nil {
-				t2 = *m.Torro2Id
-			}
-			if (m.Torro1Id == a && t2 == b) || (m.Torro1Id == b && t2 == a) {
-				return m
-			}
-		}
-		return nil
+	var torro1Rating float64
+	if err := db.QueryRowContext(ctx, `SELECT "Rating" FROM "Torrons" WHERE "Id" = $1`, torro1Id).Scan(&torro1Rating); err != nil {
+		t.Fatalf("failed to read back torro1's rating: %v", err)
 	}
-
-	match1v4 := matchOf(round1Matches, seed1Id, seed4Id)
-	match2v3 := matchOf(round1Matches, seed2Id, seed3Id)
-	if match1v4 == nil {
-		t.Fatalf("expected a seed1-vs-seed4 match in round 1, got %+v", round1Matches)
+	if !floatsClose(torro1Rating, 1500) {
+		t.Errorf("Torrons.Rating for the winner = %v, want it unchanged at 1500", torro1Rating)
 	}
-	if match2v3 == nil {
-		t.Fatalf("expected a seed2-vs-seed3 match in round 1, got %+v", round1Matches)
+
+	wantPersonal, _ := rating.UpdateRatings(1500, 1500, true, rating.DefaultEloK)
+	var personal float64
+	if err := db.QueryRowContext(ctx,
+		`SELECT "Rating" FROM "UserEloSnapshots" WHERE "UserId" = $1 AND "TorronId" = $2`,
+		user.Id, torro1Id,
+	).Scan(&personal); err != nil {
+		t.Fatalf("failed to read back the user's snapshot: %v", err)
 	}
-	if match1v4.Status != domain.BracketMatchStatusPending || match2v3.Status != domain.BracketMatchStatusPending {
-		t.Fatalf("expected both round 1 matches to be pending, got %q and %q", match1v4.Status, match2v3.Status)
+	if !floatsClose(personal, wantPersonal) {
+		t.Errorf("personal rating = %v, want %v", personal, wantPersonal)
 	}
+}
 
-	// -- 2. bracketMatchVote: cast a single vote on only ONE of the two
-	// round-1 matches, leaving the other untouched --
+// TestIntegration_UndoVote casts a vote and takes it back through
+// undoVote: the Result, both global ratings, the user's snapshots and vote
+// counts all go back to where they were, and a second undo finds nothing.
+func TestIntegration_UndoVote(t *testing.T) {
+	db := setupIntegrationDB(t)
+	ctx := context.Background()
 
-	voteTarget := fmt.Sprintf("/bracket/match/%s/vote?winner=%s", match1v4.Id, seed1Id)
-	voteReq := newIntegrationRequest(http.MethodPost, voteTarget, map[string]string{"matchId": match1v4.Id}, voterA.Id)
-	voteRec := httptest.NewRecorder()
+	pairingRepo := repository.NewPairingRepo(db)
+	torroRepo := repository.NewTorroRepo(db)
+	userRepo := repository.NewUserRepo(db)
 
-	h.bracketMatchVote(voteRec, voteReq)
+	classId := insertTestClass(t, db, "Undo Test Class")
+	torro1Id := insertTestTorro(t, db, classId, "Torró A", 1500)
+	torro2Id := insertTestTorro(t, db, classId, "Torró B", 1500)
 
-	if voteRec.Code != http.StatusOK {
-		t.Fatalf("bracketMatchVote status = %d, want %d; body: %s", voteRec.Code, http.StatusOK, voteRec.Body.String())
+	pairing, err := pairingRepo.Create(ctx, &domain.Pairing{
+		Torro1: torro1Id,
+		Torro2: torro2Id,
+		Class:  classId,
+	})
+	if err != nil {
+		t.Fatalf("failed to create test pairing: %v", err)
 	}
 
-	// Only one of the two round-1 matches has a vote, so the round isn't
-	// "fully voted" yet -- the bracket must still be sitting at round 1.
-	afterOneVote, err := bracketRepo.Get(ctx, bracket.Id)
+	user, err := userRepo.Create(ctx, &domain.User{Id: uuid.NewString()})
 	if err != nil {
-		t.Fatalf("failed to reload bracket: %v", err)
+		t.Fatalf("failed to create test user: %v", err)
 	}
-	if afterOneVote.CurrentRound != 1 || afterOneVote.Status != domain.BracketStatusInProgress {
-		t.Fatalf("bracket after one partial vote = round %d / %q, want round 1 / %q",
-			afterOneVote.CurrentRound, afterOneVote.Status, domain.BracketStatusInProgress)
+
+	h := &Handler{
+		db:                    db,
+		template:              newIntegrationTemplate(t),
+		bpool:                 bpool.NewBufferPool(8),
+		pairingRepo:           pairingRepo,
+		torroRepo:             torroRepo,
+		classRepo:             repository.NewClassRepo(db),
+		resultRepo:            repository.NewResultRepo(db),
+		userRepo:              userRepo,
+		userEloRepo:           repository.NewUserEloSnapshotRepo(db),
+		campaignRepo:          repository.NewCampaignRepo(db),
+		voteUndoRepo:          repository.NewVoteUndoRepo(db),
+		campaignRatingRepo:    repository.NewCampaignRatingRepo(db),
+		rankingSubmissionRepo: repository.NewRankingSubmissionRepo(db),
+		reasonRepo:            repository.NewReasonRepo(db),
+		similarityRepo:        repository.NewSimilarityRepo(db),
+		tasteTwinRepo:         repository.NewTasteTwinRepo(db),
+		ratingEngine:          rating.NewElo(rating.DefaultEloK),
+		voteWeighting:         trust.NewNone(),
+
+		pairingSelector: matchmaking.NewRandom(pairingRepo),
 	}
 
-	// -- 3. bracketAdvance: force the round through regardless of the
-	// still-untouched match2v3 (0-0, decided by the lower-seed tie-break) --
+	target := fmt.Sprintf("/pairings/%s/vote?id=%s", pairing.Id, torro1Id)
+	req := newIntegrationRequest(http.MethodPost, target, map[string]string{"id": pairing.Id}, user.Id)
+	rec := httptest.NewRecorder()
 
-	advanceReq := newIntegrationRequest(http.MethodPost, "/bracket/"+bracket.Id+"/advance",
-		map[string]string{"bracketId": bracket.Id}, "")
-	advanceRec := httptest.NewRecorder()
+	h.result(rec, req)
 
-	h.bracketAdvance(advanceRec, advanceReq)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("vote status = %d, want %d; body: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
 
-	if advanceRec.Code != http.StatusOK {
-		t.Fatalf("bracketAdvance status = %d, want %d; body: %s", advanceRec.Code, http.StatusOK, advanceRec.Body.String())
+	var resultId string
+	if err := db.QueryRowContext(ctx,
+		`SELECT "Id" FROM "Results" WHERE "Pairing" = $1`, pairing.Id,
+	).Scan(&resultId); err != nil {
+		t.Fatalf("failed to read back the Results row: %v", err)
+	}
+	if !strings.Contains(rec.Body.String(), "/pairings/votes/"+resultId+"/undo") {
+		t.Errorf("vote response has no undo toast for result %s", resultId)
 	}
 
-	var advancedBracket domain.Bracket
-	if err := json.Unmarshal(advanceRec.Body.Bytes(), &advancedBracket); err != nil {
-		t.Fatalf("failed to decode bracketAdvance response: %v", err)
+	undoTarget := fmt.Sprintf("/pairings/votes/%s/undo", resultId)
+	req = newIntegrationRequest(http.MethodPost, undoTarget, map[string]string{"resultId": resultId}, user.Id)
+	rec = httptest.NewRecorder()
+
+	h.undoVote(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("undo status = %d, want %d; body: %s", rec.Code, http.StatusOK, rec.Body.String())
 	}
-	if advancedBracket.CurrentRound != 2 {
-		t.Fatalf("bracket.CurrentRound after advance = %d, want 2", advancedBracket.CurrentRound)
+
+	var results int
+	if err := db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM "Results" WHERE "Pairing" = $1`, pairing.Id,
+	).Scan(&results); err != nil {
+		t.Fatalf("failed to count Results: %v", err)
 	}
-	if advancedBracket.Status != domain.BracketStatusInProgress {
-		t.Fatalf("bracket.Status after advance = %q, want %q", advancedBracket.Status, domain.BracketStatusInProgress)
+	if results != 0 {
+		t.Errorf("Results rows = %d after the undo, want 0", results)
 	}
 
-	// match2v3 was 0-0: the tie-break favours the lower (better) seed, i.e.
-	// seed2 over seed3.
-	round2Matches, err := bracketRepo.ListMatchesByRound(ctx, bracket.Id, 2)
-	if err != nil {
-		t.Fatalf("failed to list round 2 matches: %v", err)
+	for _, id := range []string{torro1Id, torro2Id} {
+		var r float64
+		if err := db.QueryRowContext(ctx, `SELECT "Rating" FROM "Torrons" WHERE "Id" = $1`, id).Scan(&r); err != nil {
+			t.Fatalf("failed to read back torro %s's rating: %v", id, err)
+		}
+		if !floatsClose(r, 1500) {
+			t.Errorf("Torrons.Rating for %s = %v, want 1500 again", id, r)
+		}
 	}
-	if len(round2Matches) != 1 {
-		t.Fatalf("len(round2Matches) = %d, want 1", len(round2Matches))
+
+	var snapshots int
+	if err := db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM "UserEloSnapshots" WHERE "UserId" = $1`, user.Id,
+	).Scan(&snapshots); err != nil {
+		t.Fatalf("failed to count snapshots: %v", err)
 	}
-	final := round2Matches[0]
-	finalTorro2 := ""
-	if final.Torro2Id != nil {
-		finalTorro2 = *final.Torro2Id
+	if snapshots != 0 {
+		t.Errorf("UserEloSnapshots rows = %d after undoing the user's only vote, want 0", snapshots)
 	}
-	gotFinalists := map[string]bool{final.Torro1Id: true, finalTorro2: true}
-	if !gotFinalists[seed1Id] || !gotFinalists[seed2Id] {
-		t.Fatalf("expected the final to be seed1 vs seed2 (seed2 winning its 0-0 tie-break over seed3), got %s vs %s",
-			final.Torro1Id, finalTorro2)
+
+	got, err := userRepo.Get(ctx, user.Id)
+	if err != nil {
+		t.Fatalf("failed to read back the user: %v", err)
 	}
-	if final.Status != domain.BracketMatchStatusPending {
-		t.Fatalf("final match status = %q, want %q", final.Status, domain.BracketMatchStatusPending)
+	if got.VoteCount != 0 || got.CurrentStreak != 0 || got.LastVoteDate != nil {
+		t.Errorf("user after undo = {VoteCount: %d, CurrentStreak: %d, LastVoteDate: %v}, want all reset",
+			got.VoteCount, got.CurrentStreak, got.LastVoteDate)
+	}
+
+	rec = httptest.NewRecorder()
+	h.undoVote(rec, newIntegrationRequest(http.MethodPost, undoTarget, map[string]string{"resultId": resultId}, user.Id))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("second undo status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+// TestIntegration_ReplayDropsPendingUndo checks a rating replay leaves no
+// vote to undo: the undo would restore the ratings from before the replay.
+func TestIntegration_ReplayDropsPendingUndo(t *testing.T) {
+	db := setupIntegrationDB(t)
+	ctx := context.Background()
+
+	pairingRepo := repository.NewPairingRepo(db)
+	torroRepo := repository.NewTorroRepo(db)
+	userRepo := repository.NewUserRepo(db)
+
+	classId := insertTestClass(t, db, "Replay Undo Test Class")
+	torro1Id := insertTestTorro(t, db, classId, "Torró A", 1500)
+	torro2Id := insertTestTorro(t, db, classId, "Torró B", 1500)
+
+	pairing, err := pairingRepo.Create(ctx, &domain.Pairing{
+		Torro1: torro1Id,
+		Torro2: torro2Id,
+		Class:  classId,
+	})
+	if err != nil {
+		t.Fatalf("failed to create test pairing: %v", err)
+	}
+
+	user, err := userRepo.Create(ctx, &domain.User{Id: uuid.NewString()})
+	if err != nil {
+		t.Fatalf("failed to create test user: %v", err)
+	}
+
+	engine := rating.NewElo(rating.DefaultEloK)
+	h := &Handler{
+		db:                    db,
+		template:              newIntegrationTemplate(t),
+		bpool:                 bpool.NewBufferPool(8),
+		pairingRepo:           pairingRepo,
+		torroRepo:             torroRepo,
+		classRepo:             repository.NewClassRepo(db),
+		resultRepo:            repository.NewResultRepo(db),
+		userRepo:              userRepo,
+		userEloRepo:           repository.NewUserEloSnapshotRepo(db),
+		campaignRepo:          repository.NewCampaignRepo(db),
+		voteUndoRepo:          repository.NewVoteUndoRepo(db),
+		campaignRatingRepo:    repository.NewCampaignRatingRepo(db),
+		rankingSubmissionRepo: repository.NewRankingSubmissionRepo(db),
+		reasonRepo:            repository.NewReasonRepo(db),
+		similarityRepo:        repository.NewSimilarityRepo(db),
+		tasteTwinRepo:         repository.NewTasteTwinRepo(db),
+		ratingEngine:          engine,
+		voteWeighting:         trust.NewNone(),
+
+		pairingSelector: matchmaking.NewRandom(pairingRepo),
+	}
+
+	target := fmt.Sprintf("/pairings/%s/vote?id=%s", pairing.Id, torro1Id)
+	rec := httptest.NewRecorder()
+	h.result(rec, newIntegrationRequest(http.MethodPost, target, map[string]string{"id": pairing.Id}, user.Id))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("vote status = %d, want %d; body: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+
+	var resultId string
+	if err := db.QueryRowContext(ctx,
+		`SELECT "Id" FROM "Results" WHERE "Pairing" = $1`, pairing.Id,
+	).Scan(&resultId); err != nil {
+		t.Fatalf("failed to read back the Results row: %v", err)
+	}
+
+	torrons, err := torroRepo.List(ctx)
+	if err != nil {
+		t.Fatalf("failed to list torrons: %v", err)
+	}
+	if _, err := repository.NewReplayRepo(db).Rebuild(ctx, func(votes []*domain.ReplayVote) *domain.Replay {
+		return replay.Run(engine, torrons, votes, replay.Options{})
+	}); err != nil {
+		t.Fatalf("Rebuild() error = %v", err)
+	}
+
+	undoTarget := fmt.Sprintf("/pairings/votes/%s/undo", resultId)
+	rec = httptest.NewRecorder()
+	h.undoVote(rec, newIntegrationRequest(http.MethodPost, undoTarget, map[string]string{"resultId": resultId}, user.Id))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("undo after the replay status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+
+	var results int
+	if err := db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM "Results" WHERE "Pairing" = $1`, pairing.Id,
+	).Scan(&results); err != nil {
+		t.Fatalf("failed to count Results: %v", err)
+	}
+	if results != 1 {
+		t.Errorf("Results rows = %d after the refused undo, want 1", results)
+	}
+}
+
+// TestIntegration_ReplayCommitRefusesChangedResults checks Commit notices
+// a vote taken back and another cast between ListVotes and Commit, which
+// leave the season's Results count as it was.
+func TestIntegration_ReplayCommitRefusesChangedResults(t *testing.T) {
+	db := setupIntegrationDB(t)
+	ctx := context.Background()
+
+	pairingRepo := repository.NewPairingRepo(db)
+	resultRepo := repository.NewResultRepo(db)
+	replayRepo := repository.NewReplayRepo(db)
+
+	classId := insertTestClass(t, db, "Replay Commit Test Class")
+	torro1Id := insertTestTorro(t, db, classId, "Torró A", 1500)
+	torro2Id := insertTestTorro(t, db, classId, "Torró B", 1500)
+
+	pairing, err := pairingRepo.Create(ctx, &domain.Pairing{
+		Torro1: torro1Id,
+		Torro2: torro2Id,
+		Class:  classId,
+	})
+	if err != nil {
+		t.Fatalf("failed to create test pairing: %v", err)
+	}
+
+	vote := func() *domain.Result {
+		t.Helper()
+		res, err := resultRepo.Create(ctx, &domain.Result{
+			Pairing: pairing.Id, Rat1Bef: 1500, Rat2Bef: 1500, Rat1Aft: 1500, Rat2Aft: 1500, Winner: &torro1Id,
+		})
+		if err != nil {
+			t.Fatalf("failed to create a Result: %v", err)
+		}
+		return res
+	}
+	taken := vote()
+
+	votes, err := replayRepo.ListVotes(ctx)
+	if err != nil {
+		t.Fatalf("failed to list votes: %v", err)
+	}
+	torrons, err := repository.NewTorroRepo(db).List(ctx)
+	if err != nil {
+		t.Fatalf("failed to list torrons: %v", err)
+	}
+	result := replay.Run(rating.NewElo(rating.DefaultEloK), torrons, votes, replay.Options{})
+
+	if _, err := db.ExecContext(ctx, `DELETE FROM "Results" WHERE "Id" = $1`, taken.Id); err != nil {
+		t.Fatalf("failed to take the vote back: %v", err)
+	}
+	vote()
+
+	err = replayRepo.Commit(ctx, result)
+	if err == nil || !strings.Contains(err.Error(), string(domain.ValidationError)) {
+		t.Errorf("Commit() error = %v, want a validation error", err)
+	}
+}
+
+// TestIntegration_ResultWeight checks how a Result's weight is stored: a
+// vote that doesn't set one counts in full, a policy's 0 stays 0 and a
+// weight no replay could reproduce is refused.
+func TestIntegration_ResultWeight(t *testing.T) {
+	db := setupIntegrationDB(t)
+	ctx := context.Background()
+
+	pairingRepo := repository.NewPairingRepo(db)
+	resultRepo := repository.NewResultRepo(db)
+
+	classId := insertTestClass(t, db, "Result Weight Test Class")
+	torro1Id := insertTestTorro(t, db, classId, "Torró A", 1500)
+	torro2Id := insertTestTorro(t, db, classId, "Torró B", 1500)
+
+	pairing, err := pairingRepo.Create(ctx, &domain.Pairing{
+		Torro1: torro1Id,
+		Torro2: torro2Id,
+		Class:  classId,
+	})
+	if err != nil {
+		t.Fatalf("failed to create test pairing: %v", err)
+	}
+
+	zero, over := 0.0, 1.5
+	for _, tt := range []struct {
+		name   string
+		weight *float64
+		want   float64
+	}{
+		{"unset", nil, 1},
+		{"zero", &zero, 0},
+	} {
+		res, err := resultRepo.Create(ctx, &domain.Result{Pairing: pairing.Id, Winner: &torro1Id, Weight: tt.weight})
+		if err != nil {
+			t.Fatalf("%s: failed to create a Result: %v", tt.name, err)
+		}
+		var stored float64
+		if err := db.QueryRowContext(ctx, `SELECT "Weight" FROM "Results" WHERE "Id" = $1`, res.Id).Scan(&stored); err != nil {
+			t.Fatalf("%s: failed to read back the weight: %v", tt.name, err)
+		}
+		if stored != tt.want {
+			t.Errorf("%s: stored weight %v, want %v", tt.name, stored, tt.want)
+		}
+	}
+
+	_, err = resultRepo.Create(ctx, &domain.Result{Pairing: pairing.Id, Winner: &torro1Id, Weight: &over})
+	if err == nil || !strings.Contains(err.Error(), string(domain.ValidationError)) {
+		t.Errorf("Create() with weight 1.5 error = %v, want a validation error", err)
+	}
+}
+
+// -- Ranking vote (rank_vote_handler.go) --
+
+func TestIntegration_RankVote(t *testing.T) {
+	db := setupIntegrationDB(t)
+	ctx := context.Background()
+
+	pairingRepo := repository.NewPairingRepo(db)
+	torroRepo := repository.NewTorroRepo(db)
+	userRepo := repository.NewUserRepo(db)
+
+	classId := insertTestClass(t, db, "Rank Test Class")
+	torroA := insertTestTorro(t, db, classId, "Torró A", 1500)
+	torroB := insertTestTorro(t, db, classId, "Torró B", 1500)
+	torroC := insertTestTorro(t, db, classId, "Torró C", 1500)
+
+	all, err := torroRepo.List(ctx)
+	if err != nil {
+		t.Fatalf("failed to list torrons: %v", err)
+	}
+	if _, err := pairingRepo.Reconcile(ctx, classId, catalog.DesiredPairings(classId, all)); err != nil {
+		t.Fatalf("failed to create test pairings: %v", err)
+	}
+
+	user, err := userRepo.Create(ctx, &domain.User{Id: uuid.NewString()})
+	if err != nil {
+		t.Fatalf("failed to create test user: %v", err)
+	}
+
+	h := &Handler{
+		db:                    db,
+		template:              newIntegrationTemplate(t),
+		bpool:                 bpool.NewBufferPool(8),
+		pairingRepo:           pairingRepo,
+		torroRepo:             torroRepo,
+		classRepo:             repository.NewClassRepo(db),
+		resultRepo:            repository.NewResultRepo(db),
+		userRepo:              userRepo,
+		userEloRepo:           repository.NewUserEloSnapshotRepo(db),
+		campaignRepo:          repository.NewCampaignRepo(db),
+		voteUndoRepo:          repository.NewVoteUndoRepo(db),
+		campaignRatingRepo:    repository.NewCampaignRatingRepo(db),
+		rankingSubmissionRepo: repository.NewRankingSubmissionRepo(db),
+		reasonRepo:            repository.NewReasonRepo(db),
+		similarityRepo:        repository.NewSimilarityRepo(db),
+		tasteTwinRepo:         repository.NewTasteTwinRepo(db),
+		ratingEngine:          rating.NewElo(rating.DefaultEloK),
+		voteWeighting:         trust.NewNone(),
+
+		pairingSelector: matchmaking.NewRandom(pairingRepo),
+	}
+
+	rank := func(order ...string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := newIntegrationRequest(http.MethodPost, "/classes/"+classId+"/rank",
+			map[string]string{"id": classId}, user.Id)
+		req.PostForm = url.Values{"torro": order}
+		rec := httptest.NewRecorder()
+		h.rankResult(rec, req)
+		return rec
+	}
+
+	if rec := rank(torroC, torroA); rec.Code != http.StatusBadRequest {
+		t.Errorf("two-torró ranking status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+
+	rec := rank(torroC, torroA, torroB)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("rank status = %d, want %d; body: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+
+	var submissions, results int
+	if err := db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM "RankingSubmissions" WHERE "UserId" = $1`, user.Id,
+	).Scan(&submissions); err != nil {
+		t.Fatalf("failed to count submissions: %v", err)
+	}
+	if err := db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM "Results" WHERE "UserId" = $1 AND "SubmissionId" IS NOT NULL`, user.Id,
+	).Scan(&results); err != nil {
+		t.Fatalf("failed to count Results: %v", err)
+	}
+	if submissions != 1 || results != 3 {
+		t.Errorf("got %d submissions with %d Results, want 1 with 3", submissions, results)
+	}
+
+	ratings := make(map[string]float64)
+	for _, id := range []string{torroA, torroB, torroC} {
+		var r float64
+		if err := db.QueryRowContext(ctx, `SELECT "Rating" FROM "Torrons" WHERE "Id" = $1`, id).Scan(&r); err != nil {
+			t.Fatalf("failed to read back torro %s's rating: %v", id, err)
+		}
+		ratings[id] = r
+	}
+	if !(ratings[torroC] > ratings[torroA] && ratings[torroA] > ratings[torroB]) {
+		t.Errorf("ratings C=%v A=%v B=%v, want C > A > B", ratings[torroC], ratings[torroA], ratings[torroB])
+	}
+
+	got, err := userRepo.Get(ctx, user.Id)
+	if err != nil {
+		t.Fatalf("failed to read back the user: %v", err)
+	}
+	if got.VoteCount != 3 {
+		t.Errorf("user VoteCount = %d, want 3", got.VoteCount)
+	}
+
+	// The whole ranking is one row in the history.
+	rec = httptest.NewRecorder()
+	h.history(rec, newIntegrationRequest(http.MethodGet, "/history", nil, user.Id))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("history status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if n := strings.Count(rec.Body.String(), `class="history-item"`); n != 1 {
+		t.Errorf("history rows = %d, want 1", n)
+	}
+	if !strings.Contains(rec.Body.String(), "Rànquing") {
+		t.Error("history row isn't labelled as a ranking")
+	}
+}
+
+// TestIntegration_RankVoteReplay checks a replay reproduces a ranking
+// vote's live ratings: its six duels share a transaction, and so can share
+// a Timestamp, but they must replay in the order they were applied.
+func TestIntegration_RankVoteReplay(t *testing.T) {
+	db := setupIntegrationDB(t)
+	ctx := context.Background()
+
+	pairingRepo := repository.NewPairingRepo(db)
+	torroRepo := repository.NewTorroRepo(db)
+	userRepo := repository.NewUserRepo(db)
+
+	classId := insertTestClass(t, db, "Rank Replay Test Class")
+	ids := []string{
+		insertTestTorro(t, db, classId, "Torró A", 1500),
+		insertTestTorro(t, db, classId, "Torró B", 1500),
+		insertTestTorro(t, db, classId, "Torró C", 1500),
+		insertTestTorro(t, db, classId, "Torró D", 1500),
+	}
+
+	all, err := torroRepo.List(ctx)
+	if err != nil {
+		t.Fatalf("failed to list torrons: %v", err)
+	}
+	if _, err := pairingRepo.Reconcile(ctx, classId, catalog.DesiredPairings(classId, all)); err != nil {
+		t.Fatalf("failed to create test pairings: %v", err)
+	}
+
+	user, err := userRepo.Create(ctx, &domain.User{Id: uuid.NewString()})
+	if err != nil {
+		t.Fatalf("failed to create test user: %v", err)
+	}
+
+	engine := rating.NewElo(rating.DefaultEloK)
+	h := &Handler{
+		db:                    db,
+		template:              newIntegrationTemplate(t),
+		bpool:                 bpool.NewBufferPool(8),
+		pairingRepo:           pairingRepo,
+		torroRepo:             torroRepo,
+		classRepo:             repository.NewClassRepo(db),
+		resultRepo:            repository.NewResultRepo(db),
+		userRepo:              userRepo,
+		userEloRepo:           repository.NewUserEloSnapshotRepo(db),
+		campaignRepo:          repository.NewCampaignRepo(db),
+		voteUndoRepo:          repository.NewVoteUndoRepo(db),
+		campaignRatingRepo:    repository.NewCampaignRatingRepo(db),
+		rankingSubmissionRepo: repository.NewRankingSubmissionRepo(db),
+		reasonRepo:            repository.NewReasonRepo(db),
+		similarityRepo:        repository.NewSimilarityRepo(db),
+		tasteTwinRepo:         repository.NewTasteTwinRepo(db),
+		ratingEngine:          engine,
+		voteWeighting:         trust.NewNone(),
+
+		pairingSelector: matchmaking.NewRandom(pairingRepo),
+	}
+
+	req := newIntegrationRequest(http.MethodPost, "/classes/"+classId+"/rank",
+		map[string]string{"id": classId}, user.Id)
+	req.PostForm = url.Values{"torro": {ids[2], ids[0], ids[3], ids[1]}}
+	rec := httptest.NewRecorder()
+	h.rankResult(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("rank status = %d, want %d; body: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+
+	votes, err := repository.NewReplayRepo(db).ListVotes(ctx)
+	if err != nil {
+		t.Fatalf("failed to list votes: %v", err)
+	}
+	result := replay.Run(engine, all, votes, replay.Options{})
+
+	type stored struct{ rat1Bef, rat2Bef, rat1Aft, rat2Aft float64 }
+	rows, err := db.QueryContext(ctx,
+		`SELECT "Id", "Torro1RatingBefore", "Torro2RatingBefore", "Torro1RatingAfter", "Torro2RatingAfter"
+		 FROM "Results" WHERE "UserId" = $1`, user.Id)
+	if err != nil {
+		t.Fatalf("failed to read back the Results: %v", err)
+	}
+	live := make(map[string]stored)
+	for rows.Next() {
+		var id string
+		var s stored
+		if err := rows.Scan(&id, &s.rat1Bef, &s.rat2Bef, &s.rat1Aft, &s.rat2Aft); err != nil {
+			t.Fatalf("failed to scan a Result: %v", err)
+		}
+		live[id] = s
+	}
+	rows.Close()
+	if len(live) != 6 {
+		t.Fatalf("got %d Results, want the ranking's 6 duels", len(live))
+	}
+
+	for _, res := range result.Results {
+		s, ok := live[res.ResultId]
+		if !ok {
+			continue
+		}
+		if !floatsClose(res.Rat1Bef, s.rat1Bef) || !floatsClose(res.Rat2Bef, s.rat2Bef) ||
+			!floatsClose(res.Rat1Aft, s.rat1Aft) || !floatsClose(res.Rat2Aft, s.rat2Aft) {
+			t.Errorf("Result %s replayed as %+v, was %+v live", res.ResultId, res, s)
+		}
+	}
+	for _, id := range ids {
+		var r float64
+		if err := db.QueryRowContext(ctx, `SELECT "Rating" FROM "Torrons" WHERE "Id" = $1`, id).Scan(&r); err != nil {
+			t.Fatalf("failed to read back torro %s's rating: %v", id, err)
+		}
+		if !floatsClose(result.Torrons[id].Rating, r) {
+			t.Errorf("torro %s replayed to %v, live %v", id, result.Torrons[id].Rating, r)
+		}
+	}
+}
+
+// -- Pairing reconciliation (internal/catalog, PairingRepo.Reconcile) --
+
+func TestIntegration_VoteReasons(t *testing.T) {
+	db := setupIntegrationDB(t)
+	ctx := context.Background()
+
+	pairingRepo := repository.NewPairingRepo(db)
+	torroRepo := repository.NewTorroRepo(db)
+	userRepo := repository.NewUserRepo(db)
+	resultRepo := repository.NewResultRepo(db)
+	reasonRepo := repository.NewReasonRepo(db)
+
+	classId := insertTestClass(t, db, "Reasons Test Class")
+	torroA := insertTestTorro(t, db, classId, "Torró A", 1500)
+	torroB := insertTestTorro(t, db, classId, "Torró B", 1500)
+
+	all, err := torroRepo.List(ctx)
+	if err != nil {
+		t.Fatalf("failed to list torrons: %v", err)
+	}
+	if _, err := pairingRepo.Reconcile(ctx, classId, catalog.DesiredPairings(classId, all)); err != nil {
+		t.Fatalf("failed to create test pairings: %v", err)
+	}
+	pairings, err := pairingRepo.ListByClass(ctx, classId)
+	if err != nil || len(pairings) != 1 {
+		t.Fatalf("failed to read back the test pairing: %v (%d pairings)", err, len(pairings))
+	}
+
+	user, err := userRepo.Create(ctx, &domain.User{Id: uuid.NewString()})
+	if err != nil {
+		t.Fatalf("failed to create test user: %v", err)
+	}
+	other, err := userRepo.Create(ctx, &domain.User{Id: uuid.NewString()})
+	if err != nil {
+		t.Fatalf("failed to create second test user: %v", err)
+	}
+
+	vote, err := resultRepo.Create(ctx, &domain.Result{
+		Pairing: pairings[0].Id,
+		Rat1Bef: 1500,
+		Rat2Bef: 1500,
+		Winner:  &torroA,
+		Rat1Aft: 1500,
+		Rat2Aft: 1500,
+		UserId:  &user.Id,
+	})
+	if err != nil {
+		t.Fatalf("failed to create test result: %v", err)
+	}
+
+	h := &Handler{
+		db:         db,
+		template:   newIntegrationTemplate(t),
+		bpool:      bpool.NewBufferPool(8),
+		torroRepo:  torroRepo,
+		reasonRepo: reasonRepo,
+	}
+
+	tag := func(userId string, tags ...string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := newIntegrationRequest(http.MethodPost, "/pairings/votes/"+vote.Id+"/reasons",
+			map[string]string{"resultId": vote.Id}, userId)
+		req.PostForm = url.Values{"tag": tags}
+		rec := httptest.NewRecorder()
+		h.voteReasons(rec, req)
+		return rec
+	}
+
+	if rec := tag(user.Id, "sabor-a-fum"); rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown tag status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if rec := tag(other.Id, "textura"); rec.Code != http.StatusNotFound {
+		t.Errorf("someone else's vote status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+
+	if rec := tag(user.Id, "textura", "dolcor"); rec.Code != http.StatusOK {
+		t.Fatalf("tag status = %d, want %d; body: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	rec := tag(user.Id, "textura")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("retag status = %d, want %d; body: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), `value="textura" checked`) {
+		t.Error("picker doesn't show the saved selection")
+	}
+
+	got, err := reasonRepo.ListForResult(ctx, vote.Id)
+	if err != nil {
+		t.Fatalf("failed to read back the reasons: %v", err)
+	}
+	if strings.Join(got, ",") != "textura" {
+		t.Errorf("reasons = %v, want the latest selection [textura]", got)
+	}
+
+	reasons, err := reasonRepo.ListForTorro(ctx, torroB)
+	if err != nil {
+		t.Fatalf("failed to list torró reasons: %v", err)
+	}
+	if len(reasons.Wins) != 0 || len(reasons.Losses) != 1 || reasons.Losses[0].TagId != "textura" {
+		t.Errorf("loser's reasons = %+v, want one textura loss", reasons)
+	}
+}
+
+func TestIntegration_Recommendations(t *testing.T) {
+	db := setupIntegrationDB(t)
+	ctx := context.Background()
+
+	userRepo := repository.NewUserRepo(db)
+	userEloRepo := repository.NewUserEloSnapshotRepo(db)
+
+	classId := insertTestClass(t, db, "Recommendations Test Class")
+	torroA := insertTestTorro(t, db, classId, "Torró A", 1500)
+	torroB := insertTestTorro(t, db, classId, "Torró B", 1500)
+	torroC := insertTestTorro(t, db, classId, "Torró C", 1500)
+
+	rate := func(ratings map[string]float64) string {
+		t.Helper()
+		user, err := userRepo.Create(ctx, &domain.User{Id: uuid.NewString()})
+		if err != nil {
+			t.Fatalf("failed to create test user: %v", err)
+		}
+		for torroId, rating := range ratings {
+			if _, err := userEloRepo.Create(ctx, &domain.UserEloSnapshot{
+				UserId:   user.Id,
+				TorronId: torroId,
+				Rating:   rating,
+			}); err != nil {
+				t.Fatalf("failed to create test snapshot: %v", err)
+			}
+		}
+		return user.Id
+	}
+
+	// Everyone who tried both rates A and B alike and C lower.
+	for i := 0; i < 4; i++ {
+		bump := float64(i * 10)
+		rate(map[string]float64{torroA: 1600 + bump, torroB: 1590 + bump, torroC: 1400 + bump})
+	}
+	newcomer := rate(map[string]float64{torroA: 1650, torroC: 1350})
+
+	h := &Handler{
+		db:             db,
+		torroRepo:      repository.NewTorroRepo(db),
+		userEloRepo:    userEloRepo,
+		similarityRepo: repository.NewSimilarityRepo(db),
+	}
+	if err := h.refitSimilarities(ctx); err != nil {
+		t.Fatalf("failed to fit similarities: %v", err)
+	}
+
+	req := newIntegrationRequest(http.MethodGet, "/api/user/recommendations?limit=50", nil, newcomer)
+	rec := httptest.NewRecorder()
+	h.handleUserRecommendations(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d; body: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+
+	var resp RecommendationsResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	var b *domain.Recommendation
+	for _, r := range resp.Recommendations {
+		switch r.TorroId {
+		case torroA, torroC:
+			t.Errorf("recommended %s, which the user already rated", r.Name)
+		case torroB:
+			b = r
+		}
+	}
+	if b == nil {
+		t.Fatalf("recommendations = %+v, want Torró B among them", resp.Recommendations)
+	}
+	if b.Basis != domain.RecommendationCollaborative || b.BecauseId != torroA {
+		t.Errorf("Torró B = %+v, want a collaborative recommendation because of Torró A", b)
+	}
+
+	req = newIntegrationRequest(http.MethodGet, "/api/user/recommendations?limit=0", nil, newcomer)
+	rec = httptest.NewRecorder()
+	h.handleUserRecommendations(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("limit=0 status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestIntegration_TasteTwins(t *testing.T) {
+	db := setupIntegrationDB(t)
+	ctx := context.Background()
+
+	userRepo := repository.NewUserRepo(db)
+	userEloRepo := repository.NewUserEloSnapshotRepo(db)
+
+	classId := insertTestClass(t, db, "Taste Twins Test Class")
+	var ids []string
+	for _, name := range []string{"A", "B", "C", "D", "E", "X"} {
+		ids = append(ids, insertTestTorro(t, db, classId, "Torró "+name, 1500))
+	}
+	shared, favourite := ids[:5], ids[5]
+
+	// rate gives a new user a snapshot of each torró, best first.
+	rate := func(best ...string) string {
+		t.Helper()
+		user, err := userRepo.Create(ctx, &domain.User{Id: uuid.NewString()})
+		if err != nil {
+			t.Fatalf("failed to create test user: %v", err)
+		}
+		for i, torroId := range best {
+			if _, err := userEloRepo.Create(ctx, &domain.UserEloSnapshot{
+				UserId:   user.Id,
+				TorronId: torroId,
+				Rating:   1700 - float64(i*40),
+			}); err != nil {
+				t.Fatalf("failed to create test snapshot: %v", err)
+			}
+		}
+		return user.Id
+	}
+
+	me := rate(shared...)
+	rate(append([]string{favourite}, shared...)...)
+	rate(append([]string{favourite}, shared...)...)
+	rate(shared[4], shared[3], shared[2], shared[1], shared[0])
+
+	h := &Handler{
+		db:            db,
+		torroRepo:     repository.NewTorroRepo(db),
+		userEloRepo:   userEloRepo,
+		tasteTwinRepo: repository.NewTasteTwinRepo(db),
+	}
+
+	req := newIntegrationRequest(http.MethodGet, "/api/user/twins", nil, me)
+	rec := httptest.NewRecorder()
+	h.handleUserTasteTwins(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d; body: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+
+	var twins domain.TasteTwins
+	if err := json.Unmarshal(rec.Body.Bytes(), &twins); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if twins.Compared != 3 || twins.Twins != 2 {
+		t.Errorf("compared %d, twins %d, want 3 and 2", twins.Compared, twins.Twins)
+	}
+	if twins.Opposite == nil || twins.Opposite.SharedTorrons != 5 {
+		t.Errorf("opposite = %+v, want one over 5 torrons", twins.Opposite)
+	}
+	if len(twins.Picks) != 1 || twins.Picks[0].TorroId != favourite || twins.Picks[0].Twins != 2 {
+		t.Errorf("picks = %+v, want Torró X from 2 twins", twins.Picks)
+	}
+	if strings.Contains(rec.Body.String(), "user_id") {
+		t.Error("the response must not identify anyone")
+	}
+}
+
+func TestIntegration_ProvisionalLeaderboard(t *testing.T) {
+	db := setupIntegrationDB(t)
+	ctx := context.Background()
+
+	userRepo := repository.NewUserRepo(db)
+	userEloRepo := repository.NewUserEloSnapshotRepo(db)
+
+	classId := insertTestClass(t, db, "Provisional Test Class")
+	favourite := insertTestTorro(t, db, classId, "Torró Preferit", 1500)
+	popular := insertTestTorro(t, db, classId, "Torró Popular", 1600)
+
+	user, err := userRepo.Create(ctx, &domain.User{Id: uuid.NewString()})
+	if err != nil {
+		t.Fatalf("failed to create test user: %v", err)
+	}
+	if _, err := userEloRepo.Create(ctx, &domain.UserEloSnapshot{
+		UserId:    user.Id,
+		TorronId:  favourite,
+		Rating:    1800,
+		VoteCount: 20,
+	}); err != nil {
+		t.Fatalf("failed to create test snapshot: %v", err)
+	}
+
+	h := &Handler{
+		db:          db,
+		classRepo:   repository.NewClassRepo(db),
+		torroRepo:   repository.NewTorroRepo(db),
+		userRepo:    userRepo,
+		userEloRepo: userEloRepo,
+	}
+
+	req := newIntegrationRequest(http.MethodGet, "/api/user/leaderboard/provisional/class/"+classId,
+		map[string]string{"classId": classId}, user.Id)
+	rec := httptest.NewRecorder()
+	h.handleUserProvisionalLeaderboard(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d; body: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+
+	var resp struct {
+		MinVotesMet bool                       `json:"min_votes_met"`
+		Confidence  string                     `json:"confidence"`
+		Entries     []*domain.ProvisionalEntry `json:"entries"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.MinVotesMet || resp.Confidence != domain.ConfidenceLow {
+		t.Errorf("min votes met %v, confidence %s, want a locked, low-confidence view", resp.MinVotesMet, resp.Confidence)
+	}
+	if len(resp.Entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(resp.Entries))
+	}
+
+	// 20 votes at 1800 over a prior of 1500: 0.8 * 1800 + 0.2 * 1500.
+	first, second := resp.Entries[0], resp.Entries[1]
+	if first.TorronId != favourite || math.Abs(first.Rating-1740) > 1e-9 || first.Weight != 0.8 {
+		t.Errorf("first = %+v, want the favourite at 1740, weighted 0.8", first)
+	}
+	if second.TorronId != popular || second.Weight != 0 || second.PersonalRating != nil {
+		t.Errorf("second = %+v, want the unrated torró on its global rating", second)
+	}
+}
+
+func TestIntegration_ReconcilePairings(t *testing.T) {
+	db := setupIntegrationDB(t)
+	ctx := context.Background()
+
+	pairingRepo := repository.NewPairingRepo(db)
+	torroRepo := repository.NewTorroRepo(db)
+
+	classId := insertTestClass(t, db, "Reconcile Test Class")
+	torroA := insertTestTorro(t, db, classId, "Torró A", 1500)
+	insertTestTorro(t, db, classId, "Torró B", 1500)
+	insertTestTorro(t, db, classId, "Torró C", 1500)
+
+	reconcile := func() *domain.PairingReconciliation {
+		t.Helper()
+		all, err := torroRepo.List(ctx)
+		if err != nil {
+			t.Fatalf("failed to list torrons: %v", err)
+		}
+		summary, err := pairingRepo.Reconcile(ctx, classId, catalog.DesiredPairings(classId, all))
+		if err != nil {
+			t.Fatalf("failed to reconcile: %v", err)
+		}
+		return summary
+	}
+	activeCount := func() int {
+		t.Helper()
+		n, err := pairingRepo.CountClass(ctx, classId)
+		if err != nil {
+			t.Fatalf("failed to count pairings: %v", err)
+		}
+		return n
+	}
+
+	if s := reconcile(); s.Desired != 3 || s.Inserted != 3 || s.Retired != 0 {
+		t.Fatalf("first reconcile = %+v, want 3 inserted", s)
+	}
+	if s := reconcile(); s.Changed() {
+		t.Errorf("second reconcile = %+v, want no changes", s)
+	}
+
+	// Discontinuing A retires its two pairings, without deleting them.
+	if _, err := db.Exec(`UPDATE "Torrons" SET "Discontinued" = TRUE WHERE "Id" = $1`, torroA); err != nil {
+		t.Fatalf("failed to discontinue torro: %v", err)
+	}
+	if s := reconcile(); s.Retired != 2 || s.Inserted != 0 {
+		t.Errorf("reconcile after discontinuing = %+v, want 2 retired", s)
+	}
+	if n := activeCount(); n != 1 {
+		t.Errorf("active pairings = %d, want 1", n)
+	}
+	var total int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM "Pairings" WHERE "Class" = $1`, classId).Scan(&total); err != nil {
+		t.Fatalf("failed to count all pairings: %v", err)
+	}
+	if total != 3 {
+		t.Errorf("pairings incl. retired = %d, want 3", total)
+	}
+
+	// Bringing A back revives them, and a new torró gets its matchups.
+	if _, err := db.Exec(`UPDATE "Torrons" SET "Discontinued" = FALSE WHERE "Id" = $1`, torroA); err != nil {
+		t.Fatalf("failed to restore torro: %v", err)
+	}
+	insertTestTorro(t, db, classId, "Torró D", 1500)
+	if s := reconcile(); s.Revived != 2 || s.Inserted != 3 || s.Retired != 0 {
+		t.Errorf("reconcile after restoring = %+v, want 2 revived and 3 inserted", s)
+	}
+	if n := activeCount(); n != 6 {
+		t.Errorf("active pairings = %d, want 6", n)
+	}
+}
+
+// -- Full bracket lifecycle (bracket_handler.go) --
+
+func TestIntegration_BracketLifecycle(t *testing.T) {
+	db := setupIntegrationDB(t)
+	ctx := context.Background()
+
+	torroRepo := repository.NewTorroRepo(db)
+	classRepo := repository.NewClassRepo(db)
+	campaignRepo := repository.NewCampaignRepo(db)
+	bracketRepo := repository.NewBracketRepo(db)
+	userRepo := repository.NewUserRepo(db)
+
+	// Torrons.Class is never literally "5" (the real "Global" pseudo-class
+	// convention) in production; a plain, non-Global class ID like this one
+	// is exactly what the rest of the app uses for every real category, so
+	// there's nothing special to work around here.
+	classId := insertTestClass(t, db, "Bracket Lifecycle Test Class")
+
+	// Seed 4 torrons with distinct ratings so the rating seed order is
+	// deterministic: seed 1 (highest) .. seed 4 (lowest).
+	seed1Id := insertTestTorro(t, db, classId, "Seed 1", 1600)
+	seed2Id := insertTestTorro(t, db, classId, "Seed 2", 1550)
+	seed3Id := insertTestTorro(t, db, classId, "Seed 3", 1500)
+	seed4Id := insertTestTorro(t, db, classId, "Seed 4", 1450)
+
+	now := time.Now().UTC()
+	campaign, err := campaignRepo.Create(ctx, &domain.Campaign{
+		Name:      "Bracket Lifecycle Test Campaign",
+		StartDate: now.Add(-1 * time.Hour).Format(time.RFC3339),
+		EndDate:   now.Add(1 * time.Hour).Format(time.RFC3339),
+		Year:      now.Year(),
+		Status:    domain.CampaignStatusActive,
+	})
+	if err != nil {
+		t.Fatalf("failed to create test campaign: %v", err)
+	}
+
+	voterA, err := userRepo.Create(ctx, &domain.User{Id: uuid.NewString()})
+	if err != nil {
+		t.Fatalf("failed to create voter A: %v", err)
+	}
+	voterB, err := userRepo.Create(ctx, &domain.User{Id: uuid.NewString()})
+	if err != nil {
+		t.Fatalf("failed to create voter B: %v", err)
+	}
+
+	h := &Handler{
+		db:           db,
+		template:     newIntegrationTemplate(t),
+		bpool:        bpool.NewBufferPool(8),
+		torroRepo:    torroRepo,
+		classRepo:    classRepo,
+		campaignRepo: campaignRepo,
+		bracketRepo:  bracketRepo,
+	}
+
+	// -- 1. bracketCreate: seed a size-4 bracket --
+
+	createTarget := fmt.Sprintf("/bracket/%s/create?size=4", classId)
+	createReq := newIntegrationRequest(http.MethodPost, createTarget, map[string]string{"classId": classId}, "")
+	createRec := httptest.NewRecorder()
+
+	h.bracketCreate(createRec, createReq)
+
+	if createRec.Code != http.StatusCreated {
+		t.Fatalf("bracketCreate status = %d, want %d; body: %s", createRec.Code, http.StatusCreated, createRec.Body.String())
+	}
+
+	var bracket domain.Bracket
+	if err := json.Unmarshal(createRec.Body.Bytes(), &bracket); err != nil {
+		t.Fatalf("failed to decode bracketCreate response: %v", err)
+	}
+
+	if bracket.ClassId != classId {
+		t.Errorf("bracket.ClassId = %q, want %q", bracket.ClassId, classId)
+	}
+	if bracket.CampaignId != campaign.Id {
+		t.Errorf("bracket.CampaignId = %q, want %q", bracket.CampaignId, campaign.Id)
+	}
+	if bracket.Size != 4 {
+		t.Errorf("bracket.Size = %d, want 4", bracket.Size)
+	}
+	if bracket.CurrentRound != 1 {
+		t.Errorf("bracket.CurrentRound = %d, want 1", bracket.CurrentRound)
+	}
+	if bracket.Status != domain.BracketStatusInProgress {
+		t.Errorf("bracket.Status = %q, want %q", bracket.Status, domain.BracketStatusInProgress)
+	}
+
+	entries, err := bracketRepo.ListEntries(ctx, bracket.Id)
+	if err != nil {
+		t.Fatalf("failed to list bracket entries: %v", err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("len(entries) = %d, want 4", len(entries))
+	}
+	seedByTorro := make(map[string]int, len(entries))
+	for _, e := range entries {
+		seedByTorro[e.TorronId] = e.Seed
+	}
+	if seedByTorro[seed1Id] != 1 || seedByTorro[seed2Id] != 2 || seedByTorro[seed3Id] != 3 || seedByTorro[seed4Id] != 4 {
+		t.Fatalf("unexpected seeding: %+v", seedByTorro)
+	}
+
+	round1Matches, err := bracketRepo.ListMatchesByRound(ctx, bracket.Id, 1)
+	if err != nil {
+		t.Fatalf("failed to list round 1 matches: %v", err)
+	}
+	if len(round1Matches) != 2 {
+		t.Fatalf("len(round1Matches) = %d, want 2", len(round1Matches))
+	}
+
+	// Standard seeding for a field of 4 is 1v4, 2v3.
+	matchOf := func(matches []*domain.BracketMatch, a, b string) *domain.BracketMatch {
+		for _, m := range matches {
+			t2 := ""
+			if m.Torro2Id != nil {
+				t2 = *m.Torro2Id
+			}
+			if (m.Torro1Id == a && t2 == b) || (m.Torro1Id == b && t2 == a) {
+				return m
+			}
+		}
+		return nil
+	}
+
+	match1v4 := matchOf(round1Matches, seed1Id, seed4Id)
+	match2v3 := matchOf(round1Matches, seed2Id, seed3Id)
+	if match1v4 == nil {
+		t.Fatalf("expected a seed1-vs-seed4 match in round 1, got %+v", round1Matches)
+	}
+	if match2v3 == nil {
+		t.Fatalf("expected a seed2-vs-seed3 match in round 1, got %+v", round1Matches)
+	}
+	if match1v4.Status != domain.BracketMatchStatusPending || match2v3.Status != domain.BracketMatchStatusPending {
+		t.Fatalf("expected both round 1 matches to be pending, got %q and %q", match1v4.Status, match2v3.Status)
+	}
+
+	// -- 2. bracketMatchVote: cast a single vote on only ONE of the two
+	// round-1 matches, leaving the other untouched --
+
+	voteTarget := fmt.Sprintf("/bracket/match/%s/vote?winner=%s", match1v4.Id, seed1Id)
+	voteReq := newIntegrationRequest(http.MethodPost, voteTarget, map[string]string{"matchId": match1v4.Id}, voterA.Id)
+	voteRec := httptest.NewRecorder()
+
+	h.bracketMatchVote(voteRec, voteReq)
+
+	if voteRec.Code != http.StatusOK {
+		t.Fatalf("bracketMatchVote status = %d, want %d; body: %s", voteRec.Code, http.StatusOK, voteRec.Body.String())
+	}
+
+	// Only one of the two round-1 matches has a vote, so the round isn't
+	// "fully voted" yet -- the bracket must still be sitting at round 1.
+	afterOneVote, err := bracketRepo.Get(ctx, bracket.Id)
+	if err != nil {
+		t.Fatalf("failed to reload bracket: %v", err)
+	}
+	if afterOneVote.CurrentRound != 1 || afterOneVote.Status != domain.BracketStatusInProgress {
+		t.Fatalf("bracket after one partial vote = round %d / %q, want round 1 / %q",
+			afterOneVote.CurrentRound, afterOneVote.Status, domain.BracketStatusInProgress)
+	}
+
+	// -- 3. bracketAdvance: force the round through regardless of the
+	// still-untouched match2v3 (0-0, decided by the lower-seed tie-break) --
+
+	advanceReq := newIntegrationRequest(http.MethodPost, "/bracket/"+bracket.Id+"/advance",
+		map[string]string{"bracketId": bracket.Id}, "")
+	advanceRec := httptest.NewRecorder()
+
+	h.bracketAdvance(advanceRec, advanceReq)
+
+	if advanceRec.Code != http.StatusOK {
+		t.Fatalf("bracketAdvance status = %d, want %d; body: %s", advanceRec.Code, http.StatusOK, advanceRec.Body.String())
+	}
+
+	var advancedBracket domain.Bracket
+	if err := json.Unmarshal(advanceRec.Body.Bytes(), &advancedBracket); err != nil {
+		t.Fatalf("failed to decode bracketAdvance response: %v", err)
+	}
+	if advancedBracket.CurrentRound != 2 {
+		t.Fatalf("bracket.CurrentRound after advance = %d, want 2", advancedBracket.CurrentRound)
+	}
+	if advancedBracket.Status != domain.BracketStatusInProgress {
+		t.Fatalf("bracket.Status after advance = %q, want %q", advancedBracket.Status, domain.BracketStatusInProgress)
+	}
+
+	// match2v3 was 0-0: the tie-break favours the lower (better) seed, i.e.
+	// seed2 over seed3.
+	round2Matches, err := bracketRepo.ListMatchesByRound(ctx, bracket.Id, 2)
+	if err != nil {
+		t.Fatalf("failed to list round 2 matches: %v", err)
+	}
+	if len(round2Matches) != 1 {
+		t.Fatalf("len(round2Matches) = %d, want 1", len(round2Matches))
+	}
+	final := round2Matches[0]
+	finalTorro2 := ""
+	if final.Torro2Id != nil {
+		finalTorro2 = *final.Torro2Id
+	}
+	gotFinalists := map[string]bool{final.Torro1Id: true, finalTorro2: true}
+	if !gotFinalists[seed1Id] || !gotFinalists[seed2Id] {
+		t.Fatalf("expected the final to be seed1 vs seed2 (seed2 winning its 0-0 tie-break over seed3), got %s vs %s",
+			final.Torro1Id, finalTorro2)
+	}
+	if final.Status != domain.BracketMatchStatusPending {
+		t.Fatalf("final match status = %q, want %q", final.Status, domain.BracketMatchStatusPending)
 	}
 
 	// -- 4. bracketMatchVote on the final: this is the bracket's only
 	// round-2 match, so a single vote fully-votes the round and should
 	// cascade the bracket straight to completed --
 
-	finalVoteTarget := fmt.Sprintf("/bracket/match/%s/vote?winner=%s", final.Id, seed1Id)
-	finalVoteReq := newIntegrationRequest(http.MethodPost, finalVoteTarget, map[string]string{"matchId": final.Id}, voterB.Id)
-	finalVoteRec := httptest.NewRecorder()
+	finalVoteTarget := fmt.Sprintf("/bracket/match/%s/vote?winner=%s", final.Id, seed1Id)
+	finalVoteReq := newIntegrationRequest(http.MethodPost, finalVoteTarget, map[string]string{"matchId": final.Id}, voterB.Id)
+	finalVoteRec := httptest.NewRecorder()
+
+	h.bracketMatchVote(finalVoteRec, finalVoteReq)
+
+	if finalVoteRec.Code != http.StatusOK {
+		t.Fatalf("final bracketMatchVote status = %d, want %d; body: %s", finalVoteRec.Code, http.StatusOK, finalVoteRec.Body.String())
+	}
+
+	completed, err := bracketRepo.Get(ctx, bracket.Id)
+	if err != nil {
+		t.Fatalf("failed to reload bracket after final vote: %v", err)
+	}
+	if completed.Status != domain.BracketStatusCompleted {
+		t.Fatalf("bracket.Status after final vote = %q, want %q", completed.Status, domain.BracketStatusCompleted)
+	}
+	if completed.ChampionId == nil || *completed.ChampionId != seed1Id {
+		t.Fatalf("bracket.ChampionId = %v, want %q", completed.ChampionId, seed1Id)
+	}
+	if completed.CompletedAt == nil {
+		t.Error("expected bracket.CompletedAt to be set once completed")
+	}
+}
+
+func TestIntegration_DoubleEliminationBracket(t *testing.T) {
+	db := setupIntegrationDB(t)
+	ctx := context.Background()
+
+	campaignRepo := repository.NewCampaignRepo(db)
+	bracketRepo := repository.NewBracketRepo(db)
+
+	classId := insertTestClass(t, db, "Double Elimination Test Class")
+	seed1Id := insertTestTorro(t, db, classId, "Seed 1", 1600)
+	seed2Id := insertTestTorro(t, db, classId, "Seed 2", 1550)
+	insertTestTorro(t, db, classId, "Seed 3", 1500)
+	insertTestTorro(t, db, classId, "Seed 4", 1450)
+
+	now := time.Now().UTC()
+	if _, err := campaignRepo.Create(ctx, &domain.Campaign{
+		Name:      "Double Elimination Test Campaign",
+		StartDate: now.Add(-1 * time.Hour).Format(time.RFC3339),
+		EndDate:   now.Add(1 * time.Hour).Format(time.RFC3339),
+		Year:      now.Year(),
+		Status:    domain.CampaignStatusActive,
+	}); err != nil {
+		t.Fatalf("failed to create test campaign: %v", err)
+	}
+
+	h := &Handler{
+		db:           db,
+		template:     newIntegrationTemplate(t),
+		bpool:        bpool.NewBufferPool(8),
+		torroRepo:    repository.NewTorroRepo(db),
+		classRepo:    repository.NewClassRepo(db),
+		campaignRepo: campaignRepo,
+		bracketRepo:  bracketRepo,
+	}
+
+	// A double-elimination bracket needs a losers side to drop into.
+	tooSmallReq := newIntegrationRequest(http.MethodPost,
+		fmt.Sprintf("/bracket/%s/create?size=2&format=double_elimination", classId),
+		map[string]string{"classId": classId}, "")
+	tooSmallRec := httptest.NewRecorder()
+	h.bracketCreate(tooSmallRec, tooSmallReq)
+	if tooSmallRec.Code != http.StatusBadRequest {
+		t.Fatalf("size-2 double elimination status = %d, want %d", tooSmallRec.Code, http.StatusBadRequest)
+	}
+
+	createReq := newIntegrationRequest(http.MethodPost,
+		fmt.Sprintf("/bracket/%s/create?size=4&format=double_elimination", classId),
+		map[string]string{"classId": classId}, "")
+	createRec := httptest.NewRecorder()
+	h.bracketCreate(createRec, createReq)
+	if createRec.Code != http.StatusCreated {
+		t.Fatalf("bracketCreate status = %d, want %d; body: %s", createRec.Code, http.StatusCreated, createRec.Body.String())
+	}
+
+	var bracket domain.Bracket
+	if err := json.Unmarshal(createRec.Body.Bytes(), &bracket); err != nil {
+		t.Fatalf("failed to decode bracketCreate response: %v", err)
+	}
+	if bracket.Format != domain.BracketFormatDoubleElimination || !bracket.GrandFinalReset {
+		t.Fatalf("bracket format = %q, reset = %v, want double elimination with a reset", bracket.Format, bracket.GrandFinalReset)
+	}
+
+	// Force every round through: 0-0 matches go to the better seed, so the
+	// favourites win throughout and the grand final needs no reset.
+	// Rounds: winners 1, winners final + losers 1, losers final, grand final.
+	for round := 1; round <= 4; round++ {
+		advanceReq := newIntegrationRequest(http.MethodPost, "/bracket/"+bracket.Id+"/advance",
+			map[string]string{"bracketId": bracket.Id}, "")
+		advanceRec := httptest.NewRecorder()
+		h.bracketAdvance(advanceRec, advanceReq)
+		if advanceRec.Code != http.StatusOK {
+			t.Fatalf("bracketAdvance in round %d status = %d; body: %s", round, advanceRec.Code, advanceRec.Body.String())
+		}
+	}
+
+	completed, err := bracketRepo.Get(ctx, bracket.Id)
+	if err != nil {
+		t.Fatalf("failed to reload bracket: %v", err)
+	}
+	if completed.Status != domain.BracketStatusCompleted || completed.ChampionId == nil || *completed.ChampionId != seed1Id {
+		t.Fatalf("bracket = %q, champion %v, want completed with seed 1", completed.Status, completed.ChampionId)
+	}
+
+	matches, err := bracketRepo.ListMatches(ctx, bracket.Id)
+	if err != nil {
+		t.Fatalf("failed to list matches: %v", err)
+	}
+	perSide := make(map[string]int)
+	for _, m := range matches {
+		perSide[m.Side]++
+	}
+	if perSide[domain.BracketSideWinners] != 3 || perSide[domain.BracketSideLosers] != 2 || perSide[domain.BracketSideGrandFinal] != 1 {
+		t.Fatalf("matches per side = %v, want 3 winners, 2 losers, 1 grand final", perSide)
+	}
+	grandFinal := matches[len(matches)-1]
+	if grandFinal.Side != domain.BracketSideGrandFinal || grandFinal.Torro1Id != seed1Id || grandFinal.Torro2Id == nil || *grandFinal.Torro2Id != seed2Id {
+		t.Fatalf("grand final = %+v, want seed 1 v seed 2", grandFinal)
+	}
+
+	overviewReq := newIntegrationRequest(http.MethodGet, "/bracket/"+classId, map[string]string{"classId": classId}, "")
+	overviewRec := httptest.NewRecorder()
+	h.bracketOverview(overviewRec, overviewReq)
+	if overviewRec.Code != http.StatusOK {
+		t.Fatalf("bracketOverview status = %d, want %d", overviewRec.Code, http.StatusOK)
+	}
+	for _, want := range []string{"Quadre de guanyadors", "Quadre de perdedors", "Final de perdedors", "Gran Final"} {
+		if !strings.Contains(overviewRec.Body.String(), want) {
+			t.Errorf("overview is missing %q", want)
+		}
+	}
+}
+
+func TestIntegration_SwissBracket(t *testing.T) {
+	db := setupIntegrationDB(t)
+	ctx := context.Background()
+
+	campaignRepo := repository.NewCampaignRepo(db)
+	bracketRepo := repository.NewBracketRepo(db)
+
+	classId := insertTestClass(t, db, "Swiss Test Class")
+	seed1Id := insertTestTorro(t, db, classId, "Seed 1", 1600)
+	insertTestTorro(t, db, classId, "Seed 2", 1550)
+	insertTestTorro(t, db, classId, "Seed 3", 1500)
+	insertTestTorro(t, db, classId, "Seed 4", 1450)
+	insertTestTorro(t, db, classId, "Seed 5", 1400)
+
+	now := time.Now().UTC()
+	if _, err := campaignRepo.Create(ctx, &domain.Campaign{
+		Name:      "Swiss Test Campaign",
+		StartDate: now.Add(-1 * time.Hour).Format(time.RFC3339),
+		EndDate:   now.Add(1 * time.Hour).Format(time.RFC3339),
+		Year:      now.Year(),
+		Status:    domain.CampaignStatusActive,
+	}); err != nil {
+		t.Fatalf("failed to create test campaign: %v", err)
+	}
+
+	h := &Handler{
+		db:           db,
+		template:     newIntegrationTemplate(t),
+		bpool:        bpool.NewBufferPool(8),
+		torroRepo:    repository.NewTorroRepo(db),
+		classRepo:    repository.NewClassRepo(db),
+		campaignRepo: campaignRepo,
+		bracketRepo:  bracketRepo,
+	}
+
+	// Five torrons can't meet everyone in more than five rounds.
+	tooManyReq := newIntegrationRequest(http.MethodPost,
+		fmt.Sprintf("/bracket/%s/create?format=swiss&rounds=6", classId),
+		map[string]string{"classId": classId}, "")
+	tooManyRec := httptest.NewRecorder()
+	h.bracketCreate(tooManyRec, tooManyReq)
+	if tooManyRec.Code != http.StatusBadRequest {
+		t.Fatalf("six-round Swiss status = %d, want %d", tooManyRec.Code, http.StatusBadRequest)
+	}
+
+	// No size: the whole class, not padded to a power of two.
+	createReq := newIntegrationRequest(http.MethodPost,
+		fmt.Sprintf("/bracket/%s/create?format=swiss", classId),
+		map[string]string{"classId": classId}, "")
+	createRec := httptest.NewRecorder()
+	h.bracketCreate(createRec, createReq)
+	if createRec.Code != http.StatusCreated {
+		t.Fatalf("bracketCreate status = %d, want %d; body: %s", createRec.Code, http.StatusCreated, createRec.Body.String())
+	}
+
+	var bracket domain.Bracket
+	if err := json.Unmarshal(createRec.Body.Bytes(), &bracket); err != nil {
+		t.Fatalf("failed to decode bracketCreate response: %v", err)
+	}
+	if bracket.Format != domain.BracketFormatSwiss || bracket.Size != 5 || bracket.Rounds != 3 {
+		t.Fatalf("bracket = %q, size %d, %d rounds, want swiss, 5, 3", bracket.Format, bracket.Size, bracket.Rounds)
+	}
+
+	round1, err := bracketRepo.ListMatchesByRound(ctx, bracket.Id, 1)
+	if err != nil {
+		t.Fatalf("failed to list round 1 matches: %v", err)
+	}
+	if len(round1) != 3 || !round1[2].IsBye() {
+		t.Fatalf("round 1 = %d matches, want two pairings and a bye", len(round1))
+	}
+
+	// 0-0 matches go to the better seed, so seed 1 wins every round.
+	for round := 1; round <= 3; round++ {
+		advanceReq := newIntegrationRequest(http.MethodPost, "/bracket/"+bracket.Id+"/advance",
+			map[string]string{"bracketId": bracket.Id}, "")
+		advanceRec := httptest.NewRecorder()
+		h.bracketAdvance(advanceRec, advanceReq)
+		if advanceRec.Code != http.StatusOK {
+			t.Fatalf("bracketAdvance in round %d status = %d; body: %s", round, advanceRec.Code, advanceRec.Body.String())
+		}
+	}
+
+	completed, err := bracketRepo.Get(ctx, bracket.Id)
+	if err != nil {
+		t.Fatalf("failed to reload bracket: %v", err)
+	}
+	if completed.Status != domain.BracketStatusCompleted || completed.ChampionId == nil || *completed.ChampionId != seed1Id {
+		t.Fatalf("bracket = %q, champion %v, want completed with seed 1", completed.Status, completed.ChampionId)
+	}
+
+	matches, err := bracketRepo.ListMatches(ctx, bracket.Id)
+	if err != nil {
+		t.Fatalf("failed to list matches: %v", err)
+	}
+	seen := make(map[[2]string]bool)
+	for _, m := range matches {
+		if m.IsBye() {
+			continue
+		}
+		if seen[[2]string{m.Torro1Id, *m.Torro2Id}] || seen[[2]string{*m.Torro2Id, m.Torro1Id}] {
+			t.Errorf("rematch in round %d: %s v %s", m.Round, m.Torro1Id, *m.Torro2Id)
+		}
+		seen[[2]string{m.Torro1Id, *m.Torro2Id}] = true
+	}
+
+	overviewReq := newIntegrationRequest(http.MethodGet, "/bracket/"+classId, map[string]string{"classId": classId}, "")
+	overviewRec := httptest.NewRecorder()
+	h.bracketOverview(overviewRec, overviewReq)
+	if overviewRec.Code != http.StatusOK {
+		t.Fatalf("bracketOverview status = %d, want %d", overviewRec.Code, http.StatusOK)
+	}
+	if !strings.Contains(overviewRec.Body.String(), "Classificació") {
+		t.Error("overview is missing the Swiss standings")
+	}
+}
+
+func TestIntegration_GroupsKnockoutBracket(t *testing.T) {
+	db := setupIntegrationDB(t)
+	ctx := context.Background()
+
+	campaignRepo := repository.NewCampaignRepo(db)
+	bracketRepo := repository.NewBracketRepo(db)
+
+	classId := insertTestClass(t, db, "Groups Test Class")
+	seed1Id := insertTestTorro(t, db, classId, "Seed 1", 1600)
+	seed2Id := insertTestTorro(t, db, classId, "Seed 2", 1550)
+	insertTestTorro(t, db, classId, "Seed 3", 1500)
+	insertTestTorro(t, db, classId, "Seed 4", 1450)
+	insertTestTorro(t, db, classId, "Seed 5", 1400)
+	insertTestTorro(t, db, classId, "Seed 6", 1350)
+
+	now := time.Now().UTC()
+	if _, err := campaignRepo.Create(ctx, &domain.Campaign{
+		Name:      "Groups Test Campaign",
+		StartDate: now.Add(-1 * time.Hour).Format(time.RFC3339),
+		EndDate:   now.Add(1 * time.Hour).Format(time.RFC3339),
+		Year:      now.Year(),
+		Status:    domain.CampaignStatusActive,
+	}); err != nil {
+		t.Fatalf("failed to create test campaign: %v", err)
+	}
+
+	h := &Handler{
+		db:           db,
+		template:     newIntegrationTemplate(t),
+		bpool:        bpool.NewBufferPool(8),
+		torroRepo:    repository.NewTorroRepo(db),
+		classRepo:    repository.NewClassRepo(db),
+		campaignRepo: campaignRepo,
+		bracketRepo:  bracketRepo,
+	}
+
+	// The knockout's size follows from the groups; an explicit one is
+	// rejected.
+	sizedReq := newIntegrationRequest(http.MethodPost,
+		fmt.Sprintf("/bracket/%s/create?format=groups_knockout&size=4", classId),
+		map[string]string{"classId": classId}, "")
+	sizedRec := httptest.NewRecorder()
+	h.bracketCreate(sizedRec, sizedReq)
+	if sizedRec.Code != http.StatusBadRequest {
+		t.Fatalf("sized groups bracket status = %d, want %d", sizedRec.Code, http.StatusBadRequest)
+	}
+
+	// Two groups of three, the winners meeting in a final.
+	createReq := newIntegrationRequest(http.MethodPost,
+		fmt.Sprintf("/bracket/%s/create?format=groups_knockout&groups=2&group_size=3&advance=1", classId),
+		map[string]string{"classId": classId}, "")
+	createRec := httptest.NewRecorder()
+	h.bracketCreate(createRec, createReq)
+	if createRec.Code != http.StatusCreated {
+		t.Fatalf("bracketCreate status = %d, want %d; body: %s", createRec.Code, http.StatusCreated, createRec.Body.String())
+	}
+
+	var bracket domain.Bracket
+	if err := json.Unmarshal(createRec.Body.Bytes(), &bracket); err != nil {
+		t.Fatalf("failed to decode bracketCreate response: %v", err)
+	}
+	if bracket.Format != domain.BracketFormatGroupsKnockout || bracket.Size != 2 || bracket.Rounds != 3 {
+		t.Fatalf("bracket = %q, size %d, %d rounds, want groups_knockout, 2, 3", bracket.Format, bracket.Size, bracket.Rounds)
+	}
+
+	groups, err := bracketRepo.ListGroups(ctx, bracket.Id)
+	if err != nil || len(groups) != 2 {
+		t.Fatalf("groups = %d (%v), want 2", len(groups), err)
+	}
+	matches, err := bracketRepo.ListMatches(ctx, bracket.Id)
+	if err != nil {
+		t.Fatalf("failed to list matches: %v", err)
+	}
+	if len(matches) != 6 {
+		t.Fatalf("group stage = %d matches, want 6", len(matches))
+	}
+
+	// Every matchday is created up front, but only the current one takes
+	// votes.
+	var matchday3 *domain.BracketMatch
+	for _, m := range matches {
+		if m.Round == 3 {
+			matchday3 = m
+			break
+		}
+	}
+	if matchday3 == nil {
+		t.Fatal("no match on matchday 3")
+	}
+	earlyReq := newIntegrationRequest(http.MethodPost,
+		fmt.Sprintf("/bracket/match/%s/vote?winner=%s", matchday3.Id, matchday3.Torro1Id),
+		map[string]string{"matchId": matchday3.Id}, uuid.NewString())
+	earlyRec := httptest.NewRecorder()
+	h.bracketMatchVote(earlyRec, earlyReq)
+	if earlyRec.Code != http.StatusBadRequest || !strings.Contains(earlyRec.Body.String(), "isn't open for voting yet") {
+		t.Errorf("vote on matchday 3 during matchday 1 = %d %s, want %d", earlyRec.Code, earlyRec.Body.String(), http.StatusBadRequest)
+	}
+
+	// Seeds 1 and 2 make up pot 1, so they land in different groups, win
+	// them on seed and meet in the final, which seed 1 takes.
+	for step := 1; step <= 4; step++ {
+		advanceReq := newIntegrationRequest(http.MethodPost, "/bracket/"+bracket.Id+"/advance",
+			map[string]string{"bracketId": bracket.Id}, "")
+		advanceRec := httptest.NewRecorder()
+		h.bracketAdvance(advanceRec, advanceReq)
+		if advanceRec.Code != http.StatusOK {
+			t.Fatalf("bracketAdvance %d status = %d; body: %s", step, advanceRec.Code, advanceRec.Body.String())
+		}
+	}
+
+	final, err := bracketRepo.ListMatchesByRound(ctx, bracket.Id, 4)
+	if err != nil || len(final) != 1 {
+		t.Fatalf("final = %d matches (%v), want 1", len(final), err)
+	}
+	finalists := map[string]bool{final[0].Torro1Id: true, *final[0].Torro2Id: true}
+	if !finalists[seed1Id] || !finalists[seed2Id] {
+		t.Errorf("final = %s v %s, want seeds 1 and 2", final[0].Torro1Id, *final[0].Torro2Id)
+	}
+
+	completed, err := bracketRepo.Get(ctx, bracket.Id)
+	if err != nil {
+		t.Fatalf("failed to reload bracket: %v", err)
+	}
+	if completed.Status != domain.BracketStatusCompleted || completed.ChampionId == nil || *completed.ChampionId != seed1Id {
+		t.Fatalf("bracket = %q, champion %v, want completed with seed 1", completed.Status, completed.ChampionId)
+	}
+
+	overviewReq := newIntegrationRequest(http.MethodGet, "/bracket/"+classId, map[string]string{"classId": classId}, "")
+	overviewRec := httptest.NewRecorder()
+	h.bracketOverview(overviewRec, overviewReq)
+	if overviewRec.Code != http.StatusOK {
+		t.Fatalf("bracketOverview status = %d, want %d", overviewRec.Code, http.StatusOK)
+	}
+	body := overviewRec.Body.String()
+	if !strings.Contains(body, "Grup A") || !strings.Contains(body, "Grup B") {
+		t.Error("overview is missing the group tables")
+	}
+}
+
+func TestIntegration_BracketRoundDeadlines(t *testing.T) {
+	db := setupIntegrationDB(t)
+	ctx := context.Background()
+
+	campaignRepo := repository.NewCampaignRepo(db)
+	bracketRepo := repository.NewBracketRepo(db)
+
+	classId := insertTestClass(t, db, "Deadline Test Class")
+	seed1Id := insertTestTorro(t, db, classId, "Seed 1", 1600)
+	insertTestTorro(t, db, classId, "Seed 2", 1550)
+	insertTestTorro(t, db, classId, "Seed 3", 1500)
+	insertTestTorro(t, db, classId, "Seed 4", 1450)
+
+	now := time.Now().UTC()
+	if _, err := campaignRepo.Create(ctx, &domain.Campaign{
+		Name:      "Deadline Test Campaign",
+		StartDate: now.Add(-1 * time.Hour).Format(time.RFC3339),
+		EndDate:   now.Add(1 * time.Hour).Format(time.RFC3339),
+		Year:      now.Year(),
+		Status:    domain.CampaignStatusActive,
+	}); err != nil {
+		t.Fatalf("failed to create test campaign: %v", err)
+	}
+
+	h := &Handler{
+		db:           db,
+		template:     newIntegrationTemplate(t),
+		bpool:        bpool.NewBufferPool(8),
+		torroRepo:    repository.NewTorroRepo(db),
+		classRepo:    repository.NewClassRepo(db),
+		campaignRepo: campaignRepo,
+		bracketRepo:  bracketRepo,
+	}
+
+	// A deadline that leaves round 1 already closed is rejected.
+	lateReq := newIntegrationRequest(http.MethodPost,
+		fmt.Sprintf("/bracket/%s/create?size=4&closes=%s", classId, now.Add(time.Hour).Format(time.RFC3339)),
+		map[string]string{"classId": classId}, "")
+	lateRec := httptest.NewRecorder()
+	h.bracketCreate(lateRec, lateReq)
+	if lateRec.Code != http.StatusBadRequest {
+		t.Fatalf("late schedule status = %d, want %d", lateRec.Code, http.StatusBadRequest)
+	}
+
+	// Two daily rounds, the final closing two days from now.
+	closes := now.Add(48 * time.Hour).Truncate(time.Second)
+	createReq := newIntegrationRequest(http.MethodPost,
+		fmt.Sprintf("/bracket/%s/create?size=4&closes=%s&every=24h", classId, closes.Format(time.RFC3339)),
+		map[string]string{"classId": classId}, "")
+	createRec := httptest.NewRecorder()
+	h.bracketCreate(createRec, createReq)
+	if createRec.Code != http.StatusCreated {
+		t.Fatalf("bracketCreate status = %d, want %d; body: %s", createRec.Code, http.StatusCreated, createRec.Body.String())
+	}
+
+	var bracket domain.Bracket
+	if err := json.Unmarshal(createRec.Body.Bytes(), &bracket); err != nil {
+		t.Fatalf("failed to decode bracketCreate response: %v", err)
+	}
+
+	rounds, err := bracketRepo.ListRounds(ctx, bracket.Id)
+	if err != nil || len(rounds) != 2 {
+		t.Fatalf("schedule = %d rounds (%v), want 2", len(rounds), err)
+	}
+
+	voteReq := newIntegrationRequest(http.MethodGet, "/bracket/"+classId+"/vote", map[string]string{"classId": classId}, "voter-1")
+	voteRec := httptest.NewRecorder()
+	h.bracketVote(voteRec, voteReq)
+	if !strings.Contains(voteRec.Body.String(), "La ronda tanca en") {
+		t.Error("vote page is missing the round countdown")
+	}
+
+	// Nothing is due yet.
+	if n, err := h.closeOverdueRounds(ctx, now); err != nil || n != 0 {
+		t.Fatalf("closeOverdueRounds before any deadline = %d (%v), want 0", n, err)
+	}
+
+	// Past round 1's deadline, its untouched matches go to the better seed.
+	if n, err := h.closeOverdueRounds(ctx, now.Add(25*time.Hour)); err != nil || n != 1 {
+		t.Fatalf("closeOverdueRounds after round 1 = %d (%v), want 1", n, err)
+	}
+	if n, err := h.closeOverdueRounds(ctx, now.Add(49*time.Hour)); err != nil || n != 1 {
+		t.Fatalf("closeOverdueRounds after the final = %d (%v), want 1", n, err)
+	}
+
+	completed, err := bracketRepo.Get(ctx, bracket.Id)
+	if err != nil {
+		t.Fatalf("failed to reload bracket: %v", err)
+	}
+	if completed.Status != domain.BracketStatusCompleted || completed.ChampionId == nil || *completed.ChampionId != seed1Id {
+		t.Fatalf("bracket = %q, champion %v, want completed with seed 1", completed.Status, completed.ChampionId)
+	}
+}
+
+// -- Bracket seeding (bracket_seeding.go) --
+
+func TestIntegration_BracketSeeding(t *testing.T) {
+	db := setupIntegrationDB(t)
+	ctx := context.Background()
+
+	campaignRepo := repository.NewCampaignRepo(db)
+	bracketRepo := repository.NewBracketRepo(db)
+
+	manualClassId := insertTestClass(t, db, "Manual Seeding Test Class")
+	strongId := insertTestTorro(t, db, manualClassId, "Strong", 1600)
+	middleId := insertTestTorro(t, db, manualClassId, "Middle", 1500)
+	weakId := insertTestTorro(t, db, manualClassId, "Weak", 1400)
+	excludedId := insertTestTorro(t, db, manualClassId, "Excluded", 1700)
+
+	randomClassId := insertTestClass(t, db, "Random Seeding Test Class")
+	for i := range 4 {
+		insertTestTorro(t, db, randomClassId, fmt.Sprintf("Drawn %d", i+1), 1500+float64(i))
+	}
+
+	now := time.Now().UTC()
+	if _, err := campaignRepo.Create(ctx, &domain.Campaign{
+		Name:      "Seeding Test Campaign",
+		StartDate: now.Add(-1 * time.Hour).Format(time.RFC3339),
+		EndDate:   now.Add(1 * time.Hour).Format(time.RFC3339),
+		Year:      now.Year(),
+		Status:    domain.CampaignStatusActive,
+	}); err != nil {
+		t.Fatalf("failed to create test campaign: %v", err)
+	}
+
+	h := &Handler{
+		db:           db,
+		template:     newIntegrationTemplate(t),
+		bpool:        bpool.NewBufferPool(8),
+		torroRepo:    repository.NewTorroRepo(db),
+		classRepo:    repository.NewClassRepo(db),
+		campaignRepo: campaignRepo,
+		bracketRepo:  bracketRepo,
+	}
+
+	create := func(classId, body string) *httptest.ResponseRecorder {
+		req := newIntegrationRequest(http.MethodPost, fmt.Sprintf("/bracket/%s/create?size=4", classId),
+			map[string]string{"classId": classId}, "")
+		req.Body = io.NopCloser(strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		h.bracketCreate(rec, req)
+		return rec
+	}
+	seedsOf := func(bracketId string) map[string]int {
+		entries, err := bracketRepo.ListEntries(ctx, bracketId)
+		if err != nil {
+			t.Fatalf("failed to list bracket entries: %v", err)
+		}
+		return seedMap(entries)
+	}
+
+	// The seed list can't name the torró it excludes.
+	badRec := create(manualClassId, fmt.Sprintf(`{"strategy": "manual", "seeds": [%q, %q], "exclude": [%q]}`,
+		weakId, excludedId, excludedId))
+	if badRec.Code != http.StatusBadRequest {
+		t.Fatalf("excluded seed status = %d, want %d", badRec.Code, http.StatusBadRequest)
+	}
+
+	manualRec := create(manualClassId, fmt.Sprintf(`{"strategy": "manual", "seeds": [%q, %q, %q], "exclude": [%q]}`,
+		weakId, strongId, middleId, excludedId))
+	if manualRec.Code != http.StatusCreated {
+		t.Fatalf("manual bracketCreate status = %d, want %d; body: %s", manualRec.Code, http.StatusCreated, manualRec.Body.String())
+	}
+	var manual domain.Bracket
+	if err := json.Unmarshal(manualRec.Body.Bytes(), &manual); err != nil {
+		t.Fatalf("failed to decode bracketCreate response: %v", err)
+	}
+	seeds := seedsOf(manual.Id)
+	if len(seeds) != 3 || seeds[weakId] != 1 || seeds[strongId] != 2 || seeds[middleId] != 3 {
+		t.Fatalf("manual seeds = %+v, want weak, strong, middle", seeds)
+	}
+
+	stored, err := bracketRepo.Get(ctx, manual.Id)
+	if err != nil {
+		t.Fatalf("failed to reload bracket: %v", err)
+	}
+	if stored.Seeding.Strategy != domain.BracketSeedingManual || len(stored.Seeding.Seeds) != 3 ||
+		len(stored.Seeding.Exclude) != 1 || stored.Seeding.Exclude[0] != excludedId {
+		t.Fatalf("stored seeding = %+v, want the manual list and its exclusion", stored.Seeding)
+	}
 
-	h.bracketMatchVote(finalVoteRec, finalVoteReq)
+	// A random draw publishes its seed, and the seed redraws the field.
+	randomRec := create(randomClassId, `{"strategy": "random"}`)
+	if randomRec.Code != http.StatusCreated {
+		t.Fatalf("random bracketCreate status = %d, want %d; body: %s", randomRec.Code, http.StatusCreated, randomRec.Body.String())
+	}
+	var random domain.Bracket
+	if err := json.Unmarshal(randomRec.Body.Bytes(), &random); err != nil {
+		t.Fatalf("failed to decode bracketCreate response: %v", err)
+	}
+	if random.Seeding.Strategy != domain.BracketSeedingRandom || random.Seeding.RandomSeed == nil {
+		t.Fatalf("random seeding = %+v, want its published seed", random.Seeding)
+	}
 
-	if finalVoteRec.Code != http.StatusOK {
-		t.Fatalf("final bracketMatchVote status = %d, want %d; body: %s", finalVoteRec.Code, http.StatusOK, finalVoteRec.Body.String())
+	field, err := h.torroRepo.ListByClass(ctx, randomClassId)
+	if err != nil {
+		t.Fatalf("failed to list the class: %v", err)
+	}
+	seeds = seedsOf(random.Id)
+	for i, torro := range tournament.SeedByDraw(field, *random.Seeding.RandomSeed) {
+		if seeds[torro.Id] != i+1 {
+			t.Fatalf("random seeds = %+v, which seed %d doesn't redraw", seeds, *random.Seeding.RandomSeed)
+		}
 	}
+}
 
-	completed, err := bracketRepo.Get(ctx, bracket.Id)
+// -- Bracket JSON API and tree export (bracket_api.go) --
+
+func TestIntegration_BracketAPI(t *testing.T) {
+	db := setupIntegrationDB(t)
+	ctx := context.Background()
+
+	campaignRepo := repository.NewCampaignRepo(db)
+	bracketRepo := repository.NewBracketRepo(db)
+	userRepo := repository.NewUserRepo(db)
+
+	classId := insertTestClass(t, db, "Bracket API Test Class")
+	seed1Id := insertTestTorro(t, db, classId, "API Seed 1", 1600)
+	insertTestTorro(t, db, classId, "API Seed 2", 1550)
+	insertTestTorro(t, db, classId, "API Seed 3", 1500)
+	seed4Id := insertTestTorro(t, db, classId, "API Seed 4", 1450)
+
+	now := time.Now().UTC()
+	if _, err := campaignRepo.Create(ctx, &domain.Campaign{
+		Name:      "Bracket API Test Campaign",
+		StartDate: now.Add(-1 * time.Hour).Format(time.RFC3339),
+		EndDate:   now.Add(1 * time.Hour).Format(time.RFC3339),
+		Year:      now.Year(),
+		Status:    domain.CampaignStatusActive,
+	}); err != nil {
+		t.Fatalf("failed to create test campaign: %v", err)
+	}
+	voter, err := userRepo.Create(ctx, &domain.User{Id: uuid.NewString()})
 	if err != nil {
-		t.Fatalf("failed to reload bracket after final vote: %v", err)
+		t.Fatalf("failed to create voter: %v", err)
 	}
-	if completed.Status != domain.BracketStatusCompleted {
-		t.Fatalf("bracket.Status after final vote = %q, want %q", completed.Status, domain.BracketStatusCompleted)
+
+	h := &Handler{
+		db:           db,
+		template:     newIntegrationTemplate(t),
+		bpool:        bpool.NewBufferPool(8),
+		torroRepo:    repository.NewTorroRepo(db),
+		classRepo:    repository.NewClassRepo(db),
+		campaignRepo: campaignRepo,
+		bracketRepo:  bracketRepo,
 	}
-	if completed.ChampionId == nil || *completed.ChampionId != seed1Id {
-		t.Fatalf("bracket.ChampionId = %v, want %q", completed.ChampionId, seed1Id)
+
+	createRec := httptest.NewRecorder()
+	h.bracketCreate(createRec, newIntegrationRequest(http.MethodPost, fmt.Sprintf("/bracket/%s/create?size=4", classId),
+		map[string]string{"classId": classId}, ""))
+	if createRec.Code != http.StatusCreated {
+		t.Fatalf("bracketCreate status = %d, want %d; body: %s", createRec.Code, http.StatusCreated, createRec.Body.String())
 	}
-	if completed.CompletedAt == nil {
-		t.Error("expected bracket.CompletedAt to be set once completed")
+	var bracket domain.Bracket
+	if err := json.Unmarshal(createRec.Body.Bytes(), &bracket); err != nil {
+		t.Fatalf("failed to decode bracketCreate response: %v", err)
+	}
+
+	round1, err := bracketRepo.ListMatchesByRound(ctx, bracket.Id, 1)
+	if err != nil {
+		t.Fatalf("failed to list round 1 matches: %v", err)
+	}
+	var match1v4 *domain.BracketMatch
+	for _, m := range round1 {
+		if m.Torro1Id == seed1Id || m.Torro1Id == seed4Id {
+			match1v4 = m
+		}
+	}
+	if match1v4 == nil {
+		t.Fatalf("expected a seed1-vs-seed4 match in round 1, got %+v", round1)
+	}
+	voteRec := httptest.NewRecorder()
+	h.bracketMatchVote(voteRec, newIntegrationRequest(http.MethodPost,
+		fmt.Sprintf("/bracket/match/%s/vote?winner=%s", match1v4.Id, seed4Id),
+		map[string]string{"matchId": match1v4.Id}, voter.Id))
+	if voteRec.Code != http.StatusOK {
+		t.Fatalf("bracketMatchVote status = %d, want %d; body: %s", voteRec.Code, http.StatusOK, voteRec.Body.String())
+	}
+
+	// -- GET /api/bracket/{bracketId} --
+
+	apiRec := httptest.NewRecorder()
+	h.bracketAPI(apiRec, newIntegrationRequest(http.MethodGet, "/api/bracket/"+bracket.Id,
+		map[string]string{"bracketId": bracket.Id}, ""))
+	if apiRec.Code != http.StatusOK {
+		t.Fatalf("bracketAPI status = %d, want %d; body: %s", apiRec.Code, http.StatusOK, apiRec.Body.String())
+	}
+	var api BracketAPIResponse
+	if err := json.Unmarshal(apiRec.Body.Bytes(), &api); err != nil {
+		t.Fatalf("failed to decode bracketAPI response: %v", err)
+	}
+	if api.Bracket == nil || api.Bracket.Id != bracket.Id || api.Bracket.Seeding.Strategy != domain.BracketSeedingRating {
+		t.Fatalf("bracketAPI bracket = %+v, want %s seeded by rating", api.Bracket, bracket.Id)
+	}
+	if len(api.Entries) != 4 || len(api.Matches) != 2 {
+		t.Fatalf("bracketAPI returned %d entries and %d matches, want 4 and 2", len(api.Entries), len(api.Matches))
+	}
+	for _, e := range api.Entries {
+		if e.BracketEntry == nil || e.Name == "" {
+			t.Errorf("bracketAPI entry %+v has no name", e)
+		}
+	}
+	for _, m := range api.Matches {
+		if len(m.Votes) != 2 {
+			t.Errorf("match %s votes = %+v, want both competitors", m.Id, m.Votes)
+		}
+		if m.Id == match1v4.Id && (m.Votes[seed4Id] != 1 || m.Votes[seed1Id] != 0) {
+			t.Errorf("match %s votes = %+v, want seed 4 on 1 and seed 1 on 0", m.Id, m.Votes)
+		}
+	}
+	if strings.Contains(apiRec.Body.String(), voter.Id) {
+		t.Error("bracketAPI leaked a voter's ID")
+	}
+
+	notFoundRec := httptest.NewRecorder()
+	h.bracketAPI(notFoundRec, newIntegrationRequest(http.MethodGet, "/api/bracket/"+uuid.NewString(),
+		map[string]string{"bracketId": uuid.NewString()}, ""))
+	if notFoundRec.Code != http.StatusNotFound {
+		t.Errorf("bracketAPI of an unknown bracket status = %d, want %d", notFoundRec.Code, http.StatusNotFound)
+	}
+
+	// -- GET /bracket/{bracketId}/tree.svg, tree.png --
+
+	tree := func(format string) *httptest.ResponseRecorder {
+		req := newIntegrationRequest(http.MethodGet, "/bracket/"+bracket.Id+"/tree."+format,
+			map[string]string{"bracketId": bracket.Id}, "")
+		req = req.WithContext(context.WithValue(req.Context(), middleware.URLFormatCtxKey, format))
+		rec := httptest.NewRecorder()
+		h.bracketTree(rec, req)
+		return rec
+	}
+	for format, contentType := range map[string]string{"svg": "image/svg+xml", "png": "image/png"} {
+		rec := tree(format)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("bracketTree %s status = %d, want %d; body: %s", format, rec.Code, http.StatusOK, rec.Body.String())
+		}
+		if got := rec.Header().Get("Content-Type"); got != contentType {
+			t.Errorf("bracketTree %s Content-Type = %q, want %q", format, got, contentType)
+		}
+	}
+	if svg := tree("svg").Body.String(); !strings.Contains(svg, "API Seed 4") {
+		t.Error("the SVG tree doesn't name seed 4")
+	}
+	if rec := tree("gif"); rec.Code != http.StatusNotFound {
+		t.Errorf("bracketTree gif status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+// -- Gran Final meta-bracket (bracket_gran_final.go) --
+
+func TestIntegration_GranFinal(t *testing.T) {
+	db := setupIntegrationDB(t)
+	ctx := context.Background()
+
+	campaignRepo := repository.NewCampaignRepo(db)
+	bracketRepo := repository.NewBracketRepo(db)
+
+	// Two classes of two, each settled by a single 0-0 final that the
+	// forced advance gives to the better seed.
+	classAId := insertTestClass(t, db, "Gran Final Test Class A")
+	classAChampionId := insertTestTorro(t, db, classAId, "Class A Champion", 1600)
+	classARunnerUpId := insertTestTorro(t, db, classAId, "Class A Runner-up", 1500)
+	classBId := insertTestClass(t, db, "Gran Final Test Class B")
+	classBChampionId := insertTestTorro(t, db, classBId, "Class B Champion", 1550)
+	classBRunnerUpId := insertTestTorro(t, db, classBId, "Class B Runner-up", 1450)
+
+	now := time.Now().UTC()
+	campaign, err := campaignRepo.Create(ctx, &domain.Campaign{
+		Name:      "Gran Final Test Campaign",
+		StartDate: now.Add(-1 * time.Hour).Format(time.RFC3339),
+		EndDate:   now.Add(1 * time.Hour).Format(time.RFC3339),
+		Year:      now.Year(),
+		Status:    domain.CampaignStatusActive,
+	})
+	if err != nil {
+		t.Fatalf("failed to create test campaign: %v", err)
+	}
+
+	// Only the two test classes (and Global) take part; the database's
+	// other classes would otherwise hold the Gran Final back for good.
+	h := &Handler{
+		db:           db,
+		template:     newIntegrationTemplate(t),
+		bpool:        bpool.NewBufferPool(8),
+		torroRepo:    repository.NewTorroRepo(db),
+		classRepo:    &fakeClassRepo{classes: []*domain.Class{{Id: classAId}, {Id: classBId}, {Id: catalog.GlobalClassId}}},
+		campaignRepo: campaignRepo,
+		bracketRepo:  bracketRepo,
+
+		granFinalRunnersUp: true,
+	}
+
+	globalRec := httptest.NewRecorder()
+	h.bracketCreate(globalRec, newIntegrationRequest(http.MethodPost, "/bracket/5/create?size=4",
+		map[string]string{"classId": catalog.GlobalClassId}, ""))
+	if globalRec.Code != http.StatusBadRequest {
+		t.Fatalf("bracketCreate for the Global class status = %d, want %d", globalRec.Code, http.StatusBadRequest)
+	}
+
+	advance := func(bracketId string) domain.Bracket {
+		rec := httptest.NewRecorder()
+		h.bracketAdvance(rec, newIntegrationRequest(http.MethodPost, "/bracket/"+bracketId+"/advance",
+			map[string]string{"bracketId": bracketId}, ""))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("bracketAdvance status = %d, want %d; body: %s", rec.Code, http.StatusOK, rec.Body.String())
+		}
+		var bracket domain.Bracket
+		if err := json.Unmarshal(rec.Body.Bytes(), &bracket); err != nil {
+			t.Fatalf("failed to decode bracketAdvance response: %v", err)
+		}
+		return bracket
+	}
+	classBracket := func(classId string) string {
+		bracket, err := h.seedAndCreateBracket(ctx, classId, bracketOptions{Size: 2, GrandFinalReset: true})
+		if err != nil {
+			t.Fatalf("failed to create the bracket of class %s: %v", classId, err)
+		}
+		// seedAndCreateBracket attaches to whichever campaign is active;
+		// pin it to this test's.
+		if _, err := db.Exec(`UPDATE "Brackets" SET "CampaignId" = $1 WHERE "Id" = $2`, campaign.Id, bracket.Id); err != nil {
+			t.Fatalf("failed to move bracket %s to the test campaign: %v", bracket.Id, err)
+		}
+		return bracket.Id
+	}
+
+	if done := advance(classBracket(classAId)); done.Status != domain.BracketStatusCompleted {
+		t.Fatalf("class A bracket status = %q, want %q", done.Status, domain.BracketStatusCompleted)
+	}
+	if _, err := bracketRepo.GetByCampaignAndClass(ctx, campaign.Id, catalog.GlobalClassId); err == nil {
+		t.Fatal("the Gran Final was created with class B still to play")
+	}
+
+	advance(classBracket(classBId))
+	granFinal, err := bracketRepo.GetByCampaignAndClass(ctx, campaign.Id, catalog.GlobalClassId)
+	if err != nil {
+		t.Fatalf("the Gran Final wasn't created once both classes completed: %v", err)
+	}
+	if !granFinal.IsGranFinal() || granFinal.Size != 4 || granFinal.Format != domain.BracketFormatSingleElimination ||
+		granFinal.Status != domain.BracketStatusInProgress {
+		t.Fatalf("Gran Final = %+v, want an in-progress single-elimination meta-bracket of 4", granFinal)
+	}
+
+	entries, err := bracketRepo.ListEntries(ctx, granFinal.Id)
+	if err != nil {
+		t.Fatalf("failed to list the Gran Final's entries: %v", err)
+	}
+	seeds := seedMap(entries)
+	if len(seeds) != 4 || seeds[classAChampionId] != 1 || seeds[classBChampionId] != 2 ||
+		seeds[classARunnerUpId] != 3 || seeds[classBRunnerUpId] != 4 {
+		t.Fatalf("Gran Final seeds = %+v, want the champions and runners-up by rating", seeds)
+	}
+
+	// Creating it again, as the scheduler's retry would, changes nothing.
+	if again, err := h.createGranFinalIfReady(ctx, campaign.Id); err != nil || again != nil {
+		t.Fatalf("createGranFinalIfReady a second time = %+v, %v; want nothing", again, err)
+	}
+
+	advance(granFinal.Id)
+	if done := advance(granFinal.Id); done.Status != domain.BracketStatusCompleted || done.ChampionId == nil ||
+		*done.ChampionId != classAChampionId {
+		t.Fatalf("Gran Final after two rounds = %+v, want class A's champion crowned", done)
+	}
+
+	champion, err := h.pressGlobalChampion(ctx)
+	if err != nil {
+		t.Fatalf("pressGlobalChampion: %v", err)
+	}
+	if champion == nil || champion.Id != classAChampionId {
+		t.Fatalf("press champion = %+v, want the Gran Final's", champion)
+	}
+}
+
+// -- Bracket pick'em (bracket_prediction_handler.go) --
+
+func TestIntegration_BracketPredictions(t *testing.T) {
+	db := setupIntegrationDB(t)
+	ctx := context.Background()
+
+	campaignRepo := repository.NewCampaignRepo(db)
+	bracketRepo := repository.NewBracketRepo(db)
+	predictionRepo := repository.NewBracketPredictionRepo(db)
+	friendCircleRepo := repository.NewFriendCircleRepo(db)
+	userRepo := repository.NewUserRepo(db)
+
+	classId := insertTestClass(t, db, "Prediction Test Class")
+	seed1Id := insertTestTorro(t, db, classId, "Seed 1", 1600)
+	seed2Id := insertTestTorro(t, db, classId, "Seed 2", 1550)
+	seed3Id := insertTestTorro(t, db, classId, "Seed 3", 1500)
+	seed4Id := insertTestTorro(t, db, classId, "Seed 4", 1450)
+
+	now := time.Now().UTC()
+	if _, err := campaignRepo.Create(ctx, &domain.Campaign{
+		Name:      "Prediction Test Campaign",
+		StartDate: now.Add(-1 * time.Hour).Format(time.RFC3339),
+		EndDate:   now.Add(1 * time.Hour).Format(time.RFC3339),
+		Year:      now.Year(),
+		Status:    domain.CampaignStatusActive,
+	}); err != nil {
+		t.Fatalf("failed to create test campaign: %v", err)
+	}
+
+	sharp, err := userRepo.Create(ctx, &domain.User{Id: uuid.NewString()})
+	if err != nil {
+		t.Fatalf("failed to create test user: %v", err)
+	}
+	bold, err := userRepo.Create(ctx, &domain.User{Id: uuid.NewString()})
+	if err != nil {
+		t.Fatalf("failed to create test user: %v", err)
+	}
+	circle, err := friendCircleRepo.Create(ctx, sharp.Id)
+	if err != nil {
+		t.Fatalf("failed to create test circle: %v", err)
+	}
+
+	h := &Handler{
+		db:                    db,
+		template:              newIntegrationTemplate(t),
+		bpool:                 bpool.NewBufferPool(8),
+		torroRepo:             repository.NewTorroRepo(db),
+		classRepo:             repository.NewClassRepo(db),
+		campaignRepo:          campaignRepo,
+		bracketRepo:           bracketRepo,
+		bracketPredictionRepo: predictionRepo,
+		friendCircleRepo:      friendCircleRepo,
+		wrappedStatsRepo:      repository.NewWrappedStatsRepo(db),
+	}
+
+	// Round 1 opens in an hour: two daily rounds, the final closing two
+	// days after that.
+	opens := now.Add(time.Hour).Truncate(time.Second)
+	closes := opens.Add(48 * time.Hour)
+	createReq := newIntegrationRequest(http.MethodPost,
+		fmt.Sprintf("/bracket/%s/create?size=4&opens=%s&closes=%s", classId, opens.Format(time.RFC3339), closes.Format(time.RFC3339)),
+		map[string]string{"classId": classId}, "")
+	createRec := httptest.NewRecorder()
+	h.bracketCreate(createRec, createReq)
+	if createRec.Code != http.StatusCreated {
+		t.Fatalf("bracketCreate status = %d, want %d; body: %s", createRec.Code, http.StatusCreated, createRec.Body.String())
+	}
+	var bracket domain.Bracket
+	if err := json.Unmarshal(createRec.Body.Bytes(), &bracket); err != nil {
+		t.Fatalf("failed to decode bracketCreate response: %v", err)
+	}
+
+	// Until round 1 opens, there is nothing to vote on.
+	matches, err := bracketRepo.ListMatches(ctx, bracket.Id)
+	if err != nil || len(matches) != 2 {
+		t.Fatalf("round 1 = %d matches (%v), want 2", len(matches), err)
+	}
+	earlyVote := newIntegrationRequest(http.MethodPost,
+		fmt.Sprintf("/bracket/match/%s/vote?winner=%s", matches[0].Id, matches[0].Torro1Id),
+		map[string]string{"matchId": matches[0].Id}, sharp.Id)
+	earlyVoteRec := httptest.NewRecorder()
+	h.bracketMatchVote(earlyVoteRec, earlyVote)
+	if earlyVoteRec.Code != http.StatusBadRequest {
+		t.Fatalf("early vote status = %d, want %d", earlyVoteRec.Code, http.StatusBadRequest)
+	}
+
+	predict := func(userId string, picks url.Values) *httptest.ResponseRecorder {
+		req := newIntegrationRequest(http.MethodPost, "/bracket/"+classId+"/predict", map[string]string{"classId": classId}, userId)
+		req.PostForm = picks
+		rec := httptest.NewRecorder()
+		h.bracketPredictSubmit(rec, req)
+		return rec
+	}
+
+	// Seeds 1 v 4 and 2 v 3 in round 1.
+	if rec := predict(sharp.Id, url.Values{"pick-1-0": {seed1Id}, "pick-1-1": {seed2Id}, "pick-2-0": {seed1Id}}); rec.Code != http.StatusOK {
+		t.Fatalf("sharp prediction status = %d; body: %s", rec.Code, rec.Body.String())
+	}
+	if rec := predict(bold.Id, url.Values{"pick-1-0": {seed4Id}, "pick-1-1": {seed3Id}, "pick-2-0": {seed1Id}}); rec.Code != http.StatusBadRequest {
+		t.Fatalf("inconsistent prediction status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if rec := predict(bold.Id, url.Values{"pick-1-0": {seed4Id}, "pick-1-1": {seed3Id}, "pick-2-0": {seed3Id}}); rec.Code != http.StatusOK {
+		t.Fatalf("bold prediction status = %d; body: %s", rec.Code, rec.Body.String())
+	}
+
+	// The scheduler plays both rounds, the better seed winning each
+	// untouched match.
+	if n, err := h.closeOverdueRounds(ctx, opens.Add(25*time.Hour)); err != nil || n != 1 {
+		t.Fatalf("closeOverdueRounds after round 1 = %d (%v), want 1", n, err)
+	}
+	if rec := predict(bold.Id, url.Values{"pick-1-0": {seed1Id}, "pick-1-1": {seed2Id}, "pick-2-0": {seed1Id}}); rec.Code != http.StatusBadRequest {
+		t.Fatalf("late prediction status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	// Save re-checks the lock itself, for a submission that passed the
+	// handler's check just before the round opened.
+	if _, err := predictionRepo.Save(ctx, &domain.BracketPrediction{
+		BracketId:  bracket.Id,
+		UserId:     bold.Id,
+		ChampionId: seed1Id,
+	}, nil); err == nil || !strings.Contains(err.Error(), string(domain.ValidationError)) {
+		t.Fatalf("late Save = %v, want a validation error", err)
+	}
+	if n, err := h.closeOverdueRounds(ctx, opens.Add(49*time.Hour)); err != nil || n != 1 {
+		t.Fatalf("closeOverdueRounds after the final = %d (%v), want 1", n, err)
+	}
+
+	scores, err := predictionRepo.Leaderboard(ctx, bracket.Id, "", 10)
+	if err != nil || len(scores) != 2 {
+		t.Fatalf("leaderboard = %d lines (%v), want 2", len(scores), err)
+	}
+	if scores[0].UserId != sharp.Id || scores[0].Points != 4 || scores[0].Correct != 3 || scores[0].Decided != 3 {
+		t.Errorf("leader = %+v, want the sharp prediction with 4 points from 3 of 3", scores[0])
+	}
+	if scores[1].Points != 0 || scores[1].Rank != 2 {
+		t.Errorf("runner-up = %+v, want 0 points in 2nd", scores[1])
+	}
+
+	circleScores, err := predictionRepo.Leaderboard(ctx, bracket.Id, circle.Id, 10)
+	if err != nil || len(circleScores) != 1 || circleScores[0].UserId != sharp.Id {
+		t.Fatalf("circle leaderboard = %d lines (%v), want only the circle's member", len(circleScores), err)
+	}
+
+	pageReq := newIntegrationRequest(http.MethodGet, "/bracket/"+classId+"/predictions?circle="+circle.Id,
+		map[string]string{"classId": classId}, bold.Id)
+	pageRec := httptest.NewRecorder()
+	h.bracketPredictions(pageRec, pageReq)
+	if !strings.Contains(pageRec.Body.String(), "Només els membres del cercle") {
+		t.Error("a non-member was shown the circle's predictions")
+	}
+
+	path, err := h.wrappedStatsRepo.BracketPath(ctx, sharp.Id, bracket.Id)
+	if err != nil {
+		t.Fatalf("BracketPath: %v", err)
+	}
+	if !path.HasPrediction || path.PredictionPoints != 4 || path.PredictionCorrect != 3 || !path.PredictedChampion {
+		t.Errorf("bracket path = %+v, want the prediction's 4 points and the champion", path)
+	}
+}
+
+// -- Rating seasons (CampaignRatingRepo.Rollover) --
+
+// TestIntegration_RolloverLocksPersonalLeaderboards checks a season
+// rollover, which clears every personal snapshot, locks the personal
+// leaderboards again - the Global one included, whose gate counts this
+// season's votes rather than the all-time VoteCount.
+func TestIntegration_RolloverLocksPersonalLeaderboards(t *testing.T) {
+	db := setupIntegrationDB(t)
+	ctx := context.Background()
+
+	userRepo := repository.NewUserRepo(db)
+	campaignRepo := repository.NewCampaignRepo(db)
+	campaignRatingRepo := repository.NewCampaignRatingRepo(db)
+
+	user, err := userRepo.Create(ctx, &domain.User{Id: uuid.NewString()})
+	if err != nil {
+		t.Fatalf("failed to create test user: %v", err)
+	}
+	if _, err := db.ExecContext(ctx,
+		`UPDATE "Users" SET "VoteCount" = 60, "ClassVotes" = '{"1": 40, "5": 20}'::jsonb WHERE "Id" = $1`, user.Id,
+	); err != nil {
+		t.Fatalf("failed to set the user's vote counts: %v", err)
+	}
+
+	h := &Handler{
+		db:                 db,
+		userRepo:           userRepo,
+		campaignRepo:       campaignRepo,
+		campaignRatingRepo: campaignRatingRepo,
+		ratingEngine:       rating.NewElo(rating.DefaultEloK),
+	}
+
+	unlocked := func(category string) bool {
+		t.Helper()
+		votes, minVotes, err := h.personalVoteGate(ctx, user.Id, category)
+		if err != nil {
+			t.Fatalf("personalVoteGate(%s) error = %v", category, err)
+		}
+		return votes >= minVotes
+	}
+	if !unlocked("1") || !unlocked("5") {
+		t.Fatal("with 40 Clàssics votes out of 60, class 1 and Global should start unlocked")
+	}
+
+	now := time.Now().UTC()
+	var campaigns []string
+	for _, name := range []string{"Rollover Test Campaign A", "Rollover Test Campaign B"} {
+		campaign, err := campaignRepo.Create(ctx, &domain.Campaign{
+			Name:      name,
+			StartDate: now.Add(-1 * time.Hour).Format(time.RFC3339),
+			EndDate:   now.Add(1 * time.Hour).Format(time.RFC3339),
+			Year:      now.Year(),
+			Status:    domain.CampaignStatusEnded,
+		})
+		if err != nil {
+			t.Fatalf("failed to create test campaign: %v", err)
+		}
+		campaigns = append(campaigns, campaign.Id)
+	}
+
+	// The first call may only claim an unowned season; the second always
+	// rolls over from the first.
+	if _, err := campaignRatingRepo.Rollover(ctx, campaigns[0], h.ratingEngine.Initial()); err != nil {
+		t.Fatalf("Rollover() error = %v", err)
+	}
+	if _, err := db.ExecContext(ctx,
+		`UPDATE "Users" SET "ClassVotes" = '{"1": 40, "5": 20}'::jsonb WHERE "Id" = $1`, user.Id,
+	); err != nil {
+		t.Fatalf("failed to set the user's vote counts: %v", err)
+	}
+	rolled, err := campaignRatingRepo.Rollover(ctx, campaigns[1], h.ratingEngine.Initial())
+	if err != nil || !rolled {
+		t.Fatalf("Rollover() = %v, %v; want a rollover", rolled, err)
+	}
+
+	if unlocked("1") {
+		t.Error("class 1 is still unlocked after the rollover")
+	}
+	if unlocked("5") {
+		t.Error("Global is still unlocked after the rollover")
+	}
+
+	got, err := userRepo.Get(ctx, user.Id)
+	if err != nil {
+		t.Fatalf("failed to read back the user: %v", err)
+	}
+	if got.VoteCount != 60 || got.SeasonVoteCount() != 0 {
+		t.Errorf("after the rollover VoteCount = %d and SeasonVoteCount() = %d, want 60 and 0",
+			got.VoteCount, got.SeasonVoteCount())
 	}
 }
@@ -23,13 +23,22 @@ type LeaderboardEntry struct {
 	RankChange       int     // Positive = up, Negative = down, 0 = no change, 999 = new
 	RankChangeAbs    int     // Absolute value of rank change for display
 	RankChangeSymbol string  // "↑" or "↓" or "—" or "NEW"
+
+	// Strength is the torró's batch Bradley–Terry fit, when one exists.
+	// Only the public ranking pages fill it in.
+	Strength *domain.TorroStrength `json:"strength,omitempty"`
+
+	// Provisional entries rank on a blend of the user's rating and the
+	// global one; WeightPercentage is the user's share of it (0-100).
+	Provisional      bool
+	WeightPercentage int
 }
 
 // LeaderboardContent holds data for template rendering
 type LeaderboardContent struct {
 	HX                 bool
 	Title              string
-	ViewType           string // "personal" or "global"
+	ViewType           string // "personal", "provisional", "global" or "yoy"
 	SelectedCategory   string
 	ShowCategoryFilter bool
 	Categories         []*domain.Class
@@ -39,6 +48,15 @@ type LeaderboardContent struct {
 	ShareText          string // URL-encoded text for social sharing
 	ShareUrl           string // URL to share
 
+	// CompareYear is the year the "yoy" view's rank changes are against,
+	// 0 when no earlier campaign has archived ratings.
+	CompareYear int
+
+	// The "provisional" view's progress towards MinVotes, which unlock the
+	// personal one, and how far it can be trusted (domain.Confidence*).
+	VoteCount  int
+	Confidence string
+
 	// Dietary/allergen filter chips state
 	FilterVegan       bool
 	FilterGlutenFree  bool
@@ -103,6 +121,9 @@ func (h *Handler) leaderboard(w http.ResponseWriter, r *http.Request) {
 	var errorMsg string
 	var minVotes int
 	var title string
+	var compareYear int
+	var voteCount int
+	var confidence string
 
 	// Fetch data based on view type
 	if viewType == "personal" {
@@ -113,6 +134,25 @@ func (h *Handler) leaderboard(w http.ResponseWriter, r *http.Request) {
 			className := h.getClassName(classes, category)
 			title = fmt.Sprintf("Els meus resultats - %s", className)
 		}
+	} else if viewType == "provisional" {
+		entries, errorMsg, voteCount, minVotes, confidence = h.fetchProvisionalLeaderboard(r, userId, category, filter)
+		if category == "global" {
+			title = "Classificació provisional - Global"
+		} else {
+			className := h.getClassName(classes, category)
+			title = fmt.Sprintf("Classificació provisional - %s", className)
+		}
+	} else if viewType == "yoy" {
+		entries, errorMsg = h.fetchGlobalLeaderboard(r, category, filter)
+		if len(entries) > 0 {
+			compareYear = h.applyYearOverYear(r.Context(), entries)
+		}
+		if category == "global" {
+			title = "Any rere any - Millor torró absolut"
+		} else {
+			className := h.getClassName(classes, category)
+			title = fmt.Sprintf("Any rere any - %s", className)
+		}
 	} else {
 		entries, errorMsg = h.fetchGlobalLeaderboard(r, category, filter)
 		if category == "global" {
@@ -151,6 +191,9 @@ func (h *Handler) leaderboard(w http.ResponseWriter, r *http.Request) {
 		MinVotes:           minVotes,
 		ShareText:          url.QueryEscape(shareText),
 		ShareUrl:           url.QueryEscape(shareUrl),
+		CompareYear:        compareYear,
+		VoteCount:          voteCount,
+		Confidence:         confidence,
 		FilterVegan:        filter.IsVegan,
 		FilterGlutenFree:   filter.IsGlutenFree,
 		FilterLactoseFree:  filter.IsLactoseFree,
@@ -172,41 +215,10 @@ func (h *Handler) leaderboard(w http.ResponseWriter, r *http.Request) {
 // fetchPersonalLeaderboard gets personalized rankings for a user, optionally
 // narrowed down by dietary filter flags
 func (h *Handler) fetchPersonalLeaderboard(r *http.Request, userId, category string, filter domain.TorroFilter) ([]LeaderboardEntry, string, int) {
-	minVotes := getMinVotesForClass(category)
-	if category == "global" {
-		// getMinVotesForClass only knows the concrete class ids "1".."5"; the
-		// "global" pseudo-category falls through to its default (25). Gate the
-		// global/absolute leaderboard on the shared threshold instead so it
-		// stays consistent with the JSON API and /wrapped, /reveal.
-		minVotes = globalLeaderboardMinVotes
-	}
-
-	// The "Global" competition is class id "5" AND the "global" pseudo-category;
-	// both mean "the whole thing", gated on total votes. The stats page unlocks
-	// class "5" against user.VoteCount (see stats_handler.go) and its "Veure
-	// resultats" link passes category=5, so this leaderboard MUST count total
-	// votes for "5" too — otherwise it re-checks the tiny per-arena count and
-	// contradicts the stats page ("51/50 unlocked" -> "no tens prou vots").
-	isGlobal := category == "global" || category == "5"
-
-	// Check if user has enough votes
-	var voteCount int
-	var err error
-	if isGlobal {
-		// For global, check total vote count
-		user, err := h.userRepo.Get(r.Context(), userId)
-		if err != nil {
-			logger.Error("[Handler - Leaderboard] Couldn't get user. %v", err)
-			return nil, "Error al carregar els resultats", 0
-		}
-		voteCount = user.VoteCount
-	} else {
-		// For specific class, check class vote count
-		voteCount, err = h.userRepo.GetVoteCountForClass(r.Context(), userId, category)
-		if err != nil {
-			logger.Error("[Handler - Leaderboard] Couldn't get vote count. %v", err)
-			return nil, "Error al carregar els resultats", 0
-		}
+	voteCount, minVotes, err := h.personalVoteGate(r.Context(), userId, category)
+	if err != nil {
+		logger.Error("[Handler - Leaderboard] Couldn't get vote count. %v", err)
+		return nil, "Error al carregar els resultats", 0
 	}
 
 	if voteCount < minVotes {
@@ -368,11 +380,31 @@ func (h *Handler) calculateRankChanges(r *http.Request, w http.ResponseWriter, e
 
 	// Create new ranks map for storing
 	currentRanks := make(map[string]int)
-
-	// Calculate changes and update entries
 	for i := range entries {
 		currentRanks[entries[i].TorronId] = entries[i].Rank
+	}
+
+	setRankChanges(entries, previousRanks)
+
+	// Store current ranks in cookie for next visit
+	ranksJSON, _ := json.Marshal(currentRanks)
+	http.SetCookie(w, &http.Cookie{
+		Name:     cookieName,
+		Value:    url.QueryEscape(string(ranksJSON)),
+		Path:     "/",
+		MaxAge:   30 * 24 * 60 * 60, // 30 days
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
 
+	return entries
+}
+
+// setRankChanges fills each entry's rank-change fields from its rank in
+// previousRanks; an entry missing from it is marked new.
+func setRankChanges(entries []LeaderboardEntry, previousRanks map[string]int) {
+	for i := range entries {
 		if prevRank, exists := previousRanks[entries[i].TorronId]; exists {
 			change := prevRank - entries[i].Rank // Positive = moved up, negative = moved down
 			entries[i].RankChange = change
@@ -398,18 +430,4 @@ func (h *Handler) calculateRankChanges(r *http.Request, w http.ResponseWriter, e
 			entries[i].RankChangeSymbol = "NOU"
 		}
 	}
-
-	// Store current ranks in cookie for next visit
-	ranksJSON, _ := json.Marshal(currentRanks)
-	http.SetCookie(w, &http.Cookie{
-		Name:     cookieName,
-		Value:    url.QueryEscape(string(ranksJSON)),
-		Path:     "/",
-		MaxAge:   30 * 24 * 60 * 60, // 30 days
-		HttpOnly: true,
-		Secure:   r.TLS != nil,
-		SameSite: http.SameSiteLaxMode,
-	})
-
-	return entries
 }
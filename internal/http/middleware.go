@@ -2,7 +2,9 @@ package http
 
 import (
 	"context"
+	"crypto/sha256"
 	"crypto/subtle"
+	"encoding/hex"
 	"fmt"
 	"net"
 	"net/http"
@@ -190,9 +192,11 @@ func (h *Handler) UserMiddleware(next http.Handler) http.Handler {
 		if userId == "" {
 			userId = uuid.NewString()
 
+			ipHash := hashClientIP(r.RemoteAddr)
 			newUser := &domain.User{
 				Id:        userId,
 				VoteCount: 0,
+				IpHash:    &ipHash,
 			}
 
 			createdUser, err := h.userRepo.Create(r.Context(), newUser)
@@ -223,6 +227,21 @@ func (h *Handler) UserMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// hashClientIP returns the truncated SHA-256 stored as a new user's
+// IpHash. By the time UserMiddleware runs, trustedProxyResolver has already
+// replaced RemoteAddr with the real client IP; a port, if any is left, is
+// dropped so two connections from one address hash the same. The fraud
+// analyzer only ever compares these hashes for equality, so the IP itself is
+// never stored.
+func hashClientIP(remoteAddr string) string {
+	host := remoteAddr
+	if h, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		host = h
+	}
+	sum := sha256.Sum256([]byte(host))
+	return hex.EncodeToString(sum[:16])
+}
+
 // GetUserIDFromContext retrieves the user ID from request context
 // Returns empty string if not found
 func GetUserIDFromContext(ctx context.Context) string {
@@ -95,3 +95,16 @@ func TestRequireAdminToken(t *testing.T) {
 		})
 	}
 }
+
+func TestHashClientIP(t *testing.T) {
+	hash := hashClientIP("203.0.113.7")
+	if len(hash) != 32 {
+		t.Errorf("hash %q has length %d, want 32", hash, len(hash))
+	}
+	if got := hashClientIP("203.0.113.7:52114"); got != hash {
+		t.Errorf("hash with a port = %q, want %q", got, hash)
+	}
+	if got := hashClientIP("203.0.113.8"); got == hash {
+		t.Error("two different IPs hashed the same")
+	}
+}
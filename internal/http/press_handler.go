@@ -57,6 +57,18 @@ type PressContent struct {
 	DuelBPercentage int
 	DuelTotalVotes  int
 
+	HasLeader       bool
+	LeaderId        string
+	LeaderName      string
+	RunnerUpId      string
+	RunnerUpName    string
+	LeaderSeparable bool // #1 ahead of #2 at the 95% level (Bradley–Terry fit)
+
+	// Reasons is what voters say decides a duel, across every tagged vote
+	// (see voteReasons).
+	HasReasons bool
+	Reasons    []domain.ReasonShare
+
 	HasChampion   bool
 	ChampionId    string
 	ChampionName  string
@@ -98,6 +110,16 @@ type pressStatsBlock struct {
 	DuelBPercentage int
 	DuelTotalVotes  int
 
+	HasLeader       bool
+	LeaderId        string
+	LeaderName      string
+	RunnerUpId      string
+	RunnerUpName    string
+	LeaderSeparable bool
+
+	HasReasons bool
+	Reasons    []domain.ReasonShare
+
 	HasChampion   bool
 	ChampionId    string
 	ChampionName  string
@@ -128,8 +150,8 @@ var pressCache struct {
 }
 
 // press renders the /premsa page: a small set of screenshot-friendly stats
-// for journalists (most voted torró, biggest riser, closest duel, Gran
-// Final result), plus a self-service snippet generator for embedding the
+// for journalists (most voted torró, biggest riser, closest duel, whether
+// the leader is clear, what decides a duel, Gran Final result), plus a self-service snippet generator for embedding the
 // live leaderboard widget (see embed_handler.go) on a third party's site.
 // The stats are served from a short-TTL in-process cache (see pressStats);
 // the embed-picker fields are always built fresh per request.
@@ -173,6 +195,16 @@ func (h *Handler) press(w http.ResponseWriter, r *http.Request) {
 		DuelBPercentage: stats.DuelBPercentage,
 		DuelTotalVotes:  stats.DuelTotalVotes,
 
+		HasLeader:       stats.HasLeader,
+		LeaderId:        stats.LeaderId,
+		LeaderName:      stats.LeaderName,
+		RunnerUpId:      stats.RunnerUpId,
+		RunnerUpName:    stats.RunnerUpName,
+		LeaderSeparable: stats.LeaderSeparable,
+
+		HasReasons: stats.HasReasons,
+		Reasons:    stats.Reasons,
+
 		HasChampion:   stats.HasChampion,
 		ChampionId:    stats.ChampionId,
 		ChampionName:  stats.ChampionName,
@@ -310,12 +342,35 @@ func (h *Handler) computePressStats(ctx context.Context) (pressStatsBlock, error
 		block.DuelTotalVotes = duel.TotalVotes
 	}
 
-	// The Gran Final is Phase 2's knockout bracket for the Global class,
+	// "Is the top spot a real difference?" comes from the scheduled batch
+	// Bradley–Terry fit rather than the live ratings; before the first fit
+	// the card shows its empty state.
+	strengths, err := h.loadStrengthView(ctx)
+	if err != nil {
+		return pressStatsBlock{}, err
+	}
+	if leader := strengths.leader(""); leader != nil {
+		block.HasLeader = true
+		block.LeaderId = leader.LeaderId
+		block.LeaderName = leader.LeaderName
+		block.RunnerUpId = leader.RunnerUpId
+		block.RunnerUpName = leader.RunnerUpName
+		block.LeaderSeparable = leader.Separable
+	}
+
+	reasons, err := h.reasonRepo.Breakdown(ctx)
+	if err != nil {
+		return pressStatsBlock{}, err
+	}
+	block.Reasons = domain.TopReasons(reasons, 0)
+	block.HasReasons = len(block.Reasons) > 0
+
+	// The Gran Final is Phase 2's meta-bracket of the class champions,
 	// separate from the Phase 1 ELO stats above. pressGlobalChampion
 	// follows the existing convention in bracket_handler.go's
-	// bracketOverview: any error resolving the latest bracket (most
-	// commonly "no bracket created yet for this class") is treated as the
-	// empty state rather than a hard failure.
+	// bracketOverview: any error resolving the latest Gran Final (most
+	// commonly "not every class bracket has completed yet") is treated as
+	// the empty state rather than a hard failure.
 	champion, err := h.pressGlobalChampion(ctx)
 	if err != nil {
 		return pressStatsBlock{}, err
@@ -341,15 +396,16 @@ func votePercentage(value float64, total int) int {
 	return int(math.Round(value / float64(total) * 100))
 }
 
-// pressGlobalChampion resolves the Global bracket's champion torró, if
-// the Gran Final has been decided. Returns (nil, nil) - not an error -
-// when there's no bracket yet, it's still in progress, or GetLatestByClass
-// itself errors (following bracketOverview's precedent that "no bracket"
-// is a legitimate empty state, not a failure). Shared by press (the page)
-// and pressKitCard (the PNG one-pager) so the two surfaces can never
-// disagree about who the champion is.
+// pressGlobalChampion resolves the champion torró of the latest Gran Final
+// meta-bracket (see createGranFinalIfReady), if it has been decided.
+// Returns (nil, nil) - not an error - when there's no Gran Final yet, it's
+// still in progress, or GetLatestGranFinal itself errors (following
+// bracketOverview's precedent that "no bracket" is a legitimate empty
+// state, not a failure). Shared by press (the page) and pressKitCard (the
+// PNG one-pager) so the two surfaces can never disagree about who the
+// champion is.
 func (h *Handler) pressGlobalChampion(ctx context.Context) (*domain.Torro, error) {
-	bracket, err := h.bracketRepo.GetLatestByClass(ctx, embedDefaultClassId)
+	bracket, err := h.bracketRepo.GetLatestGranFinal(ctx)
 	if err != nil || bracket == nil || bracket.Status != domain.BracketStatusCompleted || bracket.ChampionId == nil {
 		return nil, nil
 	}
@@ -0,0 +1,193 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+
+	"github.com/krtffl/torro/internal/domain"
+	"github.com/krtffl/torro/internal/logger"
+)
+
+// provisionalLeaderboardLimit caps a provisional leaderboard at the same
+// 100 torrons as the global one it is blended with.
+const provisionalLeaderboardLimit = 100
+
+// handleUserProvisionalLeaderboard handles
+// GET /api/user/leaderboard/provisional/class/{classId}: the user's
+// personal ratings in a class blended with the global ones, available from
+// the first vote.
+func (h *Handler) handleUserProvisionalLeaderboard(w http.ResponseWriter, r *http.Request) {
+	userId := GetUserIDFromContext(r.Context())
+	if userId == "" {
+		render.Status(r, http.StatusUnauthorized)
+		render.JSON(w, r, map[string]string{"error": "No user session found"})
+		return
+	}
+
+	classId := chi.URLParam(r, "classId")
+	classes, err := h.classRepo.List(r.Context())
+	if err != nil {
+		logger.Error("[User API - Provisional Leaderboard] Couldn't list classes. %v", err)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+		return
+	}
+	if !classExists(classes, classId) {
+		render.Render(w, r, domain.ErrNotFound(fmt.Errorf("%s: class %s not found", domain.NotFoundError, classId)))
+		return
+	}
+
+	h.renderProvisionalLeaderboard(w, r, userId, classId)
+}
+
+// handleUserProvisionalGlobalLeaderboard handles
+// GET /api/user/leaderboard/provisional/global, the provisional
+// counterpart of /api/user/leaderboard/global.
+func (h *Handler) handleUserProvisionalGlobalLeaderboard(w http.ResponseWriter, r *http.Request) {
+	userId := GetUserIDFromContext(r.Context())
+	if userId == "" {
+		render.Status(r, http.StatusUnauthorized)
+		render.JSON(w, r, map[string]string{"error": "No user session found"})
+		return
+	}
+
+	h.renderProvisionalLeaderboard(w, r, userId, "global")
+}
+
+func (h *Handler) renderProvisionalLeaderboard(w http.ResponseWriter, r *http.Request, userId, category string) {
+	voteCount, minVotes, err := h.personalVoteGate(r.Context(), userId, category)
+	if err != nil {
+		logger.Error("[User API - Provisional Leaderboard] Couldn't get vote count. %v", err)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+		return
+	}
+
+	entries, err := h.provisionalLeaderboard(r.Context(), userId, category, parseTorroFilter(r))
+	if err != nil {
+		logger.Error("[User API - Provisional Leaderboard] Couldn't build leaderboard. %v", err)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+		return
+	}
+
+	response := map[string]interface{}{
+		"user_id":            userId,
+		"vote_count":         voteCount,
+		"entries":            entries,
+		"total_entries":      len(entries),
+		"min_votes_met":      voteCount >= minVotes,
+		"min_votes_required": minVotes,
+		"confidence":         domain.ProvisionalConfidence(voteCount, minVotes),
+		"prior_votes":        domain.ProvisionalPriorVotes,
+	}
+	if category != "global" {
+		response["class_id"] = category
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, response)
+}
+
+// fetchProvisionalLeaderboard is fetchPersonalLeaderboard without the vote
+// gate: the user's ratings blended with the global ones. It also returns
+// the user's vote count, the threshold the real view unlocks at and the
+// resulting confidence.
+func (h *Handler) fetchProvisionalLeaderboard(r *http.Request, userId, category string, filter domain.TorroFilter) ([]LeaderboardEntry, string, int, int, string) {
+	voteCount, minVotes, err := h.personalVoteGate(r.Context(), userId, category)
+	if err != nil {
+		logger.Error("[Handler - Leaderboard] Couldn't get vote count. %v", err)
+		return nil, "Error al carregar els resultats", 0, 0, ""
+	}
+
+	provisional, err := h.provisionalLeaderboard(r.Context(), userId, category, filter)
+	if err != nil {
+		logger.Error("[Handler - Leaderboard] Couldn't build provisional leaderboard. %v", err)
+		return nil, "Error al carregar els resultats", 0, 0, ""
+	}
+
+	entries := make([]LeaderboardEntry, len(provisional))
+	for i, entry := range provisional {
+		entries[i] = LeaderboardEntry{
+			Rank:             entry.Rank,
+			TorronId:         entry.TorronId,
+			TorronName:       entry.TorronName,
+			TorronImage:      entry.TorronImage,
+			Rating:           entry.Rating,
+			VoteCount:        entry.VoteCount,
+			Provisional:      true,
+			WeightPercentage: int(math.Round(entry.Weight * 100)),
+		}
+	}
+
+	return entries, "", voteCount, minVotes, domain.ProvisionalConfidence(voteCount, minVotes)
+}
+
+// provisionalLeaderboard ranks the active torrons in category ("global"
+// for all of them) on userId's ratings blended with the global ones.
+func (h *Handler) provisionalLeaderboard(ctx context.Context, userId, category string, filter domain.TorroFilter) ([]*domain.ProvisionalEntry, error) {
+	classId := category
+	if category == "global" {
+		classId = ""
+	}
+
+	torrons, err := h.torroRepo.ListFiltered(ctx, classId, filter)
+	if err != nil {
+		return nil, err
+	}
+	active := make([]*domain.Torro, 0, len(torrons))
+	for _, t := range torrons {
+		if !t.Discontinued {
+			active = append(active, t)
+		}
+	}
+
+	snapshots, err := h.userEloRepo.ListByUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := domain.ProvisionalRanking(active, snapshots)
+	return entries[:min(provisionalLeaderboardLimit, len(entries))], nil
+}
+
+// personalVoteGate returns how many votes userId has towards the personal
+// leaderboard of category and how many unlock it, for both the gated view
+// and the provisional one's confidence.
+func (h *Handler) personalVoteGate(ctx context.Context, userId, category string) (int, int, error) {
+	minVotes := getMinVotesForClass(category)
+	if category == "global" {
+		// getMinVotesForClass only knows the concrete class ids "1".."5"; the
+		// "global" pseudo-category falls through to its default (25). Gate the
+		// global/absolute leaderboard on the shared threshold instead so it
+		// stays consistent with the JSON API and /wrapped, /reveal.
+		minVotes = globalLeaderboardMinVotes
+	}
+
+	// The "Global" competition is class id "5" AND the "global" pseudo-category;
+	// both mean "the whole thing", gated on total votes this season. The stats
+	// page unlocks class "5" against user.SeasonVoteCount() (see
+	// stats_handler.go) and its "Veure resultats" link passes category=5, so
+	// this leaderboard MUST count total votes for "5" too — otherwise it
+	// re-checks the tiny per-arena count and contradicts the stats page
+	// ("51/50 unlocked" -> "no tens prou vots"). Like the personal snapshots
+	// it gates, the count starts over with each rating season.
+	if category == "global" || category == "5" {
+		user, err := h.userRepo.Get(ctx, userId)
+		if err != nil {
+			return 0, 0, err
+		}
+		return user.SeasonVoteCount(), minVotes, nil
+	}
+
+	voteCount, err := h.userRepo.GetVoteCountForClass(ctx, userId, category)
+	if err != nil {
+		return 0, 0, err
+	}
+	return voteCount, minVotes, nil
+}
@@ -0,0 +1,63 @@
+package http
+
+import (
+	"html/template"
+	"strings"
+	"testing"
+
+	torrons "github.com/krtffl/torro"
+	"github.com/krtffl/torro/internal/domain"
+)
+
+func TestProvisionalLeaderboardTemplate(t *testing.T) {
+	tmpls, err := template.New("").Funcs(templateFuncs).ParseFS(torrons.Public, "public/templates/*.html")
+	if err != nil {
+		t.Fatalf("failed to parse templates: %v", err)
+	}
+
+	var sb strings.Builder
+	if err := tmpls.ExecuteTemplate(&sb, "leaderboard.html", LeaderboardContent{
+		HX:               true,
+		Title:            "Classificació provisional - Global",
+		ViewType:         "provisional",
+		SelectedCategory: "global",
+		MinVotes:         50,
+		VoteCount:        20,
+		Confidence:       domain.ConfidenceMedium,
+		Entries: []LeaderboardEntry{
+			{Rank: 1, TorronId: "a", TorronName: "Torró A", Rating: 1740, VoteCount: 20, Provisional: true, WeightPercentage: 80},
+			{Rank: 4, TorronId: "b", TorronName: "Torró B", Rating: 1500, Provisional: true},
+		},
+	}); err != nil {
+		t.Fatalf("failed to render leaderboard.html: %v", err)
+	}
+	out := sb.String()
+	for _, want := range []string{
+		"Confiança mitjana",
+		"20/50 vots",
+		"confidence-medium",
+		"80%",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("provisional leaderboard.html is missing %q", want)
+		}
+	}
+	if n := strings.Count(out, "Teu:"); n != 2 {
+		t.Errorf("%d entries show the user's weight, want both, an unrated one at 0%%", n)
+	}
+
+	// The gated personal view points at the provisional one while locked.
+	sb.Reset()
+	if err := tmpls.ExecuteTemplate(&sb, "leaderboard.html", LeaderboardContent{
+		HX:               true,
+		ViewType:         "personal",
+		SelectedCategory: "3",
+		Error:            "No tens prou vots per veure els resultats personalitzats",
+		MinVotes:         30,
+	}); err != nil {
+		t.Fatalf("failed to render locked leaderboard.html: %v", err)
+	}
+	if !strings.Contains(sb.String(), "view=provisional&category=3") {
+		t.Error("the locked personal view should link to the provisional one")
+	}
+}
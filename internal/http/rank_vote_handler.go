@@ -0,0 +1,291 @@
+package http
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+
+	"github.com/krtffl/torro/internal/domain"
+	"github.com/krtffl/torro/internal/logger"
+)
+
+// RankContent is the template payload for rank.html, the vote mode where
+// the user drags 3 or 4 torrons of a class into order instead of picking
+// one of two.
+type RankContent struct {
+	HX      bool
+	ClassId string
+	Torrons []*domain.Torro
+
+	// Saved confirms the previous ranking was recorded; Duels is how many
+	// pairwise votes it counted as.
+	Saved bool
+	Duels int
+}
+
+// rankVote handles GET /classes/{id}/rank: serves a random set of torrons
+// of the class to put in order. ?size=3 asks for three instead of
+// domain.RankingMaxSize.
+func (h *Handler) rankVote(w http.ResponseWriter, r *http.Request) {
+	logger.Info("[Handler - RankVote] Incoming request")
+
+	classId := chi.URLParam(r, "id")
+	size := domain.RankingMaxSize
+	if s, err := strconv.Atoi(r.URL.Query().Get("size")); err == nil {
+		size = max(domain.RankingMinSize, min(s, domain.RankingMaxSize))
+	}
+
+	torrons, err := h.drawRankingTorrons(r, classId, size)
+	if err != nil {
+		logger.Error("[Handler - RankVote] Couldn't draw torrons to rank in class %s. %v", classId, err)
+		render.Render(w, r, domain.ErrFromRepo(err))
+		return
+	}
+
+	h.renderRank(w, r, RankContent{
+		HX:      isHX(r),
+		ClassId: classId,
+		Torrons: torrons,
+	})
+}
+
+// rankResult handles POST /classes/{id}/rank: records the submitted order
+// (form values "torro", best first) as one RankingSubmission and every
+// pairwise outcome it implies, through the same rating and snapshot path
+// as a duel vote, then serves the next set to rank. A ranking is not
+// undoable, and it drops any pending duel undo (see VoteUndoRepo.ForgetTx).
+func (h *Handler) rankResult(w http.ResponseWriter, r *http.Request) {
+	logger.Info("[Handler - RankResult] Incoming request")
+
+	classId := chi.URLParam(r, "id")
+	if err := r.ParseForm(); err != nil {
+		render.Render(w, r, domain.ErrBadRequest(
+			fmt.Errorf("%s: couldn't read the ranking. %v", domain.ValidationError, err)))
+		return
+	}
+	submission := &domain.RankingSubmission{
+		ClassId:  classId,
+		TorroIds: r.PostForm["torro"],
+	}
+	if err := domain.ValidateRanking(submission.TorroIds); err != nil {
+		render.Render(w, r, domain.ErrBadRequest(err))
+		return
+	}
+
+	// Every implied duel must be a live pairing of the class, which also
+	// rules out discontinued torrons and ones from another class.
+	pairings, err := h.pairingRepo.ListByClass(r.Context(), classId)
+	if err != nil {
+		logger.Error("[Handler - RankResult] Couldn't list pairings of class %s. %v", classId, err)
+		render.Render(w, r, domain.ErrFromRepo(err))
+		return
+	}
+	byKey := make(map[string]*domain.Pairing, len(pairings))
+	for _, p := range pairings {
+		byKey[p.Key()] = p
+	}
+	duels := submission.Duels()
+	duelPairings := make([]*domain.Pairing, len(duels))
+	for i, d := range duels {
+		p, ok := byKey[(&domain.Pairing{Class: classId, Torro1: d.Winner, Torro2: d.Loser}).Key()]
+		if !ok {
+			render.Render(w, r, domain.ErrBadRequest(
+				fmt.Errorf("%s: these torrons can't be ranked together", domain.ValidationError)))
+			return
+		}
+		duelPairings[i] = p
+	}
+
+	userId := GetUserIDFromContext(r.Context())
+	if userId != "" {
+		submission.UserId = &userId
+	}
+
+	// Outside the transaction for the same reasons as in result.
+	if campaign, err := h.campaignRepo.GetActive(r.Context()); err == nil {
+		submission.CampaignId = &campaign.Id
+		h.syncRatingSeason(r.Context(), campaign.Id)
+	} else {
+		logger.Debug("[Handler - RankResult] No active campaign to tag this ranking with. %v", err)
+	}
+
+	tx, err := h.db.Begin()
+	if err != nil {
+		logger.Error("[Handler - RankResult] Couldn't start transaction. %v", err)
+		render.Render(w, r, domain.ErrInternal(err))
+		return
+	}
+	defer tx.Rollback() // Rollback if not committed
+
+	// Same lock order as a duel vote: torrons by id, then the user, then
+	// the undo row.
+	lockOrder := append([]string(nil), submission.TorroIds...)
+	sort.Strings(lockOrder)
+	locked := make(map[string]*domain.Torro, len(lockOrder))
+	for _, id := range lockOrder {
+		t, err := h.torroRepo.GetTx(tx, r.Context(), id)
+		if err != nil {
+			logger.Error("[Handler - RankResult] Couldn't get torro %s. %v", id, err)
+			render.Render(w, r, domain.ErrInternal(err))
+			return
+		}
+		locked[id] = t
+	}
+
+	var user *domain.User
+	if userId != "" {
+		user, err = h.userRepo.GetTx(tx, r.Context(), userId)
+		if err != nil {
+			logger.Error("[Handler - RankResult] Couldn't get user %s. %v", userId, err)
+			render.Render(w, r, domain.ErrInternal(err))
+			return
+		}
+	}
+
+	if _, err := h.rankingSubmissionRepo.CreateTx(tx, r.Context(), submission); err != nil {
+		logger.Error("[Handler - RankResult] Couldn't create ranking submission. %v", err)
+		render.Render(w, r, domain.ErrInternal(err))
+		return
+	}
+
+	for i, d := range duels {
+		p := duelPairings[i]
+		score1 := 0.0
+		if d.Winner == p.Torro1 {
+			score1 = 1
+		}
+		if _, err := h.applyDuelTx(tx, r.Context(), duelVote{
+			pairing:      p,
+			t1:           locked[p.Torro1],
+			t2:           locked[p.Torro2],
+			score1:       score1,
+			user:         user,
+			campaignId:   submission.CampaignId,
+			submissionId: &submission.Id,
+		}); err != nil {
+			logger.Error("[Handler - RankResult] Couldn't record %s over %s. %v", d.Winner, d.Loser, err)
+			render.Render(w, r, domain.ErrInternal(err))
+			return
+		}
+	}
+
+	if user != nil {
+		if err := h.voteUndoRepo.ForgetTx(tx, r.Context(), userId); err != nil {
+			logger.Error("[Handler - RankResult] Couldn't drop pending undo. %v", err)
+			render.Render(w, r, domain.ErrInternal(err))
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error("[Handler - RankResult] Couldn't commit transaction. %v", err)
+		render.Render(w, r, domain.ErrInternal(err))
+		return
+	}
+
+	torrons, err := h.drawRankingTorrons(r, classId, len(submission.TorroIds))
+	if err != nil {
+		logger.Error("[Handler - RankResult] Couldn't draw the next torrons to rank. %v", err)
+		render.Render(w, r, domain.ErrInternal(err))
+		return
+	}
+
+	h.renderRank(w, r, RankContent{
+		HX:      isHX(r),
+		ClassId: classId,
+		Torrons: torrons,
+		Saved:   true,
+		Duels:   len(duels),
+	})
+}
+
+// drawRankingTorrons picks size torrons of the class, every two of which
+// have a live pairing, in random order. The error is a NotFound one when
+// the class has no such set.
+func (h *Handler) drawRankingTorrons(r *http.Request, classId string, size int) ([]*domain.Torro, error) {
+	pairings, err := h.pairingRepo.ListByClass(r.Context(), classId)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := pickRankingSet(pairings, size, rand.Shuffle)
+	if ids == nil {
+		return nil, fmt.Errorf("%s: class %s has no %d torrons to rank together", domain.NotFoundError, classId, size)
+	}
+
+	torrons := make([]*domain.Torro, 0, len(ids))
+	for _, id := range ids {
+		t, err := h.torroRepo.Get(r.Context(), id)
+		if err != nil {
+			return nil, err
+		}
+		torrons = append(torrons, t)
+	}
+	return torrons, nil
+}
+
+// pickRankingSet returns size torró ids from pairings that are all paired
+// with each other, or nil if there are none. In a regular class every two
+// active torrons are paired; in the global class only torrons meeting each
+// other's class leaders are, so the set is grown greedily from each
+// starting torró in turn. shuffle is rand.Shuffle, injectable for tests.
+func pickRankingSet(pairings []*domain.Pairing, size int, shuffle func(n int, swap func(i, j int))) []string {
+	paired := make(map[string]map[string]bool)
+	for _, p := range pairings {
+		for _, pair := range [][2]string{{p.Torro1, p.Torro2}, {p.Torro2, p.Torro1}} {
+			if paired[pair[0]] == nil {
+				paired[pair[0]] = make(map[string]bool)
+			}
+			paired[pair[0]][pair[1]] = true
+		}
+	}
+
+	ids := make([]string, 0, len(paired))
+	for id := range paired {
+		ids = append(ids, id)
+	}
+	// Sorted first so the shuffle alone decides the order.
+	sort.Strings(ids)
+	shuffle(len(ids), func(i, j int) { ids[i], ids[j] = ids[j], ids[i] })
+
+	for start := range ids {
+		set := []string{ids[start]}
+		for _, candidate := range ids {
+			if len(set) == size {
+				break
+			}
+			fits := true
+			for _, member := range set {
+				if !paired[member][candidate] {
+					fits = false
+					break
+				}
+			}
+			if fits {
+				set = append(set, candidate)
+			}
+		}
+		if len(set) == size {
+			return set
+		}
+	}
+	return nil
+}
+
+func (h *Handler) renderRank(w http.ResponseWriter, r *http.Request, content RankContent) {
+	buf := h.bpool.Get()
+	defer h.bpool.Put(buf)
+
+	if err := h.template.ExecuteTemplate(buf, "rank.html", content); err != nil {
+		logger.Error("[Handler - RankVote] Couldn't execute template. %v", err)
+		h.renderErrorPage(w)
+		return
+	}
+
+	buf.WriteTo(w)
+}
@@ -0,0 +1,92 @@
+package http
+
+import (
+	"html/template"
+	"strings"
+	"testing"
+	"time"
+
+	torrons "github.com/krtffl/torro"
+	"github.com/krtffl/torro/internal/domain"
+)
+
+func noShuffle(int, func(i, j int)) {}
+
+func TestPickRankingSet(t *testing.T) {
+	// A regular class: every two of a, b, c, d, e are paired.
+	var full []*domain.Pairing
+	ids := []string{"a", "b", "c", "d", "e"}
+	for i := range ids {
+		for j := i + 1; j < len(ids); j++ {
+			full = append(full, &domain.Pairing{Torro1: ids[i], Torro2: ids[j]})
+		}
+	}
+	if got := pickRankingSet(full, 4, noShuffle); strings.Join(got, ",") != "a,b,c,d" {
+		t.Errorf("pickRankingSet(full, 4) = %v, want [a b c d]", got)
+	}
+
+	// A global-class shape: x only meets a and b, which meet each other;
+	// the one fully paired trio must be found whatever the start.
+	partial := []*domain.Pairing{
+		{Torro1: "x", Torro2: "a"},
+		{Torro1: "x", Torro2: "b"},
+		{Torro1: "a", Torro2: "b"},
+		{Torro1: "y", Torro2: "a"},
+	}
+	got := pickRankingSet(partial, 3, noShuffle)
+	if strings.Join(got, ",") != "a,b,x" {
+		t.Errorf("pickRankingSet(partial, 3) = %v, want [a b x]", got)
+	}
+
+	if got := pickRankingSet(partial, 4, noShuffle); got != nil {
+		t.Errorf("pickRankingSet(partial, 4) = %v, want nil", got)
+	}
+}
+
+func TestRankAndHistoryTemplates(t *testing.T) {
+	tmpls, err := template.New("").Funcs(templateFuncs).ParseFS(torrons.Public, "public/templates/*.html")
+	if err != nil {
+		t.Fatalf("failed to parse templates: %v", err)
+	}
+
+	var sb strings.Builder
+	if err := tmpls.ExecuteTemplate(&sb, "rank.html", RankContent{
+		HX:      true,
+		ClassId: "2",
+		Torrons: []*domain.Torro{{Id: "a", Name: "Torró A"}, {Id: "b", Name: "Torró B"}, {Id: "c", Name: "Torró C"}},
+		Saved:   true,
+		Duels:   3,
+	}); err != nil {
+		t.Fatalf("failed to render rank.html: %v", err)
+	}
+	out := sb.String()
+	if n := strings.Count(out, `name="torro"`); n != 3 {
+		t.Errorf("rank form has %d torró fields, want 3", n)
+	}
+	for _, want := range []string{`hx-post="/classes/2/rank"`, "Rànquing desat · 3 vots"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("rank.html is missing %q", want)
+		}
+	}
+
+	sb.Reset()
+	if err := tmpls.ExecuteTemplate(&sb, "history.html", HistoryContent{
+		HX:             true,
+		FilterCategory: "all",
+		Votes: []VoteHistory{{
+			SubmissionId: "s",
+			Ranking:      []HistoryRankedTorro{{Name: "Torró C"}, {Name: "Torró A"}, {Name: "Torró B"}},
+			CategoryName: "Clàssics",
+			Timestamp:    time.Now(),
+		}},
+	}); err != nil {
+		t.Fatalf("failed to render history.html: %v", err)
+	}
+	out = sb.String()
+	if !strings.Contains(out, "Clàssics · Rànquing") {
+		t.Error("history doesn't label the ranking")
+	}
+	if c, b := strings.Index(out, "Torró C"), strings.Index(out, "Torró B"); c < 0 || b < c {
+		t.Error("history doesn't list the ranking best first")
+	}
+}
@@ -26,6 +26,9 @@ const rankingCategoryStoredN = 10
 type RankingCategory struct {
 	Class   *domain.Class
 	Entries []LeaderboardEntry
+	// Leader says whether the category's #1 is statistically ahead of its
+	// #2 by the batch Bradley–Terry fit; nil until there is a fit.
+	Leader *domain.LeaderSeparation
 }
 
 // RankingContent is the template payload for ranquing.html, the public,
@@ -36,6 +39,8 @@ type RankingContent struct {
 	Entries    []LeaderboardEntry
 	Categories []RankingCategory
 	TotalVotes int
+	// Leader is RankingCategory.Leader across every category.
+	Leader *domain.LeaderSeparation
 	// UpdatedAt is the human-readable (Catalan) date the cached standings
 	// were computed, surfaced on-page as a freshness signal; UpdatedAtES is
 	// the same date for the Spanish pages; UpdatedAtISO is the instant for
@@ -161,6 +166,14 @@ func (h *Handler) computeRankingContent(r *http.Request) (RankingContent, error)
 	}
 	global = calculateRatingPercentages(global)
 
+	// The fit is an extra on these pages: without one they still show the
+	// live ratings, just without intervals.
+	strengths, err := h.loadStrengthView(ctx)
+	if err != nil {
+		logger.Warn("[Handler - PublicRanking] Couldn't load torró strengths. %v", err)
+	}
+	attachStrengths(global, strengths)
+
 	classes, err := h.classRepo.List(ctx)
 	if err != nil {
 		return RankingContent{}, fmt.Errorf("listing classes: %w", err)
@@ -178,7 +191,12 @@ func (h *Handler) computeRankingContent(r *http.Request) (RankingContent, error)
 		if len(entries) > rankingCategoryStoredN {
 			entries = entries[:rankingCategoryStoredN]
 		}
-		categories = append(categories, RankingCategory{Class: class, Entries: entries})
+		attachStrengths(entries, strengths)
+		categories = append(categories, RankingCategory{
+			Class:   class,
+			Entries: entries,
+			Leader:  strengths.leader(class.Id),
+		})
 	}
 
 	totalVotes, err := h.pressStatsRepo.TotalVotes(ctx)
@@ -191,6 +209,7 @@ func (h *Handler) computeRankingContent(r *http.Request) (RankingContent, error)
 		Entries:      global,
 		Categories:   categories,
 		TotalVotes:   totalVotes,
+		Leader:       strengths.leader(""),
 		UpdatedAt:    formatCatalanDate(now),
 		UpdatedAtES:  formatSpanishDate(now),
 		UpdatedAtISO: now.Format("2006-01-02"),
@@ -97,6 +97,32 @@ func TestRankingTemplate(t *testing.T) {
 		}
 	})
 
+	t.Run("strengths and leader verdict", func(t *testing.T) {
+		content := rankingTestContent()
+		content.Entries[0].Strength = &domain.TorroStrength{Strength: 1702.4, Lower: 1670, Upper: 1734.8}
+		content.Leader = &domain.LeaderSeparation{LeaderName: "Praliné", RunnerUpName: "Massapà"}
+
+		var sb strings.Builder
+		if err := tmpls.ExecuteTemplate(&sb, "ranquing.html", content); err != nil {
+			t.Fatalf("failed to render: %v", err)
+		}
+		body := sb.String()
+		for _, want := range []string{"BT 1702 ±32", "Massapà", "empat estadístic"} {
+			if !strings.Contains(body, want) {
+				t.Errorf("expected body to contain %q", want)
+			}
+		}
+
+		content.Leader.Separable = true
+		sb.Reset()
+		if err := tmpls.ExecuteTemplate(&sb, "ranquing.html", content); err != nil {
+			t.Fatalf("failed to render: %v", err)
+		}
+		if body := sb.String(); strings.Contains(body, "empat estadístic") || !strings.Contains(body, "95% de confiança") {
+			t.Error("a separable leader should be reported as a real difference")
+		}
+	})
+
 	t.Run("millors-vicens full page", func(t *testing.T) {
 		var sb strings.Builder
 		content := rankingTestContent()
@@ -0,0 +1,228 @@
+package http
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+
+	"github.com/krtffl/torro/internal/domain"
+	"github.com/krtffl/torro/internal/logger"
+)
+
+// torroReasonLimit is how many reasons the detail page lists for a torró's
+// wins and for its losses.
+const torroReasonLimit = 3
+
+// ReasonPicker is the "why?" tag picker rendered after a decided vote.
+type ReasonPicker struct {
+	ResultId string
+	Tags     []ReasonChoice
+
+	// Saved confirms the selection was just stored.
+	Saved bool
+}
+
+// ReasonChoice is one tag of the picker.
+type ReasonChoice struct {
+	Id       string
+	Label    string
+	Selected bool
+}
+
+// TorroReasonsResponse is what a torró wins and loses for, as served by
+// GET /api/torro/{id}/reasons.
+type TorroReasonsResponse struct {
+	TorroId string               `json:"torro_id"`
+	Wins    []domain.ReasonShare `json:"wins"`
+	Losses  []domain.ReasonShare `json:"losses"`
+}
+
+// newReasonPicker builds the picker for resultId with selected ticked. It
+// returns nil, hiding the picker, when the vocabulary is empty or can't be
+// read: a reason is a nice-to-have and must never get in the way of voting.
+func (h *Handler) newReasonPicker(ctx context.Context, resultId string, selected []string) *ReasonPicker {
+	tags, err := h.reasonRepo.ListTags(ctx)
+	if err != nil {
+		logger.Warn("[Handler - Reasons] Couldn't list reason tags. %v", err)
+		return nil
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	ticked := make(map[string]bool, len(selected))
+	for _, id := range selected {
+		ticked[id] = true
+	}
+
+	picker := &ReasonPicker{
+		ResultId: resultId,
+		Tags:     make([]ReasonChoice, 0, len(tags)),
+	}
+	for _, t := range tags {
+		picker.Tags = append(picker.Tags, ReasonChoice{Id: t.Id, Label: t.Label, Selected: ticked[t.Id]})
+	}
+	return picker
+}
+
+// voteReasons handles POST /pairings/votes/{resultId}/reasons: replaces the
+// reasons of one of the user's own decided votes with the submitted
+// selection (form values "tag", possibly none) and re-renders the picker.
+// The picker posts its whole selection on every change, so this is
+// idempotent.
+func (h *Handler) voteReasons(w http.ResponseWriter, r *http.Request) {
+	logger.Info("[Handler - VoteReasons] Incoming request")
+
+	resultId := chi.URLParam(r, "resultId")
+
+	userId := GetUserIDFromContext(r.Context())
+	if userId == "" {
+		logger.Warn("[Handler - VoteReasons] No user ID in context")
+		render.Render(w, r, domain.ErrUnauthorized(
+			fmt.Errorf("%s: no user to tag a vote for", domain.ValidationError)))
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		render.Render(w, r, domain.ErrBadRequest(
+			fmt.Errorf("%s: couldn't read the reasons. %v", domain.ValidationError, err)))
+		return
+	}
+	tagIds := r.PostForm["tag"]
+
+	tags, err := h.reasonRepo.ListTags(r.Context())
+	if err != nil {
+		logger.Error("[Handler - VoteReasons] Couldn't list reason tags. %v", err)
+		render.Render(w, r, domain.ErrInternal(err))
+		return
+	}
+	if err := domain.ValidateReasons(tagIds, tags); err != nil {
+		render.Render(w, r, domain.ErrBadRequest(err))
+		return
+	}
+
+	if err := h.reasonRepo.SetForResult(r.Context(), resultId, userId, tagIds); err != nil {
+		logger.Error("[Handler - VoteReasons] Couldn't set reasons of vote %s. %v", resultId, err)
+		render.Render(w, r, domain.ErrFromRepo(err))
+		return
+	}
+
+	picker := h.newReasonPicker(r.Context(), resultId, tagIds)
+	if picker == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	picker.Saved = true
+
+	buf := h.bpool.Get()
+	defer h.bpool.Put(buf)
+
+	if err := h.template.ExecuteTemplate(buf, "reason-picker", picker); err != nil {
+		logger.Error("[Handler - VoteReasons] Couldn't execute template. %v", err)
+		h.renderErrorPage(w)
+		return
+	}
+
+	buf.WriteTo(w)
+}
+
+// torroReasons handles GET /api/torro/{id}/reasons: every reason the
+// torró's wins and losses were tagged with, most frequent first.
+func (h *Handler) torroReasons(w http.ResponseWriter, r *http.Request) {
+	logger.Info("[Handler - TorroReasons] Incoming request")
+
+	id := chi.URLParam(r, "id")
+	if _, err := h.torroRepo.Get(r.Context(), id); err != nil {
+		logger.Error("[Handler - TorroReasons] Couldn't get torro %s. %v", id, err)
+		render.Render(w, r, domain.ErrFromRepo(err))
+		return
+	}
+
+	reasons, err := h.reasonRepo.ListForTorro(r.Context(), id)
+	if err != nil {
+		logger.Error("[Handler - TorroReasons] Couldn't list reasons of torro %s. %v", id, err)
+		render.Render(w, r, domain.ErrInternal(err))
+		return
+	}
+
+	response := TorroReasonsResponse{
+		TorroId: id,
+		Wins:    domain.TopReasons(reasons.Wins, 0),
+		Losses:  domain.TopReasons(reasons.Losses, 0),
+	}
+	if response.Wins == nil {
+		response.Wins = []domain.ReasonShare{}
+	}
+	if response.Losses == nil {
+		response.Losses = []domain.ReasonShare{}
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, response)
+}
+
+// exportReasons handles GET /api/reasons/export.csv: every torró's wins and
+// losses per reason, for the press page's data download.
+func (h *Handler) exportReasons(w http.ResponseWriter, r *http.Request) {
+	logger.Info("[Handler - ExportReasons] Incoming request")
+
+	rows, err := h.reasonRepo.Export(r.Context())
+	if err != nil {
+		logger.Error("[Handler - ExportReasons] Couldn't export reasons. %v", err)
+		render.Render(w, r, domain.ErrInternal(err))
+		return
+	}
+
+	buf := h.bpool.Get()
+	defer h.bpool.Put(buf)
+
+	if err := writeReasonExport(buf, rows); err != nil {
+		logger.Error("[Handler - ExportReasons] Couldn't write export. %v", err)
+		render.Render(w, r, domain.ErrInternal(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="torrorendum-motius.csv"`)
+	buf.WriteTo(w)
+}
+
+// getTorroReasons returns the torró's top reasons to win and to lose for
+// the detail page. Both are nil if nobody has tagged its duels yet or the
+// query fails; the page renders without them either way.
+func (h *Handler) getTorroReasons(ctx context.Context, torroId string) ([]domain.ReasonShare, []domain.ReasonShare) {
+	reasons, err := h.reasonRepo.ListForTorro(ctx, torroId)
+	if err != nil {
+		logger.Warn("[Handler - TorroDetail] Couldn't load reasons for %s. %v", torroId, err)
+		return nil, nil
+	}
+	return domain.TopReasons(reasons.Wins, torroReasonLimit), domain.TopReasons(reasons.Losses, torroReasonLimit)
+}
+
+func writeReasonExport(w io.Writer, rows []*domain.ReasonExportRow) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"torro_id", "torro", "class_id", "reason_id", "reason", "wins", "losses"}); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := cw.Write([]string{
+			row.TorroId,
+			row.TorroName,
+			row.ClassId,
+			row.TagId,
+			row.TagLabel,
+			strconv.Itoa(row.Wins),
+			strconv.Itoa(row.Losses),
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,82 @@
+package http
+
+import (
+	"html/template"
+	"strings"
+	"testing"
+
+	torrons "github.com/krtffl/torro"
+	"github.com/krtffl/torro/internal/domain"
+)
+
+func TestWriteReasonExport(t *testing.T) {
+	var sb strings.Builder
+	err := writeReasonExport(&sb, []*domain.ReasonExportRow{
+		{TorroId: "a", TorroName: "Torró d'Alacant", ClassId: "1", TagId: "textura", TagLabel: "Textura", Wins: 4, Losses: 1},
+		{TorroId: "b", TorroName: "Xixona, tou", ClassId: "1", TagId: "dolcor", TagLabel: "Dolçor", Wins: 0, Losses: 2},
+	})
+	if err != nil {
+		t.Fatalf("writeReasonExport() error = %v", err)
+	}
+
+	want := "torro_id,torro,class_id,reason_id,reason,wins,losses\n" +
+		"a,Torró d'Alacant,1,textura,Textura,4,1\n" +
+		"b,\"Xixona, tou\",1,dolcor,Dolçor,0,2\n"
+	if sb.String() != want {
+		t.Errorf("writeReasonExport() =\n%s\nwant\n%s", sb.String(), want)
+	}
+}
+
+func TestReasonTemplates(t *testing.T) {
+	tmpls, err := template.New("").Funcs(templateFuncs).ParseFS(torrons.Public, "public/templates/*.html")
+	if err != nil {
+		t.Fatalf("failed to parse templates: %v", err)
+	}
+
+	var sb strings.Builder
+	if err := tmpls.ExecuteTemplate(&sb, "pairing.html", Content{
+		HX:      true,
+		Pairing: &domain.Pairing{Id: "p"},
+		Torrons: []*domain.Torro{{Id: "a", Pairing: "p"}, {Id: "b", Pairing: "p"}},
+		Reasons: &ReasonPicker{
+			ResultId: "r1",
+			Tags: []ReasonChoice{
+				{Id: "textura", Label: "Textura", Selected: true},
+				{Id: "dolcor", Label: "Dolçor"},
+			},
+		},
+	}); err != nil {
+		t.Fatalf("failed to render pairing.html: %v", err)
+	}
+	out := sb.String()
+	for _, want := range []string{
+		`hx-post="/pairings/votes/r1/reasons"`,
+		`value="textura" checked`,
+		`value="dolcor">`,
+		"Dolçor",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("pairing.html is missing %q", want)
+		}
+	}
+
+	sb.Reset()
+	if err := tmpls.ExecuteTemplate(&sb, "torro.html", TorroDetailContent{
+		HX: true,
+		Torro: TorroDetail{
+			Id:          "a",
+			Name:        "Torró A",
+			HasReasons:  true,
+			WinReasons:  []domain.ReasonShare{{TagId: "textura", Label: "Textura", Percentage: 60}},
+			LossReasons: []domain.ReasonShare{{TagId: "dolcor", Label: "Dolçor", Percentage: 100}},
+		},
+	}); err != nil {
+		t.Fatalf("failed to render torro.html: %v", err)
+	}
+	out = sb.String()
+	for _, want := range []string{"Guanya per", "Textura <span>60%</span>", "Perd per", "Dolçor <span>100%</span>"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("torro.html is missing %q", want)
+		}
+	}
+}
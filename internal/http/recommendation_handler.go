@@ -0,0 +1,183 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/render"
+
+	"github.com/krtffl/torro/internal/domain"
+	"github.com/krtffl/torro/internal/logger"
+	"github.com/krtffl/torro/internal/recommend"
+)
+
+// recommenderFitInterval is how often the item-item similarities are
+// recomputed from every user's snapshots. A new vote only nudges one
+// user's ratings, so the fit goes stale slowly.
+const recommenderFitInterval = 15 * time.Minute
+
+// Recommendation list sizes: the page shows recommendationPageSize and the
+// API serves ?limit= up to recommendationMaxLimit.
+const (
+	recommendationPageSize = 12
+	recommendationMaxLimit = 50
+)
+
+// RecommendationsContent is the template payload for recommendations.html.
+type RecommendationsContent struct {
+	HX              bool
+	Recommendations []*domain.Recommendation
+
+	// Popular is set when the user has no snapshots yet, so the list is
+	// the community's favourites rather than a personal prediction.
+	Popular bool
+}
+
+// RecommendationsResponse is the user's recommendations, as served by
+// GET /api/user/recommendations.
+type RecommendationsResponse struct {
+	UserId          string                   `json:"user_id"`
+	Recommendations []*domain.Recommendation `json:"recommendations"`
+}
+
+// runRecommenderFitter loops until ctx is cancelled, refitting the
+// similarities shortly after boot and then every recommenderFitInterval.
+func (h *Handler) runRecommenderFitter(ctx context.Context) {
+	timer := time.NewTimer(20 * time.Second)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		fitCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+		if err := h.refitSimilarities(fitCtx); err != nil {
+			logger.Warn("[Recommender] Couldn't refit torró similarities. %v", err)
+		}
+		cancel()
+
+		timer.Reset(recommenderFitInterval)
+	}
+}
+
+// refitSimilarities recomputes the similarities from the current snapshots
+// and replaces the stored ones.
+func (h *Handler) refitSimilarities(ctx context.Context) error {
+	snapshots, err := h.similarityRepo.ListRatings(ctx)
+	if err != nil {
+		return err
+	}
+
+	similarities := recommend.Similarities(snapshots, time.Now())
+	if err := h.similarityRepo.Replace(ctx, similarities); err != nil {
+		return err
+	}
+
+	logger.Info("[Recommender] Fitted %d torró similarities over %d snapshots", len(similarities), len(snapshots))
+	return nil
+}
+
+// recommendations handles GET /recomanacions: the torrons the user hasn't
+// rated yet that they are most likely to enjoy, each with the rated torró
+// it is recommended because of.
+func (h *Handler) recommendations(w http.ResponseWriter, r *http.Request) {
+	logger.Info("[Handler - Recommendations] Incoming request")
+
+	// A visitor without a session gets the community's favourites, same as
+	// a user who hasn't voted yet.
+	userId := GetUserIDFromContext(r.Context())
+
+	recommendations, err := h.recommendFor(r.Context(), userId, recommendationPageSize)
+	if err != nil {
+		logger.Error("[Handler - Recommendations] Couldn't recommend torrons to user %s. %v", userId, err)
+		h.renderErrorPage(w)
+		return
+	}
+
+	content := RecommendationsContent{
+		HX:              isHX(r),
+		Recommendations: recommendations,
+		Popular:         len(recommendations) > 0 && recommendations[0].Basis == domain.RecommendationPopular,
+	}
+
+	buf := h.bpool.Get()
+	defer h.bpool.Put(buf)
+
+	if err := h.template.ExecuteTemplate(buf, "recommendations.html", content); err != nil {
+		logger.Error("[Handler - Recommendations] Couldn't execute template. %v", err)
+		h.renderErrorPage(w)
+		return
+	}
+
+	buf.WriteTo(w)
+}
+
+// handleUserRecommendations handles GET /api/user/recommendations: the
+// current user's recommendations, ?limit= of them (10 by default).
+func (h *Handler) handleUserRecommendations(w http.ResponseWriter, r *http.Request) {
+	userId := GetUserIDFromContext(r.Context())
+	if userId == "" {
+		render.Status(r, http.StatusUnauthorized)
+		render.JSON(w, r, map[string]string{"error": "No user session found"})
+		return
+	}
+
+	limit := 10
+	if l := r.URL.Query().Get("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n < 1 {
+			render.Render(w, r, domain.ErrBadRequest(
+				fmt.Errorf("%s: limit must be a positive number", domain.ValidationError)))
+			return
+		}
+		limit = min(n, recommendationMaxLimit)
+	}
+
+	recommendations, err := h.recommendFor(r.Context(), userId, limit)
+	if err != nil {
+		logger.Error("[User API - Recommendations] Couldn't recommend torrons. %v", err)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+		return
+	}
+	if recommendations == nil {
+		recommendations = []*domain.Recommendation{}
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, RecommendationsResponse{
+		UserId:          userId,
+		Recommendations: recommendations,
+	})
+}
+
+// recommendFor returns the limit best recommendations for userId, the
+// community's favourites when userId is empty.
+func (h *Handler) recommendFor(ctx context.Context, userId string, limit int) ([]*domain.Recommendation, error) {
+	var snapshots []*domain.UserEloSnapshot
+	if userId != "" {
+		var err error
+		snapshots, err = h.userEloRepo.ListByUser(ctx, userId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	similarities, err := h.similarityRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	torrons, err := h.torroRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return recommend.Recommend(snapshots, similarities, torrons, limit), nil
+}
@@ -0,0 +1,57 @@
+package http
+
+import (
+	"html/template"
+	"strings"
+	"testing"
+
+	torrons "github.com/krtffl/torro"
+	"github.com/krtffl/torro/internal/domain"
+)
+
+func TestRecommendationsTemplate(t *testing.T) {
+	tmpls, err := template.New("").Funcs(templateFuncs).ParseFS(torrons.Public, "public/templates/*.html")
+	if err != nil {
+		t.Fatalf("failed to parse templates: %v", err)
+	}
+
+	var sb strings.Builder
+	if err := tmpls.ExecuteTemplate(&sb, "recommendations.html", RecommendationsContent{
+		HX: true,
+		Recommendations: []*domain.Recommendation{
+			{
+				TorroId:     "b",
+				Name:        "Torró B",
+				Predicted:   1642.4,
+				Basis:       domain.RecommendationCollaborative,
+				BecauseId:   "a",
+				BecauseName: "Torró A",
+			},
+			{TorroId: "c", Name: "Torró C", Predicted: 1510, Basis: domain.RecommendationCatalog},
+		},
+	}); err != nil {
+		t.Fatalf("failed to render recommendations.html: %v", err)
+	}
+	out := sb.String()
+	for _, want := range []string{
+		`href="/torro/b"`,
+		"Perquè t'agrada Torró A",
+		">1642<",
+		"Torró C",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("recommendations.html is missing %q", want)
+		}
+	}
+	if strings.Contains(out, "Dels més votats") {
+		t.Error("a personal list shouldn't be labelled as the most voted")
+	}
+
+	sb.Reset()
+	if err := tmpls.ExecuteTemplate(&sb, "recommendations.html", RecommendationsContent{HX: true}); err != nil {
+		t.Fatalf("failed to render empty recommendations.html: %v", err)
+	}
+	if !strings.Contains(sb.String(), "Ja els has valorat tots") {
+		t.Error("an empty list should say every torró has been rated")
+	}
+}
@@ -0,0 +1,292 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+
+	"github.com/krtffl/torro/internal/domain"
+	"github.com/krtffl/torro/internal/logger"
+)
+
+// ratingSeasonCheckInterval is how often the season keeper looks for a
+// newly active campaign. Votes check too, so this only bounds how long the
+// leaderboards show last campaign's ratings when nobody is voting.
+const ratingSeasonCheckInterval = time.Minute
+
+// CampaignRatingsResponse is one campaign's ratings, as served by
+// GET /api/campaign/{campaignId}/ratings and its personal counterpart.
+type CampaignRatingsResponse struct {
+	CampaignId string                   `json:"campaign_id"`
+	Live       bool                     `json:"live"`
+	Ratings    []*domain.CampaignRating `json:"ratings"`
+}
+
+// CampaignComparisonResponse is every torró's standing in a campaign
+// against the campaign before it, as served by GET /api/campaign/compare.
+type CampaignComparisonResponse struct {
+	Current  *domain.Campaign           `json:"current"`
+	Previous *domain.Campaign           `json:"previous"`
+	Torrons  []*domain.RatingComparison `json:"torrons"`
+}
+
+// runRatingSeasonKeeper loops until ctx is cancelled, starting the active
+// campaign's rating season at boot and then checking every
+// ratingSeasonCheckInterval.
+func (h *Handler) runRatingSeasonKeeper(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		// A rollover archives and resets every rating under the replay
+		// locks, so it gets the same room as a replay.
+		runCtx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+		if campaign, err := h.campaignRepo.GetActive(runCtx); err == nil {
+			if h.syncRatingSeason(runCtx, campaign.Id) {
+				if err := h.refitStrengths(runCtx); err != nil {
+					logger.Warn("[Season] Couldn't refit torró strengths. %v", err)
+				}
+			}
+		}
+		cancel()
+
+		timer.Reset(ratingSeasonCheckInterval)
+	}
+}
+
+// syncRatingSeason makes sure the live ratings belong to campaignId,
+// rolling the season over if they belong to an earlier campaign. It reports
+// whether a rollover happened. Failures are only logged: a vote must not be
+// turned away because the archive couldn't be written, and the next check
+// retries.
+func (h *Handler) syncRatingSeason(ctx context.Context, campaignId string) bool {
+	if current := h.seasonCampaign.Load(); current != nil && *current == campaignId {
+		return false
+	}
+
+	// Checked before Rollover because Rollover takes the replay locks even
+	// when there is nothing to do.
+	season, err := h.campaignRatingRepo.GetSeason(ctx)
+	if err != nil {
+		logger.Warn("[Season] Couldn't get the rating season. %v", err)
+		return false
+	}
+
+	rolled := false
+	if season.CampaignId == nil || *season.CampaignId != campaignId {
+		rolled, err = h.campaignRatingRepo.Rollover(ctx, campaignId, h.ratingEngine.Initial())
+		if err != nil {
+			logger.Warn("[Season] Couldn't start the rating season of campaign %s. %v", campaignId, err)
+			return false
+		}
+		if rolled {
+			logger.Info("[Season] Archived the previous campaign's ratings and started campaign %s", campaignId)
+		}
+	}
+
+	h.seasonCampaign.Store(&campaignId)
+	return rolled
+}
+
+// handleCampaignRatings handles GET /api/campaign/{campaignId}/ratings: a
+// campaign's global ratings by rank, live for the current season.
+func (h *Handler) handleCampaignRatings(w http.ResponseWriter, r *http.Request) {
+	logger.Info("[Handler - CampaignRatings] Incoming request")
+
+	campaignId := chi.URLParam(r, "campaignId")
+	if _, err := h.campaignRepo.Get(r.Context(), campaignId); err != nil {
+		logger.Error("[Handler - CampaignRatings] Couldn't get campaign %s. %v", campaignId, err)
+		render.Render(w, r, domain.ErrFromRepo(err))
+		return
+	}
+
+	ratings, err := h.campaignRatingRepo.List(r.Context(), campaignId)
+	if err != nil {
+		logger.Error("[Handler - CampaignRatings] Couldn't list ratings of campaign %s. %v", campaignId, err)
+		render.Render(w, r, domain.ErrInternal(err))
+		return
+	}
+
+	h.renderCampaignRatings(w, r, campaignId, ratings)
+}
+
+// handleUserCampaignRatings handles GET /api/user/campaign/{campaignId}/ratings:
+// the current user's personal ratings in a campaign.
+func (h *Handler) handleUserCampaignRatings(w http.ResponseWriter, r *http.Request) {
+	logger.Info("[Handler - UserCampaignRatings] Incoming request")
+
+	userId := GetUserIDFromContext(r.Context())
+	if userId == "" {
+		render.Render(w, r, domain.ErrUnauthorized(
+			fmt.Errorf("%s: no user session found", domain.ValidationError)))
+		return
+	}
+
+	campaignId := chi.URLParam(r, "campaignId")
+	if _, err := h.campaignRepo.Get(r.Context(), campaignId); err != nil {
+		logger.Error("[Handler - UserCampaignRatings] Couldn't get campaign %s. %v", campaignId, err)
+		render.Render(w, r, domain.ErrFromRepo(err))
+		return
+	}
+
+	ratings, err := h.campaignRatingRepo.ListForUser(r.Context(), campaignId, userId)
+	if err != nil {
+		logger.Error("[Handler - UserCampaignRatings] Couldn't list ratings of user %s in campaign %s. %v",
+			userId, campaignId, err)
+		render.Render(w, r, domain.ErrInternal(err))
+		return
+	}
+
+	h.renderCampaignRatings(w, r, campaignId, ratings)
+}
+
+func (h *Handler) renderCampaignRatings(
+	w http.ResponseWriter,
+	r *http.Request,
+	campaignId string,
+	ratings []*domain.CampaignRating,
+) {
+	live := false
+	if season, err := h.campaignRatingRepo.GetSeason(r.Context()); err == nil {
+		live = season.CampaignId != nil && *season.CampaignId == campaignId
+	}
+	if ratings == nil {
+		ratings = []*domain.CampaignRating{}
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, CampaignRatingsResponse{
+		CampaignId: campaignId,
+		Live:       live,
+		Ratings:    ratings,
+	})
+}
+
+// handleCampaignCompare handles GET /api/campaign/compare: each torró's
+// rank in a campaign (?campaign=<id>, the current season's by default)
+// against the latest earlier year with archived ratings.
+func (h *Handler) handleCampaignCompare(w http.ResponseWriter, r *http.Request) {
+	logger.Info("[Handler - CampaignCompare] Incoming request")
+
+	campaignId := r.URL.Query().Get("campaign")
+	if campaignId == "" {
+		season, err := h.campaignRatingRepo.GetSeason(r.Context())
+		if err != nil {
+			logger.Error("[Handler - CampaignCompare] Couldn't get the rating season. %v", err)
+			render.Render(w, r, domain.ErrInternal(err))
+			return
+		}
+		if season.CampaignId == nil {
+			render.Render(w, r, domain.ErrNotFound(
+				fmt.Errorf("%s: no campaign has started a rating season yet", domain.NotFoundError)))
+			return
+		}
+		campaignId = *season.CampaignId
+	}
+
+	comparison, err := h.compareWithPreviousCampaign(r.Context(), campaignId)
+	if err != nil {
+		logger.Error("[Handler - CampaignCompare] Couldn't compare campaign %s. %v", campaignId, err)
+		render.Render(w, r, domain.ErrFromRepo(err))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, comparison)
+}
+
+// compareWithPreviousCampaign lines campaignId's ratings up against the
+// previous year's. The error is a NotFound one when either campaign is
+// missing.
+func (h *Handler) compareWithPreviousCampaign(ctx context.Context, campaignId string) (*CampaignComparisonResponse, error) {
+	current, err := h.campaignRepo.Get(ctx, campaignId)
+	if err != nil {
+		return nil, err
+	}
+
+	previousId, err := h.campaignRatingRepo.PreviousCampaignId(ctx, current.Year)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := h.campaignRepo.Get(ctx, previousId)
+	if err != nil {
+		return nil, err
+	}
+
+	currentRatings, err := h.campaignRatingRepo.List(ctx, current.Id)
+	if err != nil {
+		return nil, err
+	}
+	previousRatings, err := h.campaignRatingRepo.List(ctx, previous.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CampaignComparisonResponse{
+		Current:  current,
+		Previous: previous,
+		Torrons:  domain.CompareCampaignRatings(currentRatings, previousRatings),
+	}, nil
+}
+
+// applyYearOverYear sets each leaderboard entry's rank change against the
+// previous campaign, re-ranking that campaign's ratings among the same
+// torrons so a class or a dietary filter compares like with like. It
+// returns the previous campaign's year, or 0 when there is none to compare
+// with.
+func (h *Handler) applyYearOverYear(ctx context.Context, entries []LeaderboardEntry) int {
+	season, err := h.campaignRatingRepo.GetSeason(ctx)
+	if err != nil || season.CampaignId == nil {
+		return 0
+	}
+	current, err := h.campaignRepo.Get(ctx, *season.CampaignId)
+	if err != nil {
+		return 0
+	}
+	previousId, err := h.campaignRatingRepo.PreviousCampaignId(ctx, current.Year)
+	if err != nil {
+		return 0
+	}
+	previous, err := h.campaignRepo.Get(ctx, previousId)
+	if err != nil {
+		return 0
+	}
+	ratings, err := h.campaignRatingRepo.List(ctx, previousId)
+	if err != nil {
+		logger.Warn("[Handler - Leaderboard] Couldn't list ratings of campaign %s. %v", previousId, err)
+		return 0
+	}
+
+	shown := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		shown[e.TorronId] = true
+	}
+	var scope []*domain.CampaignRating
+	for _, rating := range ratings {
+		if shown[rating.TorroId] {
+			scope = append(scope, rating)
+		}
+	}
+	sort.SliceStable(scope, func(i, j int) bool {
+		return scope[i].Rating > scope[j].Rating
+	})
+
+	previousRanks := make(map[string]int, len(scope))
+	for i, rating := range scope {
+		previousRanks[rating.TorroId] = i + 1
+	}
+	setRankChanges(entries, previousRanks)
+
+	return previous.Year
+}
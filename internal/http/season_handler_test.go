@@ -0,0 +1,77 @@
+package http
+
+import (
+	"html/template"
+	"strings"
+	"testing"
+
+	torrons "github.com/krtffl/torro"
+)
+
+func TestSetRankChanges(t *testing.T) {
+	entries := []LeaderboardEntry{
+		{Rank: 1, TorronId: "a"},
+		{Rank: 2, TorronId: "b"},
+		{Rank: 3, TorronId: "c"},
+		{Rank: 4, TorronId: "d"},
+	}
+	setRankChanges(entries, map[string]int{"a": 3, "b": 2, "c": 1})
+
+	want := []struct {
+		change int
+		abs    int
+		symbol string
+	}{
+		{2, 2, "↑"},
+		{0, 0, "—"},
+		{-2, 2, "↓"},
+		{999, 0, "NOU"},
+	}
+	for i, w := range want {
+		e := entries[i]
+		if e.RankChange != w.change || e.RankChangeAbs != w.abs || e.RankChangeSymbol != w.symbol {
+			t.Errorf("%s: change/abs/symbol = %d/%d/%s, want %d/%d/%s",
+				e.TorronId, e.RankChange, e.RankChangeAbs, e.RankChangeSymbol, w.change, w.abs, w.symbol)
+		}
+	}
+}
+
+// TestLeaderboardTemplateYearOverYear renders the year-over-year view with
+// and without an earlier campaign to compare against.
+func TestLeaderboardTemplateYearOverYear(t *testing.T) {
+	tmpls, err := template.New("").Funcs(templateFuncs).ParseFS(torrons.Public, "public/templates/*.html")
+	if err != nil {
+		t.Fatalf("failed to parse templates: %v", err)
+	}
+
+	entries := []LeaderboardEntry{{Rank: 1, TorronId: "a", TorronName: "Torró A"}}
+	setRankChanges(entries, map[string]int{"a": 4})
+
+	var sb strings.Builder
+	if err := tmpls.ExecuteTemplate(&sb, "leaderboard.html", LeaderboardContent{
+		ViewType:         "yoy",
+		SelectedCategory: "global",
+		Entries:          entries,
+		CompareYear:      2024,
+	}); err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+	out := sb.String()
+	for _, want := range []string{"Posició respecte a 2024", "+3", "Any rere any"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("year-over-year view is missing %q", want)
+		}
+	}
+
+	sb.Reset()
+	if err := tmpls.ExecuteTemplate(&sb, "leaderboard.html", LeaderboardContent{
+		ViewType:         "yoy",
+		SelectedCategory: "global",
+		Entries:          []LeaderboardEntry{{Rank: 1, TorronId: "a", TorronName: "Torró A"}},
+	}); err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+	if !strings.Contains(sb.String(), "cap any anterior") {
+		t.Error("year-over-year view without a previous campaign doesn't say so")
+	}
+}
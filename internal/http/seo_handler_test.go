@@ -34,7 +34,7 @@ func (f *fakeTorroRepo) ListByClass(ctx context.Context, classId string) ([]*dom
 func (f *fakeTorroRepo) ListFiltered(ctx context.Context, classId string, filter domain.TorroFilter) ([]*domain.Torro, error) {
 	return nil, nil
 }
-func (f *fakeTorroRepo) Update(ctx context.Context, id string, rating float64) (*domain.Torro, error) {
+func (f *fakeTorroRepo) Update(ctx context.Context, id string, state domain.RatingState) (*domain.Torro, error) {
 	return nil, nil
 }
 func (f *fakeTorroRepo) TopNByClass(ctx context.Context, classId string, n int) ([]*domain.Torro, error) {
@@ -43,7 +43,7 @@ func (f *fakeTorroRepo) TopNByClass(ctx context.Context, classId string, n int)
 func (f *fakeTorroRepo) GetTx(tx *sql.Tx, ctx context.Context, id string) (*domain.Torro, error) {
 	return nil, nil
 }
-func (f *fakeTorroRepo) UpdateTx(tx *sql.Tx, ctx context.Context, id string, rating float64) (*domain.Torro, error) {
+func (f *fakeTorroRepo) UpdateTx(tx *sql.Tx, ctx context.Context, id string, state domain.RatingState) (*domain.Torro, error) {
 	return nil, nil
 }
 
@@ -112,7 +112,7 @@ func (srv *Server) Run() error {
 	// endpoint rather than one network address. 20/minute comfortably
 	// covers fast human clicking while meaningfully slowing scripted ELO
 	// manipulation - see docs/design-prompts or project notes for why a
-	// permanent per-pairing block isn't used instead: GetRandom draws
+	// permanent per-pairing block isn't used instead: the pairing selector draws
 	// repeatedly from the same small fixed pairing pool per class, so
 	// blocking a re-vote outright would eventually lock a user out of
 	// voting entirely once they'd seen every pairing once.
@@ -263,7 +263,13 @@ func (srv *Server) Run() error {
 		r.Get("/classes", srv.handler.classes)
 		r.Get("/classes/{id}/vote", srv.handler.vote)
 
+		// Ranking vote: order 3-4 torrons of a class at once.
+		r.Get("/classes/{id}/rank", srv.handler.rankVote)
+		r.With(voteRateLimiter).Post("/classes/{id}/rank", srv.handler.rankResult)
+
 		r.With(voteRateLimiter).Post("/pairings/{id}/vote", srv.handler.result)
+		r.With(voteRateLimiter).Post("/pairings/votes/{resultId}/undo", srv.handler.undoVote)
+		r.With(voteRateLimiter).Post("/pairings/votes/{resultId}/reasons", srv.handler.voteReasons)
 
 		// Product detail page
 		r.Get("/torro/{id}", srv.handler.torroDetail)
@@ -281,6 +287,9 @@ func (srv *Server) Run() error {
 		// Voting history page
 		r.Get("/history", srv.handler.history)
 
+		// Torrons the user hasn't rated yet, predicted from their snapshots
+		r.Get("/recomanacions", srv.handler.recommendations)
+
 		// Shareable result card (PNG). Registered without the ".png"
 		// suffix: the global middleware.URLFormat (registered above)
 		// strips any trailing ".ext" from the routing path before chi
@@ -294,6 +303,12 @@ func (srv *Server) Run() error {
 		r.Get("/bracket/{classId}", srv.handler.bracketOverview)
 		r.Get("/bracket/{classId}/vote", srv.handler.bracketVote)
 		r.With(voteRateLimiter).Post("/bracket/match/{matchId}/vote", srv.handler.bracketMatchVote)
+		r.Get("/bracket/{classId}/predict", srv.handler.bracketPredict)
+		r.With(voteRateLimiter).Post("/bracket/{classId}/predict", srv.handler.bracketPredictSubmit)
+		r.Get("/bracket/{classId}/predictions", srv.handler.bracketPredictions)
+		// The knockout tree as tree.svg or tree.png, registered without the
+		// extension for the same URLFormat reason as /share/card above.
+		r.Get("/bracket/{bracketId}/tree", srv.handler.bracketTree)
 
 		// Admin-only bracket management, gated by a shared-secret bearer
 		// token. See Handler.RequireAdminToken (middleware.go) and the
@@ -301,6 +316,17 @@ func (srv *Server) Run() error {
 		r.With(srv.handler.RequireAdminToken).Post("/bracket/{classId}/create", srv.handler.bracketCreate)
 		r.With(srv.handler.RequireAdminToken).Post("/bracket/{bracketId}/advance", srv.handler.bracketAdvance)
 
+		// Admin-only vote-fraud review, behind the same token. The analyzer
+		// also runs on its own schedule (runFraudAnalyzer); see
+		// internal/fraud for the signals it scores.
+		r.With(srv.handler.RequireAdminToken).Get("/admin/fraud/report", srv.handler.fraudReport)
+		r.With(srv.handler.RequireAdminToken).Post("/admin/fraud/analyze", srv.handler.fraudAnalyze)
+		r.With(srv.handler.RequireAdminToken).Post("/admin/fraud/users/{userId}/release", srv.handler.fraudRelease)
+
+		// Admin-only pairing reconciliation, also run at every startup
+		// (api.ReconcilePairings); see internal/catalog.
+		r.With(srv.handler.RequireAdminToken).Post("/admin/pairings/reconcile", srv.handler.reconcilePairings)
+
 		// Advent daily duel: one featured pairing per calendar day
 		r.Get("/advent", srv.handler.advent)
 
@@ -375,6 +401,22 @@ func (srv *Server) Run() error {
 
 		// Get personalized global leaderboard
 		r.Get("/leaderboard/global", srv.handler.handleUserGlobalLeaderboard)
+
+		// Get the personal leaderboard for a class blended with the global
+		// one, from the first vote
+		r.Get("/leaderboard/provisional/class/{classId}", srv.handler.handleUserProvisionalLeaderboard)
+
+		// Get the provisional personal global leaderboard
+		r.Get("/leaderboard/provisional/global", srv.handler.handleUserProvisionalGlobalLeaderboard)
+
+		// Get personal ratings in a campaign, archived or live
+		r.Get("/campaign/{campaignId}/ratings", srv.handler.handleUserCampaignRatings)
+
+		// Get recommendations of torrons the user hasn't rated yet
+		r.Get("/recommendations", srv.handler.handleUserRecommendations)
+
+		// Get how the user's taste compares with everyone else's
+		r.Get("/twins", srv.handler.handleUserTasteTwins)
 	})
 	// **********           **********
 
@@ -388,6 +430,31 @@ func (srv *Server) Run() error {
 
 		// Get active campaign information
 		r.Get("/info", srv.handler.handleCampaignInfo)
+
+		// Get each torró's rank against the previous year's
+		r.Get("/compare", srv.handler.handleCampaignCompare)
+
+		// Get a campaign's global ratings, archived or live
+		r.Get("/{campaignId}/ratings", srv.handler.handleCampaignRatings)
+	})
+
+	r.Route("/api/torro", func(r chi.Router) {
+		// Get a torró's rating over time (JSON)
+		r.Get("/{id}/history", srv.handler.torroHistory)
+
+		// Get what a torró's wins and losses were tagged with (JSON)
+		r.Get("/{id}/reasons", srv.handler.torroReasons)
+	})
+
+	r.Route("/api/reasons", func(r chi.Router) {
+		// Download every torró's reasons to win and lose (CSV). Registered
+		// without the ".csv" suffix for the same reason as /share/card.
+		r.Get("/export", srv.handler.exportReasons)
+	})
+
+	r.Route("/api/bracket", func(r chi.Router) {
+		// Get a bracket with its entries, matches and vote tallies (JSON)
+		r.Get("/{bracketId}", srv.handler.bracketAPI)
 	})
 
 	r.Route("/api/leaderboard", func(r chi.Router) {
@@ -420,6 +487,13 @@ func (srv *Server) Run() error {
 		go srv.handler.runIndexNowPinger(srv.ctx, srv.indexNowKey)
 	}
 
+	go srv.handler.runRatingSeasonKeeper(srv.ctx)
+	go srv.handler.runStrengthFitter(srv.ctx)
+	go srv.handler.runFraudAnalyzer(srv.ctx)
+	go srv.handler.runTrustScorer(srv.ctx)
+	go srv.handler.runRecommenderFitter(srv.ctx)
+	go srv.handler.runBracketScheduler(srv.ctx)
+
 	go func() {
 		<-srv.ctx.Done()
 		// Drain in-flight requests with a FRESH, bounded deadline. Reusing
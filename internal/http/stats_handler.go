@@ -7,6 +7,7 @@ import (
 
 	"github.com/krtffl/torro/internal/domain"
 	"github.com/krtffl/torro/internal/logger"
+	"github.com/krtffl/torro/internal/taste"
 )
 
 // CategoryProgress represents progress towards unlocking a category
@@ -20,6 +21,13 @@ type CategoryProgress struct {
 	ProgressPercentage int
 	VotesRemaining     int
 	Unlocked           bool
+
+	// Pairing coverage: how many of the class's duels the user has been
+	// shown, and how many full passes through them they've completed.
+	PairingsSeen       int
+	PairingsTotal      int
+	CoveragePercentage int
+	CoveragePasses     int
 }
 
 // Achievement represents a user achievement/badge
@@ -73,6 +81,14 @@ type StatsContent struct {
 	Achievements       []Achievement
 	CurrentStreak      int
 	LongestStreak      int
+
+	// TasteTwins compares the user's ranking with everyone else's; nil
+	// when it couldn't be worked out.
+	TasteTwins *domain.TasteTwins
+
+	// TwinMinOverlap is how many torrons the user must share with someone
+	// to be compared (taste.MinOverlap), for the empty state's copy.
+	TwinMinOverlap int
 }
 
 // stats handles the user statistics page
@@ -117,6 +133,17 @@ func (h *Handler) stats(w http.ResponseWriter, r *http.Request) {
 		classVotes = make(map[string]int)
 	}
 
+	// Pairing coverage per class. Purely informational, so a failed lookup
+	// just leaves the coverage line off each category.
+	coverage := make(map[string]*domain.PairingCoverage)
+	if list, err := h.pairingRepo.CoverageByUser(r.Context(), userId); err != nil {
+		logger.Warn("[Handler - Stats] Couldn't get pairing coverage. %v", err)
+	} else {
+		for _, c := range list {
+			coverage[c.ClassId] = c
+		}
+	}
+
 	// Category icons mapping
 	categoryIcons := map[string]template.HTML{
 		"1": iconCategoryClassics,    // Clàssics
@@ -144,8 +171,8 @@ func (h *Handler) stats(w http.ResponseWriter, r *http.Request) {
 		minVotes := getMinVotesForClass(class.Id)
 
 		var voteCount int
-		if class.Id == "5" { // Global uses total votes
-			voteCount = user.VoteCount
+		if class.Id == "5" { // Global uses the season's total votes
+			voteCount = user.SeasonVoteCount()
 		} else {
 			voteCount = classVotes[class.Id]
 		}
@@ -170,7 +197,7 @@ func (h *Handler) stats(w http.ResponseWriter, r *http.Request) {
 			icon = iconCategoryFallback
 		}
 
-		categoryProgress = append(categoryProgress, CategoryProgress{
+		progress := CategoryProgress{
 			Id:                 class.Id,
 			Name:               class.Name,
 			Tag:                categoryTags[class.Id],
@@ -180,7 +207,15 @@ func (h *Handler) stats(w http.ResponseWriter, r *http.Request) {
 			ProgressPercentage: progressPercentage,
 			VotesRemaining:     votesRemaining,
 			Unlocked:           unlocked,
-		})
+		}
+		if c, ok := coverage[class.Id]; ok {
+			progress.PairingsSeen = c.Seen
+			progress.PairingsTotal = c.Total
+			progress.CoveragePercentage = c.Percentage()
+			progress.CoveragePasses = c.Passes
+		}
+
+		categoryProgress = append(categoryProgress, progress)
 	}
 
 	// Determine user rank based on total votes
@@ -235,6 +270,14 @@ func (h *Handler) stats(w http.ResponseWriter, r *http.Request) {
 		Achievements:       achievements,
 		CurrentStreak:      user.CurrentStreak,
 		LongestStreak:      user.LongestStreak,
+		TwinMinOverlap:     taste.MinOverlap,
+	}
+
+	// Taste twins are a bonus: a failed comparison just leaves the card off.
+	if twins, err := h.findTasteTwins(r.Context(), userId); err != nil {
+		logger.Warn("[Handler - Stats] Couldn't compare tastes. %v", err)
+	} else {
+		content.TasteTwins = twins
 	}
 
 	buf := h.bpool.Get()
@@ -0,0 +1,141 @@
+package http
+
+import (
+	"context"
+	"time"
+
+	"github.com/krtffl/torro/internal/domain"
+	"github.com/krtffl/torro/internal/logger"
+	"github.com/krtffl/torro/internal/strength"
+)
+
+// strengthFitInterval is how often the batch Bradley–Terry fit is rerun
+// over the rating season's Results. The public pages that show it are
+// cached for rankingCacheTTL anyway, and a fit over a whole season is too
+// heavy to run per vote.
+const strengthFitInterval = 15 * time.Minute
+
+// runStrengthFitter loops until ctx is cancelled, refitting the torró
+// strengths shortly after boot and then every strengthFitInterval.
+func (h *Handler) runStrengthFitter(ctx context.Context) {
+	timer := time.NewTimer(10 * time.Second)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		// Bounded for the same reason as the IndexNow pinger: one wedged
+		// query must not stall the loop for the life of the process.
+		fitCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+		if err := h.refitStrengths(fitCtx); err != nil {
+			logger.Warn("[Strength] Couldn't refit torró strengths. %v", err)
+		}
+		cancel()
+
+		timer.Reset(strengthFitInterval)
+	}
+}
+
+// refitStrengths runs the batch fit over the current Results and replaces
+// the stored one.
+func (h *Handler) refitStrengths(ctx context.Context) error {
+	torrons, err := h.torroRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	records, err := h.strengthRepo.ListPairRecords(ctx)
+	if err != nil {
+		return err
+	}
+
+	strengths := strength.Fit(torrons, records, time.Now())
+	if err := h.strengthRepo.Replace(ctx, strengths); err != nil {
+		return err
+	}
+
+	logger.Info("[Strength] Fitted %d torrons over %d head-to-head records", len(strengths), len(records))
+	return nil
+}
+
+// strengthView is the latest stored fit, indexed for the leaderboard
+// surfaces. A nil *strengthView (no fit could be loaded) answers every
+// lookup with nil, so callers can render without strengths.
+type strengthView struct {
+	all   []*domain.TorroStrength
+	byId  map[string]*domain.TorroStrength
+	names map[string]string
+}
+
+// loadStrengthView reads the stored fit along with the torró names the
+// leader summaries need.
+func (h *Handler) loadStrengthView(ctx context.Context) (*strengthView, error) {
+	strengths, err := h.strengthRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	torrons, err := h.torroRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	view := &strengthView{
+		all:   strengths,
+		byId:  make(map[string]*domain.TorroStrength, len(strengths)),
+		names: make(map[string]string, len(torrons)),
+	}
+	for _, s := range strengths {
+		view.byId[s.TorroId] = s
+	}
+	for _, t := range torrons {
+		view.names[t.Id] = t.Name
+	}
+
+	return view, nil
+}
+
+// get returns the fitted strength for a torró, or nil if it has none yet.
+func (v *strengthView) get(torroId string) *domain.TorroStrength {
+	if v == nil {
+		return nil
+	}
+	return v.byId[torroId]
+}
+
+// leader reports whether the fit's #1 is separable from its #2, across
+// every class when classId is empty or within one class otherwise. nil
+// when there are fewer than two ranked torrons to compare.
+func (v *strengthView) leader(classId string) *domain.LeaderSeparation {
+	if v == nil {
+		return nil
+	}
+
+	scope := v.all
+	if classId != "" {
+		scope = nil
+		for _, s := range v.all {
+			if s.ClassId == classId {
+				scope = append(scope, s)
+			}
+		}
+	}
+
+	sep := strength.Leaders(scope)
+	if sep != nil {
+		sep.LeaderName = v.names[sep.LeaderId]
+		sep.RunnerUpName = v.names[sep.RunnerUpId]
+	}
+	return sep
+}
+
+// attachStrengths sets each entry's fitted strength from the view.
+func attachStrengths(entries []LeaderboardEntry, view *strengthView) {
+	for i := range entries {
+		entries[i].Strength = view.get(entries[i].TorronId)
+	}
+}
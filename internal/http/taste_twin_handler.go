@@ -0,0 +1,60 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	"github.com/krtffl/torro/internal/domain"
+	"github.com/krtffl/torro/internal/logger"
+	"github.com/krtffl/torro/internal/taste"
+)
+
+// handleUserTasteTwins handles GET /api/user/twins: how the current user's
+// ranking of torrons compares with everyone else's, as aggregates only.
+func (h *Handler) handleUserTasteTwins(w http.ResponseWriter, r *http.Request) {
+	userId := GetUserIDFromContext(r.Context())
+	if userId == "" {
+		render.Status(r, http.StatusUnauthorized)
+		render.JSON(w, r, map[string]string{"error": "No user session found"})
+		return
+	}
+
+	twins, err := h.findTasteTwins(r.Context(), userId)
+	if err != nil {
+		logger.Error("[User API - Taste Twins] Couldn't compare tastes. %v", err)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, twins)
+}
+
+// findTasteTwins compares userId's snapshots with those of every user who
+// shares at least taste.MinOverlap rated torrons with them.
+func (h *Handler) findTasteTwins(ctx context.Context, userId string) (*domain.TasteTwins, error) {
+	mine, err := h.userEloRepo.ListByUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	// Nobody can share enough torrons with a user who hasn't rated that
+	// many, so skip the query.
+	if len(mine) < taste.MinOverlap {
+		return taste.Twins(mine, nil, nil), nil
+	}
+
+	others, err := h.tasteTwinRepo.ListOverlapping(ctx, userId, taste.MinOverlap)
+	if err != nil {
+		return nil, err
+	}
+
+	torrons, err := h.torroRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return taste.Twins(mine, others, torrons), nil
+}
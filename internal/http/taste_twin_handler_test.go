@@ -0,0 +1,56 @@
+package http
+
+import (
+	"html/template"
+	"strings"
+	"testing"
+
+	torrons "github.com/krtffl/torro"
+	"github.com/krtffl/torro/internal/domain"
+)
+
+func TestTasteTwinsTemplate(t *testing.T) {
+	tmpls, err := template.New("").Funcs(templateFuncs).ParseFS(torrons.Public, "public/templates/*.html")
+	if err != nil {
+		t.Fatalf("failed to parse templates: %v", err)
+	}
+
+	var sb strings.Builder
+	if err := tmpls.ExecuteTemplate(&sb, "stats.html", StatsContent{
+		HX: true,
+		TasteTwins: &domain.TasteTwins{
+			Compared: 12,
+			Twins:    3,
+			Closest:  []*domain.TasteMatch{{Correlation: 0.914, SharedTorrons: 8}},
+			Opposite: &domain.TasteMatch{Correlation: -0.9, SharedTorrons: 6},
+			Picks:    []*domain.TwinPick{{TorroId: "x", Name: "Torró X", Twins: 2}},
+		},
+	}); err != nil {
+		t.Fatalf("failed to render stats.html: %v", err)
+	}
+	out := sb.String()
+	for _, want := range []string{
+		"Bessons de gust",
+		"de 12 amb qui et podem comparar",
+		"91% d'acord en 8 torrons",
+		"6 torrons dels teus gairebé al revés",
+		`href="/torro/x"`,
+		"2 bessons",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("stats.html is missing %q", want)
+		}
+	}
+
+	sb.Reset()
+	if err := tmpls.ExecuteTemplate(&sb, "stats.html", StatsContent{
+		HX:             true,
+		TasteTwins:     &domain.TasteTwins{},
+		TwinMinOverlap: 5,
+	}); err != nil {
+		t.Fatalf("failed to render stats.html without twins: %v", err)
+	}
+	if !strings.Contains(sb.String(), "Valora almenys 5 torrons") {
+		t.Error("a user nobody can be compared with should be told how to get compared")
+	}
+}
@@ -55,6 +55,17 @@ type TorroDetail struct {
 	ClassName    string
 
 	RelatedTorros []RelatedTorro
+
+	// History is the torró's rating sparkline over the active campaign (or
+	// every vote, outside one).
+	HasHistory bool
+	History    RatingChart
+
+	// WinReasons and LossReasons are what voters said the torró wins and
+	// loses its duels for, most frequent first.
+	HasReasons  bool
+	WinReasons  []domain.ReasonShare
+	LossReasons []domain.ReasonShare
 }
 
 // RelatedTorro is a minimal cross-link entry for other torrons in the same
@@ -99,12 +110,17 @@ func (h *Handler) torroDetail(w http.ResponseWriter, r *http.Request) {
 		className = h.getClassName(classes, t.Class)
 	}
 
+	detail := newTorroDetail(t, rank, className, related)
+	detail.History, detail.HasHistory = h.getRatingChart(r.Context(), t.Id)
+	detail.WinReasons, detail.LossReasons = h.getTorroReasons(r.Context(), t.Id)
+	detail.HasReasons = len(detail.WinReasons) > 0 || len(detail.LossReasons) > 0
+
 	buf := h.bpool.Get()
 	defer h.bpool.Put(buf)
 
 	if err := h.template.ExecuteTemplate(buf, "torro.html", TorroDetailContent{
 		HX:    isHX(r),
-		Torro: newTorroDetail(t, rank, className, related),
+		Torro: detail,
 	}); err != nil {
 		logger.Error("[Handler - TorroDetail] Couldn't execute template. %v", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
@@ -150,6 +166,25 @@ func (h *Handler) getRankAndRelated(ctx context.Context, t *domain.Torro) (int,
 	return rank, related
 }
 
+// getRatingChart draws the torró's daily rating history over the active
+// campaign, or over every vote when no campaign is running. ok is false if
+// there is nothing to draw or the query fails; the page renders without the
+// chart either way.
+func (h *Handler) getRatingChart(ctx context.Context, torroId string) (RatingChart, bool) {
+	filter := domain.RatingHistoryFilter{Bucket: domain.RatingHistoryDay}
+	if campaign, err := h.campaignRepo.GetActive(ctx); err == nil {
+		filter.CampaignId = &campaign.Id
+	}
+
+	points, err := h.resultRepo.ListRatingHistory(ctx, torroId, filter)
+	if err != nil {
+		logger.Warn("[Handler - TorroDetail] Couldn't load rating history for %s. %v", torroId, err)
+		return RatingChart{}, false
+	}
+
+	return newRatingChart(points, ratingHistoryBuckets[filter.Bucket])
+}
+
 // newTorroDetail builds the presentation model for the detail page from the
 // domain torró, resolving nullable/optional fields defensively so nothing
 // ever renders as a bare pointer or "undefined".
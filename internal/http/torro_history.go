@@ -0,0 +1,189 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+
+	"github.com/krtffl/torro/internal/domain"
+	"github.com/krtffl/torro/internal/logger"
+)
+
+// Rating chart geometry, in SVG user units. The SVG scales to its container
+// width; these only fix the aspect ratio and the stroke's breathing room.
+const (
+	ratingChartWidth   = 320
+	ratingChartHeight  = 80
+	ratingChartPadding = 4
+
+	// ratingChartMinSpan keeps a torró that has barely moved from drawing
+	// a few points of noise as a cliff.
+	ratingChartMinSpan = 20
+)
+
+// ratingHistoryBuckets maps the bucket query parameter to its width.
+var ratingHistoryBuckets = map[string]time.Duration{
+	domain.RatingHistoryHour: time.Hour,
+	domain.RatingHistoryDay:  24 * time.Hour,
+}
+
+// TorroHistoryResponse is a torró's rating over time, as served by
+// GET /api/torro/{id}/history.
+type TorroHistoryResponse struct {
+	TorroId    string                       `json:"torro_id"`
+	Bucket     string                       `json:"bucket"`
+	CampaignId *string                      `json:"campaign_id,omitempty"`
+	Points     []*domain.RatingHistoryPoint `json:"points"`
+}
+
+// RatingChart is the presentation model for the rating sparkline on the
+// product detail page: the SVG paths precomputed, plus the labels around
+// them.
+type RatingChart struct {
+	Width  int
+	Height int
+
+	// Line is the rating path, Area the same path closed along the bottom
+	// edge for the fill underneath. EndX and EndY mark the latest rating.
+	Line string
+	Area string
+	EndX float64
+	EndY float64
+
+	Min    float64
+	Max    float64
+	Start  float64
+	End    float64
+	Change float64
+	Votes  int
+	From   time.Time
+	To     time.Time
+}
+
+// Rising reports whether the torró ended the period above where it began.
+func (c RatingChart) Rising() bool {
+	return c.Change >= 0
+}
+
+// newRatingChart lays a rating history out as a sparkline, on a time axis
+// so quiet stretches read as flat rather than being squeezed out. It starts
+// at the first bucket's Open and then follows each bucket's closing Rating,
+// placed at the end of its bucket. ok is false when there is no history to
+// draw.
+func newRatingChart(points []*domain.RatingHistoryPoint, bucket time.Duration) (RatingChart, bool) {
+	if len(points) == 0 {
+		return RatingChart{}, false
+	}
+
+	type vertex struct {
+		at     time.Time
+		rating float64
+	}
+	vertices := make([]vertex, 0, len(points)+1)
+	vertices = append(vertices, vertex{points[0].Time, points[0].Open})
+	for _, p := range points {
+		vertices = append(vertices, vertex{p.Time.Add(bucket), p.Rating})
+	}
+
+	chart := RatingChart{
+		Width:  ratingChartWidth,
+		Height: ratingChartHeight,
+		Min:    vertices[0].rating,
+		Max:    vertices[0].rating,
+		Start:  vertices[0].rating,
+		End:    vertices[len(vertices)-1].rating,
+		From:   vertices[0].at,
+		To:     vertices[len(vertices)-1].at,
+	}
+	chart.Change = chart.End - chart.Start
+	for _, v := range vertices {
+		chart.Min = min(chart.Min, v.rating)
+		chart.Max = max(chart.Max, v.rating)
+	}
+	for _, p := range points {
+		chart.Votes += p.Votes
+	}
+
+	lo, hi := chart.Min, chart.Max
+	if span := hi - lo; span < ratingChartMinSpan {
+		lo -= (ratingChartMinSpan - span) / 2
+		hi = lo + ratingChartMinSpan
+	}
+	duration := chart.To.Sub(chart.From)
+
+	innerW := float64(ratingChartWidth - 2*ratingChartPadding)
+	innerH := float64(ratingChartHeight - 2*ratingChartPadding)
+
+	var line strings.Builder
+	var x, y float64
+	for i, v := range vertices {
+		x = ratingChartPadding + innerW*float64(v.at.Sub(chart.From))/float64(duration)
+		y = ratingChartPadding + innerH*(hi-v.rating)/(hi-lo)
+		if i == 0 {
+			fmt.Fprintf(&line, "M%.1f %.1f", x, y)
+		} else {
+			fmt.Fprintf(&line, " L%.1f %.1f", x, y)
+		}
+	}
+	chart.Line = line.String()
+	chart.EndX, chart.EndY = x, y
+
+	bottom := float64(ratingChartHeight - ratingChartPadding)
+	chart.Area = fmt.Sprintf("%s L%.1f %.1f L%.1f %.1f Z",
+		chart.Line, x, bottom, float64(ratingChartPadding), bottom)
+
+	return chart, true
+}
+
+// torroHistory handles GET /api/torro/{id}/history: the torró's rating over
+// time in hourly or daily buckets (?bucket=hour|day, daily by default),
+// optionally limited to one campaign's votes (?campaign=<id>).
+func (h *Handler) torroHistory(w http.ResponseWriter, r *http.Request) {
+	logger.Info("[Handler - TorroHistory] Incoming request")
+
+	id := chi.URLParam(r, "id")
+
+	bucket := r.URL.Query().Get("bucket")
+	if bucket == "" {
+		bucket = domain.RatingHistoryDay
+	}
+	if _, ok := ratingHistoryBuckets[bucket]; !ok {
+		render.Render(w, r, domain.ErrBadRequest(
+			fmt.Errorf("%s: bucket must be %q or %q", domain.ValidationError,
+				domain.RatingHistoryHour, domain.RatingHistoryDay)))
+		return
+	}
+
+	filter := domain.RatingHistoryFilter{Bucket: bucket}
+	if campaignId := r.URL.Query().Get("campaign"); campaignId != "" {
+		filter.CampaignId = &campaignId
+	}
+
+	if _, err := h.torroRepo.Get(r.Context(), id); err != nil {
+		logger.Error("[Handler - TorroHistory] Couldn't get torro %s. %v", id, err)
+		render.Render(w, r, domain.ErrFromRepo(err))
+		return
+	}
+
+	points, err := h.resultRepo.ListRatingHistory(r.Context(), id, filter)
+	if err != nil {
+		logger.Error("[Handler - TorroHistory] Couldn't list rating history for torro %s. %v", id, err)
+		render.Render(w, r, domain.ErrInternal(err))
+		return
+	}
+	if points == nil {
+		points = []*domain.RatingHistoryPoint{}
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, TorroHistoryResponse{
+		TorroId:    id,
+		Bucket:     bucket,
+		CampaignId: filter.CampaignId,
+		Points:     points,
+	})
+}
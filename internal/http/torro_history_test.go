@@ -0,0 +1,97 @@
+package http
+
+import (
+	"html/template"
+	"strings"
+	"testing"
+	"time"
+
+	torrons "github.com/krtffl/torro"
+	"github.com/krtffl/torro/internal/domain"
+)
+
+func TestNewRatingChart(t *testing.T) {
+	day := 24 * time.Hour
+	start := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	if _, ok := newRatingChart(nil, day); ok {
+		t.Fatal("an empty history produced a chart")
+	}
+
+	points := []*domain.RatingHistoryPoint{
+		{Time: start, Open: 1500, Rating: 1540, Votes: 6},
+		{Time: start.Add(3 * day), Open: 1540, Rating: 1520, Votes: 2},
+	}
+	chart, ok := newRatingChart(points, day)
+	if !ok {
+		t.Fatal("no chart for a two-day history")
+	}
+
+	if chart.Start != 1500 || chart.End != 1520 || chart.Change != 20 || !chart.Rising() {
+		t.Errorf("start/end/change = %v/%v/%v, want 1500/1520/20 rising", chart.Start, chart.End, chart.Change)
+	}
+	if chart.Min != 1500 || chart.Max != 1540 || chart.Votes != 8 {
+		t.Errorf("min/max/votes = %v/%v/%d, want 1500/1540/8", chart.Min, chart.Max, chart.Votes)
+	}
+	if !chart.To.Equal(start.Add(4 * day)) {
+		t.Errorf("To = %v, want the end of the last bucket", chart.To)
+	}
+
+	// Three vertices on a time axis: the open at the left edge, the first
+	// close a quarter of the way along and the last at the right edge, at
+	// the top (1540) and bottom (1500) of the range.
+	want := "M4.0 76.0 L82.0 4.0 L316.0 40.0"
+	if chart.Line != want {
+		t.Errorf("Line = %q, want %q", chart.Line, want)
+	}
+	if !strings.HasPrefix(chart.Area, chart.Line) || !strings.HasSuffix(chart.Area, "Z") {
+		t.Errorf("Area = %q doesn't close the line", chart.Area)
+	}
+	if chart.EndX != 316 || chart.EndY != 40 {
+		t.Errorf("end marker at (%v, %v), want (316, 40)", chart.EndX, chart.EndY)
+	}
+}
+
+func TestNewRatingChartFlat(t *testing.T) {
+	start := time.Date(2025, 12, 1, 10, 0, 0, 0, time.UTC)
+	chart, ok := newRatingChart([]*domain.RatingHistoryPoint{
+		{Time: start, Open: 1500, Rating: 1502, Votes: 1},
+	}, time.Hour)
+	if !ok {
+		t.Fatal("no chart for a one-bucket history")
+	}
+
+	// A two-point wobble is drawn within ratingChartMinSpan, not stretched
+	// across the whole height.
+	if chart.Line != "M4.0 43.6 L316.0 36.4" {
+		t.Errorf("Line = %q", chart.Line)
+	}
+}
+
+// TestTorroTemplateRatingChart renders the detail page with a chart and
+// checks the paths reach the SVG intact.
+func TestTorroTemplateRatingChart(t *testing.T) {
+	tmpls, err := template.New("").Funcs(templateFuncs).ParseFS(torrons.Public, "public/templates/*.html")
+	if err != nil {
+		t.Fatalf("failed to parse templates: %v", err)
+	}
+
+	start := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	chart, _ := newRatingChart([]*domain.RatingHistoryPoint{
+		{Time: start, Open: 1500, Rating: 1480, Votes: 3},
+	}, 24*time.Hour)
+
+	var sb strings.Builder
+	if err := tmpls.ExecuteTemplate(&sb, "torro.html", TorroDetailContent{
+		Torro: TorroDetail{Id: "42", Name: "Torró", Rating: 1480, HasHistory: true, History: chart},
+	}); err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+	out := sb.String()
+
+	for _, want := range []string{`d="` + chart.Line + `"`, "is-falling", "-20 ELO · 3 vots", "01/12", "02/12"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("detail page is missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package http
+
+import (
+	"context"
+	"time"
+
+	"github.com/krtffl/torro/internal/logger"
+)
+
+// trustScoreInterval is how often every voter's trust score is recomputed.
+// A score only feeds the weight of votes still to come, so a new user
+// waiting half an hour to earn more of a say is fine.
+const trustScoreInterval = 30 * time.Minute
+
+// runTrustScorer loops until ctx is cancelled, scoring every voter's trust
+// a minute after boot and then every trustScoreInterval.
+func (h *Handler) runTrustScorer(ctx context.Context) {
+	timer := time.NewTimer(time.Minute)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		runCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+		if n, err := h.scoreTrust(runCtx); err != nil {
+			logger.Warn("[Trust] Couldn't score voter trust. %v", err)
+		} else {
+			logger.Info("[Trust] Scored the trust of %d voters", n)
+		}
+		cancel()
+
+		timer.Reset(trustScoreInterval)
+	}
+}
+
+// scoreTrust recomputes and stores every voter's trust score under the
+// configured vote weighting, returning how many were scored.
+func (h *Handler) scoreTrust(ctx context.Context) (int, error) {
+	signals, err := h.trustRepo.ListSignals(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	scores := make(map[string]float64, len(signals))
+	for _, s := range signals {
+		scores[s.UserId] = h.voteWeighting.Score(s, now)
+	}
+
+	if err := h.trustRepo.SaveScores(ctx, scores); err != nil {
+		return 0, err
+	}
+	return len(scores), nil
+}
@@ -0,0 +1,175 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+
+	"github.com/krtffl/torro/internal/domain"
+	"github.com/krtffl/torro/internal/logger"
+)
+
+// voteUndoWindow is how long after a vote its undo toast stays usable:
+// long enough to notice a mis-tap, short enough that the vote has barely
+// been built on.
+const voteUndoWindow = 10 * time.Second
+
+// VoteUndoToast is the undo offer rendered on the pairing partial after a
+// vote.
+type VoteUndoToast struct {
+	ResultId string
+	Seconds  int
+}
+
+// undoVote handles POST /pairings/votes/{resultId}/undo: takes back the
+// user's latest vote if it is still inside voteUndoWindow, reversing the
+// Result, both global ratings, the user's snapshots, vote counts, streak
+// and advent vote in one transaction, then re-renders the duel the vote was
+// cast on.
+func (h *Handler) undoVote(w http.ResponseWriter, r *http.Request) {
+	logger.Info("[Handler - UndoVote] Incoming request")
+
+	resultId := chi.URLParam(r, "resultId")
+
+	userId := GetUserIDFromContext(r.Context())
+	if userId == "" {
+		logger.Warn("[Handler - UndoVote] No user ID in context")
+		render.Render(w, r, domain.ErrUnauthorized(
+			fmt.Errorf("%s: no user to undo a vote for", domain.ValidationError)))
+		return
+	}
+
+	// Read the undo once outside the transaction only to learn which
+	// torrons to lock; it is read again, locked, once they are.
+	peek, err := h.voteUndoRepo.Get(r.Context(), userId)
+	if err != nil {
+		logger.Error("[Handler - UndoVote] Couldn't get undoable vote for user %s. %v", userId, err)
+		render.Render(w, r, domain.ErrFromRepo(err))
+		return
+	}
+	if peek.ResultId != resultId {
+		render.Render(w, r, domain.ErrConflict(
+			fmt.Errorf("%s: only your latest vote can be undone", domain.ValidationError)))
+		return
+	}
+
+	tx, err := h.db.Begin()
+	if err != nil {
+		logger.Error("[Handler - UndoVote] Couldn't start transaction. %v", err)
+		render.Render(w, r, domain.ErrInternal(err))
+		return
+	}
+	defer tx.Rollback() // Rollback if not committed
+
+	// Same lock order as a vote (torrons by id, then the user, then the
+	// undo row) so an undo and a vote can never deadlock each other.
+	var t1, t2 *domain.Torro
+	if peek.Torro1 <= peek.Torro2 {
+		if t1, err = h.torroRepo.GetTx(tx, r.Context(), peek.Torro1); err == nil {
+			t2, err = h.torroRepo.GetTx(tx, r.Context(), peek.Torro2)
+		}
+	} else {
+		if t2, err = h.torroRepo.GetTx(tx, r.Context(), peek.Torro2); err == nil {
+			t1, err = h.torroRepo.GetTx(tx, r.Context(), peek.Torro1)
+		}
+	}
+	if err != nil {
+		logger.Error("[Handler - UndoVote] Couldn't get torro. %v", err)
+		render.Render(w, r, domain.ErrInternal(err))
+		return
+	}
+
+	if _, err := h.userRepo.GetTx(tx, r.Context(), userId); err != nil {
+		logger.Error("[Handler - UndoVote] Couldn't get user %s. %v", userId, err)
+		render.Render(w, r, domain.ErrInternal(err))
+		return
+	}
+
+	// A vote that landed between the peek and the locks has replaced the
+	// undo, and with it possibly the torrons.
+	undo, err := h.voteUndoRepo.GetTx(tx, r.Context(), userId)
+	if err != nil {
+		logger.Error("[Handler - UndoVote] Couldn't get undoable vote for user %s. %v", userId, err)
+		render.Render(w, r, domain.ErrFromRepo(err))
+		return
+	}
+	if undo.ResultId != resultId {
+		render.Render(w, r, domain.ErrConflict(
+			fmt.Errorf("%s: only your latest vote can be undone", domain.ValidationError)))
+		return
+	}
+	if undo.Expired(voteUndoWindow, time.Now().UTC()) {
+		render.Render(w, r, domain.ErrConflict(
+			fmt.Errorf("%s: the undo window has passed", domain.ValidationError)))
+		return
+	}
+
+	if _, err := h.torroRepo.UpdateTx(tx, r.Context(), undo.Torro1,
+		domain.UndoRating(t1.RatingState(), undo.Torro1Before, undo.Rat1Aft)); err != nil {
+		logger.Error("[Handler - UndoVote] Couldn't restore rating of torro %s. %v", undo.Torro1, err)
+		render.Render(w, r, domain.ErrInternal(err))
+		return
+	}
+	if _, err := h.torroRepo.UpdateTx(tx, r.Context(), undo.Torro2,
+		domain.UndoRating(t2.RatingState(), undo.Torro2Before, undo.Rat2Aft)); err != nil {
+		logger.Error("[Handler - UndoVote] Couldn't restore rating of torro %s. %v", undo.Torro2, err)
+		render.Render(w, r, domain.ErrInternal(err))
+		return
+	}
+
+	if err := h.voteUndoRepo.RevertTx(tx, r.Context(), undo); err != nil {
+		logger.Error("[Handler - UndoVote] Couldn't revert vote %s. %v", undo.ResultId, err)
+		render.Render(w, r, domain.ErrInternal(err))
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error("[Handler - UndoVote] Couldn't commit transaction. %v", err)
+		render.Render(w, r, domain.ErrInternal(err))
+		return
+	}
+	logger.Info("[Handler - UndoVote] User %s undid vote %s", userId, undo.ResultId)
+
+	p, err := h.pairingRepo.Get(r.Context(), undo.PairingId)
+	if err != nil {
+		logger.Error("[Handler - UndoVote] Couldn't get pairing with ID %s. %v", undo.PairingId, err)
+		render.Render(w, r, domain.ErrInternal(err))
+		return
+	}
+
+	pt1, err := h.torroRepo.Get(r.Context(), p.Torro1)
+	if err != nil {
+		logger.Error("[Handler - UndoVote] Couldn't get torro. %v", err)
+		render.Render(w, r, domain.ErrInternal(err))
+		return
+	}
+
+	pt2, err := h.torroRepo.Get(r.Context(), p.Torro2)
+	if err != nil {
+		logger.Error("[Handler - UndoVote] Couldn't get torro. %v", err)
+		render.Render(w, r, domain.ErrInternal(err))
+		return
+	}
+
+	pt1.Pairing = p.Id
+	pt2.Pairing = p.Id
+
+	buf := h.bpool.Get()
+	defer h.bpool.Put(buf)
+
+	if err := h.template.ExecuteTemplate(buf, "pairing.html", Content{
+		Pairing: p,
+		Torrons: []*domain.Torro{pt1, pt2},
+		HX:      isHX(r),
+		Undone:  true,
+	}); err != nil {
+		logger.Error("[Handler - UndoVote] Couldn't execute template. %v", err)
+		h.renderErrorPage(w)
+		return
+	}
+
+	buf.WriteTo(w)
+}
@@ -0,0 +1,59 @@
+package http
+
+import (
+	"html/template"
+	"strings"
+	"testing"
+
+	torrons "github.com/krtffl/torro"
+	"github.com/krtffl/torro/internal/domain"
+)
+
+// TestPairingTemplateUndoToast renders the pairing partial after a vote and
+// after an undo, and checks the toast points at the right Result and carries
+// the undo window through html/template's CSS escaping intact.
+func TestPairingTemplateUndoToast(t *testing.T) {
+	tmpls, err := template.New("").Funcs(templateFuncs).ParseFS(torrons.Public, "public/templates/*.html")
+	if err != nil {
+		t.Fatalf("failed to parse templates: %v", err)
+	}
+
+	pairing := &domain.Pairing{Id: "p1", Torro1: "1", Torro2: "2", Class: "c1"}
+	duel := []*domain.Torro{
+		{Id: "1", Name: "Torró A", Pairing: "p1"},
+		{Id: "2", Name: "Torró B", Pairing: "p1"},
+	}
+
+	var sb strings.Builder
+	if err := tmpls.ExecuteTemplate(&sb, "pairing.html", Content{
+		Pairing: pairing,
+		Torrons: duel,
+		HX:      true,
+		Undo:    &VoteUndoToast{ResultId: "r1", Seconds: 10},
+	}); err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+	out := sb.String()
+	for _, want := range []string{`hx-post="/pairings/votes/r1/undo"`, `--undo-window: 10s`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("vote partial is missing %q", want)
+		}
+	}
+
+	sb.Reset()
+	if err := tmpls.ExecuteTemplate(&sb, "pairing.html", Content{
+		Pairing: pairing,
+		Torrons: duel,
+		HX:      true,
+		Undone:  true,
+	}); err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+	out = sb.String()
+	if strings.Contains(out, "/undo") {
+		t.Error("undone partial still offers an undo")
+	}
+	if !strings.Contains(out, "Vot desfet") {
+		t.Error("undone partial has no confirmation")
+	}
+}
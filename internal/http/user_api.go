@@ -30,6 +30,9 @@ type UserStatsResponse struct {
 	SnapshotCount int            `json:"snapshot_count"`
 	CurrentStreak int            `json:"current_streak"`
 	LongestStreak int            `json:"longest_streak"`
+
+	// Coverage is the user's progress through each class's pairings.
+	Coverage []*domain.PairingCoverage `json:"coverage"`
 }
 
 // handleUserStats returns statistics for the current user
@@ -70,6 +73,14 @@ func (h *Handler) handleUserStats(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	coverage, err := h.pairingRepo.CoverageByUser(r.Context(), userId)
+	if err != nil {
+		logger.Error("[User API - Stats] Couldn't get pairing coverage. %v", err)
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+		return
+	}
+
 	response := UserStatsResponse{
 		UserId:        user.Id,
 		TotalVotes:    user.VoteCount,
@@ -79,6 +90,7 @@ func (h *Handler) handleUserStats(w http.ResponseWriter, r *http.Request) {
 		SnapshotCount: len(snapshots),
 		CurrentStreak: user.CurrentStreak,
 		LongestStreak: user.LongestStreak,
+		Coverage:      coverage,
 	}
 
 	render.Status(r, http.StatusOK)
@@ -171,17 +183,20 @@ func (h *Handler) handleUserGlobalLeaderboard(w http.ResponseWriter, r *http.Req
 		// Continue with partial data
 	}
 
-	totalVotes := 0
+	// The entries are this season's snapshots, so the gate counts this
+	// season's votes; total_votes stays the all-time count.
+	totalVotes, seasonVotes := 0, 0
 	if user != nil {
-		totalVotes = user.VoteCount
+		totalVotes, seasonVotes = user.VoteCount, user.SeasonVoteCount()
 	}
 
 	response := map[string]interface{}{
 		"user_id":            userId,
 		"total_votes":        totalVotes,
+		"season_votes":       seasonVotes,
 		"entries":            entries,
 		"total_entries":      len(entries),
-		"min_votes_met":      totalVotes >= globalLeaderboardMinVotes,
+		"min_votes_met":      seasonVotes >= globalLeaderboardMinVotes,
 		"min_votes_required": globalLeaderboardMinVotes,
 	}
 
@@ -38,6 +38,11 @@ type WrappedContent struct {
 	HasChampion           bool
 	ChampionName          string
 	MatchedChampion       bool
+	HasPrediction         bool
+	PredictionPoints      int
+	PredictionDecided     int
+	PredictionCorrect     int
+	PredictedChampion     bool
 }
 
 // wrapped handles GET /wrapped: the personal "Torrorèndum Wrapped"
@@ -182,10 +187,17 @@ func (h *Handler) wrappedCardData(ctx context.Context, userId string) (sharecard
 			data.BracketMatchesDecided = bracketPath.MatchesDecided
 			data.BracketPicksCorrect = bracketPath.PicksCorrect
 		}
+		if bracketPath.HasPrediction {
+			data.HasPrediction = true
+			data.PredictionPoints = bracketPath.PredictionPoints
+			data.PredictionDecided = bracketPath.PredictionDecided
+			data.PredictionCorrect = bracketPath.PredictionCorrect
+		}
 		if bracketPath.HasChampion {
 			data.HasChampion = true
 			data.ChampionName = bracketPath.ChampionName
 			data.MatchedChampion = bracketPath.MatchedChampion
+			data.PredictedChampion = bracketPath.PredictedChampion
 		}
 	}
 
@@ -229,5 +241,10 @@ func wrappedContentFromCardData(data sharecard.WrappedData) WrappedContent {
 		HasChampion:           data.HasChampion,
 		ChampionName:          data.ChampionName,
 		MatchedChampion:       data.MatchedChampion,
+		HasPrediction:         data.HasPrediction,
+		PredictionPoints:      data.PredictionPoints,
+		PredictionDecided:     data.PredictionDecided,
+		PredictionCorrect:     data.PredictionCorrect,
+		PredictedChampion:     data.PredictedChampion,
 	}
 }
@@ -0,0 +1,141 @@
+package matchmaking
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+
+	"github.com/krtffl/torro/internal/domain"
+	"github.com/krtffl/torro/internal/logger"
+)
+
+// minScore keeps every candidate drawable: even a settled, lopsided pairing
+// the user has already voted on can come up now and then.
+const minScore = 0.01
+
+// Adaptive favours the duels a vote is most informative for: close current
+// ratings, uncertain ratings and pairings with few votes. For a signed-in
+// user it also steers towards torrons missing from their own snapshots, so
+// their personal leaderboard fills in with fewer votes.
+type Adaptive struct {
+	random *Random
+	repo   domain.PairingRepo
+
+	// Ratio is the share of duels picked by score; the rest are uniform.
+	Ratio float64
+	// PersonalWeight blends the user's snapshot gaps into the score, from
+	// 0 (global needs only) to 1 (personal gaps only).
+	PersonalWeight float64
+
+	// float64 returns a uniform number in [0, 1). Swapped out in tests.
+	float64 func() float64
+}
+
+// NewAdaptive returns an adaptive selector backed by repo.
+func NewAdaptive(repo domain.PairingRepo, ratio, personalWeight float64) *Adaptive {
+	return &Adaptive{
+		random:         NewRandom(repo),
+		repo:           repo,
+		Ratio:          ratio,
+		PersonalWeight: personalWeight,
+		float64:        rand.Float64,
+	}
+}
+
+func (s *Adaptive) Name() string {
+	return domain.PairingSelectorAdaptive
+}
+
+func (s *Adaptive) Next(ctx context.Context, classId, userId, excludeId string) (*domain.Pairing, error) {
+	if s.float64() >= s.Ratio {
+		return s.random.Next(ctx, classId, userId, excludeId)
+	}
+
+	candidates, err := s.repo.ListCandidates(ctx, classId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	weights := make([]float64, len(candidates))
+	for i, c := range candidates {
+		if c.Pairing.Id == excludeId && len(candidates) > 1 {
+			continue
+		}
+		weights[i] = weight(c, userId != "", s.PersonalWeight)
+	}
+
+	// No candidates at all (unknown class): let the uniform path report it
+	// the same way it always has.
+	if p := pickWeighted(candidates, weights, s.float64()); p != nil {
+		return p, nil
+	}
+	logger.Debug("[Matchmaking - Adaptive] No candidates in class %s, falling back to random", classId)
+	return s.random.Next(ctx, classId, userId, excludeId)
+}
+
+// weight is the draw weight of a candidate. Squaring the score sharpens the
+// draw towards the best candidates without collapsing it onto a single
+// pairing every voter would then see.
+func weight(c *domain.PairingCandidate, personal bool, personalWeight float64) float64 {
+	score := Score(c, personal, personalWeight)
+	return score * score
+}
+
+// pickWeighted draws one candidate with probability proportional to its
+// weight, using u in [0, 1). Zero-weight candidates are never drawn; nil is
+// returned when every weight is zero.
+func pickWeighted(candidates []*domain.PairingCandidate, weights []float64, u float64) *domain.Pairing {
+	var total float64
+	for _, w := range weights {
+		total += w
+	}
+	if total == 0 {
+		return nil
+	}
+
+	target := u * total
+	last := -1
+	for i, w := range weights {
+		if w == 0 {
+			continue
+		}
+		if target < w {
+			return candidates[i].Pairing
+		}
+		target -= w
+		last = i
+	}
+
+	// Rounding can leave target a hair above the last weight.
+	return candidates[last].Pairing
+}
+
+// Score rates how much a vote on c would teach, in (0, 1].
+//
+// The global part multiplies three signals: closeness (4p(1-p), where p is
+// the Elo win expectancy, so an even duel scores 1), uncertainty (the mean
+// rating variance relative to a fresh torró's, which stays at 1 under Elo)
+// and novelty (1/sqrt(1+votes)). When personal is set, it is blended with
+// the user's own gap: how rarely both torrons have appeared in their duels,
+// discounted if they already voted on this exact pairing.
+func Score(c *domain.PairingCandidate, personal bool, personalWeight float64) float64 {
+	p := 1 / (1 + math.Pow(10, (c.State2.Rating-c.State1.Rating)/400))
+	closeness := 4 * p * (1 - p)
+
+	sigma0 := float64(domain.DefaultRatingDeviation)
+	uncertainty := (c.State1.Deviation*c.State1.Deviation + c.State2.Deviation*c.State2.Deviation) /
+		(2 * sigma0 * sigma0)
+	uncertainty = math.Min(uncertainty, 1)
+
+	novelty := 1 / math.Sqrt(1+float64(c.Votes))
+
+	score := closeness * (0.5 + 0.5*uncertainty) * novelty
+
+	if personal {
+		gap := 2 / float64(2+c.UserTorro1Votes+c.UserTorro2Votes)
+		gap /= float64(1 + c.UserVotes)
+		score = (1-personalWeight)*score + personalWeight*gap
+	}
+
+	return math.Max(score, minScore)
+}
@@ -0,0 +1,144 @@
+package matchmaking
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+// fakePairingRepo serves a fixed candidate list and records which uniform
+// path, if any, the selector fell back to.
+type fakePairingRepo struct {
+	domain.PairingRepo
+	candidates []*domain.PairingCandidate
+	randomHits int
+}
+
+func (f *fakePairingRepo) ListCandidates(ctx context.Context, classId, userId string) ([]*domain.PairingCandidate, error) {
+	return f.candidates, nil
+}
+
+func (f *fakePairingRepo) GetRandom(ctx context.Context, classId string) (*domain.Pairing, error) {
+	f.randomHits++
+	return &domain.Pairing{Id: "random"}, nil
+}
+
+func (f *fakePairingRepo) GetRandomExcluding(ctx context.Context, classId, excludeId string) (*domain.Pairing, error) {
+	f.randomHits++
+	return &domain.Pairing{Id: "random"}, nil
+}
+
+func candidate(id string, r1, r2 float64, votes int) *domain.PairingCandidate {
+	return &domain.PairingCandidate{
+		Pairing: &domain.Pairing{Id: id},
+		State1:  domain.RatingState{Rating: r1, Deviation: domain.DefaultRatingDeviation},
+		State2:  domain.RatingState{Rating: r2, Deviation: domain.DefaultRatingDeviation},
+		Votes:   votes,
+	}
+}
+
+func TestScore(t *testing.T) {
+	even := candidate("even", 1500, 1510, 0)
+	lopsided := candidate("lopsided", 1200, 1800, 0)
+	if Score(even, false, 0) <= Score(lopsided, false, 0) {
+		t.Errorf("close duel should outscore a lopsided one")
+	}
+
+	fresh := candidate("fresh", 1500, 1500, 0)
+	worn := candidate("worn", 1500, 1500, 200)
+	if Score(fresh, false, 0) <= Score(worn, false, 0) {
+		t.Errorf("unvoted pairing should outscore a heavily voted one")
+	}
+
+	uncertain := candidate("uncertain", 1500, 1500, 10)
+	settled := candidate("settled", 1500, 1500, 10)
+	settled.State1.Deviation, settled.State2.Deviation = 50, 50
+	if Score(uncertain, false, 0) <= Score(settled, false, 0) {
+		t.Errorf("uncertain ratings should outscore settled ones")
+	}
+
+	// Personal gaps: a pairing of torrons the user has never seen beats
+	// one they've already voted on, all else equal.
+	gap := candidate("gap", 1500, 1500, 10)
+	seen := candidate("seen", 1500, 1500, 10)
+	seen.UserVotes, seen.UserTorro1Votes, seen.UserTorro2Votes = 1, 8, 8
+	if Score(gap, true, 0.5) <= Score(seen, true, 0.5) {
+		t.Errorf("user's snapshot gap should outscore what they've already seen")
+	}
+	if Score(gap, false, 0.5) != Score(seen, false, 0.5) {
+		t.Errorf("anonymous scores must ignore the per-user counts")
+	}
+
+	if s := Score(candidate("floor", 0, 4000, 100000), false, 0); s < minScore {
+		t.Errorf("score %v fell below the floor %v", s, minScore)
+	}
+}
+
+func TestAdaptiveNext(t *testing.T) {
+	repo := &fakePairingRepo{candidates: []*domain.PairingCandidate{
+		candidate("best", 1500, 1500, 0),
+		candidate("worst", 1000, 2000, 500),
+	}}
+	s := NewAdaptive(repo, 0.8, 0)
+
+	// Below the ratio: pick by score. A draw at the very start of the
+	// cumulative weights lands on the first (best) candidate.
+	s.float64 = func() float64 { return 0 }
+	p, err := s.Next(context.Background(), "1", "", "")
+	if err != nil {
+		t.Fatalf("Next error = %v", err)
+	}
+	if p.Id != "best" {
+		t.Errorf("Next = %q, want %q", p.Id, "best")
+	}
+
+	// The pairing just voted on is skipped while there is another one.
+	p, err = s.Next(context.Background(), "1", "", "best")
+	if err != nil {
+		t.Fatalf("Next error = %v", err)
+	}
+	if p.Id != "worst" {
+		t.Errorf("Next excluding best = %q, want %q", p.Id, "worst")
+	}
+
+	// At or above the ratio: uniform path.
+	s.float64 = func() float64 { return 0.9 }
+	if p, _ := s.Next(context.Background(), "1", "", ""); p.Id != "random" || repo.randomHits != 1 {
+		t.Errorf("Next above ratio = %q (random hits %d), want the uniform path", p.Id, repo.randomHits)
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, name := range []string{"", domain.PairingSelectorAdaptive, domain.PairingSelectorRandom, domain.PairingSelectorCoverage} {
+		if _, err := New(name, &fakePairingRepo{}, Options{}); err != nil {
+			t.Errorf("New(%q) error = %v", name, err)
+		}
+	}
+	if _, err := New("round-robin", &fakePairingRepo{}, Options{}); err == nil {
+		t.Errorf("New with an unknown name should fail")
+	}
+}
+
+func TestNewAdaptiveOptions(t *testing.T) {
+	zero, over := 0.0, 1.5
+	tests := []struct {
+		name               string
+		opts               Options
+		wantRatio, wantPer float64
+	}{
+		{"unset", Options{}, DefaultAdaptiveRatio, DefaultPersonalWeight},
+		{"zero is honoured", Options{AdaptiveRatio: &zero, PersonalWeight: &zero}, 0, 0},
+		{"clamped", Options{AdaptiveRatio: &over, PersonalWeight: &over}, 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newAdaptive(&fakePairingRepo{}, tt.opts)
+			if s.Ratio != tt.wantRatio || s.PersonalWeight != tt.wantPer {
+				t.Errorf("newAdaptive() ratio %v and personal weight %v, want %v and %v",
+					s.Ratio, s.PersonalWeight, tt.wantRatio, tt.wantPer)
+			}
+		})
+	}
+}
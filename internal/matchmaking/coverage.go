@@ -0,0 +1,83 @@
+package matchmaking
+
+import (
+	"context"
+	"math/rand/v2"
+
+	"github.com/krtffl/torro/internal/domain"
+	"github.com/krtffl/torro/internal/logger"
+)
+
+// Coverage makes sure a signed-in voter sees every pairing of a class before
+// any repeats. While the user still has unseen pairings it draws among them
+// only, weighted like the adaptive selector; once the class is fully covered
+// it cycles through the pairings least-recently-seen first. Anonymous
+// sessions, which have no history to cover, go to the adaptive selector.
+type Coverage struct {
+	adaptive *Adaptive
+	repo     domain.PairingRepo
+
+	// float64 returns a uniform number in [0, 1). Swapped out in tests.
+	float64 func() float64
+}
+
+// NewCoverage returns a coverage selector backed by repo, falling back to
+// adaptive for anonymous sessions.
+func NewCoverage(repo domain.PairingRepo, adaptive *Adaptive) *Coverage {
+	return &Coverage{
+		adaptive: adaptive,
+		repo:     repo,
+		float64:  rand.Float64,
+	}
+}
+
+func (s *Coverage) Name() string {
+	return domain.PairingSelectorCoverage
+}
+
+func (s *Coverage) Next(ctx context.Context, classId, userId, excludeId string) (*domain.Pairing, error) {
+	if userId == "" {
+		return s.adaptive.Next(ctx, classId, userId, excludeId)
+	}
+
+	candidates, err := s.repo.ListCandidates(ctx, classId, userId)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		logger.Debug("[Matchmaking - Coverage] No candidates in class %s, falling back to adaptive", classId)
+		return s.adaptive.Next(ctx, classId, userId, excludeId)
+	}
+	if len(candidates) == 1 {
+		return candidates[0].Pairing, nil
+	}
+
+	weights := make([]float64, len(candidates))
+	for i, c := range candidates {
+		if c.Covered() || c.Pairing.Id == excludeId {
+			continue
+		}
+		weights[i] = weight(c, true, s.adaptive.PersonalWeight)
+	}
+	if p := pickWeighted(candidates, weights, s.float64()); p != nil {
+		return p, nil
+	}
+
+	return leastRecentlySeen(candidates, excludeId), nil
+}
+
+// leastRecentlySeen returns the candidate other than excludeId that the user
+// was served longest ago. Candidates never served (covered only by a vote
+// cast elsewhere) have a zero UserLastSeen and so come first.
+func leastRecentlySeen(candidates []*domain.PairingCandidate, excludeId string) *domain.Pairing {
+	var oldest *domain.PairingCandidate
+	for _, c := range candidates {
+		if c.Pairing.Id == excludeId {
+			continue
+		}
+		if oldest == nil || c.UserLastSeen.Before(oldest.UserLastSeen) {
+			oldest = c
+		}
+	}
+	return oldest.Pairing
+}
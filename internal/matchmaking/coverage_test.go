@@ -0,0 +1,68 @@
+package matchmaking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+func TestCoverageServesUnseenFirst(t *testing.T) {
+	seen := candidate("seen", 1500, 1500, 0)
+	seen.UserSeen = 1
+	voted := candidate("voted", 1500, 1500, 0)
+	voted.UserVotes = 1
+	unseen := candidate("unseen", 1000, 2000, 500) // a poor duel, but new to this user
+
+	repo := &fakePairingRepo{candidates: []*domain.PairingCandidate{seen, voted, unseen}}
+	s := NewCoverage(repo, NewAdaptive(repo, 1, 0.5))
+
+	for _, u := range []float64{0, 0.5, 0.99} {
+		s.float64 = func() float64 { return u }
+		p, err := s.Next(context.Background(), "1", "user", "")
+		if err != nil {
+			t.Fatalf("Next error = %v", err)
+		}
+		if p.Id != "unseen" {
+			t.Errorf("u=%v: Next = %q, want the only unseen pairing", u, p.Id)
+		}
+	}
+}
+
+func TestCoverageCyclesLeastRecentlySeen(t *testing.T) {
+	now := time.Now()
+	a := candidate("a", 1500, 1500, 0)
+	a.UserSeen, a.UserLastSeen = 2, now.Add(-time.Minute)
+	b := candidate("b", 1500, 1500, 0)
+	b.UserSeen, b.UserLastSeen = 1, now.Add(-time.Hour)
+	c := candidate("c", 1500, 1500, 0)
+	c.UserSeen, c.UserLastSeen = 1, now
+
+	repo := &fakePairingRepo{candidates: []*domain.PairingCandidate{a, b, c}}
+	s := NewCoverage(repo, NewAdaptive(repo, 1, 0.5))
+
+	p, err := s.Next(context.Background(), "1", "user", "")
+	if err != nil {
+		t.Fatalf("Next error = %v", err)
+	}
+	if p.Id != "b" {
+		t.Errorf("Next = %q, want the least recently seen %q", p.Id, "b")
+	}
+
+	// The pairing just voted on is never served straight back.
+	p, _ = s.Next(context.Background(), "1", "user", "b")
+	if p.Id != "a" {
+		t.Errorf("Next excluding b = %q, want %q", p.Id, "a")
+	}
+}
+
+func TestCoverageAnonymousUsesAdaptive(t *testing.T) {
+	repo := &fakePairingRepo{candidates: []*domain.PairingCandidate{candidate("only", 1500, 1500, 0)}}
+	adaptive := NewAdaptive(repo, 0, 0.5) // ratio 0: always uniform
+	s := NewCoverage(repo, adaptive)
+
+	if p, _ := s.Next(context.Background(), "1", "", ""); p.Id != "random" {
+		t.Errorf("anonymous Next = %q, want the adaptive selector's pick", p.Id)
+	}
+}
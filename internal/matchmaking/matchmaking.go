@@ -0,0 +1,62 @@
+// Package matchmaking holds the domain.PairingSelector implementations that
+// decide which Phase 1 duel the vote screen serves next: the original
+// uniform draw, an adaptive selector that spends votes where they teach the
+// ranking the most, and a per-user coverage scheduler on top of it.
+package matchmaking
+
+import (
+	"fmt"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+// DefaultAdaptiveRatio is the share of duels the adaptive selector picks by
+// score when none is configured. The remainder stay uniformly random, so
+// every pairing keeps a chance of being seen however lopsided it looks.
+const DefaultAdaptiveRatio = 0.8
+
+// DefaultPersonalWeight is how much of a signed-in user's score comes from
+// their own snapshot gaps rather than the global ranking's needs.
+const DefaultPersonalWeight = 0.5
+
+// Options carries the tunable parameters of every selector. Nil fields fall
+// back to the package defaults; 0 is honoured like any other value.
+type Options struct {
+	AdaptiveRatio  *float64
+	PersonalWeight *float64
+}
+
+// New returns the selector registered under name (one of the
+// domain.PairingSelector* names). An empty name selects the adaptive
+// selector.
+func New(name string, repo domain.PairingRepo, opts Options) (domain.PairingSelector, error) {
+	switch name {
+	case domain.PairingSelectorRandom:
+		return NewRandom(repo), nil
+	case "", domain.PairingSelectorAdaptive:
+		return newAdaptive(repo, opts), nil
+	case domain.PairingSelectorCoverage:
+		return NewCoverage(repo, newAdaptive(repo, opts)), nil
+	default:
+		return nil, fmt.Errorf("unknown pairing selector %q", name)
+	}
+}
+
+func newAdaptive(repo domain.PairingRepo, opts Options) *Adaptive {
+	return NewAdaptive(
+		repo,
+		clamp01(orDefault(opts.AdaptiveRatio, DefaultAdaptiveRatio)),
+		clamp01(orDefault(opts.PersonalWeight, DefaultPersonalWeight)),
+	)
+}
+
+func orDefault(v *float64, def float64) float64 {
+	if v == nil {
+		return def
+	}
+	return *v
+}
+
+func clamp01(v float64) float64 {
+	return max(0, min(v, 1))
+}
@@ -0,0 +1,29 @@
+package matchmaking
+
+import (
+	"context"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+// Random serves a uniformly random pairing of the class, which is how every
+// duel was picked before selectors were pluggable.
+type Random struct {
+	repo domain.PairingRepo
+}
+
+// NewRandom returns a uniform selector backed by repo.
+func NewRandom(repo domain.PairingRepo) *Random {
+	return &Random{repo: repo}
+}
+
+func (s *Random) Name() string {
+	return domain.PairingSelectorRandom
+}
+
+func (s *Random) Next(ctx context.Context, classId, userId, excludeId string) (*domain.Pairing, error) {
+	if excludeId == "" {
+		return s.repo.GetRandom(ctx, classId)
+	}
+	return s.repo.GetRandomExcluding(ctx, classId, excludeId)
+}
@@ -1,6 +1,10 @@
-package http
+package rating
 
-import "math"
+import (
+	"math"
+
+	"github.com/krtffl/torro/internal/domain"
+)
 
 // CalculateExpectedScore calculates the expected score for a player
 // using the standard ELO formula:
@@ -37,3 +41,43 @@ func UpdateRatings(rating1, rating2 float64, player1Won bool, kFactor float64) (
 
 	return newRating1, newRating2
 }
+
+// DefaultEloK is the K-factor used when none is configured. A value of 42
+// provides:
+// - Fast convergence for new items (larger rating changes)
+// - Balanced sensitivity for established items
+// - Standard K-factor is 32 for masters, 40 for beginners
+// - 42 chosen for this system's moderate volatility needs
+const DefaultEloK = 42
+
+// Elo is the classic sequential Elo engine. It only ever moves Rating;
+// Deviation and Volatility pass through unchanged.
+type Elo struct {
+	K float64
+}
+
+// NewElo returns an Elo engine with the given K-factor.
+func NewElo(k float64) *Elo {
+	return &Elo{K: k}
+}
+
+func (e *Elo) Name() string {
+	return domain.RatingAlgorithmElo
+}
+
+func (e *Elo) Initial() domain.RatingState {
+	return domain.RatingState{
+		Rating:     domain.DefaultRating,
+		Deviation:  domain.DefaultRatingDeviation,
+		Volatility: domain.DefaultRatingVolatility,
+	}
+}
+
+func (e *Elo) Update(a, b domain.RatingState, scoreA float64) (domain.RatingState, domain.RatingState) {
+	expA := CalculateExpectedScore(a.Rating, b.Rating)
+	expB := CalculateExpectedScore(b.Rating, a.Rating)
+
+	a.Rating = CalculateNewRating(a.Rating, expA, scoreA, e.K)
+	b.Rating = CalculateNewRating(b.Rating, expB, 1-scoreA, e.K)
+	return a, b
+}
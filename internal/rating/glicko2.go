@@ -0,0 +1,121 @@
+package rating
+
+import (
+	"math"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+// glicko2Scale converts between the public 1500-centred rating scale and
+// Glicko-2's internal mu/phi scale (Glickman, "Example of the Glicko-2
+// system", step 2).
+const glicko2Scale = 173.7178
+
+// glicko2Epsilon is the convergence tolerance of the volatility iteration.
+const glicko2Epsilon = 0.000001
+
+// DefaultGlicko2Tau is the system constant used when none is configured.
+// It constrains how fast volatility can change; Glickman recommends values
+// between 0.3 and 1.2, smaller meaning steadier ratings.
+const DefaultGlicko2Tau = 0.5
+
+// Glicko2 is a Glicko-2 engine that treats every duel as its own rating
+// period. A torró that has rarely been voted on keeps a large deviation, so
+// its first few results move it a lot and later ones settle it, instead of
+// every vote swinging every rating by the same fixed K.
+type Glicko2 struct {
+	Tau float64
+}
+
+// NewGlicko2 returns a Glicko-2 engine with the given system constant.
+func NewGlicko2(tau float64) *Glicko2 {
+	return &Glicko2{Tau: tau}
+}
+
+func (g *Glicko2) Name() string {
+	return domain.RatingAlgorithmGlicko2
+}
+
+func (g *Glicko2) Initial() domain.RatingState {
+	return domain.RatingState{
+		Rating:     domain.DefaultRating,
+		Deviation:  domain.DefaultRatingDeviation,
+		Volatility: domain.DefaultRatingVolatility,
+	}
+}
+
+func (g *Glicko2) Update(a, b domain.RatingState, scoreA float64) (domain.RatingState, domain.RatingState) {
+	// Both sides are updated against the opponent's pre-duel state.
+	return g.update(a, b, scoreA), g.update(b, a, 1-scoreA)
+}
+
+// update runs steps 2-8 of the Glicko-2 algorithm for player p against a
+// single opponent o with actual score s.
+func (g *Glicko2) update(p, o domain.RatingState, s float64) domain.RatingState {
+	mu := (p.Rating - domain.DefaultRating) / glicko2Scale
+	phi := p.Deviation / glicko2Scale
+	sigma := p.Volatility
+	muJ := (o.Rating - domain.DefaultRating) / glicko2Scale
+	phiJ := o.Deviation / glicko2Scale
+
+	gJ := glicko2G(phiJ)
+	e := 1 / (1 + math.Exp(-gJ*(mu-muJ)))
+	v := 1 / (gJ * gJ * e * (1 - e))
+	delta := v * gJ * (s - e)
+
+	sigma = g.volatility(phi, sigma, v, delta)
+
+	phiStar := math.Sqrt(phi*phi + sigma*sigma)
+	phi = 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	mu = mu + phi*phi*gJ*(s-e)
+
+	return domain.RatingState{
+		Rating:     mu*glicko2Scale + domain.DefaultRating,
+		Deviation:  phi * glicko2Scale,
+		Volatility: sigma,
+	}
+}
+
+// volatility solves for the new volatility with the Illinois algorithm
+// (step 5 of the Glicko-2 paper).
+func (g *Glicko2) volatility(phi, sigma, v, delta float64) float64 {
+	a := math.Log(sigma * sigma)
+	tau2 := g.Tau * g.Tau
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-phi*phi-v-ex)/(2*d*d) - (x-a)/tau2
+	}
+
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*g.Tau) < 0 {
+			k++
+		}
+		B = a - k*g.Tau
+	}
+
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > glicko2Epsilon {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+
+	return math.Exp(A / 2)
+}
+
+// glicko2G is the g(phi) weighting function: it discounts a result against
+// an opponent whose own rating is uncertain.
+func glicko2G(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
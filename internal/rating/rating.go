@@ -0,0 +1,51 @@
+// Package rating holds the domain.RatingEngine implementations that turn a
+// single Phase 1 duel into new ratings for both torrons: classic Elo,
+// Glicko-2 and TrueSkill. The engines are pure functions of their inputs;
+// reading, locking and persisting the ratings is the caller's job.
+package rating
+
+import (
+	"fmt"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+// Options carries the tunable parameters of every engine. Zero values fall
+// back to the package defaults, so callers only need to set what the
+// configuration actually overrides.
+type Options struct {
+	EloK          float64
+	Glicko2Tau    float64
+	TrueSkillBeta float64
+	TrueSkillTau  float64
+
+	TrueSkillDrawProbability float64
+}
+
+// New returns the engine registered under algorithm (one of the
+// domain.RatingAlgorithm* names). An empty algorithm selects Elo, which
+// keeps every existing deployment on the behaviour it had before engines
+// were pluggable.
+func New(algorithm string, opts Options) (domain.RatingEngine, error) {
+	switch algorithm {
+	case "", domain.RatingAlgorithmElo:
+		return NewElo(orDefault(opts.EloK, DefaultEloK)), nil
+	case domain.RatingAlgorithmGlicko2:
+		return NewGlicko2(orDefault(opts.Glicko2Tau, DefaultGlicko2Tau)), nil
+	case domain.RatingAlgorithmTrueSkill:
+		return NewTrueSkill(
+			orDefault(opts.TrueSkillBeta, DefaultTrueSkillBeta),
+			orDefault(opts.TrueSkillTau, DefaultTrueSkillTau),
+			orDefault(opts.TrueSkillDrawProbability, DefaultTrueSkillDrawProbability),
+		), nil
+	default:
+		return nil, fmt.Errorf("unknown rating algorithm %q", algorithm)
+	}
+}
+
+func orDefault(v, def float64) float64 {
+	if v <= 0 {
+		return def
+	}
+	return v
+}
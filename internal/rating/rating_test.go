@@ -0,0 +1,160 @@
+package rating
+
+import (
+	"math"
+	"testing"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		algorithm string
+		wantName  string
+		wantErr   bool
+	}{
+		{"", domain.RatingAlgorithmElo, false},
+		{domain.RatingAlgorithmElo, domain.RatingAlgorithmElo, false},
+		{domain.RatingAlgorithmGlicko2, domain.RatingAlgorithmGlicko2, false},
+		{domain.RatingAlgorithmTrueSkill, domain.RatingAlgorithmTrueSkill, false},
+		{"bradley-terry", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.algorithm, func(t *testing.T) {
+			engine, err := New(tt.algorithm, Options{})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("New(%q) error = nil, want an error", tt.algorithm)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("New(%q) error = %v", tt.algorithm, err)
+			}
+			if engine.Name() != tt.wantName {
+				t.Errorf("New(%q).Name() = %q, want %q", tt.algorithm, engine.Name(), tt.wantName)
+			}
+		})
+	}
+}
+
+// TestEloEngineMatchesUpdateRatings guards that the Elo engine is a drop-in
+// replacement for the UpdateRatings call the vote handler used to make.
+func TestEloEngineMatchesUpdateRatings(t *testing.T) {
+	engine := NewElo(DefaultEloK)
+	a := domain.RatingState{Rating: 1620, Deviation: 120, Volatility: 0.06}
+	b := domain.RatingState{Rating: 1480, Deviation: 300, Volatility: 0.07}
+
+	for _, aWon := range []bool{true, false} {
+		score := 0.0
+		if aWon {
+			score = 1
+		}
+		gotA, gotB := engine.Update(a, b, score)
+		wantA, wantB := UpdateRatings(a.Rating, b.Rating, aWon, DefaultEloK)
+
+		if math.Abs(gotA.Rating-wantA) > 1e-9 || math.Abs(gotB.Rating-wantB) > 1e-9 {
+			t.Errorf("aWon=%v: Update = (%v, %v), want (%v, %v)", aWon, gotA.Rating, gotB.Rating, wantA, wantB)
+		}
+		if gotA.Deviation != a.Deviation || gotA.Volatility != a.Volatility ||
+			gotB.Deviation != b.Deviation || gotB.Volatility != b.Volatility {
+			t.Errorf("aWon=%v: Elo touched deviation/volatility: %+v, %+v", aWon, gotA, gotB)
+		}
+	}
+}
+
+// TestDeviationAwareEngines checks the behaviour the deviation-aware engines
+// exist for: the winner goes up, the loser goes down, both become more
+// certain, and a rarely-seen torró moves further than an established one
+// for the same result.
+func TestDeviationAwareEngines(t *testing.T) {
+	engines := []domain.RatingEngine{
+		NewGlicko2(DefaultGlicko2Tau),
+		NewTrueSkill(DefaultTrueSkillBeta, DefaultTrueSkillTau, DefaultTrueSkillDrawProbability),
+	}
+
+	for _, engine := range engines {
+		t.Run(engine.Name(), func(t *testing.T) {
+			fresh := engine.Initial()
+			settled := domain.RatingState{Rating: 1500, Deviation: 60, Volatility: 0.06}
+			opponent := domain.RatingState{Rating: 1500, Deviation: 60, Volatility: 0.06}
+
+			freshWin, oppLoss := engine.Update(fresh, opponent, 1)
+			if freshWin.Rating <= fresh.Rating {
+				t.Errorf("winner's rating didn't increase: %v -> %v", fresh.Rating, freshWin.Rating)
+			}
+			if oppLoss.Rating >= opponent.Rating {
+				t.Errorf("loser's rating didn't decrease: %v -> %v", opponent.Rating, oppLoss.Rating)
+			}
+			if freshWin.Deviation >= fresh.Deviation {
+				t.Errorf("winner's deviation didn't shrink: %v -> %v", fresh.Deviation, freshWin.Deviation)
+			}
+
+			settledWin, _ := engine.Update(settled, opponent, 1)
+			if freshWin.Rating-fresh.Rating <= settledWin.Rating-settled.Rating {
+				t.Errorf("fresh gain %v should exceed settled gain %v",
+					freshWin.Rating-fresh.Rating, settledWin.Rating-settled.Rating)
+			}
+
+			// Score is from a's point of view: b winning must mirror a winning.
+			bLoss, bWin := engine.Update(opponent, fresh, 0)
+			if math.Abs(bWin.Rating-freshWin.Rating) > 1e-9 || math.Abs(bLoss.Rating-oppLoss.Rating) > 1e-9 {
+				t.Errorf("update isn't symmetric in argument order: (%v, %v) vs (%v, %v)",
+					bWin.Rating, bLoss.Rating, freshWin.Rating, oppLoss.Rating)
+			}
+		})
+	}
+}
+
+// TestGlicko2Stable runs a long alternating series and checks the volatility
+// iteration neither diverges nor produces NaNs.
+func TestGlicko2Stable(t *testing.T) {
+	engine := NewGlicko2(DefaultGlicko2Tau)
+	a, b := engine.Initial(), engine.Initial()
+
+	for i := 0; i < 1000; i++ {
+		a, b = engine.Update(a, b, float64(i%3%2))
+	}
+
+	for _, s := range []domain.RatingState{a, b} {
+		if math.IsNaN(s.Rating) || math.IsNaN(s.Deviation) || math.IsNaN(s.Volatility) {
+			t.Fatalf("state went NaN: %+v", s)
+		}
+		if s.Deviation <= 0 || s.Deviation > domain.DefaultRatingDeviation {
+			t.Errorf("deviation out of range: %v", s.Deviation)
+		}
+	}
+}
+
+// TestDraws checks every engine on a "can't decide" vote: two equal torrons
+// stay where they are, and a draw against a weaker torró costs the favourite
+// rating.
+func TestDraws(t *testing.T) {
+	engines := []domain.RatingEngine{
+		NewElo(DefaultEloK),
+		NewGlicko2(DefaultGlicko2Tau),
+		NewTrueSkill(DefaultTrueSkillBeta, DefaultTrueSkillTau, DefaultTrueSkillDrawProbability),
+	}
+
+	for _, engine := range engines {
+		t.Run(engine.Name(), func(t *testing.T) {
+			even := domain.RatingState{Rating: 1500, Deviation: 200, Volatility: 0.06}
+			gotA, gotB := engine.Update(even, even, 0.5)
+			if math.Abs(gotA.Rating-even.Rating) > 1e-9 || math.Abs(gotB.Rating-even.Rating) > 1e-9 {
+				t.Errorf("draw between equals moved ratings: %v, %v", gotA.Rating, gotB.Rating)
+			}
+			if gotA.Deviation > even.Deviation+1e-9 {
+				t.Errorf("draw grew deviation: %v -> %v", even.Deviation, gotA.Deviation)
+			}
+
+			strong := domain.RatingState{Rating: 1700, Deviation: 200, Volatility: 0.06}
+			weak := domain.RatingState{Rating: 1400, Deviation: 200, Volatility: 0.06}
+			gotStrong, gotWeak := engine.Update(strong, weak, 0.5)
+			if gotStrong.Rating >= strong.Rating || gotWeak.Rating <= weak.Rating {
+				t.Errorf("draw should pull ratings together: (%v, %v) -> (%v, %v)",
+					strong.Rating, weak.Rating, gotStrong.Rating, gotWeak.Rating)
+			}
+		})
+	}
+}
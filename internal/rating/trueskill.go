@@ -0,0 +1,136 @@
+package rating
+
+import (
+	"math"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+// TrueSkill defaults, expressed on this project's 1500-centred scale rather
+// than Microsoft's mu=25. The usual ratios are kept (beta = sigma0/2,
+// tau = sigma0/100) with sigma0 = domain.DefaultRatingDeviation, so a fresh
+// torró starts with the same uncertainty under TrueSkill as under Glicko-2.
+const (
+	DefaultTrueSkillBeta = domain.DefaultRatingDeviation / 2
+	DefaultTrueSkillTau  = domain.DefaultRatingDeviation / 100
+
+	// DefaultTrueSkillDrawProbability is the assumed share of "can't
+	// decide" votes between two evenly matched torrons. It sets the draw
+	// margin, which both draws and wins are scored against.
+	DefaultTrueSkillDrawProbability = 0.10
+)
+
+// TrueSkill is a two-player TrueSkill engine. Rating is the skill mean (mu)
+// and Deviation its standard deviation (sigma); Volatility is unused.
+type TrueSkill struct {
+	// Beta is the performance noise: the rating gap that gives the stronger
+	// side roughly a 76% chance of winning.
+	Beta float64
+	// Tau is the additive dynamics factor applied before every update, which
+	// keeps sigma from collapsing to zero over a long season.
+	Tau float64
+	// DrawProbability is the chance of a draw between equal torrons.
+	DrawProbability float64
+}
+
+// NewTrueSkill returns a TrueSkill engine with the given beta, tau and draw
+// probability.
+func NewTrueSkill(beta, tau, drawProbability float64) *TrueSkill {
+	return &TrueSkill{Beta: beta, Tau: tau, DrawProbability: drawProbability}
+}
+
+func (t *TrueSkill) Name() string {
+	return domain.RatingAlgorithmTrueSkill
+}
+
+func (t *TrueSkill) Initial() domain.RatingState {
+	return domain.RatingState{
+		Rating:     domain.DefaultRating,
+		Deviation:  domain.DefaultRatingDeviation,
+		Volatility: domain.DefaultRatingVolatility,
+	}
+}
+
+func (t *TrueSkill) Update(a, b domain.RatingState, scoreA float64) (domain.RatingState, domain.RatingState) {
+	// For a decided duel, orient the update so "first" is the winner; a
+	// draw is symmetric and keeps the argument order.
+	first, second := a, b
+	if scoreA < 0.5 {
+		first, second = b, a
+	}
+
+	var1 := first.Deviation*first.Deviation + t.Tau*t.Tau
+	var2 := second.Deviation*second.Deviation + t.Tau*t.Tau
+	c2 := 2*t.Beta*t.Beta + var1 + var2
+	c := math.Sqrt(c2)
+
+	x := (first.Rating - second.Rating) / c
+	eps := t.drawMargin() / c
+
+	var v, w float64
+	if scoreA == 0.5 {
+		v, w = trueSkillDraw(x, eps)
+	} else {
+		v, w = trueSkillWin(x, eps)
+	}
+
+	first.Rating += var1 / c * v
+	second.Rating -= var2 / c * v
+	first.Deviation = math.Sqrt(var1 * (1 - var1/c2*w))
+	second.Deviation = math.Sqrt(var2 * (1 - var2/c2*w))
+
+	if scoreA < 0.5 {
+		return second, first
+	}
+	return first, second
+}
+
+// drawMargin converts DrawProbability into the performance gap below which
+// a duel reads as a draw: eps = Phi^-1((p+1)/2) * sqrt(2) * beta.
+func (t *TrueSkill) drawMargin() float64 {
+	if t.DrawProbability <= 0 {
+		return 0
+	}
+	return normPPF((t.DrawProbability+1)/2) * math.Sqrt2 * t.Beta
+}
+
+// trueSkillWin returns the mean and variance corrections (v, w) for the
+// first side winning, with x the normalised rating gap and eps the
+// normalised draw margin. For a huge upset Phi underflows, so v falls back
+// to its asymptote.
+func trueSkillWin(x, eps float64) (float64, float64) {
+	d := x - eps
+	denom := normCDF(d)
+	var v float64
+	if denom < 1e-12 {
+		v = -d
+	} else {
+		v = normPDF(d) / denom
+	}
+	return v, v * (v + d)
+}
+
+// trueSkillDraw returns the mean and variance corrections (v, w) for a draw.
+// With no draw margin a draw carries no information, so nothing moves.
+func trueSkillDraw(x, eps float64) (float64, float64) {
+	denom := normCDF(eps-x) - normCDF(-eps-x)
+	if denom < 1e-12 {
+		return 0, 0
+	}
+	v := (normPDF(-eps-x) - normPDF(eps-x)) / denom
+	w := v*v + ((eps-x)*normPDF(eps-x)+(eps+x)*normPDF(eps+x))/denom
+	return v, w
+}
+
+func normPDF(x float64) float64 {
+	return math.Exp(-x*x/2) / math.Sqrt(2*math.Pi)
+}
+
+func normCDF(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
+
+// normPPF is the standard normal quantile function, Phi^-1.
+func normPPF(p float64) float64 {
+	return math.Sqrt2 * math.Erfinv(2*p-1)
+}
@@ -0,0 +1,321 @@
+// Package recommend predicts how a user would rate the torrons they haven't
+// rated yet, from their personal snapshots (domain.UserEloSnapshot).
+//
+// The main signal is item-item collaborative filtering. Two torrons are
+// similar when the users who rated both liked or disliked them together.
+// That is measured as the adjusted cosine of their ratings, each centred on
+// the user's own mean so that a generous rater and a harsh one agree. The
+// cosine is shrunk towards zero when few users rated both. A user's
+// predicted rating for a torró is their mean plus the similarity-weighted
+// deviation of the torrons they rated that are most like it.
+//
+// A torró too new or too rarely rated to have similarities falls back to
+// the catalog: it borrows from the rated torrons it shares a class,
+// ingredients and intensity with. A user with no snapshots at all gets the
+// community's ratings.
+package recommend
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+const (
+	// MinCoRaters is how many users must have rated both torrons before
+	// their similarity is stored at all.
+	MinCoRaters = 3
+
+	// Shrinkage damps the similarity of a pair rated by n users by
+	// n / (n + Shrinkage), so a perfect agreement among three users
+	// doesn't outweigh a strong one among thirty.
+	Shrinkage = 10
+
+	// Neighbours is how many of the most similar rated torrons a
+	// prediction is built from.
+	Neighbours = 20
+)
+
+// Catalog similarity weights, summing to 1.
+const (
+	classWeight       = 0.4
+	ingredientsWeight = 0.4
+	intensityWeight   = 0.2
+
+	// maxIntensityGap is the widest gap between two IntensityLevel values
+	// (1 to 5).
+	maxIntensityGap = 4
+)
+
+// Similarities computes the item-item similarity of every two torrons rated
+// by at least MinCoRaters of the users in snapshots. Only UserId, TorronId
+// and Rating are read. Pairs come with Torro1 < Torro2, sorted, and only
+// those with a non-zero similarity are returned.
+func Similarities(snapshots []*domain.UserEloSnapshot, now time.Time) []*domain.TorroSimilarity {
+	byUser := make(map[string]map[string]float64)
+	for _, s := range snapshots {
+		if byUser[s.UserId] == nil {
+			byUser[s.UserId] = make(map[string]float64)
+		}
+		byUser[s.UserId][s.TorronId] = s.Rating
+	}
+
+	type pair struct{ a, b string }
+	type tally struct {
+		dot, sqA, sqB float64
+		n             int
+	}
+	tallies := make(map[pair]*tally)
+
+	for _, ratings := range byUser {
+		if len(ratings) < 2 {
+			continue
+		}
+
+		ids := make([]string, 0, len(ratings))
+		for id := range ratings {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		mu := mean(ratings)
+
+		for i := 0; i < len(ids); i++ {
+			da := ratings[ids[i]] - mu
+			for j := i + 1; j < len(ids); j++ {
+				db := ratings[ids[j]] - mu
+				key := pair{ids[i], ids[j]}
+				t := tallies[key]
+				if t == nil {
+					t = &tally{}
+					tallies[key] = t
+				}
+				t.dot += da * db
+				t.sqA += da * da
+				t.sqB += db * db
+				t.n++
+			}
+		}
+	}
+
+	var similarities []*domain.TorroSimilarity
+	for key, t := range tallies {
+		if t.n < MinCoRaters || t.sqA == 0 || t.sqB == 0 {
+			continue
+		}
+		cosine := t.dot / math.Sqrt(t.sqA*t.sqB)
+		similarity := cosine * float64(t.n) / float64(t.n+Shrinkage)
+		if similarity == 0 {
+			continue
+		}
+		similarities = append(similarities, &domain.TorroSimilarity{
+			Torro1:     key.a,
+			Torro2:     key.b,
+			Similarity: similarity,
+			CoRaters:   t.n,
+			FittedAt:   now,
+		})
+	}
+
+	sort.Slice(similarities, func(i, j int) bool {
+		if similarities[i].Torro1 != similarities[j].Torro1 {
+			return similarities[i].Torro1 < similarities[j].Torro1
+		}
+		return similarities[i].Torro2 < similarities[j].Torro2
+	})
+	return similarities
+}
+
+// neighbour is a torró the user rated, weighted by how much it says about
+// the candidate.
+type neighbour struct {
+	id        string
+	weight    float64
+	deviation float64 // the user's rating of it minus their mean
+}
+
+// Recommend predicts the user's rating of every active torró they have no
+// snapshot of and returns the limit best, highest prediction first (all of
+// them when limit is not positive). userSnapshots are the user's own;
+// similarities are the latest fit of Similarities; torrons is the whole
+// catalog.
+//
+// A candidate with similarities to rated torrons is predicted from them
+// (domain.RecommendationCollaborative), so one unlike what the user loved
+// is predicted low rather than left out. Otherwise it is predicted from the
+// rated torrons most like it in the catalog (domain.RecommendationCatalog),
+// and left out if none is. With no snapshots every candidate gets its
+// community rating (domain.RecommendationPopular).
+func Recommend(
+	userSnapshots []*domain.UserEloSnapshot,
+	similarities []*domain.TorroSimilarity,
+	torrons []*domain.Torro,
+	limit int,
+) []*domain.Recommendation {
+	catalog := make(map[string]*domain.Torro, len(torrons))
+	for _, t := range torrons {
+		catalog[t.Id] = t
+	}
+
+	ratings := make(map[string]float64, len(userSnapshots))
+	for _, s := range userSnapshots {
+		if catalog[s.TorronId] != nil {
+			ratings[s.TorronId] = s.Rating
+		}
+	}
+	mu := mean(ratings)
+
+	// Only similarities to torrons the user rated can take part.
+	similar := make(map[string][]neighbour)
+	for _, s := range similarities {
+		if r, ok := ratings[s.Torro2]; ok {
+			similar[s.Torro1] = append(similar[s.Torro1], neighbour{s.Torro2, s.Similarity, r - mu})
+		}
+		if r, ok := ratings[s.Torro1]; ok {
+			similar[s.Torro2] = append(similar[s.Torro2], neighbour{s.Torro1, s.Similarity, r - mu})
+		}
+	}
+
+	var recommendations []*domain.Recommendation
+	for _, t := range torrons {
+		if t.Discontinued {
+			continue
+		}
+		if _, rated := ratings[t.Id]; rated {
+			continue
+		}
+
+		rec := &domain.Recommendation{
+			TorroId: t.Id,
+			Name:    t.Name,
+			Image:   t.Image,
+			ClassId: t.Class,
+		}
+
+		switch {
+		case len(ratings) == 0:
+			rec.Predicted = t.Rating
+			rec.Basis = domain.RecommendationPopular
+
+		case len(similar[t.Id]) > 0:
+			rec.Predicted, rec.BecauseId = predict(mu, similar[t.Id])
+			rec.Basis = domain.RecommendationCollaborative
+
+		default:
+			var alike []neighbour
+			for id, r := range ratings {
+				if w := catalogSimilarity(t, catalog[id]); w > 0 {
+					alike = append(alike, neighbour{id, w, r - mu})
+				}
+			}
+			if len(alike) == 0 {
+				continue
+			}
+			rec.Predicted, rec.BecauseId = predict(mu, alike)
+			rec.Basis = domain.RecommendationCatalog
+		}
+
+		if because := catalog[rec.BecauseId]; because != nil {
+			rec.BecauseName = because.Name
+		}
+		recommendations = append(recommendations, rec)
+	}
+
+	sort.Slice(recommendations, func(i, j int) bool {
+		if recommendations[i].Predicted != recommendations[j].Predicted {
+			return recommendations[i].Predicted > recommendations[j].Predicted
+		}
+		return recommendations[i].TorroId < recommendations[j].TorroId
+	})
+	if limit > 0 && len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+	return recommendations
+}
+
+// predict returns mu plus the weighted mean deviation of the Neighbours
+// neighbours with the heaviest weights (by magnitude: a negative one counts
+// the deviation against the candidate), and the torró the user liked that
+// pulled the prediction up the most. The second value is empty when no
+// liked torró did.
+func predict(mu float64, neighbours []neighbour) (float64, string) {
+	sort.Slice(neighbours, func(i, j int) bool {
+		wi, wj := math.Abs(neighbours[i].weight), math.Abs(neighbours[j].weight)
+		if wi != wj {
+			return wi > wj
+		}
+		return neighbours[i].id < neighbours[j].id
+	})
+	if len(neighbours) > Neighbours {
+		neighbours = neighbours[:Neighbours]
+	}
+
+	var sum, weights, best float64
+	because := ""
+	for _, n := range neighbours {
+		sum += n.weight * n.deviation
+		weights += math.Abs(n.weight)
+		// Only a torró the user liked can be thanked for it: disliking one
+		// unlike the candidate pulls up just as well, but "because you
+		// disliked X" is no explanation.
+		if pull := n.weight * n.deviation; n.deviation > 0 && pull > best {
+			best, because = pull, n.id
+		}
+	}
+	return mu + sum/weights, because
+}
+
+// catalogSimilarity scores how alike two torrons are on paper, from 0 to 1:
+// the same class, the overlap of their main ingredients and how close
+// their intensity is.
+func catalogSimilarity(a, b *domain.Torro) float64 {
+	score := 0.0
+	if a.Class == b.Class {
+		score += classWeight
+	}
+	score += ingredientsWeight * jaccard(a.MainIngredients, b.MainIngredients)
+	if a.IntensityLevel != nil && b.IntensityLevel != nil {
+		gap := math.Abs(float64(*a.IntensityLevel - *b.IntensityLevel))
+		score += intensityWeight * (1 - gap/maxIntensityGap)
+	}
+	return score
+}
+
+// jaccard is the size of the intersection of a and b over that of their
+// union, 0 when both are empty.
+func jaccard(a, b []string) float64 {
+	set := make(map[string]bool, len(a))
+	for _, x := range a {
+		set[x] = true
+	}
+	union := len(set)
+	common := 0
+	seen := make(map[string]bool, len(b))
+	for _, x := range b {
+		if seen[x] {
+			continue
+		}
+		seen[x] = true
+		if set[x] {
+			common++
+		} else {
+			union++
+		}
+	}
+	if union == 0 {
+		return 0
+	}
+	return float64(common) / float64(union)
+}
+
+func mean(ratings map[string]float64) float64 {
+	if len(ratings) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, r := range ratings {
+		sum += r
+	}
+	return sum / float64(len(ratings))
+}
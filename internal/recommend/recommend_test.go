@@ -0,0 +1,158 @@
+package recommend
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+func torro(id, class string) *domain.Torro {
+	return &domain.Torro{Id: id, Name: "Torró " + id, Class: class}
+}
+
+func snap(user, torro string, rating float64) *domain.UserEloSnapshot {
+	return &domain.UserEloSnapshot{UserId: user, TorronId: torro, Rating: rating}
+}
+
+func find(sims []*domain.TorroSimilarity, a, b string) *domain.TorroSimilarity {
+	for _, s := range sims {
+		if s.Torro1 == a && s.Torro2 == b {
+			return s
+		}
+	}
+	return nil
+}
+
+// tasteSnapshots has four users who love a and b together and dislike c,
+// so a and b come out similar and c unlike both.
+func tasteSnapshots() []*domain.UserEloSnapshot {
+	var snapshots []*domain.UserEloSnapshot
+	for i, user := range []string{"u1", "u2", "u3", "u4"} {
+		bump := float64(i * 10)
+		snapshots = append(snapshots,
+			snap(user, "a", 1600+bump),
+			snap(user, "b", 1590+bump),
+			snap(user, "c", 1400+bump),
+		)
+	}
+	return snapshots
+}
+
+func TestSimilarities(t *testing.T) {
+	sims := Similarities(tasteSnapshots(), time.Now())
+
+	ab, ac := find(sims, "a", "b"), find(sims, "a", "c")
+	if ab == nil || ac == nil {
+		t.Fatalf("similarities = %v, want a–b and a–c", sims)
+	}
+	if ab.CoRaters != 4 {
+		t.Errorf("a–b co-raters = %d, want 4", ab.CoRaters)
+	}
+	// Perfect agreement, shrunk by 4 / (4 + Shrinkage).
+	if want := 4.0 / (4 + Shrinkage); math.Abs(ab.Similarity-want) > 1e-9 {
+		t.Errorf("a–b similarity = %.4f, want %.4f", ab.Similarity, want)
+	}
+	if ac.Similarity >= 0 {
+		t.Errorf("a–c similarity = %.4f, want negative", ac.Similarity)
+	}
+}
+
+func TestSimilaritiesNeedCoRaters(t *testing.T) {
+	snapshots := tasteSnapshots()[:(MinCoRaters-1)*3]
+	if sims := Similarities(snapshots, time.Now()); len(sims) != 0 {
+		t.Errorf("similarities from %d users = %v, want none", MinCoRaters-1, sims)
+	}
+}
+
+func TestRecommendCollaborative(t *testing.T) {
+	torrons := []*domain.Torro{torro("a", "1"), torro("b", "1"), torro("c", "1"), torro("d", "2")}
+	sims := Similarities(tasteSnapshots(), time.Now())
+
+	// The new user loved a and disliked d; b is like a, c unlike it.
+	user := []*domain.UserEloSnapshot{snap("new", "a", 1650), snap("new", "d", 1350)}
+	recs := Recommend(user, sims, torrons, 0)
+
+	if len(recs) != 2 {
+		t.Fatalf("got %d recommendations, want b and c", len(recs))
+	}
+	b := recs[0]
+	if b.TorroId != "b" || b.Basis != domain.RecommendationCollaborative {
+		t.Fatalf("first = %s (%s), want b (collaborative)", b.TorroId, b.Basis)
+	}
+	if b.Predicted != 1650 {
+		t.Errorf("b predicted %.1f, want a's 1650: a is its only similar rated torró", b.Predicted)
+	}
+	if b.BecauseId != "a" || b.BecauseName != "Torró a" {
+		t.Errorf("b because = %s %q, want a", b.BecauseId, b.BecauseName)
+	}
+
+	// c is unlike a, which the user loved, so it is predicted as far below
+	// their mean as a is above it, with nothing to thank for it.
+	c := recs[1]
+	if c.TorroId != "c" || c.Basis != domain.RecommendationCollaborative {
+		t.Fatalf("second = %s (%s), want c (collaborative)", c.TorroId, c.Basis)
+	}
+	if c.Predicted != 1350 || c.BecauseId != "" {
+		t.Errorf("c predicted %.1f because %q, want 1350 because nothing", c.Predicted, c.BecauseId)
+	}
+}
+
+func TestRecommendBecauseLiked(t *testing.T) {
+	torrons := []*domain.Torro{torro("a", "1"), torro("b", "1"), torro("c", "1"), torro("d", "2")}
+	sims := Similarities(tasteSnapshots(), time.Now())
+
+	// Hating c, which is unlike b, pulls b up more than loving a does, but
+	// b is still recommended because of a.
+	user := []*domain.UserEloSnapshot{snap("new", "a", 1600), snap("new", "c", 1200), snap("new", "d", 1500)}
+	recs := Recommend(user, sims, torrons, 1)
+
+	if len(recs) != 1 || recs[0].TorroId != "b" {
+		t.Fatalf("recommendations = %v, want b", recs)
+	}
+	if recs[0].BecauseId != "a" {
+		t.Errorf("b because = %q, want a", recs[0].BecauseId)
+	}
+}
+
+func TestRecommendCatalogFallback(t *testing.T) {
+	intensity := func(n int) *int { return &n }
+	torrons := []*domain.Torro{
+		{Id: "liked", Name: "Liked", Class: "1", MainIngredients: []string{"ametlla", "mel"}, IntensityLevel: intensity(4)},
+		{Id: "meh", Name: "Meh", Class: "2", MainIngredients: []string{"xocolata"}, IntensityLevel: intensity(1)},
+		{Id: "twin", Name: "Twin", Class: "1", MainIngredients: []string{"ametlla", "mel"}, IntensityLevel: intensity(4)},
+		{Id: "choc", Name: "Choc", Class: "2", MainIngredients: []string{"xocolata"}, IntensityLevel: intensity(2)},
+		{Id: "gone", Name: "Gone", Class: "1", Discontinued: true},
+	}
+	user := []*domain.UserEloSnapshot{snap("new", "liked", 1700), snap("new", "meh", 1300)}
+
+	recs := Recommend(user, nil, torrons, 0)
+	if len(recs) != 2 {
+		t.Fatalf("got %d recommendations, want twin and choc", len(recs))
+	}
+	if recs[0].TorroId != "twin" || recs[0].BecauseId != "liked" {
+		t.Errorf("first = %s because %s, want twin because liked", recs[0].TorroId, recs[0].BecauseId)
+	}
+	if recs[1].TorroId != "choc" || recs[1].Predicted >= 1500 {
+		t.Errorf("second = %s at %.1f, want choc below the user's mean", recs[1].TorroId, recs[1].Predicted)
+	}
+	for _, rec := range recs {
+		if rec.Basis != domain.RecommendationCatalog {
+			t.Errorf("%s basis = %s, want catalog", rec.TorroId, rec.Basis)
+		}
+	}
+}
+
+func TestRecommendPopularWithoutSnapshots(t *testing.T) {
+	torrons := []*domain.Torro{torro("a", "1"), torro("b", "1"), torro("c", "1")}
+	torrons[0].Rating, torrons[1].Rating, torrons[2].Rating = 1500, 1620, 1480
+
+	recs := Recommend(nil, nil, torrons, 2)
+	if len(recs) != 2 || recs[0].TorroId != "b" || recs[1].TorroId != "a" {
+		t.Fatalf("recommendations = %v, want b then a", recs)
+	}
+	if recs[0].Basis != domain.RecommendationPopular || recs[0].Predicted != 1620 || recs[0].BecauseId != "" {
+		t.Errorf("b = %+v, want its community rating with no because", recs[0])
+	}
+}
@@ -0,0 +1,194 @@
+// Package replay rebuilds ratings from the Results history: every vote is
+// re-run, in the order it was cast, through a chosen domain.RatingEngine,
+// exactly as the vote handler would have applied it. It backs cmd/rerate.
+package replay
+
+import (
+	"sort"
+	"time"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+// Options narrows down which votes a replay applies. Excluded votes keep
+// their Results row but no longer move any rating.
+type Options struct {
+	// ExcludeUsers drops every vote cast by these user ids.
+	ExcludeUsers map[string]bool
+
+	// ExcludeCampaign drops every vote tagged with this campaign id.
+	ExcludeCampaign string
+}
+
+func (o Options) excludes(v *domain.ReplayVote) bool {
+	if v.UserId != nil && o.ExcludeUsers[*v.UserId] {
+		return true
+	}
+	return o.ExcludeCampaign != "" && v.CampaignId != nil && *v.CampaignId == o.ExcludeCampaign
+}
+
+// Run replays votes (already in cast order) over torrons, all starting from
+// engine.Initial(). A user's snapshot of a torró is created the first time
+// they vote on it, from the torró's global rating just before that vote and
+// with full uncertainty, mirroring UserEloSnapshotRepo.GetOrCreateTx. An
+// excluded vote is recorded with equal before and after ratings. So is a
+// quarantined user's vote, which still updates that user's snapshots, as
+// the vote handler does. Each vote moves the global ratings by the weight
+// it was cast with; snapshots always take the full update.
+func Run(engine domain.RatingEngine, torrons []*domain.Torro, votes []*domain.ReplayVote, opts Options) *domain.Replay {
+	states := make(map[string]domain.RatingState, len(torrons))
+	for _, t := range torrons {
+		states[t.Id] = engine.Initial()
+	}
+	state := func(id string) domain.RatingState {
+		s, ok := states[id]
+		if !ok {
+			s = engine.Initial()
+			states[id] = s
+		}
+		return s
+	}
+
+	type snapshotKey struct{ userId, torroId string }
+	snapshots := make(map[snapshotKey]*domain.UserEloSnapshot)
+	var order []snapshotKey
+	snapshot := func(userId, torroId string, global domain.RatingState) *domain.UserEloSnapshot {
+		key := snapshotKey{userId, torroId}
+		s, ok := snapshots[key]
+		if !ok {
+			s = &domain.UserEloSnapshot{
+				UserId:           userId,
+				TorronId:         torroId,
+				Rating:           global.Rating,
+				RatingDeviation:  domain.DefaultRatingDeviation,
+				RatingVolatility: domain.DefaultRatingVolatility,
+			}
+			snapshots[key] = s
+			order = append(order, key)
+		}
+		return s
+	}
+
+	replay := &domain.Replay{
+		Results: make([]domain.ReplayedResult, 0, len(votes)),
+	}
+
+	for _, v := range votes {
+		before1, before2 := state(v.Torro1), state(v.Torro2)
+		result := domain.ReplayedResult{
+			ResultId: v.ResultId,
+			Rat1Bef:  before1.Rating,
+			Rat2Bef:  before2.Rating,
+			Rat1Aft:  before1.Rating,
+			Rat2Aft:  before2.Rating,
+		}
+
+		if opts.excludes(v) {
+			replay.Results = append(replay.Results, result)
+			replay.Skipped++
+			continue
+		}
+
+		score := v.ScoreTorro1()
+		if v.Quarantined {
+			replay.Quarantined++
+		} else {
+			after1, after2 := domain.WeightedUpdate(engine, before1, before2, score, v.Weight)
+			states[v.Torro1], states[v.Torro2] = after1, after2
+			result.Rat1Aft, result.Rat2Aft = after1.Rating, after2.Rating
+			replay.Applied++
+		}
+
+		if v.UserId != nil {
+			s1 := snapshot(*v.UserId, v.Torro1, before1)
+			s2 := snapshot(*v.UserId, v.Torro2, before2)
+			new1, new2 := engine.Update(s1.RatingState(), s2.RatingState(), score)
+			lastUpdated := v.Timestamp.UTC().Format(time.RFC3339)
+			for _, u := range []struct {
+				s     *domain.UserEloSnapshot
+				state domain.RatingState
+			}{{s1, new1}, {s2, new2}} {
+				u.s.SetRatingState(u.state)
+				u.s.VoteCount++
+				u.s.LastUpdated = lastUpdated
+			}
+		}
+
+		replay.Results = append(replay.Results, result)
+	}
+
+	replay.Torrons = states
+	replay.Snapshots = make([]*domain.UserEloSnapshot, 0, len(order))
+	for _, key := range order {
+		replay.Snapshots = append(replay.Snapshots, snapshots[key])
+	}
+
+	return replay
+}
+
+// RankChange compares one torró's live standing in its class with its
+// standing after a replay.
+type RankChange struct {
+	TorroId    string
+	Name       string
+	Class      string
+	LiveRank   int
+	NewRank    int
+	LiveRating float64
+	NewRating  float64
+}
+
+// Moved reports how many places the torró climbed (positive) or fell.
+func (c RankChange) Moved() int {
+	return c.LiveRank - c.NewRank
+}
+
+// RankDiff ranks torrons within their class by live rating and by replayed
+// rating, and returns one RankChange per torró ordered by class and then by
+// new rank.
+func RankDiff(torrons []*domain.Torro, replay *domain.Replay) []RankChange {
+	byClass := make(map[string][]*RankChange)
+	var classes []string
+	for _, t := range torrons {
+		if _, ok := byClass[t.Class]; !ok {
+			classes = append(classes, t.Class)
+		}
+		byClass[t.Class] = append(byClass[t.Class], &RankChange{
+			TorroId:    t.Id,
+			Name:       t.Name,
+			Class:      t.Class,
+			LiveRating: t.Rating,
+			NewRating:  replay.Torrons[t.Id].Rating,
+		})
+	}
+	sort.Strings(classes)
+
+	var diff []RankChange
+	for _, class := range classes {
+		changes := byClass[class]
+
+		rank(changes, func(c *RankChange) float64 { return c.LiveRating }, func(c *RankChange, r int) { c.LiveRank = r })
+		rank(changes, func(c *RankChange) float64 { return c.NewRating }, func(c *RankChange, r int) { c.NewRank = r })
+
+		for _, c := range changes {
+			diff = append(diff, *c)
+		}
+	}
+
+	return diff
+}
+
+// rank sorts changes by rating descending (name as a stable tie-break) and
+// assigns 1-based ranks. The last call decides the slice's final order.
+func rank(changes []*RankChange, rating func(*RankChange) float64, set func(*RankChange, int)) {
+	sort.SliceStable(changes, func(i, j int) bool {
+		ri, rj := rating(changes[i]), rating(changes[j])
+		if ri != rj {
+			return ri > rj
+		}
+		return changes[i].Name < changes[j].Name
+	})
+	for i, c := range changes {
+		set(c, i+1)
+	}
+}
@@ -0,0 +1,200 @@
+package replay
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/krtffl/torro/internal/domain"
+	"github.com/krtffl/torro/internal/rating"
+)
+
+func ptr(s string) *string { return &s }
+
+func vote(id, t1, t2 string, winner *string, user string, minute int) *domain.ReplayVote {
+	v := &domain.ReplayVote{
+		ResultId:  id,
+		Torro1:    t1,
+		Torro2:    t2,
+		Winner:    winner,
+		Outcome:   domain.ResultOutcomeWin,
+		Timestamp: time.Date(2025, 12, 1, 10, minute, 0, 0, time.UTC),
+		Weight:    1,
+	}
+	if winner == nil {
+		v.Outcome = domain.ResultOutcomeDraw
+	}
+	if user != "" {
+		v.UserId = ptr(user)
+	}
+	return v
+}
+
+var testTorrons = []*domain.Torro{
+	{Id: "a", Name: "Alacant", Class: "1", Rating: 1490},
+	{Id: "b", Name: "Xixona", Class: "1", Rating: 1510},
+	{Id: "c", Name: "Gema", Class: "2", Rating: 1500},
+}
+
+// TestRunMatchesSequentialElo checks a replay through Elo lands exactly
+// where applying the same votes one by one with UpdateRatings does, and
+// that the rewritten before/after columns chain from one vote to the next.
+func TestRunMatchesSequentialElo(t *testing.T) {
+	votes := []*domain.ReplayVote{
+		vote("r1", "a", "b", ptr("a"), "u1", 0),
+		vote("r2", "a", "b", ptr("b"), "u2", 1),
+		vote("r3", "b", "a", ptr("b"), "u1", 2),
+	}
+
+	got := Run(rating.NewElo(rating.DefaultEloK), testTorrons, votes, Options{})
+
+	a, b := 1500.0, 1500.0
+	a, b = rating.UpdateRatings(a, b, true, rating.DefaultEloK)
+	a, b = rating.UpdateRatings(a, b, false, rating.DefaultEloK)
+	b, a = rating.UpdateRatings(b, a, true, rating.DefaultEloK)
+
+	if math.Abs(got.Torrons["a"].Rating-a) > 1e-9 || math.Abs(got.Torrons["b"].Rating-b) > 1e-9 {
+		t.Errorf("final ratings = (%v, %v), want (%v, %v)", got.Torrons["a"].Rating, got.Torrons["b"].Rating, a, b)
+	}
+	if got.Torrons["c"].Rating != domain.DefaultRating {
+		t.Errorf("unvoted torró rating = %v, want the initial %v", got.Torrons["c"].Rating, domain.DefaultRating)
+	}
+	if got.Applied != 3 || got.Skipped != 0 {
+		t.Errorf("applied/skipped = %d/%d, want 3/0", got.Applied, got.Skipped)
+	}
+
+	// r3 has the torrons the other way round: its "before" must pick up
+	// where r2 left b and a.
+	if got.Results[2].Rat1Bef != got.Results[1].Rat2Aft || got.Results[2].Rat2Bef != got.Results[1].Rat1Aft {
+		t.Errorf("r3 before = (%v, %v) doesn't chain from r2 after = (%v, %v)",
+			got.Results[2].Rat1Bef, got.Results[2].Rat2Bef, got.Results[1].Rat1Aft, got.Results[1].Rat2Aft)
+	}
+
+	// u1 voted twice on a/b, u2 once: three users-torró pairs each.
+	counts := map[string]int{}
+	for _, s := range got.Snapshots {
+		counts[s.UserId+"/"+s.TorronId] = s.VoteCount
+	}
+	want := map[string]int{"u1/a": 2, "u1/b": 2, "u2/a": 1, "u2/b": 1}
+	for k, n := range want {
+		if counts[k] != n {
+			t.Errorf("snapshot %s vote count = %d, want %d", k, counts[k], n)
+		}
+	}
+}
+
+func TestRunExclusions(t *testing.T) {
+	campaignVote := vote("r2", "a", "b", ptr("a"), "u2", 1)
+	campaignVote.CampaignId = ptr("c2024")
+	votes := []*domain.ReplayVote{
+		vote("r1", "a", "b", ptr("a"), "fraud", 0),
+		campaignVote,
+		vote("r3", "a", "b", nil, "u3", 2),
+	}
+
+	got := Run(rating.NewElo(rating.DefaultEloK), testTorrons, votes, Options{
+		ExcludeUsers:    map[string]bool{"fraud": true},
+		ExcludeCampaign: "c2024",
+	})
+
+	if got.Applied != 1 || got.Skipped != 2 {
+		t.Fatalf("applied/skipped = %d/%d, want 1/2", got.Applied, got.Skipped)
+	}
+	// Only the draw between two 1500s was applied, so nothing moved.
+	if got.Torrons["a"].Rating != 1500 || got.Torrons["b"].Rating != 1500 {
+		t.Errorf("ratings moved: a=%v b=%v", got.Torrons["a"].Rating, got.Torrons["b"].Rating)
+	}
+	for _, res := range got.Results[:2] {
+		if res.Rat1Bef != res.Rat1Aft || res.Rat2Bef != res.Rat2Aft {
+			t.Errorf("excluded result %s changed ratings: %+v", res.ResultId, res)
+		}
+	}
+	for _, s := range got.Snapshots {
+		if s.UserId == "fraud" || s.UserId == "u2" {
+			t.Errorf("excluded vote produced a snapshot for %s", s.UserId)
+		}
+	}
+}
+
+// TestRunQuarantined checks a quarantined user's vote leaves the global
+// ratings alone but still builds that user's snapshots.
+func TestRunQuarantined(t *testing.T) {
+	quarantined := vote("r1", "a", "b", ptr("a"), "bot", 0)
+	quarantined.Quarantined = true
+	votes := []*domain.ReplayVote{quarantined, vote("r2", "a", "b", ptr("b"), "u1", 1)}
+
+	got := Run(rating.NewElo(rating.DefaultEloK), testTorrons, votes, Options{})
+
+	if got.Applied != 1 || got.Quarantined != 1 || got.Skipped != 0 {
+		t.Fatalf("applied/quarantined/skipped = %d/%d/%d, want 1/1/0", got.Applied, got.Quarantined, got.Skipped)
+	}
+	if res := got.Results[0]; res.Rat1Bef != res.Rat1Aft || res.Rat2Bef != res.Rat2Aft {
+		t.Errorf("quarantined result changed global ratings: %+v", res)
+	}
+
+	wantB, wantA := rating.UpdateRatings(1500, 1500, true, rating.DefaultEloK)
+	if got.Torrons["a"].Rating != wantA || got.Torrons["b"].Rating != wantB {
+		t.Errorf("final ratings = (%v, %v), want only u1's vote applied: (%v, %v)",
+			got.Torrons["a"].Rating, got.Torrons["b"].Rating, wantA, wantB)
+	}
+
+	var botSnapshots int
+	for _, s := range got.Snapshots {
+		if s.UserId == "bot" {
+			botSnapshots++
+			if s.VoteCount != 1 || s.Rating == domain.DefaultRating {
+				t.Errorf("bot snapshot of %s = %+v, want one vote applied", s.TorronId, s)
+			}
+		}
+	}
+	if botSnapshots != 2 {
+		t.Errorf("bot has %d snapshots, want 2", botSnapshots)
+	}
+}
+
+// TestRunWeighted checks a vote cast with a trust weight moves the global
+// ratings exactly as Elo with K scaled by that weight, while the voter's
+// snapshots take the full update.
+func TestRunWeighted(t *testing.T) {
+	weighted := vote("r1", "a", "b", ptr("a"), "newcomer", 0)
+	weighted.Weight = 0.25
+
+	got := Run(rating.NewElo(rating.DefaultEloK), testTorrons, []*domain.ReplayVote{weighted}, Options{})
+
+	wantA, wantB := rating.UpdateRatings(1500, 1500, true, rating.DefaultEloK*0.25)
+	if math.Abs(got.Torrons["a"].Rating-wantA) > 1e-9 || math.Abs(got.Torrons["b"].Rating-wantB) > 1e-9 {
+		t.Errorf("final ratings = (%v, %v), want (%v, %v)",
+			got.Torrons["a"].Rating, got.Torrons["b"].Rating, wantA, wantB)
+	}
+
+	fullA, _ := rating.UpdateRatings(1500, 1500, true, rating.DefaultEloK)
+	for _, s := range got.Snapshots {
+		if s.TorronId == "a" && math.Abs(s.Rating-fullA) > 1e-9 {
+			t.Errorf("snapshot of a = %v, want the full update to %v", s.Rating, fullA)
+		}
+	}
+}
+
+func TestRankDiff(t *testing.T) {
+	replay := &domain.Replay{Torrons: map[string]domain.RatingState{
+		"a": {Rating: 1600},
+		"b": {Rating: 1400},
+		"c": {Rating: 1500},
+	}}
+
+	diff := RankDiff(testTorrons, replay)
+	if len(diff) != 3 {
+		t.Fatalf("len(diff) = %d, want 3", len(diff))
+	}
+
+	// Class 1: live order is b, a; replayed order is a, b.
+	if diff[0].TorroId != "a" || diff[0].NewRank != 1 || diff[0].LiveRank != 2 || diff[0].Moved() != 1 {
+		t.Errorf("diff[0] = %+v, want a climbing from 2 to 1", diff[0])
+	}
+	if diff[1].TorroId != "b" || diff[1].Moved() != -1 {
+		t.Errorf("diff[1] = %+v, want b falling from 1 to 2", diff[1])
+	}
+	if diff[2].Class != "2" || diff[2].Moved() != 0 {
+		t.Errorf("diff[2] = %+v, want c alone in class 2", diff[2])
+	}
+}
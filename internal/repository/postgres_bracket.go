@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -32,21 +33,41 @@ func (r *postgresBracketRepo) Create(ctx context.Context, bracket *domain.Bracke
 	if bracket.Status == "" {
 		bracket.Status = domain.BracketStatusInProgress
 	}
+	if bracket.Kind == "" {
+		bracket.Kind = domain.BracketKindClass
+	}
+	if bracket.Format == "" {
+		bracket.Format = domain.BracketFormatSingleElimination
+	}
 	if bracket.CreatedAt == "" {
 		bracket.CreatedAt = time.Now().UTC().Format(time.RFC3339)
 	}
+	if bracket.Seeding.Strategy == "" {
+		bracket.Seeding.Strategy = domain.BracketSeedingRating
+	}
+	inputs, err := json.Marshal(bracket.Seeding.BracketSeedingInputs)
+	if err != nil {
+		return nil, err
+	}
 
-	err := r.db.QueryRowContext(ctx,
-		`INSERT INTO "Brackets" ("Id", "CampaignId", "ClassId", "Size", "CurrentRound", "Status", "CreatedAt")
-		 VALUES ($1, $2, $3, $4, $5, $6, $7)
+	err = r.db.QueryRowContext(ctx,
+		`INSERT INTO "Brackets" ("Id", "CampaignId", "ClassId", "Kind", "Size", "Format", "GrandFinalReset", "Rounds", "CurrentRound", "Status", "CreatedAt",
+		                         "SeedingStrategy", "SeedingInputs")
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 		 RETURNING "Id"`,
 		bracket.Id,
 		bracket.CampaignId,
 		bracket.ClassId,
+		bracket.Kind,
 		bracket.Size,
+		bracket.Format,
+		bracket.GrandFinalReset,
+		bracket.Rounds,
 		bracket.CurrentRound,
 		bracket.Status,
 		bracket.CreatedAt,
+		bracket.Seeding.Strategy,
+		inputs,
 	).Scan(&bracket.Id)
 	if err != nil {
 		return nil, handleErrors(err)
@@ -57,7 +78,7 @@ func (r *postgresBracketRepo) Create(ctx context.Context, bracket *domain.Bracke
 
 func (r *postgresBracketRepo) Get(ctx context.Context, id string) (*domain.Bracket, error) {
 	row := r.db.QueryRowContext(ctx,
-		`SELECT "Id", "CampaignId", "ClassId", "Size", "CurrentRound", "Status", "ChampionId", "CreatedAt", "CompletedAt"
+		`SELECT "Id", "CampaignId", "ClassId", "Kind", "Size", "Format", "GrandFinalReset", "Rounds", "CurrentRound", "Status", "ChampionId", "CreatedAt", "CompletedAt", "SeedingStrategy", "SeedingInputs"
 		 FROM "Brackets"
 		 WHERE "Id" = $1`,
 		id,
@@ -67,7 +88,7 @@ func (r *postgresBracketRepo) Get(ctx context.Context, id string) (*domain.Brack
 
 func (r *postgresBracketRepo) GetByCampaignAndClass(ctx context.Context, campaignId string, classId string) (*domain.Bracket, error) {
 	row := r.db.QueryRowContext(ctx,
-		`SELECT "Id", "CampaignId", "ClassId", "Size", "CurrentRound", "Status", "ChampionId", "CreatedAt", "CompletedAt"
+		`SELECT "Id", "CampaignId", "ClassId", "Kind", "Size", "Format", "GrandFinalReset", "Rounds", "CurrentRound", "Status", "ChampionId", "CreatedAt", "CompletedAt", "SeedingStrategy", "SeedingInputs"
 		 FROM "Brackets"
 		 WHERE "CampaignId" = $1 AND "ClassId" = $2`,
 		campaignId,
@@ -78,7 +99,7 @@ func (r *postgresBracketRepo) GetByCampaignAndClass(ctx context.Context, campaig
 
 func (r *postgresBracketRepo) GetLatestByClass(ctx context.Context, classId string) (*domain.Bracket, error) {
 	row := r.db.QueryRowContext(ctx,
-		`SELECT "Id", "CampaignId", "ClassId", "Size", "CurrentRound", "Status", "ChampionId", "CreatedAt", "CompletedAt"
+		`SELECT "Id", "CampaignId", "ClassId", "Kind", "Size", "Format", "GrandFinalReset", "Rounds", "CurrentRound", "Status", "ChampionId", "CreatedAt", "CompletedAt", "SeedingStrategy", "SeedingInputs"
 		 FROM "Brackets"
 		 WHERE "ClassId" = $1
 		 ORDER BY "CreatedAt" DESC
@@ -88,6 +109,18 @@ func (r *postgresBracketRepo) GetLatestByClass(ctx context.Context, classId stri
 	return scanBracket(row)
 }
 
+func (r *postgresBracketRepo) GetLatestGranFinal(ctx context.Context) (*domain.Bracket, error) {
+	row := r.db.QueryRowContext(ctx,
+		`SELECT "Id", "CampaignId", "ClassId", "Kind", "Size", "Format", "GrandFinalReset", "Rounds", "CurrentRound", "Status", "ChampionId", "CreatedAt", "CompletedAt", "SeedingStrategy", "SeedingInputs"
+		 FROM "Brackets"
+		 WHERE "Kind" = $1
+		 ORDER BY "CreatedAt" DESC
+		 LIMIT 1`,
+		domain.BracketKindGranFinal,
+	)
+	return scanBracket(row)
+}
+
 func (r *postgresBracketRepo) UpdateRound(ctx context.Context, id string, round int) error {
 	_, err := r.db.ExecContext(ctx,
 		`UPDATE "Brackets" SET "CurrentRound" = $2 WHERE "Id" = $1`,
@@ -118,14 +151,15 @@ func (r *postgresBracketRepo) CreateEntry(ctx context.Context, entry *domain.Bra
 	}
 
 	err := r.db.QueryRowContext(ctx,
-		`INSERT INTO "BracketEntries" ("Id", "BracketId", "TorronId", "Seed", "SeedRating")
-		 VALUES ($1, $2, $3, $4, $5)
+		`INSERT INTO "BracketEntries" ("Id", "BracketId", "TorronId", "Seed", "SeedRating", "GroupId")
+		 VALUES ($1, $2, $3, $4, $5, $6)
 		 RETURNING "Id"`,
 		entry.Id,
 		entry.BracketId,
 		entry.TorronId,
 		entry.Seed,
 		entry.SeedRating,
+		entry.GroupId,
 	).Scan(&entry.Id)
 	if err != nil {
 		return nil, handleErrors(err)
@@ -136,7 +170,7 @@ func (r *postgresBracketRepo) CreateEntry(ctx context.Context, entry *domain.Bra
 
 func (r *postgresBracketRepo) ListEntries(ctx context.Context, bracketId string) ([]*domain.BracketEntry, error) {
 	rows, err := r.db.QueryContext(ctx,
-		`SELECT "Id", "BracketId", "TorronId", "Seed", "SeedRating"
+		`SELECT "Id", "BracketId", "TorronId", "Seed", "SeedRating", "GroupId"
 		 FROM "BracketEntries"
 		 WHERE "BracketId" = $1
 		 ORDER BY "Seed" ASC`,
@@ -150,7 +184,114 @@ func (r *postgresBracketRepo) ListEntries(ctx context.Context, bracketId string)
 	return scanBracketEntries(rows)
 }
 
+// -- Bracket rounds --
+
+func (r *postgresBracketRepo) ListRounds(ctx context.Context, bracketId string) ([]*domain.BracketRound, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT "BracketId", "Round", "OpensAt", "ClosesAt"
+		 FROM "BracketRounds"
+		 WHERE "BracketId" = $1
+		 ORDER BY "Round" ASC`,
+		bracketId,
+	)
+	if err != nil {
+		return nil, handleErrors(err)
+	}
+	defer rows.Close()
+
+	var rounds []*domain.BracketRound
+	for rows.Next() {
+		round, err := scanBracketRound(rows)
+		if err != nil {
+			return nil, err
+		}
+		rounds = append(rounds, round)
+	}
+
+	return rounds, nil
+}
+
+func (r *postgresBracketRepo) GetRound(ctx context.Context, bracketId string, round int) (*domain.BracketRound, error) {
+	row := r.db.QueryRowContext(ctx,
+		`SELECT "BracketId", "Round", "OpensAt", "ClosesAt"
+		 FROM "BracketRounds"
+		 WHERE "BracketId" = $1 AND "Round" = $2`,
+		bracketId,
+		round,
+	)
+	return scanBracketRound(row)
+}
+
+func (r *postgresBracketRepo) ListOverdueBrackets(ctx context.Context, now string) ([]*domain.Bracket, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT b."Id", b."CampaignId", b."ClassId", b."Kind", b."Size", b."Format", b."GrandFinalReset", b."Rounds", b."CurrentRound", b."Status", b."ChampionId", b."CreatedAt", b."CompletedAt", b."SeedingStrategy", b."SeedingInputs"
+		 FROM "Brackets" b
+		 JOIN "BracketRounds" br ON br."BracketId" = b."Id" AND br."Round" = b."CurrentRound"
+		 WHERE b."Status" = $1 AND br."ClosesAt" <= $2
+		 ORDER BY br."ClosesAt" ASC`,
+		domain.BracketStatusInProgress,
+		now,
+	)
+	if err != nil {
+		return nil, handleErrors(err)
+	}
+	defer rows.Close()
+
+	var brackets []*domain.Bracket
+	for rows.Next() {
+		bracket, err := scanBracket(rows)
+		if err != nil {
+			return nil, err
+		}
+		brackets = append(brackets, bracket)
+	}
+
+	return brackets, nil
+}
+
+// -- Bracket groups --
+
+func (r *postgresBracketRepo) CreateGroup(ctx context.Context, group *domain.BracketGroup) (*domain.BracketGroup, error) {
+	if group.Id == "" {
+		group.Id = uuid.NewString()
+	}
+
+	err := r.db.QueryRowContext(ctx,
+		`INSERT INTO "BracketGroups" ("Id", "BracketId", "Position", "Name")
+		 VALUES ($1, $2, $3, $4)
+		 RETURNING "Id"`,
+		group.Id,
+		group.BracketId,
+		group.Position,
+		group.Name,
+	).Scan(&group.Id)
+	if err != nil {
+		return nil, handleErrors(err)
+	}
+
+	return group, nil
+}
+
+func (r *postgresBracketRepo) ListGroups(ctx context.Context, bracketId string) ([]*domain.BracketGroup, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT "Id", "BracketId", "Position", "Name"
+		 FROM "BracketGroups"
+		 WHERE "BracketId" = $1
+		 ORDER BY "Position" ASC`,
+		bracketId,
+	)
+	if err != nil {
+		return nil, handleErrors(err)
+	}
+	defer rows.Close()
+
+	return scanBracketGroups(rows)
+}
+
 // -- Bracket matches --
+//
+// Within a round, matches are ordered "Side" DESC: winners, losers, then
+// the grand final, which is how a double-elimination round reads.
 
 func (r *postgresBracketRepo) CreateMatch(ctx context.Context, match *domain.BracketMatch) (*domain.BracketMatch, error) {
 	if match.Id == "" {
@@ -159,17 +300,22 @@ func (r *postgresBracketRepo) CreateMatch(ctx context.Context, match *domain.Bra
 	if match.Status == "" {
 		match.Status = domain.BracketMatchStatusPending
 	}
+	if match.Side == "" {
+		match.Side = domain.BracketSideWinners
+	}
 	if match.CreatedAt == "" {
 		match.CreatedAt = time.Now().UTC().Format(time.RFC3339)
 	}
 
 	err := r.db.QueryRowContext(ctx,
-		`INSERT INTO "BracketMatches" ("Id", "BracketId", "Round", "Slot", "Torro1Id", "Torro2Id", "WinnerId", "Status", "CreatedAt")
-		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`INSERT INTO "BracketMatches" ("Id", "BracketId", "Round", "Side", "GroupId", "Slot", "Torro1Id", "Torro2Id", "WinnerId", "Status", "CreatedAt")
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 		 RETURNING "Id"`,
 		match.Id,
 		match.BracketId,
 		match.Round,
+		match.Side,
+		match.GroupId,
 		match.Slot,
 		match.Torro1Id,
 		match.Torro2Id,
@@ -186,7 +332,7 @@ func (r *postgresBracketRepo) CreateMatch(ctx context.Context, match *domain.Bra
 
 func (r *postgresBracketRepo) GetMatch(ctx context.Context, id string) (*domain.BracketMatch, error) {
 	row := r.db.QueryRowContext(ctx,
-		`SELECT "Id", "BracketId", "Round", "Slot", "Torro1Id", "Torro2Id", "WinnerId", "Status", "CreatedAt"
+		`SELECT "Id", "BracketId", "Round", "Side", "GroupId", "Slot", "Torro1Id", "Torro2Id", "WinnerId", "Status", "CreatedAt"
 		 FROM "BracketMatches"
 		 WHERE "Id" = $1`,
 		id,
@@ -196,10 +342,10 @@ func (r *postgresBracketRepo) GetMatch(ctx context.Context, id string) (*domain.
 
 func (r *postgresBracketRepo) ListMatchesByRound(ctx context.Context, bracketId string, round int) ([]*domain.BracketMatch, error) {
 	rows, err := r.db.QueryContext(ctx,
-		`SELECT "Id", "BracketId", "Round", "Slot", "Torro1Id", "Torro2Id", "WinnerId", "Status", "CreatedAt"
+		`SELECT "Id", "BracketId", "Round", "Side", "GroupId", "Slot", "Torro1Id", "Torro2Id", "WinnerId", "Status", "CreatedAt"
 		 FROM "BracketMatches"
 		 WHERE "BracketId" = $1 AND "Round" = $2
-		 ORDER BY "Slot" ASC`,
+		 ORDER BY "Side" DESC, "Slot" ASC`,
 		bracketId,
 		round,
 	)
@@ -213,10 +359,10 @@ func (r *postgresBracketRepo) ListMatchesByRound(ctx context.Context, bracketId
 
 func (r *postgresBracketRepo) ListMatches(ctx context.Context, bracketId string) ([]*domain.BracketMatch, error) {
 	rows, err := r.db.QueryContext(ctx,
-		`SELECT "Id", "BracketId", "Round", "Slot", "Torro1Id", "Torro2Id", "WinnerId", "Status", "CreatedAt"
+		`SELECT "Id", "BracketId", "Round", "Side", "GroupId", "Slot", "Torro1Id", "Torro2Id", "WinnerId", "Status", "CreatedAt"
 		 FROM "BracketMatches"
 		 WHERE "BracketId" = $1
-		 ORDER BY "Round" ASC, "Slot" ASC`,
+		 ORDER BY "Round" ASC, "Side" DESC, "Slot" ASC`,
 		bracketId,
 	)
 	if err != nil {
@@ -229,7 +375,7 @@ func (r *postgresBracketRepo) ListMatches(ctx context.Context, bracketId string)
 
 func (r *postgresBracketRepo) ListOpenMatchesForUser(ctx context.Context, bracketId string, round int, userId string) ([]*domain.BracketMatch, error) {
 	rows, err := r.db.QueryContext(ctx,
-		`SELECT m."Id", m."BracketId", m."Round", m."Slot", m."Torro1Id", m."Torro2Id", m."WinnerId", m."Status", m."CreatedAt"
+		`SELECT m."Id", m."BracketId", m."Round", m."Side", m."GroupId", m."Slot", m."Torro1Id", m."Torro2Id", m."WinnerId", m."Status", m."CreatedAt"
 		 FROM "BracketMatches" m
 		 WHERE m."BracketId" = $1
 		   AND m."Round" = $2
@@ -238,7 +384,7 @@ func (r *postgresBracketRepo) ListOpenMatchesForUser(ctx context.Context, bracke
 		       SELECT 1 FROM "BracketMatchVotes" v
 		       WHERE v."MatchId" = m."Id" AND v."UserId" = $4
 		   )
-		 ORDER BY m."Slot" ASC`,
+		 ORDER BY m."Side" DESC, m."Slot" ASC`,
 		bracketId,
 		round,
 		domain.BracketMatchStatusPending,
@@ -323,6 +469,39 @@ func (r *postgresBracketRepo) CountVotesByTorron(ctx context.Context, matchId st
 	return scanVoteCounts(rows)
 }
 
+func (r *postgresBracketRepo) CountVotesByBracket(ctx context.Context, bracketId string) (map[string]map[string]int, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT v."MatchId", v."TorronId", COUNT(*)
+		 FROM "BracketMatchVotes" v
+		 JOIN "BracketMatches" m ON m."Id" = v."MatchId"
+		 WHERE m."BracketId" = $1
+		 GROUP BY v."MatchId", v."TorronId"`,
+		bracketId,
+	)
+	if err != nil {
+		return nil, handleErrors(err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]map[string]int)
+	for rows.Next() {
+		var matchId, torronId string
+		var count int
+		if err := rows.Scan(&matchId, &torronId, &count); err != nil {
+			return nil, handleErrors(err)
+		}
+		if counts[matchId] == nil {
+			counts[matchId] = make(map[string]int)
+		}
+		counts[matchId][torronId] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, handleErrors(err)
+	}
+
+	return counts, nil
+}
+
 // -- Transaction methods --
 
 func (r *postgresBracketRepo) CreateTx(tx *sql.Tx, ctx context.Context, bracket *domain.Bracket) (*domain.Bracket, error) {
@@ -335,21 +514,41 @@ func (r *postgresBracketRepo) CreateTx(tx *sql.Tx, ctx context.Context, bracket
 	if bracket.Status == "" {
 		bracket.Status = domain.BracketStatusInProgress
 	}
+	if bracket.Kind == "" {
+		bracket.Kind = domain.BracketKindClass
+	}
+	if bracket.Format == "" {
+		bracket.Format = domain.BracketFormatSingleElimination
+	}
 	if bracket.CreatedAt == "" {
 		bracket.CreatedAt = time.Now().UTC().Format(time.RFC3339)
 	}
+	if bracket.Seeding.Strategy == "" {
+		bracket.Seeding.Strategy = domain.BracketSeedingRating
+	}
+	inputs, err := json.Marshal(bracket.Seeding.BracketSeedingInputs)
+	if err != nil {
+		return nil, err
+	}
 
-	err := tx.QueryRowContext(ctx,
-		`INSERT INTO "Brackets" ("Id", "CampaignId", "ClassId", "Size", "CurrentRound", "Status", "CreatedAt")
-		 VALUES ($1, $2, $3, $4, $5, $6, $7)
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO "Brackets" ("Id", "CampaignId", "ClassId", "Kind", "Size", "Format", "GrandFinalReset", "Rounds", "CurrentRound", "Status", "CreatedAt",
+		                         "SeedingStrategy", "SeedingInputs")
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 		 RETURNING "Id"`,
 		bracket.Id,
 		bracket.CampaignId,
 		bracket.ClassId,
+		bracket.Kind,
 		bracket.Size,
+		bracket.Format,
+		bracket.GrandFinalReset,
+		bracket.Rounds,
 		bracket.CurrentRound,
 		bracket.Status,
 		bracket.CreatedAt,
+		bracket.Seeding.Strategy,
+		inputs,
 	).Scan(&bracket.Id)
 	if err != nil {
 		return nil, handleErrors(err)
@@ -369,7 +568,7 @@ func (r *postgresBracketRepo) CreateTx(tx *sql.Tx, ctx context.Context, bracket
 // drop one voter's vote behind a duplicate-key 500 - see cascadeAdvance).
 func (r *postgresBracketRepo) GetTx(tx *sql.Tx, ctx context.Context, id string) (*domain.Bracket, error) {
 	row := tx.QueryRowContext(ctx,
-		`SELECT "Id", "CampaignId", "ClassId", "Size", "CurrentRound", "Status", "ChampionId", "CreatedAt", "CompletedAt"
+		`SELECT "Id", "CampaignId", "ClassId", "Kind", "Size", "Format", "GrandFinalReset", "Rounds", "CurrentRound", "Status", "ChampionId", "CreatedAt", "CompletedAt", "SeedingStrategy", "SeedingInputs"
 		 FROM "Brackets"
 		 WHERE "Id" = $1
 		 FOR UPDATE`,
@@ -400,20 +599,74 @@ func (r *postgresBracketRepo) CompleteTx(tx *sql.Tx, ctx context.Context, id str
 	return handleErrors(err)
 }
 
+func (r *postgresBracketRepo) CreateRoundTx(tx *sql.Tx, ctx context.Context, round *domain.BracketRound) (*domain.BracketRound, error) {
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO "BracketRounds" ("BracketId", "Round", "OpensAt", "ClosesAt")
+		 VALUES ($1, $2, $3, $4)`,
+		round.BracketId,
+		round.Round,
+		round.OpensAt,
+		round.ClosesAt,
+	)
+	if err != nil {
+		return nil, handleErrors(err)
+	}
+
+	return round, nil
+}
+
+func (r *postgresBracketRepo) CreateGroupTx(tx *sql.Tx, ctx context.Context, group *domain.BracketGroup) (*domain.BracketGroup, error) {
+	if group.Id == "" {
+		group.Id = uuid.NewString()
+	}
+
+	err := tx.QueryRowContext(ctx,
+		`INSERT INTO "BracketGroups" ("Id", "BracketId", "Position", "Name")
+		 VALUES ($1, $2, $3, $4)
+		 RETURNING "Id"`,
+		group.Id,
+		group.BracketId,
+		group.Position,
+		group.Name,
+	).Scan(&group.Id)
+	if err != nil {
+		return nil, handleErrors(err)
+	}
+
+	return group, nil
+}
+
+func (r *postgresBracketRepo) ListGroupsTx(tx *sql.Tx, ctx context.Context, bracketId string) ([]*domain.BracketGroup, error) {
+	rows, err := tx.QueryContext(ctx,
+		`SELECT "Id", "BracketId", "Position", "Name"
+		 FROM "BracketGroups"
+		 WHERE "BracketId" = $1
+		 ORDER BY "Position" ASC`,
+		bracketId,
+	)
+	if err != nil {
+		return nil, handleErrors(err)
+	}
+	defer rows.Close()
+
+	return scanBracketGroups(rows)
+}
+
 func (r *postgresBracketRepo) CreateEntryTx(tx *sql.Tx, ctx context.Context, entry *domain.BracketEntry) (*domain.BracketEntry, error) {
 	if entry.Id == "" {
 		entry.Id = uuid.NewString()
 	}
 
 	err := tx.QueryRowContext(ctx,
-		`INSERT INTO "BracketEntries" ("Id", "BracketId", "TorronId", "Seed", "SeedRating")
-		 VALUES ($1, $2, $3, $4, $5)
+		`INSERT INTO "BracketEntries" ("Id", "BracketId", "TorronId", "Seed", "SeedRating", "GroupId")
+		 VALUES ($1, $2, $3, $4, $5, $6)
 		 RETURNING "Id"`,
 		entry.Id,
 		entry.BracketId,
 		entry.TorronId,
 		entry.Seed,
 		entry.SeedRating,
+		entry.GroupId,
 	).Scan(&entry.Id)
 	if err != nil {
 		return nil, handleErrors(err)
@@ -424,7 +677,7 @@ func (r *postgresBracketRepo) CreateEntryTx(tx *sql.Tx, ctx context.Context, ent
 
 func (r *postgresBracketRepo) ListEntriesTx(tx *sql.Tx, ctx context.Context, bracketId string) ([]*domain.BracketEntry, error) {
 	rows, err := tx.QueryContext(ctx,
-		`SELECT "Id", "BracketId", "TorronId", "Seed", "SeedRating"
+		`SELECT "Id", "BracketId", "TorronId", "Seed", "SeedRating", "GroupId"
 		 FROM "BracketEntries"
 		 WHERE "BracketId" = $1
 		 ORDER BY "Seed" ASC`,
@@ -445,17 +698,22 @@ func (r *postgresBracketRepo) CreateMatchTx(tx *sql.Tx, ctx context.Context, mat
 	if match.Status == "" {
 		match.Status = domain.BracketMatchStatusPending
 	}
+	if match.Side == "" {
+		match.Side = domain.BracketSideWinners
+	}
 	if match.CreatedAt == "" {
 		match.CreatedAt = time.Now().UTC().Format(time.RFC3339)
 	}
 
 	err := tx.QueryRowContext(ctx,
-		`INSERT INTO "BracketMatches" ("Id", "BracketId", "Round", "Slot", "Torro1Id", "Torro2Id", "WinnerId", "Status", "CreatedAt")
-		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`INSERT INTO "BracketMatches" ("Id", "BracketId", "Round", "Side", "GroupId", "Slot", "Torro1Id", "Torro2Id", "WinnerId", "Status", "CreatedAt")
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 		 RETURNING "Id"`,
 		match.Id,
 		match.BracketId,
 		match.Round,
+		match.Side,
+		match.GroupId,
 		match.Slot,
 		match.Torro1Id,
 		match.Torro2Id,
@@ -472,7 +730,7 @@ func (r *postgresBracketRepo) CreateMatchTx(tx *sql.Tx, ctx context.Context, mat
 
 func (r *postgresBracketRepo) GetMatchTx(tx *sql.Tx, ctx context.Context, id string) (*domain.BracketMatch, error) {
 	row := tx.QueryRowContext(ctx,
-		`SELECT "Id", "BracketId", "Round", "Slot", "Torro1Id", "Torro2Id", "WinnerId", "Status", "CreatedAt"
+		`SELECT "Id", "BracketId", "Round", "Side", "GroupId", "Slot", "Torro1Id", "Torro2Id", "WinnerId", "Status", "CreatedAt"
 		 FROM "BracketMatches"
 		 WHERE "Id" = $1`,
 		id,
@@ -482,10 +740,10 @@ func (r *postgresBracketRepo) GetMatchTx(tx *sql.Tx, ctx context.Context, id str
 
 func (r *postgresBracketRepo) ListMatchesByRoundTx(tx *sql.Tx, ctx context.Context, bracketId string, round int) ([]*domain.BracketMatch, error) {
 	rows, err := tx.QueryContext(ctx,
-		`SELECT "Id", "BracketId", "Round", "Slot", "Torro1Id", "Torro2Id", "WinnerId", "Status", "CreatedAt"
+		`SELECT "Id", "BracketId", "Round", "Side", "GroupId", "Slot", "Torro1Id", "Torro2Id", "WinnerId", "Status", "CreatedAt"
 		 FROM "BracketMatches"
 		 WHERE "BracketId" = $1 AND "Round" = $2
-		 ORDER BY "Slot" ASC`,
+		 ORDER BY "Side" DESC, "Slot" ASC`,
 		bracketId,
 		round,
 	)
@@ -497,6 +755,22 @@ func (r *postgresBracketRepo) ListMatchesByRoundTx(tx *sql.Tx, ctx context.Conte
 	return scanBracketMatches(rows)
 }
 
+func (r *postgresBracketRepo) ListMatchesTx(tx *sql.Tx, ctx context.Context, bracketId string) ([]*domain.BracketMatch, error) {
+	rows, err := tx.QueryContext(ctx,
+		`SELECT "Id", "BracketId", "Round", "Side", "GroupId", "Slot", "Torro1Id", "Torro2Id", "WinnerId", "Status", "CreatedAt"
+		 FROM "BracketMatches"
+		 WHERE "BracketId" = $1
+		 ORDER BY "Round" ASC, "Side" DESC, "Slot" ASC`,
+		bracketId,
+	)
+	if err != nil {
+		return nil, handleErrors(err)
+	}
+	defer rows.Close()
+
+	return scanBracketMatches(rows)
+}
+
 func (r *postgresBracketRepo) SetMatchWinnerTx(tx *sql.Tx, ctx context.Context, matchId string, winnerId string) error {
 	_, err := tx.ExecContext(ctx,
 		`UPDATE "BracketMatches"
@@ -561,21 +835,31 @@ func scanBracket(row row) (*domain.Bracket, error) {
 	bracket := &domain.Bracket{}
 	var championId sql.NullString
 	var completedAt sql.NullString
+	var seedingInputs []byte
 
 	err := row.Scan(
 		&bracket.Id,
 		&bracket.CampaignId,
 		&bracket.ClassId,
+		&bracket.Kind,
 		&bracket.Size,
+		&bracket.Format,
+		&bracket.GrandFinalReset,
+		&bracket.Rounds,
 		&bracket.CurrentRound,
 		&bracket.Status,
 		&championId,
 		&bracket.CreatedAt,
 		&completedAt,
+		&bracket.Seeding.Strategy,
+		&seedingInputs,
 	)
 	if err != nil {
 		return nil, handleErrors(err)
 	}
+	if err := json.Unmarshal(seedingInputs, &bracket.Seeding.BracketSeedingInputs); err != nil {
+		return nil, err
+	}
 
 	if championId.Valid {
 		bracket.ChampionId = &championId.String
@@ -591,23 +875,61 @@ func scanBracketEntries(rows *sql.Rows) ([]*domain.BracketEntry, error) {
 	var entries []*domain.BracketEntry
 	for rows.Next() {
 		entry := &domain.BracketEntry{}
+		var groupId sql.NullString
 		if err := rows.Scan(
 			&entry.Id,
 			&entry.BracketId,
 			&entry.TorronId,
 			&entry.Seed,
 			&entry.SeedRating,
+			&groupId,
 		); err != nil {
 			return nil, handleErrors(err)
 		}
+		if groupId.Valid {
+			entry.GroupId = &groupId.String
+		}
 		entries = append(entries, entry)
 	}
 
 	return entries, nil
 }
 
+func scanBracketRound(row row) (*domain.BracketRound, error) {
+	round := &domain.BracketRound{}
+	if err := row.Scan(
+		&round.BracketId,
+		&round.Round,
+		&round.OpensAt,
+		&round.ClosesAt,
+	); err != nil {
+		return nil, handleErrors(err)
+	}
+
+	return round, nil
+}
+
+func scanBracketGroups(rows *sql.Rows) ([]*domain.BracketGroup, error) {
+	var groups []*domain.BracketGroup
+	for rows.Next() {
+		group := &domain.BracketGroup{}
+		if err := rows.Scan(
+			&group.Id,
+			&group.BracketId,
+			&group.Position,
+			&group.Name,
+		); err != nil {
+			return nil, handleErrors(err)
+		}
+		groups = append(groups, group)
+	}
+
+	return groups, nil
+}
+
 func scanBracketMatch(row row) (*domain.BracketMatch, error) {
 	match := &domain.BracketMatch{}
+	var groupId sql.NullString
 	var torro2Id sql.NullString
 	var winnerId sql.NullString
 
@@ -615,6 +937,8 @@ func scanBracketMatch(row row) (*domain.BracketMatch, error) {
 		&match.Id,
 		&match.BracketId,
 		&match.Round,
+		&match.Side,
+		&groupId,
 		&match.Slot,
 		&match.Torro1Id,
 		&torro2Id,
@@ -626,6 +950,9 @@ func scanBracketMatch(row row) (*domain.BracketMatch, error) {
 		return nil, handleErrors(err)
 	}
 
+	if groupId.Valid {
+		match.GroupId = &groupId.String
+	}
 	if torro2Id.Valid {
 		match.Torro2Id = &torro2Id.String
 	}
@@ -0,0 +1,280 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+type postgresBracketPredictionRepo struct {
+	db *sql.DB
+}
+
+func NewBracketPredictionRepo(db *sql.DB) domain.BracketPredictionRepo {
+	return &postgresBracketPredictionRepo{
+		db: db,
+	}
+}
+
+// predictionScoreSelect scores predictions against their bracket's decided
+// winners-side matches, byes left out, a correct pick in round r being
+// worth domain.PredictionPoints(r). Callers add the WHERE on p (the
+// prediction) and u (its user) and group by p."Id", t."Name".
+const predictionScoreSelect = `
+        SELECT p."UserId", p."ChampionId", t."Name", p."UpdatedAt",
+               COALESCE(SUM(1 << (m."Round" - 1)) FILTER (WHERE m."WinnerId" = k."TorronId"), 0) AS "Points",
+               COUNT(m."Id") FILTER (WHERE m."WinnerId" = k."TorronId") AS "Correct",
+               COUNT(m."Id") AS "Decided"
+        FROM "BracketPredictions" p
+        JOIN "Users" u ON u."Id" = p."UserId"
+        JOIN "Torrons" t ON t."Id" = p."ChampionId"
+        LEFT JOIN "BracketPredictionPicks" k ON k."PredictionId" = p."Id"
+        LEFT JOIN "BracketMatches" m
+            ON m."BracketId" = p."BracketId" AND m."Side" = 'winners'
+           AND m."Round" = k."Round" AND m."Slot" = k."Slot"
+           AND m."WinnerId" IS NOT NULL AND m."Torro2Id" IS NOT NULL`
+
+func (r *postgresBracketPredictionRepo) Save(
+	ctx context.Context,
+	prediction *domain.BracketPrediction,
+	picks []*domain.BracketPredictionPick,
+) (*domain.BracketPrediction, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, handleErrors(err)
+	}
+	defer tx.Rollback()
+
+	// The handler checks the lock before it gets here; re-check it under
+	// the bracket's row lock, so a round 1 opening (or the bracket
+	// advancing) in between can't slip a late prediction in.
+	if err := predictionOpenTx(ctx, tx, prediction.BracketId); err != nil {
+		return nil, err
+	}
+
+	if prediction.Id == "" {
+		prediction.Id = uuid.NewString()
+	}
+
+	// A resubmission keeps its row (and Id), so the old picks go below.
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO "BracketPredictions" ("Id", "BracketId", "UserId", "ChampionId")
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT ("BracketId", "UserId") DO UPDATE
+		 SET "ChampionId" = EXCLUDED."ChampionId", "UpdatedAt" = NOW()
+		 RETURNING "Id", "CreatedAt", "UpdatedAt"`,
+		prediction.Id,
+		prediction.BracketId,
+		prediction.UserId,
+		prediction.ChampionId,
+	).Scan(&prediction.Id, &prediction.CreatedAt, &prediction.UpdatedAt)
+	if err != nil {
+		return nil, handleErrors(err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM "BracketPredictionPicks" WHERE "PredictionId" = $1`,
+		prediction.Id,
+	); err != nil {
+		return nil, handleErrors(err)
+	}
+
+	for _, pick := range picks {
+		pick.PredictionId = prediction.Id
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO "BracketPredictionPicks" ("PredictionId", "Round", "Slot", "TorronId")
+			 VALUES ($1, $2, $3, $4)`,
+			pick.PredictionId,
+			pick.Round,
+			pick.Slot,
+			pick.TorronId,
+		); err != nil {
+			return nil, handleErrors(err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, handleErrors(err)
+	}
+
+	return prediction, nil
+}
+
+// predictionOpenTx locks a bracket's row and refuses with a validation error
+// once its predictions are locked: round 1 has opened (at the bracket's
+// creation when it has no BracketRounds row for it), the bracket has moved
+// past round 1, or it is completed.
+func predictionOpenTx(ctx context.Context, tx *sql.Tx, bracketId string) error {
+	var currentRound int
+	var status string
+	var createdAt time.Time
+	err := tx.QueryRowContext(ctx,
+		`SELECT "CurrentRound", "Status", "CreatedAt"
+		 FROM "Brackets"
+		 WHERE "Id" = $1
+		 FOR UPDATE`,
+		bracketId,
+	).Scan(&currentRound, &status, &createdAt)
+	if err != nil {
+		return handleErrors(err)
+	}
+
+	locksAt := createdAt
+	err = tx.QueryRowContext(ctx,
+		`SELECT "OpensAt" FROM "BracketRounds" WHERE "BracketId" = $1 AND "Round" = 1`,
+		bracketId,
+	).Scan(&locksAt)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return handleErrors(err)
+	}
+
+	if currentRound != 1 || status != domain.BracketStatusInProgress || !time.Now().UTC().Before(locksAt) {
+		return fmt.Errorf("%s: predictions locked when round 1 opened", domain.ValidationError)
+	}
+	return nil
+}
+
+func (r *postgresBracketPredictionRepo) GetForUser(
+	ctx context.Context,
+	bracketId string,
+	userId string,
+) (*domain.BracketPrediction, []*domain.BracketPredictionPick, error) {
+	prediction := &domain.BracketPrediction{}
+	err := r.db.QueryRowContext(ctx,
+		`SELECT "Id", "BracketId", "UserId", "ChampionId", "CreatedAt", "UpdatedAt"
+		 FROM "BracketPredictions"
+		 WHERE "BracketId" = $1 AND "UserId" = $2`,
+		bracketId,
+		userId,
+	).Scan(
+		&prediction.Id,
+		&prediction.BracketId,
+		&prediction.UserId,
+		&prediction.ChampionId,
+		&prediction.CreatedAt,
+		&prediction.UpdatedAt,
+	)
+	if err != nil {
+		return nil, nil, handleErrors(err)
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT "PredictionId", "Round", "Slot", "TorronId"
+		 FROM "BracketPredictionPicks"
+		 WHERE "PredictionId" = $1
+		 ORDER BY "Round" ASC, "Slot" ASC`,
+		prediction.Id,
+	)
+	if err != nil {
+		return nil, nil, handleErrors(err)
+	}
+	defer rows.Close()
+
+	var picks []*domain.BracketPredictionPick
+	for rows.Next() {
+		pick := &domain.BracketPredictionPick{}
+		if err := rows.Scan(&pick.PredictionId, &pick.Round, &pick.Slot, &pick.TorronId); err != nil {
+			return nil, nil, handleErrors(err)
+		}
+		picks = append(picks, pick)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, handleErrors(err)
+	}
+
+	return prediction, picks, nil
+}
+
+func (r *postgresBracketPredictionRepo) Leaderboard(
+	ctx context.Context,
+	bracketId string,
+	circleId string,
+	limit int,
+) ([]*domain.BracketPredictionScore, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`WITH scores AS (`+predictionScoreSelect+`
+            WHERE p."BracketId" = $1
+              AND NOT u."Quarantined"
+              AND ($2::VARCHAR = '' OR EXISTS (
+                  SELECT 1 FROM "FriendCircleMembers" fcm
+                  WHERE fcm."CircleId" = $2 AND fcm."UserId" = p."UserId"))
+            GROUP BY p."Id", t."Name"
+        )
+        SELECT "UserId", "ChampionId", "Name", "Points", "Correct", "Decided",
+               RANK() OVER (ORDER BY "Points" DESC, "Correct" DESC) AS "Rank"
+        FROM scores
+        ORDER BY "Points" DESC, "Correct" DESC, "UpdatedAt" ASC
+        LIMIT $3`,
+		bracketId,
+		circleId,
+		limit,
+	)
+	if err != nil {
+		return nil, handleErrors(err)
+	}
+	defer rows.Close()
+
+	var scores []*domain.BracketPredictionScore
+	for rows.Next() {
+		score := &domain.BracketPredictionScore{}
+		err := rows.Scan(
+			&score.UserId,
+			&score.ChampionId,
+			&score.ChampionName,
+			&score.Points,
+			&score.Correct,
+			&score.Decided,
+			&score.Rank,
+		)
+		if err != nil {
+			return nil, handleErrors(err)
+		}
+		scores = append(scores, score)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, handleErrors(err)
+	}
+
+	return scores, nil
+}
+
+func (r *postgresBracketPredictionRepo) ScoreForUser(
+	ctx context.Context,
+	bracketId string,
+	userId string,
+) (*domain.BracketPredictionScore, error) {
+	return scorePrediction(ctx, r.db, bracketId, userId)
+}
+
+// scorePrediction scores one user's prediction for a bracket. It is shared
+// with postgresWrappedStatsRepo.BracketPath, which reads the same score.
+func scorePrediction(ctx context.Context, db *sql.DB, bracketId string, userId string) (*domain.BracketPredictionScore, error) {
+	score := &domain.BracketPredictionScore{}
+	var updatedAt string
+	err := db.QueryRowContext(ctx,
+		predictionScoreSelect+`
+        WHERE p."BracketId" = $1 AND p."UserId" = $2
+        GROUP BY p."Id", t."Name"`,
+		bracketId,
+		userId,
+	).Scan(
+		&score.UserId,
+		&score.ChampionId,
+		&score.ChampionName,
+		&updatedAt,
+		&score.Points,
+		&score.Correct,
+		&score.Decided,
+	)
+	if err != nil {
+		return nil, handleErrors(err)
+	}
+
+	return score, nil
+}
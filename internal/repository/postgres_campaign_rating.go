@@ -0,0 +1,293 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+type postgresCampaignRatingRepo struct {
+	db *sql.DB
+}
+
+func NewCampaignRatingRepo(db *sql.DB) domain.CampaignRatingRepo {
+	return &postgresCampaignRatingRepo{
+		db: db,
+	}
+}
+
+// inSeason limits a query over "Results" res to the current rating
+// season's votes.
+const inSeason = `res."Timestamp" >= COALESCE((SELECT "StartedAt" FROM "RatingSeason"), '-infinity')`
+
+// seasonResults is the Results rows of the current rating season.
+const seasonResults = `
+        SELECT res.* FROM "Results" res WHERE ` + inSeason
+
+// seasonVotes counts each torró's votes in the current rating season, as
+// a "votes" CTE of (tid, n).
+const seasonVotes = `
+        WITH season AS (` + seasonResults + `
+        ),
+        votes AS (
+            SELECT tid, COUNT(*) AS n
+            FROM (
+                SELECT p."Torro1" AS tid FROM season res JOIN "Pairings" p ON p."Id" = res."Pairing"
+                UNION ALL
+                SELECT p."Torro2" AS tid FROM season res JOIN "Pairings" p ON p."Id" = res."Pairing"
+            ) v
+            GROUP BY tid
+        )`
+
+func (r *postgresCampaignRatingRepo) GetSeason(ctx context.Context) (*domain.RatingSeason, error) {
+	season := &domain.RatingSeason{}
+	err := r.db.QueryRowContext(ctx,
+		`SELECT "CampaignId", "StartedAt" FROM "RatingSeason"`,
+	).Scan(&season.CampaignId, &season.StartedAt)
+	if err != nil {
+		return nil, handleErrors(err)
+	}
+
+	return season, nil
+}
+
+func (r *postgresCampaignRatingRepo) Rollover(
+	ctx context.Context,
+	campaignId string,
+	initial domain.RatingState,
+) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, handleErrors(err)
+	}
+	defer tx.Rollback()
+
+	// The same locks as a rating replay: no vote may land on the live
+	// ratings while they are archived and reset.
+	if err := lockReplayTables(ctx, tx); err != nil {
+		return false, err
+	}
+
+	var current *string
+	if err := tx.QueryRowContext(ctx,
+		`SELECT "CampaignId" FROM "RatingSeason" FOR UPDATE`,
+	).Scan(&current); err != nil {
+		return false, handleErrors(err)
+	}
+
+	if current != nil && *current == campaignId {
+		return false, nil
+	}
+
+	if current == nil {
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE "RatingSeason" SET "CampaignId" = $1`, campaignId,
+		); err != nil {
+			return false, handleErrors(err)
+		}
+		if err := tx.Commit(); err != nil {
+			return false, handleErrors(err)
+		}
+		return false, nil
+	}
+
+	if err := archiveSeason(ctx, tx, *current); err != nil {
+		return false, err
+	}
+
+	// Start over: every torró from the engine's initial state, every
+	// personal ranking and class vote count from nothing. A pending undo
+	// would restore last season's state, so those go too.
+	for _, stmt := range []string{
+		`DELETE FROM "UserEloSnapshots"`,
+		`DELETE FROM "VoteUndos"`,
+		`UPDATE "Users" SET "ClassVotes" = '{}'::jsonb`,
+	} {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return false, handleErrors(err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE "Torrons" SET "Rating" = $1, "RatingDeviation" = $2, "RatingVolatility" = $3`,
+		initial.Rating, initial.Deviation, initial.Volatility,
+	); err != nil {
+		return false, handleErrors(err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE "RatingSeason" SET "CampaignId" = $1, "StartedAt" = NOW()`, campaignId,
+	); err != nil {
+		return false, handleErrors(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, handleErrors(err)
+	}
+
+	return true, nil
+}
+
+// archiveSeason copies the live ratings into campaignId's archive: every
+// torró voted on this season, ranked among those, and every personal
+// snapshot.
+func archiveSeason(ctx context.Context, tx *sql.Tx, campaignId string) error {
+	for _, stmt := range []string{
+		`DELETE FROM "CampaignRatings" WHERE "CampaignId" = $1`,
+		`DELETE FROM "CampaignUserEloSnapshots" WHERE "CampaignId" = $1`,
+	} {
+		if _, err := tx.ExecContext(ctx, stmt, campaignId); err != nil {
+			return handleErrors(err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`
+        INSERT INTO "CampaignRatings" ("CampaignId", "TorroId", "ClassId", "Rating", "RatingDeviation",
+                                       "RatingVolatility", "Votes", "Rank", "ClassRank")`+seasonVotes+`
+        SELECT $1, t."Id", t."Class", t."Rating", t."RatingDeviation", t."RatingVolatility", v.n,
+               RANK() OVER (ORDER BY t."Rating" DESC),
+               RANK() OVER (PARTITION BY t."Class" ORDER BY t."Rating" DESC)
+        FROM "Torrons" t
+        JOIN votes v ON v.tid = t."Id"`,
+		campaignId,
+	); err != nil {
+		return handleErrors(err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`
+        INSERT INTO "CampaignUserEloSnapshots" ("CampaignId", "UserId", "TorronId", "Rating",
+                                                "RatingDeviation", "RatingVolatility", "VoteCount")
+        SELECT $1, "UserId", "TorronId", "Rating", "RatingDeviation", "RatingVolatility", "VoteCount"
+        FROM "UserEloSnapshots"`,
+		campaignId,
+	); err != nil {
+		return handleErrors(err)
+	}
+
+	return nil
+}
+
+func (r *postgresCampaignRatingRepo) List(ctx context.Context, campaignId string) ([]*domain.CampaignRating, error) {
+	live, err := r.isLive(ctx, campaignId)
+	if err != nil {
+		return nil, err
+	}
+
+	if live {
+		return r.listCampaignRatings(ctx, campaignId, seasonVotes+`
+        SELECT t."Id", t."Name", t."Class", t."Rating", t."RatingDeviation", t."RatingVolatility",
+               COALESCE(v.n, 0),
+               RANK() OVER (ORDER BY t."Rating" DESC),
+               RANK() OVER (PARTITION BY t."Class" ORDER BY t."Rating" DESC)
+        FROM "Torrons" t
+        LEFT JOIN votes v ON v.tid = t."Id"
+        WHERE NOT t."Discontinued"
+        ORDER BY t."Rating" DESC`)
+	}
+
+	return r.listCampaignRatings(ctx, campaignId, `
+        SELECT c."TorroId", t."Name", c."ClassId", c."Rating", c."RatingDeviation", c."RatingVolatility",
+               c."Votes", c."Rank", c."ClassRank"
+        FROM "CampaignRatings" c
+        JOIN "Torrons" t ON t."Id" = c."TorroId"
+        WHERE c."CampaignId" = $1
+        ORDER BY c."Rank", c."Rating" DESC`, campaignId)
+}
+
+func (r *postgresCampaignRatingRepo) ListForUser(
+	ctx context.Context,
+	campaignId string,
+	userId string,
+) ([]*domain.CampaignRating, error) {
+	live, err := r.isLive(ctx, campaignId)
+	if err != nil {
+		return nil, err
+	}
+
+	if live {
+		return r.listCampaignRatings(ctx, campaignId, `
+        SELECT s."TorronId", t."Name", t."Class", s."Rating", s."RatingDeviation", s."RatingVolatility",
+               s."VoteCount",
+               RANK() OVER (ORDER BY s."Rating" DESC),
+               RANK() OVER (PARTITION BY t."Class" ORDER BY s."Rating" DESC)
+        FROM "UserEloSnapshots" s
+        JOIN "Torrons" t ON t."Id" = s."TorronId"
+        WHERE s."UserId" = $1
+        ORDER BY s."Rating" DESC`, userId)
+	}
+
+	return r.listCampaignRatings(ctx, campaignId, `
+        SELECT s."TorronId", t."Name", t."Class", s."Rating", s."RatingDeviation", s."RatingVolatility",
+               s."VoteCount",
+               RANK() OVER (ORDER BY s."Rating" DESC),
+               RANK() OVER (PARTITION BY t."Class" ORDER BY s."Rating" DESC)
+        FROM "CampaignUserEloSnapshots" s
+        JOIN "Torrons" t ON t."Id" = s."TorronId"
+        WHERE s."CampaignId" = $1 AND s."UserId" = $2
+        ORDER BY s."Rating" DESC`, campaignId, userId)
+}
+
+func (r *postgresCampaignRatingRepo) PreviousCampaignId(ctx context.Context, year int) (string, error) {
+	var id string
+	err := r.db.QueryRowContext(ctx,
+		`
+        SELECT c."Id"
+        FROM "Campaigns" c
+        WHERE c."Year" < $1
+          AND EXISTS (SELECT 1 FROM "CampaignRatings" r WHERE r."CampaignId" = c."Id")
+        ORDER BY c."Year" DESC, c."StartDate" DESC
+        LIMIT 1`,
+		year,
+	).Scan(&id)
+	if err != nil {
+		return "", handleErrors(err)
+	}
+
+	return id, nil
+}
+
+// isLive reports whether campaignId owns the live ratings.
+func (r *postgresCampaignRatingRepo) isLive(ctx context.Context, campaignId string) (bool, error) {
+	season, err := r.GetSeason(ctx)
+	if err != nil {
+		return false, err
+	}
+	return season.CampaignId != nil && *season.CampaignId == campaignId, nil
+}
+
+func (r *postgresCampaignRatingRepo) listCampaignRatings(
+	ctx context.Context,
+	campaignId string,
+	query string,
+	args ...any,
+) ([]*domain.CampaignRating, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, handleErrors(err)
+	}
+
+	defer rows.Close()
+	var ratings []*domain.CampaignRating
+
+	for rows.Next() {
+		c := &domain.CampaignRating{CampaignId: campaignId}
+		if err := rows.Scan(
+			&c.TorroId,
+			&c.Name,
+			&c.ClassId,
+			&c.Rating,
+			&c.Deviation,
+			&c.Volatility,
+			&c.Votes,
+			&c.Rank,
+			&c.ClassRank,
+		); err != nil {
+			return nil, handleErrors(err)
+		}
+		ratings = append(ratings, c)
+	}
+
+	return ratings, nil
+}
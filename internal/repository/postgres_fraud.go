@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+type postgresFraudRepo struct {
+	db *sql.DB
+}
+
+func NewFraudRepo(db *sql.DB) domain.FraudRepo {
+	return &postgresFraudRepo{
+		db: db,
+	}
+}
+
+// ListSignals computes every signal in one pass over Results. A vote's gap
+// is measured from the same user's previous vote, ordered as the replay
+// orders them (by "Seq", the order they were applied in); a user's first
+// vote has no gap and never counts as fast. The duels a ranking vote
+// implies are left out: they land within moments of each other, have no
+// left or right side, and hand the top-ranked torró several wins at once.
+func (r *postgresFraudRepo) ListSignals(ctx context.Context, minVotes int) ([]*domain.FraudSignals, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`
+        WITH votes AS (
+            SELECT res."UserId" AS user_id, res."Winner" AS winner, p."Torro1" AS torro1,
+                   res."Timestamp" - LAG(res."Timestamp") OVER (
+                       PARTITION BY res."UserId" ORDER BY res."Seq"
+                   ) AS gap
+            FROM "Results" res
+            JOIN "Pairings" p ON p."Id" = res."Pairing"
+            WHERE res."UserId" IS NOT NULL AND res."SubmissionId" IS NULL
+        ),
+        per_user AS (
+            SELECT user_id,
+                   COUNT(*) AS votes,
+                   COUNT(winner) AS decided,
+                   COUNT(*) FILTER (WHERE winner = torro1) AS left_picks,
+                   COUNT(*) FILTER (WHERE gap < INTERVAL '1 second') AS fast_votes
+            FROM votes
+            GROUP BY user_id
+            HAVING COUNT(*) >= $1
+        ),
+        favourites AS (
+            SELECT user_id, MAX(wins) AS favourite_wins
+            FROM (
+                SELECT user_id, winner, COUNT(*) AS wins
+                FROM votes
+                WHERE winner IS NOT NULL
+                GROUP BY user_id, winner
+            ) w
+            GROUP BY user_id
+        )
+        SELECT u."Id", pu.votes, pu.decided, pu.left_picks, pu.fast_votes,
+               COALESCE(f.favourite_wins, 0),
+               (SELECT COUNT(*) FROM "Users" o
+                WHERE o."IpHash" = u."IpHash" AND o."Id" <> u."Id"
+                  AND o."FirstSeen" BETWEEN u."FirstSeen" - INTERVAL '1 day'
+                                        AND u."FirstSeen" + INTERVAL '1 day'),
+               u."Quarantined", u."QuarantineReleasedAt" IS NOT NULL
+        FROM per_user pu
+        JOIN "Users" u ON u."Id" = pu.user_id
+        LEFT JOIN favourites f ON f.user_id = pu.user_id`,
+		minVotes,
+	)
+	if err != nil {
+		return nil, handleErrors(err)
+	}
+
+	defer rows.Close()
+	var signals []*domain.FraudSignals
+
+	for rows.Next() {
+		s := &domain.FraudSignals{}
+		if err := rows.Scan(
+			&s.UserId,
+			&s.Votes,
+			&s.Decided,
+			&s.LeftPicks,
+			&s.FastVotes,
+			&s.FavouriteWins,
+			&s.IpSiblings,
+			&s.Quarantined,
+			&s.Released,
+		); err != nil {
+			return nil, handleErrors(err)
+		}
+		signals = append(signals, s)
+	}
+
+	return signals, nil
+}
+
+func (r *postgresFraudRepo) ListQuarantined(ctx context.Context) ([]*domain.QuarantinedUser, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`
+        SELECT "Id", "FraudScore", "QuarantinedAt", "VoteCount"
+        FROM "Users"
+        WHERE "Quarantined"
+        ORDER BY "QuarantinedAt" DESC`,
+	)
+	if err != nil {
+		return nil, handleErrors(err)
+	}
+
+	defer rows.Close()
+	var users []*domain.QuarantinedUser
+
+	for rows.Next() {
+		u := &domain.QuarantinedUser{}
+		if err := rows.Scan(
+			&u.UserId,
+			&u.FraudScore,
+			&u.QuarantinedAt,
+			&u.VoteCount,
+		); err != nil {
+			return nil, handleErrors(err)
+		}
+		users = append(users, u)
+	}
+
+	return users, nil
+}
+
+func (r *postgresFraudRepo) Quarantine(ctx context.Context, userId string, score float64) (bool, error) {
+	res, err := r.db.ExecContext(ctx,
+		`
+        UPDATE "Users"
+        SET "Quarantined" = TRUE, "QuarantinedAt" = $2, "FraudScore" = $3
+        WHERE "Id" = $1 AND NOT "Quarantined" AND "QuarantineReleasedAt" IS NULL`,
+		userId,
+		time.Now().UTC().Format(time.RFC3339),
+		score,
+	)
+	if err != nil {
+		return false, handleErrors(err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, handleErrors(err)
+	}
+
+	return affected > 0, nil
+}
+
+// Release answers not-found for an unknown user or one that isn't
+// quarantined, so the admin endpoint can tell a typo from a no-op.
+func (r *postgresFraudRepo) Release(ctx context.Context, userId string) error {
+	var id string
+	err := r.db.QueryRowContext(ctx,
+		`
+        UPDATE "Users"
+        SET "Quarantined" = FALSE, "QuarantineReleasedAt" = $2
+        WHERE "Id" = $1 AND "Quarantined"
+        RETURNING "Id"`,
+		userId,
+		time.Now().UTC().Format(time.RFC3339),
+	).Scan(&id)
+	if err != nil {
+		return handleErrors(err)
+	}
+
+	return nil
+}
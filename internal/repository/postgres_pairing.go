@@ -4,9 +4,11 @@ import (
 	"context"
 	"crypto/rand"
 	"database/sql"
+	"errors"
 	"math/big"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 
 	"github.com/krtffl/torro/internal/domain"
 )
@@ -24,7 +26,7 @@ func NewPairingRepo(db *sql.DB) domain.PairingRepo {
 func (r *postgresPairingRepo) Get(ctx context.Context, id string) (*domain.Pairing, error) {
 	row := r.db.QueryRowContext(ctx,
 		`
-        SELECT "Id", "Torro1", "Torro2", "Class"
+        SELECT "Id", "Torro1", "Torro2", "Class", "RetiredAt"
         FROM "Pairings"
         WHERE "Id" = $1`,
 		id,
@@ -35,6 +37,7 @@ func (r *postgresPairingRepo) Get(ctx context.Context, id string) (*domain.Pairi
 		&pairing.Torro1,
 		&pairing.Torro2,
 		&pairing.Class,
+		&pairing.RetiredAt,
 	)
 	if err != nil {
 		return nil, handleErrors(err)
@@ -70,7 +73,8 @@ func (r *postgresPairingRepo) List(ctx context.Context) ([]*domain.Pairing, erro
 	rows, err := r.db.QueryContext(ctx,
 		`
         SELECT "Id", "Torro1", "Torro2", "Class"
-        FROM "Pairings"`,
+        FROM "Pairings"
+        WHERE "RetiredAt" IS NULL`,
 	)
 	if err != nil {
 		return nil, handleErrors(err)
@@ -100,7 +104,7 @@ func (r *postgresPairingRepo) ListByClass(ctx context.Context, classId string) (
 		`
         SELECT "Id", "Torro1", "Torro2", "Class"
         FROM "Pairings"
-        WHERE "Class" = $1`,
+        WHERE "Class" = $1 AND "RetiredAt" IS NULL`,
 		classId,
 	)
 	if err != nil {
@@ -150,7 +154,7 @@ func (r *postgresPairingRepo) GetRandom(ctx context.Context, classId string) (*d
 		`
         SELECT "Id", "Torro1", "Torro2", "Class"
         FROM "Pairings"
-        WHERE "Class" = $1
+        WHERE "Class" = $1 AND "RetiredAt" IS NULL
         LIMIT 1 OFFSET $2`,
 		classId,
 		offset,
@@ -175,7 +179,7 @@ func (r *postgresPairingRepo) GetRandom(ctx context.Context, classId string) (*d
 func (r *postgresPairingRepo) GetRandomExcluding(ctx context.Context, classId, excludeId string) (*domain.Pairing, error) {
 	var count int
 	if err := r.db.QueryRowContext(ctx,
-		`SELECT COUNT(*) FROM "Pairings" WHERE "Class" = $1 AND "Id" <> $2`,
+		`SELECT COUNT(*) FROM "Pairings" WHERE "Class" = $1 AND "Id" <> $2 AND "RetiredAt" IS NULL`,
 		classId, excludeId,
 	).Scan(&count); err != nil {
 		return nil, handleErrors(err)
@@ -197,7 +201,7 @@ func (r *postgresPairingRepo) GetRandomExcluding(ctx context.Context, classId, e
 		`
         SELECT "Id", "Torro1", "Torro2", "Class"
         FROM "Pairings"
-        WHERE "Class" = $1 AND "Id" <> $2
+        WHERE "Class" = $1 AND "Id" <> $2 AND "RetiredAt" IS NULL
         LIMIT 1 OFFSET $3`,
 		classId,
 		excludeId,
@@ -243,7 +247,7 @@ func (r *postgresPairingRepo) GetDeterministic(ctx context.Context, classId stri
 		`
         SELECT "Id", "Torro1", "Torro2", "Class"
         FROM "Pairings"
-        WHERE "Class" = $1
+        WHERE "Class" = $1 AND "RetiredAt" IS NULL
         ORDER BY "Id"
         LIMIT 1 OFFSET $2`,
 		classId,
@@ -263,12 +267,151 @@ func (r *postgresPairingRepo) GetDeterministic(ctx context.Context, classId stri
 	return pairing, nil
 }
 
+// ListCandidates loads every pairing of the class joined with both torrons'
+// rating state, the pairing's all-time vote count and, for userId, how many
+// of those votes are theirs and how often each torró shows up in their own
+// snapshots, plus when the vote screen last served it to them. An empty
+// userId matches no Results, snapshot or view rows, so the per-user columns
+// come back as zero for anonymous sessions.
+func (r *postgresPairingRepo) ListCandidates(ctx context.Context, classId, userId string) ([]*domain.PairingCandidate, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`
+        SELECT p."Id", p."Torro1", p."Torro2", p."Class",
+               t1."Rating", t1."RatingDeviation", t1."RatingVolatility",
+               t2."Rating", t2."RatingDeviation", t2."RatingVolatility",
+               COALESCE(v.votes, 0), COALESCE(v.user_votes, 0),
+               COALESCE(s1."VoteCount", 0), COALESCE(s2."VoteCount", 0),
+               COALESCE(pv."SeenCount", 0), pv."LastSeenAt"
+        FROM "Pairings" p
+        JOIN "Torrons" t1 ON t1."Id" = p."Torro1"
+        JOIN "Torrons" t2 ON t2."Id" = p."Torro2"
+        LEFT JOIN (
+            SELECT res."Pairing",
+                   COUNT(*) AS votes,
+                   COUNT(*) FILTER (WHERE res."UserId" = $2) AS user_votes
+            FROM "Results" res
+            JOIN "Pairings" rp ON rp."Id" = res."Pairing"
+            WHERE rp."Class" = $1
+            GROUP BY res."Pairing"
+        ) v ON v."Pairing" = p."Id"
+        LEFT JOIN "UserEloSnapshots" s1 ON s1."UserId" = $2 AND s1."TorronId" = p."Torro1"
+        LEFT JOIN "UserEloSnapshots" s2 ON s2."UserId" = $2 AND s2."TorronId" = p."Torro2"
+        LEFT JOIN "UserPairingViews" pv ON pv."UserId" = $2 AND pv."PairingId" = p."Id"
+        WHERE p."Class" = $1 AND p."RetiredAt" IS NULL`,
+		classId,
+		userId,
+	)
+	if err != nil {
+		return nil, handleErrors(err)
+	}
+
+	defer rows.Close()
+	var candidates []*domain.PairingCandidate
+
+	for rows.Next() {
+		c := &domain.PairingCandidate{Pairing: &domain.Pairing{}}
+		var lastSeen sql.NullTime
+		if err := rows.Scan(
+			&c.Pairing.Id,
+			&c.Pairing.Torro1,
+			&c.Pairing.Torro2,
+			&c.Pairing.Class,
+			&c.State1.Rating,
+			&c.State1.Deviation,
+			&c.State1.Volatility,
+			&c.State2.Rating,
+			&c.State2.Deviation,
+			&c.State2.Volatility,
+			&c.Votes,
+			&c.UserVotes,
+			&c.UserTorro1Votes,
+			&c.UserTorro2Votes,
+			&c.UserSeen,
+			&lastSeen,
+		); err != nil {
+			return nil, handleErrors(err)
+		}
+		c.UserLastSeen = lastSeen.Time
+		candidates = append(candidates, c)
+	}
+
+	return candidates, nil
+}
+
+// MarkSeen upserts the user's view row for the pairing, bumping its serve
+// count and last-seen time.
+func (r *postgresPairingRepo) MarkSeen(ctx context.Context, userId, pairingId string) error {
+	_, err := r.db.ExecContext(ctx,
+		`
+        INSERT INTO "UserPairingViews" ("UserId", "PairingId", "SeenCount", "LastSeenAt")
+        VALUES ($1, $2, 1, NOW())
+        ON CONFLICT ("UserId", "PairingId") DO UPDATE
+        SET "SeenCount" = "UserPairingViews"."SeenCount" + 1,
+            "LastSeenAt" = NOW()`,
+		userId,
+		pairingId,
+	)
+	if err != nil {
+		return handleErrors(err)
+	}
+
+	return nil
+}
+
+// CoverageByUser counts, per class, the pairings the user has been served
+// or has voted on (a vote cast from somewhere other than the vote screen,
+// such as the advent duel, still counts), and the serve count of their
+// least-seen pairing.
+func (r *postgresPairingRepo) CoverageByUser(ctx context.Context, userId string) ([]*domain.PairingCoverage, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`
+        SELECT p."Class",
+               COUNT(*) FILTER (
+                   WHERE pv."PairingId" IS NOT NULL
+                      OR EXISTS (
+                          SELECT 1 FROM "Results" res
+                          WHERE res."Pairing" = p."Id" AND res."UserId" = $1
+                      )
+               ),
+               COUNT(*),
+               MIN(COALESCE(pv."SeenCount", 0))
+        FROM "Pairings" p
+        LEFT JOIN "UserPairingViews" pv ON pv."UserId" = $1 AND pv."PairingId" = p."Id"
+        WHERE p."RetiredAt" IS NULL
+        GROUP BY p."Class"
+        ORDER BY p."Class"`,
+		userId,
+	)
+	if err != nil {
+		return nil, handleErrors(err)
+	}
+
+	defer rows.Close()
+	var coverage []*domain.PairingCoverage
+
+	for rows.Next() {
+		c := &domain.PairingCoverage{}
+		if err := rows.Scan(
+			&c.ClassId,
+			&c.Seen,
+			&c.Total,
+			&c.Passes,
+		); err != nil {
+			return nil, handleErrors(err)
+		}
+		coverage = append(coverage, c)
+	}
+
+	return coverage, nil
+}
+
 func (r *postgresPairingRepo) Count(ctx context.Context) (int, error) {
 	var count int
 	err := r.db.QueryRowContext(ctx,
 		`
         SELECT COUNT(*)
-        FROM "Pairings"`,
+        FROM "Pairings"
+        WHERE "RetiredAt" IS NULL`,
 	).Scan(
 		&count,
 	)
@@ -285,7 +428,7 @@ func (r *postgresPairingRepo) CountClass(ctx context.Context, classId string) (i
 		`
         SELECT COUNT(*)
         FROM "Pairings"
-        WHERE "Class" = $1`,
+        WHERE "Class" = $1 AND "RetiredAt" IS NULL`,
 		classId,
 	).Scan(
 		&count,
@@ -296,3 +439,109 @@ func (r *postgresPairingRepo) CountClass(ctx context.Context, classId string) (i
 
 	return count, nil
 }
+
+func (r *postgresPairingRepo) Reconcile(
+	ctx context.Context,
+	classId string,
+	desired []*domain.Pairing,
+) (*domain.PairingReconciliation, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, handleErrors(err)
+	}
+	defer tx.Rollback()
+
+	// Lock the class's pairings so two instances reconciling at boot
+	// can't both retire or revive the same rows.
+	rows, err := tx.QueryContext(ctx,
+		`
+        SELECT "Id", "Torro1", "Torro2", "Class", "RetiredAt"
+        FROM "Pairings"
+        WHERE "Class" = $1
+        FOR UPDATE`,
+		classId,
+	)
+	if err != nil {
+		return nil, handleErrors(err)
+	}
+
+	existing := make(map[string]*domain.Pairing)
+	for rows.Next() {
+		p := &domain.Pairing{}
+		if err := rows.Scan(&p.Id, &p.Torro1, &p.Torro2, &p.Class, &p.RetiredAt); err != nil {
+			rows.Close()
+			return nil, handleErrors(err)
+		}
+		existing[p.Key()] = p
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, handleErrors(err)
+	}
+
+	summary := &domain.PairingReconciliation{ClassId: classId, Desired: len(desired)}
+	wanted := make(map[string]bool, len(desired))
+	var revive, retire []string
+
+	for _, d := range desired {
+		key := d.Key()
+		wanted[key] = true
+
+		if p, ok := existing[key]; ok {
+			if p.RetiredAt != nil {
+				revive = append(revive, p.Id)
+			}
+			continue
+		}
+
+		// ON CONFLICT covers a matchup another instance inserted after
+		// the read above.
+		var id string
+		err := tx.QueryRowContext(ctx,
+			`
+            INSERT INTO "Pairings" ("Id", "Torro1", "Torro2", "Class")
+            VALUES ($1, $2, $3, $4)
+            ON CONFLICT (LEAST("Torro1", "Torro2"), GREATEST("Torro1", "Torro2"), "Class") DO NOTHING
+            RETURNING "Id"`,
+			uuid.NewString(), d.Torro1, d.Torro2, classId,
+		).Scan(&id)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, handleErrors(err)
+		}
+		summary.Inserted++
+	}
+
+	for key, p := range existing {
+		if !wanted[key] && p.RetiredAt == nil {
+			retire = append(retire, p.Id)
+		}
+	}
+
+	if len(revive) > 0 {
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE "Pairings" SET "RetiredAt" = NULL WHERE "Id" = ANY($1)`,
+			pq.Array(revive),
+		); err != nil {
+			return nil, handleErrors(err)
+		}
+	}
+	if len(retire) > 0 {
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE "Pairings" SET "RetiredAt" = NOW() WHERE "Id" = ANY($1)`,
+			pq.Array(retire),
+		); err != nil {
+			return nil, handleErrors(err)
+		}
+	}
+	summary.Revived = len(revive)
+	summary.Retired = len(retire)
+
+	if err := tx.Commit(); err != nil {
+		return nil, handleErrors(err)
+	}
+
+	return summary, nil
+}
@@ -279,6 +279,7 @@ func (r *postgresPersonaRepo) topTorro(ctx context.Context, userId string, class
         FROM "Results" r
         JOIN "Pairings" p ON p."Id" = r."Pairing"
         WHERE r."UserId" = $1 AND ($2::text IS NULL OR p."Class" = $2)
+          AND r."Winner" IS NOT NULL
         GROUP BY r."Winner"
         ORDER BY COUNT(*) DESC, r."Winner" ASC
         LIMIT 1`,
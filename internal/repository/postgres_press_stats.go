@@ -93,7 +93,8 @@ func (r *postgresPressStatsRepo) BiggestRiser(ctx context.Context, windowDays in
 // vote-count gap, among pairings that have accumulated at least
 // minTotalVotes votes in total. Each distinct 2-torró matchup is exactly
 // one Pairings row (pairings are de-duplicated at creation time), so
-// grouping Results by Pairing reliably identifies one duel.
+// grouping Results by Pairing reliably identifies one duel. Only decided
+// votes count: a draw says nothing about which side the crowd prefers.
 func (r *postgresPressStatsRepo) ClosestDuel(ctx context.Context, minTotalVotes int) (*domain.ClosestDuel, error) {
 	row := r.db.QueryRowContext(ctx,
 		`
@@ -103,6 +104,7 @@ func (r *postgresPressStatsRepo) ClosestDuel(ctx context.Context, minTotalVotes
                COUNT(*) AS "TotalVotes"
         FROM "Results" res
         JOIN "Pairings" p ON res."Pairing" = p."Id"
+        WHERE res."Winner" IS NOT NULL
         GROUP BY res."Pairing", p."Torro1", p."Torro2"
         HAVING COUNT(*) >= $1
         ORDER BY ABS(
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+type postgresRankingSubmissionRepo struct {
+	db *sql.DB
+}
+
+func NewRankingSubmissionRepo(db *sql.DB) domain.RankingSubmissionRepo {
+	return &postgresRankingSubmissionRepo{
+		db: db,
+	}
+}
+
+func (r *postgresRankingSubmissionRepo) CreateTx(
+	tx *sql.Tx,
+	ctx context.Context,
+	submission *domain.RankingSubmission,
+) (*domain.RankingSubmission, error) {
+	if submission.Id == "" {
+		submission.Id = uuid.NewString()
+	}
+
+	err := tx.QueryRowContext(ctx,
+		`INSERT INTO "RankingSubmissions" ("Id", "UserId", "ClassId", "CampaignId", "TorroIds")
+		 VALUES ($1, $2, $3, $4, $5)
+		 RETURNING "CreatedAt"`,
+		submission.Id,
+		submission.UserId,
+		submission.ClassId,
+		submission.CampaignId,
+		pq.Array(submission.TorroIds),
+	).Scan(&submission.CreatedAt)
+	if err != nil {
+		return nil, handleErrors(err)
+	}
+
+	return submission, nil
+}
@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+type postgresReasonRepo struct {
+	db *sql.DB
+}
+
+func NewReasonRepo(db *sql.DB) domain.ReasonRepo {
+	return &postgresReasonRepo{
+		db: db,
+	}
+}
+
+func (r *postgresReasonRepo) ListTags(ctx context.Context) ([]*domain.ReasonTag, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`
+        SELECT "Id", "Label", "Position", "Active"
+        FROM "ReasonTags"
+        WHERE "Active"
+        ORDER BY "Position", "Id"`,
+	)
+	if err != nil {
+		return nil, handleErrors(err)
+	}
+
+	defer rows.Close()
+	var tags []*domain.ReasonTag
+
+	for rows.Next() {
+		tag := &domain.ReasonTag{}
+		if err := rows.Scan(&tag.Id, &tag.Label, &tag.Position, &tag.Active); err != nil {
+			return nil, handleErrors(err)
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
+
+func (r *postgresReasonRepo) ListForResult(ctx context.Context, resultId string) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT "TagId" FROM "ResultReasons" WHERE "ResultId" = $1 ORDER BY "TagId"`,
+		resultId,
+	)
+	if err != nil {
+		return nil, handleErrors(err)
+	}
+
+	defer rows.Close()
+	var tagIds []string
+
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, handleErrors(err)
+		}
+		tagIds = append(tagIds, id)
+	}
+
+	return tagIds, nil
+}
+
+func (r *postgresReasonRepo) SetForResult(ctx context.Context, resultId, userId string, tagIds []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return handleErrors(err)
+	}
+	defer tx.Rollback()
+
+	// Locked so an undo can't delete the Result between the check and the
+	// insert.
+	var winner sql.NullString
+	if err := tx.QueryRowContext(ctx,
+		`SELECT "Winner" FROM "Results" WHERE "Id" = $1 AND "UserId" = $2 FOR UPDATE`,
+		resultId,
+		userId,
+	).Scan(&winner); err != nil {
+		return handleErrors(err)
+	}
+	if !winner.Valid {
+		return fmt.Errorf("%s: a draw has no reason to give", domain.ValidationError)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM "ResultReasons" WHERE "ResultId" = $1`,
+		resultId,
+	); err != nil {
+		return handleErrors(err)
+	}
+
+	if len(tagIds) > 0 {
+		if _, err := tx.ExecContext(ctx,
+			`
+            INSERT INTO "ResultReasons" ("ResultId", "TagId")
+            SELECT $1, unnest($2::VARCHAR[])`,
+			resultId,
+			pq.Array(tagIds),
+		); err != nil {
+			return handleErrors(err)
+		}
+	}
+
+	return handleErrors(tx.Commit())
+}
+
+func (r *postgresReasonRepo) ListForTorro(ctx context.Context, torroId string) (*domain.TorroReasons, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`
+        SELECT rt."Id", rt."Label",
+               COUNT(*) FILTER (WHERE res."Winner" = $1),
+               COUNT(*) FILTER (WHERE res."Winner" <> $1)
+        FROM "ResultReasons" rr
+        JOIN "ReasonTags" rt ON rt."Id" = rr."TagId"
+        JOIN "Results" res ON res."Id" = rr."ResultId"
+        JOIN "Pairings" p ON p."Id" = res."Pairing"
+        WHERE $1 IN (p."Torro1", p."Torro2")
+        GROUP BY rt."Id", rt."Label", rt."Position"
+        ORDER BY rt."Position"`,
+		torroId,
+	)
+	if err != nil {
+		return nil, handleErrors(err)
+	}
+
+	defer rows.Close()
+	reasons := &domain.TorroReasons{TorroId: torroId}
+
+	for rows.Next() {
+		var id, label string
+		var wins, losses int
+		if err := rows.Scan(&id, &label, &wins, &losses); err != nil {
+			return nil, handleErrors(err)
+		}
+		if wins > 0 {
+			reasons.Wins = append(reasons.Wins, &domain.ReasonCount{TagId: id, Label: label, Count: wins})
+		}
+		if losses > 0 {
+			reasons.Losses = append(reasons.Losses, &domain.ReasonCount{TagId: id, Label: label, Count: losses})
+		}
+	}
+
+	return reasons, nil
+}
+
+func (r *postgresReasonRepo) Breakdown(ctx context.Context) ([]*domain.ReasonCount, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`
+        SELECT rt."Id", rt."Label", COUNT(*)
+        FROM "ResultReasons" rr
+        JOIN "ReasonTags" rt ON rt."Id" = rr."TagId"
+        GROUP BY rt."Id", rt."Label", rt."Position"
+        ORDER BY rt."Position"`,
+	)
+	if err != nil {
+		return nil, handleErrors(err)
+	}
+
+	defer rows.Close()
+	var counts []*domain.ReasonCount
+
+	for rows.Next() {
+		c := &domain.ReasonCount{}
+		if err := rows.Scan(&c.TagId, &c.Label, &c.Count); err != nil {
+			return nil, handleErrors(err)
+		}
+		counts = append(counts, c)
+	}
+
+	return counts, nil
+}
+
+// Export attributes each reason to both torrons of its duel: a win for the
+// Winner and a loss for the other side.
+func (r *postgresReasonRepo) Export(ctx context.Context) ([]*domain.ReasonExportRow, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`
+        SELECT t."Id", t."Name", t."Class", rt."Id", rt."Label",
+               COUNT(*) FILTER (WHERE sides."Won"),
+               COUNT(*) FILTER (WHERE NOT sides."Won")
+        FROM (
+            SELECT rr."TagId", res."Winner" AS "TorroId", TRUE AS "Won"
+            FROM "ResultReasons" rr
+            JOIN "Results" res ON res."Id" = rr."ResultId"
+            UNION ALL
+            SELECT rr."TagId",
+                   CASE WHEN res."Winner" = p."Torro1" THEN p."Torro2" ELSE p."Torro1" END,
+                   FALSE
+            FROM "ResultReasons" rr
+            JOIN "Results" res ON res."Id" = rr."ResultId"
+            JOIN "Pairings" p ON p."Id" = res."Pairing"
+        ) sides
+        JOIN "Torrons" t ON t."Id" = sides."TorroId"
+        JOIN "ReasonTags" rt ON rt."Id" = sides."TagId"
+        GROUP BY t."Id", t."Name", t."Class", rt."Id", rt."Label", rt."Position"
+        ORDER BY t."Name", rt."Position"`,
+	)
+	if err != nil {
+		return nil, handleErrors(err)
+	}
+
+	defer rows.Close()
+	var export []*domain.ReasonExportRow
+
+	for rows.Next() {
+		row := &domain.ReasonExportRow{}
+		if err := rows.Scan(
+			&row.TorroId,
+			&row.TorroName,
+			&row.ClassId,
+			&row.TagId,
+			&row.TagLabel,
+			&row.Wins,
+			&row.Losses,
+		); err != nil {
+			return nil, handleErrors(err)
+		}
+		export = append(export, row)
+	}
+
+	return export, nil
+}
@@ -0,0 +1,307 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+// replayBatchSize bounds how many rows each UPDATE/INSERT ... UNNEST in
+// Commit carries, keeping a full-history rewrite to a few hundred
+// statements instead of one per Result.
+const replayBatchSize = 1000
+
+type postgresReplayRepo struct {
+	db *sql.DB
+}
+
+func NewReplayRepo(db *sql.DB) domain.ReplayRepo {
+	return &postgresReplayRepo{
+		db: db,
+	}
+}
+
+// ListVotes orders by Seq, the order the live votes moved the ratings in,
+// so the duels of one ranking vote replay in the order they were applied
+// even when they share a Timestamp. Only the current
+// rating season's votes are read: the live ratings start from scratch with
+// each season (see CampaignRatingRepo.Rollover).
+func (r *postgresReplayRepo) ListVotes(ctx context.Context) ([]*domain.ReplayVote, error) {
+	return listReplayVotes(ctx, r.db)
+}
+
+func (r *postgresReplayRepo) Commit(ctx context.Context, replay *domain.Replay) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return handleErrors(err)
+	}
+	defer tx.Rollback()
+
+	// Block new votes for the rest of the transaction, then make sure the
+	// season's Results are still exactly the ones replayed: a vote the
+	// replay never saw would otherwise keep a rating computed under the old
+	// rules. Comparing the Ids, not just the count, also catches an undo
+	// followed by a new vote.
+	if err := lockReplayTables(ctx, tx); err != nil {
+		return err
+	}
+	ids := make([]string, len(replay.Results))
+	for i, res := range replay.Results {
+		ids[i] = res.ResultId
+	}
+	var count, unseen int
+	if err := tx.QueryRowContext(ctx,
+		`
+        SELECT COUNT(*), COUNT(*) FILTER (WHERE NOT res."Id" = ANY($1))
+        FROM "Results" res
+        WHERE `+inSeason,
+		pq.Array(ids),
+	).Scan(&count, &unseen); err != nil {
+		return handleErrors(err)
+	}
+	if count != len(replay.Results) || unseen > 0 {
+		return fmt.Errorf("%s: Results changed since the replay was computed (%d rows now, %d of them not replayed, %d replayed)",
+			domain.ValidationError, count, unseen, len(replay.Results))
+	}
+
+	if err := commitReplay(ctx, tx, replay); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return handleErrors(err)
+	}
+
+	return nil
+}
+
+func (r *postgresReplayRepo) Rebuild(ctx context.Context, compute func([]*domain.ReplayVote) *domain.Replay) (*domain.Replay, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, handleErrors(err)
+	}
+	defer tx.Rollback()
+
+	if err := lockReplayTables(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	votes, err := listReplayVotes(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	replay := compute(votes)
+	if err := commitReplay(ctx, tx, replay); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, handleErrors(err)
+	}
+
+	return replay, nil
+}
+
+// lockReplayTables holds off votes until the transaction ends. "Torrons"
+// goes first: the vote handler locks its two torró rows before inserting
+// into "Results", so taking the locks in the same order means a replay
+// waits for in-flight votes instead of deadlocking with them.
+func lockReplayTables(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.ExecContext(ctx, `LOCK TABLE "Torrons" IN EXCLUSIVE MODE`); err != nil {
+		return handleErrors(err)
+	}
+	if _, err := tx.ExecContext(ctx, `LOCK TABLE "Results" IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+		return handleErrors(err)
+	}
+	return nil
+}
+
+// replayQuerier is the part of *sql.DB and *sql.Tx listReplayVotes needs.
+type replayQuerier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+func listReplayVotes(ctx context.Context, q replayQuerier) ([]*domain.ReplayVote, error) {
+	rows, err := q.QueryContext(ctx,
+		`
+        SELECT res."Id", p."Torro1", p."Torro2", res."Winner", res."Outcome",
+               res."UserId", res."CampaignId", res."Timestamp",
+               COALESCE(u."Quarantined", FALSE), res."Weight"
+        FROM "Results" res
+        JOIN "Pairings" p ON p."Id" = res."Pairing"
+        LEFT JOIN "Users" u ON u."Id" = res."UserId"
+        WHERE `+inSeason+`
+        ORDER BY res."Seq" ASC`,
+	)
+	if err != nil {
+		return nil, handleErrors(err)
+	}
+
+	defer rows.Close()
+	var votes []*domain.ReplayVote
+
+	for rows.Next() {
+		vote := &domain.ReplayVote{}
+		if err := rows.Scan(
+			&vote.ResultId,
+			&vote.Torro1,
+			&vote.Torro2,
+			&vote.Winner,
+			&vote.Outcome,
+			&vote.UserId,
+			&vote.CampaignId,
+			&vote.Timestamp,
+			&vote.Quarantined,
+			&vote.Weight,
+		); err != nil {
+			return nil, handleErrors(err)
+		}
+		votes = append(votes, vote)
+	}
+
+	return votes, nil
+}
+
+func commitReplay(ctx context.Context, tx *sql.Tx, replay *domain.Replay) error {
+	if err := commitReplayTorrons(ctx, tx, replay.Torrons); err != nil {
+		return err
+	}
+	if err := commitReplayResults(ctx, tx, replay.Results); err != nil {
+		return err
+	}
+	if err := commitReplaySnapshots(ctx, tx, replay.Snapshots); err != nil {
+		return err
+	}
+
+	// A pending undo would restore the state from before the replay and
+	// take back a rating change computed under the old rules, so the
+	// pending undos go, as they do on a season rollover.
+	if _, err := tx.ExecContext(ctx, `DELETE FROM "VoteUndos"`); err != nil {
+		return handleErrors(err)
+	}
+	return nil
+}
+
+func commitReplayTorrons(ctx context.Context, tx *sql.Tx, torrons map[string]domain.RatingState) error {
+	ids := make([]string, 0, len(torrons))
+	ratings := make([]float64, 0, len(torrons))
+	deviations := make([]float64, 0, len(torrons))
+	volatilities := make([]float64, 0, len(torrons))
+	for id, state := range torrons {
+		ids = append(ids, id)
+		ratings = append(ratings, state.Rating)
+		deviations = append(deviations, state.Deviation)
+		volatilities = append(volatilities, state.Volatility)
+	}
+
+	_, err := tx.ExecContext(ctx,
+		`
+        UPDATE "Torrons" t
+        SET "Rating" = u.rating, "RatingDeviation" = u.deviation, "RatingVolatility" = u.volatility
+        FROM UNNEST($1::varchar[], $2::numeric[], $3::numeric[], $4::numeric[])
+             AS u(id, rating, deviation, volatility)
+        WHERE t."Id" = u.id`,
+		pq.Array(ids),
+		pq.Array(ratings),
+		pq.Array(deviations),
+		pq.Array(volatilities),
+	)
+	if err != nil {
+		return handleErrors(err)
+	}
+
+	return nil
+}
+
+func commitReplayResults(ctx context.Context, tx *sql.Tx, results []domain.ReplayedResult) error {
+	for start := 0; start < len(results); start += replayBatchSize {
+		batch := results[start:min(start+replayBatchSize, len(results))]
+
+		ids := make([]string, len(batch))
+		rat1Bef := make([]float64, len(batch))
+		rat2Bef := make([]float64, len(batch))
+		rat1Aft := make([]float64, len(batch))
+		rat2Aft := make([]float64, len(batch))
+		for i, res := range batch {
+			ids[i] = res.ResultId
+			rat1Bef[i], rat2Bef[i] = res.Rat1Bef, res.Rat2Bef
+			rat1Aft[i], rat2Aft[i] = res.Rat1Aft, res.Rat2Aft
+		}
+
+		_, err := tx.ExecContext(ctx,
+			`
+            UPDATE "Results" res
+            SET "Torro1RatingBefore" = u.rat1_bef, "Torro2RatingBefore" = u.rat2_bef,
+                "Torro1RatingAfter" = u.rat1_aft, "Torro2RatingAfter" = u.rat2_aft
+            FROM UNNEST($1::varchar[], $2::numeric[], $3::numeric[], $4::numeric[], $5::numeric[])
+                 AS u(id, rat1_bef, rat2_bef, rat1_aft, rat2_aft)
+            WHERE res."Id" = u.id`,
+			pq.Array(ids),
+			pq.Array(rat1Bef),
+			pq.Array(rat2Bef),
+			pq.Array(rat1Aft),
+			pq.Array(rat2Aft),
+		)
+		if err != nil {
+			return handleErrors(err)
+		}
+	}
+
+	return nil
+}
+
+// commitReplaySnapshots rebuilds UserEloSnapshots from scratch: a snapshot
+// only ever exists because its user voted on that torró, so the replayed
+// set is the complete table, and snapshots fed solely by excluded votes go.
+func commitReplaySnapshots(ctx context.Context, tx *sql.Tx, snapshots []*domain.UserEloSnapshot) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM "UserEloSnapshots"`); err != nil {
+		return handleErrors(err)
+	}
+
+	for start := 0; start < len(snapshots); start += replayBatchSize {
+		batch := snapshots[start:min(start+replayBatchSize, len(snapshots))]
+
+		ids := make([]string, len(batch))
+		userIds := make([]string, len(batch))
+		torronIds := make([]string, len(batch))
+		ratings := make([]float64, len(batch))
+		voteCounts := make([]int64, len(batch))
+		lastUpdated := make([]string, len(batch))
+		deviations := make([]float64, len(batch))
+		volatilities := make([]float64, len(batch))
+		for i, s := range batch {
+			ids[i] = uuid.NewString()
+			userIds[i], torronIds[i] = s.UserId, s.TorronId
+			ratings[i], voteCounts[i] = s.Rating, int64(s.VoteCount)
+			lastUpdated[i] = s.LastUpdated
+			deviations[i], volatilities[i] = s.RatingDeviation, s.RatingVolatility
+		}
+
+		_, err := tx.ExecContext(ctx,
+			`
+            INSERT INTO "UserEloSnapshots" ("Id", "UserId", "TorronId", "Rating", "VoteCount", "LastUpdated",
+                                            "RatingDeviation", "RatingVolatility")
+            SELECT * FROM UNNEST($1::varchar[], $2::varchar[], $3::varchar[], $4::numeric[], $5::int[],
+                                 $6::timestamp[], $7::numeric[], $8::numeric[])`,
+			pq.Array(ids),
+			pq.Array(userIds),
+			pq.Array(torronIds),
+			pq.Array(ratings),
+			pq.Array(voteCounts),
+			pq.Array(lastUpdated),
+			pq.Array(deviations),
+			pq.Array(volatilities),
+		)
+		if err != nil {
+			return handleErrors(err)
+		}
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"fmt"
 
 	"github.com/google/uuid"
 
@@ -22,13 +23,20 @@ func NewResultRepo(db *sql.DB) domain.ResultRepo {
 func (r *postgresResultRepo) Create(ctx context.Context, result *domain.Result) (
 	*domain.Result, error,
 ) {
-	err := r.db.QueryRowContext(ctx,
+	result.Outcome = resultOutcome(result)
+	weight, err := resultWeight(result)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.QueryRowContext(ctx,
 		`
         INSERT INTO "Results"
         ("Id", "Pairing", "Torro1RatingBefore", "Torro2RatingBefore",
-        "Winner", "Torro1RatingAfter", "Torro2RatingAfter", "UserId", "CampaignId")
+        "Winner", "Torro1RatingAfter", "Torro2RatingAfter", "UserId", "CampaignId",
+        "Outcome", "SubmissionId", "Weight")
         VALUES
-        ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+        ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
         RETURNING "Id"`,
 		uuid.NewString(),
 		result.Pairing,
@@ -39,6 +47,9 @@ func (r *postgresResultRepo) Create(ctx context.Context, result *domain.Result)
 		result.Rat2Aft,
 		result.UserId,
 		result.CampaignId,
+		result.Outcome,
+		result.SubmissionId,
+		weight,
 	).Scan(&result.Id)
 	if err != nil {
 		return nil, handleErrors(err)
@@ -47,18 +58,82 @@ func (r *postgresResultRepo) Create(ctx context.Context, result *domain.Result)
 	return result, nil
 }
 
+func (r *postgresResultRepo) ListRatingHistory(
+	ctx context.Context,
+	torroId string,
+	filter domain.RatingHistoryFilter,
+) ([]*domain.RatingHistoryPoint, error) {
+	campaignId := ""
+	if filter.CampaignId != nil {
+		campaignId = *filter.CampaignId
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		`
+        WITH votes AS (
+            SELECT res."Timestamp", res."Seq",
+                   CASE WHEN p."Torro1" = $1 THEN res."Torro1RatingBefore" ELSE res."Torro2RatingBefore" END AS "Before",
+                   CASE WHEN p."Torro1" = $1 THEN res."Torro1RatingAfter" ELSE res."Torro2RatingAfter" END AS "After"
+            FROM "Results" res
+            JOIN "Pairings" p ON p."Id" = res."Pairing"
+            WHERE (p."Torro1" = $1 OR p."Torro2" = $1)
+              AND ($3 = '' OR res."CampaignId" = $3)
+        )
+        SELECT date_trunc($2, "Timestamp") AS "Bucket",
+               (ARRAY_AGG("Before" ORDER BY "Seq"))[1],
+               (ARRAY_AGG("After" ORDER BY "Seq" DESC))[1],
+               COUNT(*)
+        FROM votes
+        GROUP BY "Bucket"
+        ORDER BY "Bucket"`,
+		torroId,
+		filter.Bucket,
+		campaignId,
+	)
+	if err != nil {
+		return nil, handleErrors(err)
+	}
+	defer rows.Close()
+
+	var points []*domain.RatingHistoryPoint
+	for rows.Next() {
+		p := &domain.RatingHistoryPoint{}
+		if err := rows.Scan(&p.Time, &p.Open, &p.Rating, &p.Votes); err != nil {
+			return nil, handleErrors(err)
+		}
+		points = append(points, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, handleErrors(err)
+	}
+
+	return points, nil
+}
+
 // Transaction method
 
 func (r *postgresResultRepo) CreateTx(tx *sql.Tx, ctx context.Context, result *domain.Result) (
 	*domain.Result, error,
 ) {
-	err := tx.QueryRowContext(ctx,
+	result.Outcome = resultOutcome(result)
+	weight, err := resultWeight(result)
+	if err != nil {
+		return nil, err
+	}
+
+	// The duels of one ranking vote share a transaction, so NOW() would
+	// stamp them all alike; clock_timestamp() gives each its own moment.
+	// The replay and the rating history go by "Seq", not "Timestamp", for
+	// the order they were applied in.
+	err = tx.QueryRowContext(ctx,
 		`
         INSERT INTO "Results"
         ("Id", "Pairing", "Torro1RatingBefore", "Torro2RatingBefore",
-        "Winner", "Torro1RatingAfter", "Torro2RatingAfter", "UserId", "CampaignId")
+        "Winner", "Torro1RatingAfter", "Torro2RatingAfter", "UserId", "CampaignId",
+        "Outcome", "SubmissionId", "Weight", "Timestamp")
         VALUES
-        ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+        ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12,
+        CASE WHEN $11::VARCHAR IS NULL THEN NOW() ELSE clock_timestamp() END)
         RETURNING "Id"`,
 		uuid.NewString(),
 		result.Pairing,
@@ -69,6 +144,9 @@ func (r *postgresResultRepo) CreateTx(tx *sql.Tx, ctx context.Context, result *d
 		result.Rat2Aft,
 		result.UserId,
 		result.CampaignId,
+		result.Outcome,
+		result.SubmissionId,
+		weight,
 	).Scan(&result.Id)
 	if err != nil {
 		return nil, handleErrors(err)
@@ -76,3 +154,27 @@ func (r *postgresResultRepo) CreateTx(tx *sql.Tx, ctx context.Context, result *d
 
 	return result, nil
 }
+
+// resultOutcome defaults an unset Outcome from Winner, so callers that only
+// ever record decided duels don't have to spell out ResultOutcomeWin.
+func resultOutcome(result *domain.Result) string {
+	if result.Outcome != "" {
+		return result.Outcome
+	}
+	if result.Winner == nil {
+		return domain.ResultOutcomeDraw
+	}
+	return domain.ResultOutcomeWin
+}
+
+// resultWeight defaults an unset Weight to a full vote and refuses one
+// outside 0 to 1, which no replay could reproduce.
+func resultWeight(result *domain.Result) (float64, error) {
+	if result.Weight == nil {
+		return 1, nil
+	}
+	if w := *result.Weight; w < 0 || w > 1 {
+		return 0, fmt.Errorf("%s: vote weight %v is outside 0 to 1", domain.ValidationError, w)
+	}
+	return *result.Weight, nil
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+type postgresSimilarityRepo struct {
+	db *sql.DB
+}
+
+func NewSimilarityRepo(db *sql.DB) domain.SimilarityRepo {
+	return &postgresSimilarityRepo{
+		db: db,
+	}
+}
+
+func (r *postgresSimilarityRepo) ListRatings(ctx context.Context) ([]*domain.UserEloSnapshot, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`
+        SELECT s."UserId", s."TorronId", s."Rating"
+        FROM "UserEloSnapshots" s
+        JOIN "Users" u ON u."Id" = s."UserId"
+        WHERE NOT u."Quarantined"
+          AND s."UserId" IN (
+              SELECT "UserId" FROM "UserEloSnapshots"
+              GROUP BY "UserId"
+              HAVING COUNT(*) >= 2
+          )
+        ORDER BY s."UserId"`,
+	)
+	if err != nil {
+		return nil, handleErrors(err)
+	}
+
+	defer rows.Close()
+	var snapshots []*domain.UserEloSnapshot
+
+	for rows.Next() {
+		s := &domain.UserEloSnapshot{}
+		if err := rows.Scan(
+			&s.UserId,
+			&s.TorronId,
+			&s.Rating,
+		); err != nil {
+			return nil, handleErrors(err)
+		}
+		snapshots = append(snapshots, s)
+	}
+
+	return snapshots, nil
+}
+
+func (r *postgresSimilarityRepo) List(ctx context.Context) ([]*domain.TorroSimilarity, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`
+        SELECT "Torro1", "Torro2", "Similarity", "CoRaters", "FittedAt"
+        FROM "TorroSimilarities"`,
+	)
+	if err != nil {
+		return nil, handleErrors(err)
+	}
+
+	defer rows.Close()
+	var similarities []*domain.TorroSimilarity
+
+	for rows.Next() {
+		s := &domain.TorroSimilarity{}
+		if err := rows.Scan(
+			&s.Torro1,
+			&s.Torro2,
+			&s.Similarity,
+			&s.CoRaters,
+			&s.FittedAt,
+		); err != nil {
+			return nil, handleErrors(err)
+		}
+		similarities = append(similarities, s)
+	}
+
+	return similarities, nil
+}
+
+func (r *postgresSimilarityRepo) Replace(ctx context.Context, similarities []*domain.TorroSimilarity) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return handleErrors(err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM "TorroSimilarities"`); err != nil {
+		return handleErrors(err)
+	}
+
+	torrons1 := make([]string, len(similarities))
+	torrons2 := make([]string, len(similarities))
+	values := make([]float64, len(similarities))
+	coRaters := make([]int64, len(similarities))
+	fittedAt := make([]string, len(similarities))
+	for i, s := range similarities {
+		torrons1[i], torrons2[i] = s.Torro1, s.Torro2
+		values[i], coRaters[i] = s.Similarity, int64(s.CoRaters)
+		fittedAt[i] = s.FittedAt.UTC().Format("2006-01-02 15:04:05")
+	}
+
+	// Torrons deleted since the snapshots were read would fail the foreign
+	// keys, so only pairs of torrons that still exist are kept.
+	_, err = tx.ExecContext(ctx,
+		`
+        INSERT INTO "TorroSimilarities" ("Torro1", "Torro2", "Similarity", "CoRaters", "FittedAt")
+        SELECT s.* FROM UNNEST($1::varchar[], $2::varchar[], $3::numeric[], $4::int[], $5::timestamp[])
+            AS s("Torro1", "Torro2", "Similarity", "CoRaters", "FittedAt")
+        WHERE EXISTS (SELECT 1 FROM "Torrons" t WHERE t."Id" = s."Torro1")
+          AND EXISTS (SELECT 1 FROM "Torrons" t WHERE t."Id" = s."Torro2")`,
+		pq.Array(torrons1),
+		pq.Array(torrons2),
+		pq.Array(values),
+		pq.Array(coRaters),
+		pq.Array(fittedAt),
+	)
+	if err != nil {
+		return handleErrors(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return handleErrors(err)
+	}
+
+	return nil
+}
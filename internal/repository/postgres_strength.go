@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+type postgresStrengthRepo struct {
+	db *sql.DB
+}
+
+func NewStrengthRepo(db *sql.DB) domain.StrengthRepo {
+	return &postgresStrengthRepo{
+		db: db,
+	}
+}
+
+func (r *postgresStrengthRepo) ListPairRecords(ctx context.Context) ([]*domain.PairRecord, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`
+        SELECT p."Torro1", p."Torro2",
+               COUNT(*) FILTER (WHERE res."Winner" = p."Torro1"),
+               COUNT(*) FILTER (WHERE res."Winner" = p."Torro2"),
+               COUNT(*) FILTER (WHERE res."Winner" IS NULL)
+        FROM "Results" res
+        JOIN "Pairings" p ON p."Id" = res."Pairing"
+        LEFT JOIN "Users" u ON u."Id" = res."UserId"
+        WHERE NOT COALESCE(u."Quarantined", FALSE)
+          AND `+inSeason+`
+        GROUP BY p."Torro1", p."Torro2"`,
+	)
+	if err != nil {
+		return nil, handleErrors(err)
+	}
+
+	defer rows.Close()
+	var records []*domain.PairRecord
+
+	for rows.Next() {
+		record := &domain.PairRecord{}
+		if err := rows.Scan(
+			&record.Torro1,
+			&record.Torro2,
+			&record.Wins1,
+			&record.Wins2,
+			&record.Draws,
+		); err != nil {
+			return nil, handleErrors(err)
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+func (r *postgresStrengthRepo) List(ctx context.Context) ([]*domain.TorroStrength, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`
+        SELECT s."TorroId", t."Class", s."Strength", s."Lower", s."Upper", s."StdError",
+               s."Comparisons", s."Rank", s."ClassRank", s."SeparableFromNext",
+               s."SeparableFromNextInClass", s."FittedAt"
+        FROM "TorroStrengths" s
+        JOIN "Torrons" t ON t."Id" = s."TorroId"
+        ORDER BY s."Strength" DESC`,
+	)
+	if err != nil {
+		return nil, handleErrors(err)
+	}
+
+	defer rows.Close()
+	var strengths []*domain.TorroStrength
+
+	for rows.Next() {
+		s := &domain.TorroStrength{}
+		if err := rows.Scan(
+			&s.TorroId,
+			&s.ClassId,
+			&s.Strength,
+			&s.Lower,
+			&s.Upper,
+			&s.StdError,
+			&s.Comparisons,
+			&s.Rank,
+			&s.ClassRank,
+			&s.SeparableFromNext,
+			&s.SeparableFromNextInClass,
+			&s.FittedAt,
+		); err != nil {
+			return nil, handleErrors(err)
+		}
+		strengths = append(strengths, s)
+	}
+
+	return strengths, nil
+}
+
+func (r *postgresStrengthRepo) Replace(ctx context.Context, strengths []*domain.TorroStrength) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return handleErrors(err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM "TorroStrengths"`); err != nil {
+		return handleErrors(err)
+	}
+
+	ids := make([]string, len(strengths))
+	values := make([]float64, len(strengths))
+	lowers := make([]float64, len(strengths))
+	uppers := make([]float64, len(strengths))
+	stdErrors := make([]float64, len(strengths))
+	comparisons := make([]int64, len(strengths))
+	ranks := make([]int64, len(strengths))
+	classRanks := make([]int64, len(strengths))
+	separable := make([]bool, len(strengths))
+	separableInClass := make([]bool, len(strengths))
+	fittedAt := make([]string, len(strengths))
+	for i, s := range strengths {
+		ids[i] = s.TorroId
+		values[i], lowers[i], uppers[i], stdErrors[i] = s.Strength, s.Lower, s.Upper, s.StdError
+		comparisons[i], ranks[i], classRanks[i] = int64(s.Comparisons), int64(s.Rank), int64(s.ClassRank)
+		separable[i], separableInClass[i] = s.SeparableFromNext, s.SeparableFromNextInClass
+		fittedAt[i] = s.FittedAt.UTC().Format("2006-01-02 15:04:05")
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`
+        INSERT INTO "TorroStrengths" ("TorroId", "Strength", "Lower", "Upper", "StdError",
+                                      "Comparisons", "Rank", "ClassRank", "SeparableFromNext",
+                                      "SeparableFromNextInClass", "FittedAt")
+        SELECT * FROM UNNEST($1::varchar[], $2::numeric[], $3::numeric[], $4::numeric[], $5::numeric[],
+                             $6::int[], $7::int[], $8::int[], $9::boolean[], $10::boolean[],
+                             $11::timestamp[])`,
+		pq.Array(ids),
+		pq.Array(values),
+		pq.Array(lowers),
+		pq.Array(uppers),
+		pq.Array(stdErrors),
+		pq.Array(comparisons),
+		pq.Array(ranks),
+		pq.Array(classRanks),
+		pq.Array(separable),
+		pq.Array(separableInClass),
+		pq.Array(fittedAt),
+	)
+	if err != nil {
+		return handleErrors(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return handleErrors(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+type postgresTasteTwinRepo struct {
+	db *sql.DB
+}
+
+func NewTasteTwinRepo(db *sql.DB) domain.TasteTwinRepo {
+	return &postgresTasteTwinRepo{
+		db: db,
+	}
+}
+
+func (r *postgresTasteTwinRepo) ListOverlapping(
+	ctx context.Context,
+	userId string,
+	minOverlap int,
+) ([]*domain.UserEloSnapshot, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`
+        WITH overlapping AS (
+            SELECT other."UserId"
+            FROM "UserEloSnapshots" mine
+            JOIN "UserEloSnapshots" other
+              ON other."TorronId" = mine."TorronId" AND other."UserId" <> mine."UserId"
+            JOIN "Users" u ON u."Id" = other."UserId"
+            WHERE mine."UserId" = $1
+              AND NOT u."Quarantined"
+            GROUP BY other."UserId"
+            HAVING COUNT(*) >= $2
+        )
+        SELECT s."UserId", s."TorronId", s."Rating"
+        FROM "UserEloSnapshots" s
+        JOIN overlapping o ON o."UserId" = s."UserId"
+        ORDER BY s."UserId"`,
+		userId,
+		minOverlap,
+	)
+	if err != nil {
+		return nil, handleErrors(err)
+	}
+
+	defer rows.Close()
+	var snapshots []*domain.UserEloSnapshot
+
+	for rows.Next() {
+		s := &domain.UserEloSnapshot{}
+		if err := rows.Scan(
+			&s.UserId,
+			&s.TorronId,
+			&s.Rating,
+		); err != nil {
+			return nil, handleErrors(err)
+		}
+		snapshots = append(snapshots, s)
+	}
+
+	return snapshots, nil
+}
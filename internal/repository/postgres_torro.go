@@ -23,7 +23,7 @@ func NewTorroRepo(db *sql.DB) domain.TorroRepo {
 func (r *postgresTorroRepo) List(ctx context.Context) ([]*domain.Torro, error) {
 	rows, err := r.db.QueryContext(ctx,
 		`
-        SELECT "Id", "Name", "Rating", "Image", "Class"
+        SELECT "Id", "Name", "Rating", "Image", "Class", "Discontinued"
         FROM "Torrons"`,
 	)
 	if err != nil {
@@ -41,6 +41,7 @@ func (r *postgresTorroRepo) List(ctx context.Context) ([]*domain.Torro, error) {
 			&torro.Rating,
 			&torro.Image,
 			&torro.Class,
+			&torro.Discontinued,
 		); err != nil {
 			return nil, handleErrors(err)
 		}
@@ -89,7 +90,8 @@ func (r *postgresTorroRepo) Get(ctx context.Context, id string) (*domain.Torro,
                "Description", "Weight", "Price", "ProductUrl",
                "Allergens", "MainIngredients",
                "IsVegan", "IsGlutenFree", "IsLactoseFree", "IsOrganic",
-               "IntensityLevel", "IsNew2025", "Discontinued", "YearAdded"
+               "IntensityLevel", "IsNew2025", "Discontinued", "YearAdded",
+               "RatingDeviation", "RatingVolatility"
         FROM "Torrons"
         WHERE "Id" = $1`,
 		id,
@@ -116,6 +118,8 @@ func (r *postgresTorroRepo) Get(ctx context.Context, id string) (*domain.Torro,
 		&torro.IsNew2025,
 		&torro.Discontinued,
 		&torro.YearAdded,
+		&torro.RatingDeviation,
+		&torro.RatingVolatility,
 	)
 	if err != nil {
 		return nil, handleErrors(err)
@@ -226,7 +230,7 @@ func (r *postgresTorroRepo) TopNByClass(ctx context.Context, classId string, n i
 	return torrons, nil
 }
 
-func (r *postgresTorroRepo) Update(ctx context.Context, id string, rating float64) (
+func (r *postgresTorroRepo) Update(ctx context.Context, id string, state domain.RatingState) (
 	*domain.Torro, error,
 ) {
 	updatedTorro := &domain.Torro{}
@@ -234,15 +238,20 @@ func (r *postgresTorroRepo) Update(ctx context.Context, id string, rating float6
 	err := r.db.QueryRowContext(ctx,
 		`
         UPDATE "Torrons" SET
-        "Rating" = $2
+        "Rating" = $2,
+        "RatingDeviation" = $3,
+        "RatingVolatility" = $4
         WHERE "Id" = $1
         RETURNING "Id", "Name", "Rating", "Image", "Class",
                   "Description", "Weight", "Price", "ProductUrl",
                   "Allergens", "MainIngredients",
                   "IsVegan", "IsGlutenFree", "IsLactoseFree", "IsOrganic",
-                  "IntensityLevel", "IsNew2025", "Discontinued", "YearAdded"`,
+                  "IntensityLevel", "IsNew2025", "Discontinued", "YearAdded",
+                  "RatingDeviation", "RatingVolatility"`,
 		id,
-		rating,
+		state.Rating,
+		state.Deviation,
+		state.Volatility,
 	).Scan(
 		&updatedTorro.Id,
 		&updatedTorro.Name,
@@ -263,6 +272,8 @@ func (r *postgresTorroRepo) Update(ctx context.Context, id string, rating float6
 		&updatedTorro.IsNew2025,
 		&updatedTorro.Discontinued,
 		&updatedTorro.YearAdded,
+		&updatedTorro.RatingDeviation,
+		&updatedTorro.RatingVolatility,
 	)
 	if err != nil {
 		return nil, handleErrors(err)
@@ -281,7 +292,8 @@ func (r *postgresTorroRepo) Update(ctx context.Context, id string, rating float6
 func (r *postgresTorroRepo) GetTx(tx *sql.Tx, ctx context.Context, id string) (*domain.Torro, error) {
 	row := tx.QueryRowContext(ctx,
 		`
-        SELECT "Id", "Name", "Rating", "Image", "Class"
+        SELECT "Id", "Name", "Rating", "Image", "Class",
+               "RatingDeviation", "RatingVolatility"
         FROM "Torrons"
         WHERE "Id" = $1
         FOR UPDATE`,
@@ -295,6 +307,8 @@ func (r *postgresTorroRepo) GetTx(tx *sql.Tx, ctx context.Context, id string) (*
 		&torro.Rating,
 		&torro.Image,
 		&torro.Class,
+		&torro.RatingDeviation,
+		&torro.RatingVolatility,
 	)
 	if err != nil {
 		return nil, handleErrors(err)
@@ -302,21 +316,26 @@ func (r *postgresTorroRepo) GetTx(tx *sql.Tx, ctx context.Context, id string) (*
 	return torro, nil
 }
 
-func (r *postgresTorroRepo) UpdateTx(tx *sql.Tx, ctx context.Context, id string, rating float64) (*domain.Torro, error) {
+func (r *postgresTorroRepo) UpdateTx(tx *sql.Tx, ctx context.Context, id string, state domain.RatingState) (*domain.Torro, error) {
 	updatedTorro := &domain.Torro{}
 
 	err := tx.QueryRowContext(ctx,
 		`
         UPDATE "Torrons" SET
-        "Rating" = $2
+        "Rating" = $2,
+        "RatingDeviation" = $3,
+        "RatingVolatility" = $4
         WHERE "Id" = $1
         RETURNING "Id", "Name", "Rating", "Image", "Class",
                   "Description", "Weight", "Price", "ProductUrl",
                   "Allergens", "MainIngredients",
                   "IsVegan", "IsGlutenFree", "IsLactoseFree", "IsOrganic",
-                  "IntensityLevel", "IsNew2025", "Discontinued", "YearAdded"`,
+                  "IntensityLevel", "IsNew2025", "Discontinued", "YearAdded",
+                  "RatingDeviation", "RatingVolatility"`,
 		id,
-		rating,
+		state.Rating,
+		state.Deviation,
+		state.Volatility,
 	).Scan(
 		&updatedTorro.Id,
 		&updatedTorro.Name,
@@ -337,6 +356,8 @@ func (r *postgresTorroRepo) UpdateTx(tx *sql.Tx, ctx context.Context, id string,
 		&updatedTorro.IsNew2025,
 		&updatedTorro.Discontinued,
 		&updatedTorro.YearAdded,
+		&updatedTorro.RatingDeviation,
+		&updatedTorro.RatingVolatility,
 	)
 	if err != nil {
 		return nil, handleErrors(err)
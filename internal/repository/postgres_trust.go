@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+type postgresTrustRepo struct {
+	db *sql.DB
+}
+
+func NewTrustRepo(db *sql.DB) domain.TrustRepo {
+	return &postgresTrustRepo{
+		db: db,
+	}
+}
+
+// ListSignals reads agreement off the before ratings every Result records:
+// a vote agreed with the community when its winner was the higher-rated
+// torró going into it. Draws and duels between equally rated torrons say
+// nothing either way.
+func (r *postgresTrustRepo) ListSignals(ctx context.Context) ([]*domain.TrustSignals, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`
+        SELECT u."Id", u."FirstSeen", u."VoteCount", u."LongestStreak",
+               COUNT(res."Id") FILTER (
+                   WHERE res."Winner" IS NOT NULL
+                     AND res."Torro1RatingBefore" <> res."Torro2RatingBefore"),
+               COUNT(res."Id") FILTER (
+                   WHERE (res."Winner" = p."Torro1" AND res."Torro1RatingBefore" > res."Torro2RatingBefore")
+                      OR (res."Winner" = p."Torro2" AND res."Torro2RatingBefore" > res."Torro1RatingBefore"))
+        FROM "Users" u
+        LEFT JOIN "Results" res ON res."UserId" = u."Id"
+        LEFT JOIN "Pairings" p ON p."Id" = res."Pairing"
+        WHERE u."VoteCount" > 0
+        GROUP BY u."Id"`,
+	)
+	if err != nil {
+		return nil, handleErrors(err)
+	}
+
+	defer rows.Close()
+	var signals []*domain.TrustSignals
+
+	for rows.Next() {
+		s := &domain.TrustSignals{}
+		if err := rows.Scan(
+			&s.UserId,
+			&s.FirstSeen,
+			&s.Votes,
+			&s.LongestStreak,
+			&s.Comparable,
+			&s.Agreed,
+		); err != nil {
+			return nil, handleErrors(err)
+		}
+		signals = append(signals, s)
+	}
+
+	return signals, nil
+}
+
+func (r *postgresTrustRepo) SaveScores(ctx context.Context, scores map[string]float64) error {
+	if len(scores) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(scores))
+	values := make([]float64, 0, len(scores))
+	for id, score := range scores {
+		ids = append(ids, id)
+		values = append(values, score)
+	}
+
+	_, err := r.db.ExecContext(ctx,
+		`
+        UPDATE "Users" u
+        SET "TrustScore" = s.score, "TrustScoredAt" = NOW()
+        FROM unnest($1::VARCHAR[], $2::NUMERIC[]) AS s(id, score)
+        WHERE u."Id" = s.id`,
+		pq.Array(ids),
+		pq.Array(values),
+	)
+	return handleErrors(err)
+}
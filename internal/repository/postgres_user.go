@@ -25,7 +25,8 @@ func NewUserRepo(db *sql.DB) domain.UserRepo {
 func (r *postgresUserRepo) Get(ctx context.Context, id string) (*domain.User, error) {
 	row := r.db.QueryRowContext(ctx,
 		`SELECT "Id", "FirstSeen", "LastSeen", "VoteCount", "ClassVotes",
-		        "CurrentStreak", "LongestStreak", "LastVoteDate"
+		        "CurrentStreak", "LongestStreak", "LastVoteDate", "Quarantined",
+		        "TrustScore"
 		 FROM "Users"
 		 WHERE "Id" = $1`,
 		id,
@@ -41,6 +42,8 @@ func (r *postgresUserRepo) Get(ctx context.Context, id string) (*domain.User, er
 		&user.CurrentStreak,
 		&user.LongestStreak,
 		&user.LastVoteDate,
+		&user.Quarantined,
+		&user.TrustScore,
 	)
 	if err != nil {
 		return nil, handleErrors(err)
@@ -70,14 +73,15 @@ func (r *postgresUserRepo) Create(ctx context.Context, user *domain.User) (*doma
 	}
 
 	err := r.db.QueryRowContext(ctx,
-		`INSERT INTO "Users" ("Id", "FirstSeen", "LastSeen", "VoteCount", "ClassVotes")
-		 VALUES ($1, $2, $3, $4, $5)
+		`INSERT INTO "Users" ("Id", "FirstSeen", "LastSeen", "VoteCount", "ClassVotes", "IpHash")
+		 VALUES ($1, $2, $3, $4, $5, $6)
 		 RETURNING "Id"`,
 		user.Id,
 		user.FirstSeen,
 		user.LastSeen,
 		user.VoteCount,
 		user.ClassVotes,
+		user.IpHash,
 	).Scan(&user.Id)
 
 	if err != nil {
@@ -166,12 +170,16 @@ func (r *postgresUserRepo) UpdateLastSeen(ctx context.Context, userId string) er
 
 // Transaction methods
 
+// GetTx locks the user's row FOR UPDATE, so the streak it returns is the
+// one the rest of the vote transaction overwrites.
 func (r *postgresUserRepo) GetTx(tx *sql.Tx, ctx context.Context, id string) (*domain.User, error) {
 	row := tx.QueryRowContext(ctx,
 		`SELECT "Id", "FirstSeen", "LastSeen", "VoteCount", "ClassVotes",
-		        "CurrentStreak", "LongestStreak", "LastVoteDate"
+		        "CurrentStreak", "LongestStreak", "LastVoteDate", "Quarantined",
+		        "TrustScore"
 		 FROM "Users"
-		 WHERE "Id" = $1`,
+		 WHERE "Id" = $1
+		 FOR UPDATE`,
 		id,
 	)
 
@@ -185,6 +193,8 @@ func (r *postgresUserRepo) GetTx(tx *sql.Tx, ctx context.Context, id string) (*d
 		&user.CurrentStreak,
 		&user.LongestStreak,
 		&user.LastVoteDate,
+		&user.Quarantined,
+		&user.TrustScore,
 	)
 	if err != nil {
 		return nil, handleErrors(err)
@@ -22,7 +22,8 @@ func NewUserEloSnapshotRepo(db *sql.DB) domain.UserEloSnapshotRepo {
 
 func (r *postgresUserEloSnapshotRepo) Get(ctx context.Context, userId string, torronId string) (*domain.UserEloSnapshot, error) {
 	row := r.db.QueryRowContext(ctx,
-		`SELECT "Id", "UserId", "TorronId", "Rating", "VoteCount", "LastUpdated"
+		`SELECT "Id", "UserId", "TorronId", "Rating", "VoteCount", "LastUpdated",
+		        "RatingDeviation", "RatingVolatility"
 		 FROM "UserEloSnapshots"
 		 WHERE "UserId" = $1 AND "TorronId" = $2`,
 		userId,
@@ -37,6 +38,8 @@ func (r *postgresUserEloSnapshotRepo) Get(ctx context.Context, userId string, to
 		&snapshot.Rating,
 		&snapshot.VoteCount,
 		&snapshot.LastUpdated,
+		&snapshot.RatingDeviation,
+		&snapshot.RatingVolatility,
 	)
 	if err != nil {
 		return nil, handleErrors(err)
@@ -54,9 +57,15 @@ func (r *postgresUserEloSnapshotRepo) Create(ctx context.Context, snapshot *doma
 		snapshot.LastUpdated = time.Now().UTC().Format(time.RFC3339)
 	}
 
+	if snapshot.RatingDeviation == 0 {
+		snapshot.RatingDeviation = domain.DefaultRatingDeviation
+		snapshot.RatingVolatility = domain.DefaultRatingVolatility
+	}
+
 	err := r.db.QueryRowContext(ctx,
-		`INSERT INTO "UserEloSnapshots" ("Id", "UserId", "TorronId", "Rating", "VoteCount", "LastUpdated")
-		 VALUES ($1, $2, $3, $4, $5, $6)
+		`INSERT INTO "UserEloSnapshots" ("Id", "UserId", "TorronId", "Rating", "VoteCount", "LastUpdated",
+		                                 "RatingDeviation", "RatingVolatility")
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		 RETURNING "Id"`,
 		snapshot.Id,
 		snapshot.UserId,
@@ -64,6 +73,8 @@ func (r *postgresUserEloSnapshotRepo) Create(ctx context.Context, snapshot *doma
 		snapshot.Rating,
 		snapshot.VoteCount,
 		snapshot.LastUpdated,
+		snapshot.RatingDeviation,
+		snapshot.RatingVolatility,
 	).Scan(&snapshot.Id)
 
 	if err != nil {
@@ -78,13 +89,16 @@ func (r *postgresUserEloSnapshotRepo) Update(ctx context.Context, snapshot *doma
 
 	_, err := r.db.ExecContext(ctx,
 		`UPDATE "UserEloSnapshots"
-		 SET "Rating" = $3, "VoteCount" = $4, "LastUpdated" = $5
+		 SET "Rating" = $3, "VoteCount" = $4, "LastUpdated" = $5,
+		     "RatingDeviation" = $6, "RatingVolatility" = $7
 		 WHERE "UserId" = $1 AND "TorronId" = $2`,
 		snapshot.UserId,
 		snapshot.TorronId,
 		snapshot.Rating,
 		snapshot.VoteCount,
 		snapshot.LastUpdated,
+		snapshot.RatingDeviation,
+		snapshot.RatingVolatility,
 	)
 
 	if err != nil {
@@ -114,10 +128,12 @@ func (r *postgresUserEloSnapshotRepo) GetOrCreate(ctx context.Context, userId st
 
 	// Create new snapshot with global rating as baseline
 	newSnapshot := &domain.UserEloSnapshot{
-		UserId:    userId,
-		TorronId:  torronId,
-		Rating:    globalRating,
-		VoteCount: 0,
+		UserId:           userId,
+		TorronId:         torronId,
+		Rating:           globalRating,
+		VoteCount:        0,
+		RatingDeviation:  domain.DefaultRatingDeviation,
+		RatingVolatility: domain.DefaultRatingVolatility,
 	}
 
 	return r.Create(ctx, newSnapshot)
@@ -209,7 +225,8 @@ func (r *postgresUserEloSnapshotRepo) GetUserGlobalLeaderboard(ctx context.Conte
 
 func (r *postgresUserEloSnapshotRepo) ListByUser(ctx context.Context, userId string) ([]*domain.UserEloSnapshot, error) {
 	rows, err := r.db.QueryContext(ctx,
-		`SELECT "Id", "UserId", "TorronId", "Rating", "VoteCount", "LastUpdated"
+		`SELECT "Id", "UserId", "TorronId", "Rating", "VoteCount", "LastUpdated",
+		        "RatingDeviation", "RatingVolatility"
 		 FROM "UserEloSnapshots"
 		 WHERE "UserId" = $1
 		 ORDER BY "Rating" DESC`,
@@ -230,6 +247,8 @@ func (r *postgresUserEloSnapshotRepo) ListByUser(ctx context.Context, userId str
 			&snapshot.Rating,
 			&snapshot.VoteCount,
 			&snapshot.LastUpdated,
+			&snapshot.RatingDeviation,
+			&snapshot.RatingVolatility,
 		)
 		if err != nil {
 			return nil, handleErrors(err)
@@ -248,7 +267,8 @@ func (r *postgresUserEloSnapshotRepo) GetTx(tx *sql.Tx, ctx context.Context, use
 	// serialize their personalized read-modify-write instead of clobbering
 	// each other (same lost-update class as the global Torrons.Rating fix).
 	row := tx.QueryRowContext(ctx,
-		`SELECT "Id", "UserId", "TorronId", "Rating", "VoteCount", "LastUpdated"
+		`SELECT "Id", "UserId", "TorronId", "Rating", "VoteCount", "LastUpdated",
+		        "RatingDeviation", "RatingVolatility"
 		 FROM "UserEloSnapshots"
 		 WHERE "UserId" = $1 AND "TorronId" = $2
 		 FOR UPDATE`,
@@ -264,6 +284,8 @@ func (r *postgresUserEloSnapshotRepo) GetTx(tx *sql.Tx, ctx context.Context, use
 		&snapshot.Rating,
 		&snapshot.VoteCount,
 		&snapshot.LastUpdated,
+		&snapshot.RatingDeviation,
+		&snapshot.RatingVolatility,
 	)
 	if err != nil {
 		return nil, handleErrors(err)
@@ -291,18 +313,24 @@ func (r *postgresUserEloSnapshotRepo) GetOrCreateTx(tx *sql.Tx, ctx context.Cont
 	}
 
 	// Create new snapshot with global rating as baseline
+	// The personal view starts from the global rating as its baseline but
+	// with full uncertainty: the user hasn't told us anything about this
+	// torró yet, however settled the crowd's rating is.
 	newSnapshot := &domain.UserEloSnapshot{
-		Id:          uuid.NewString(),
-		UserId:      userId,
-		TorronId:    torronId,
-		Rating:      globalRating,
-		VoteCount:   0,
-		LastUpdated: time.Now().UTC().Format(time.RFC3339),
+		Id:               uuid.NewString(),
+		UserId:           userId,
+		TorronId:         torronId,
+		Rating:           globalRating,
+		VoteCount:        0,
+		LastUpdated:      time.Now().UTC().Format(time.RFC3339),
+		RatingDeviation:  domain.DefaultRatingDeviation,
+		RatingVolatility: domain.DefaultRatingVolatility,
 	}
 
 	err = tx.QueryRowContext(ctx,
-		`INSERT INTO "UserEloSnapshots" ("Id", "UserId", "TorronId", "Rating", "VoteCount", "LastUpdated")
-		 VALUES ($1, $2, $3, $4, $5, $6)
+		`INSERT INTO "UserEloSnapshots" ("Id", "UserId", "TorronId", "Rating", "VoteCount", "LastUpdated",
+		                                 "RatingDeviation", "RatingVolatility")
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		 RETURNING "Id"`,
 		newSnapshot.Id,
 		newSnapshot.UserId,
@@ -310,6 +338,8 @@ func (r *postgresUserEloSnapshotRepo) GetOrCreateTx(tx *sql.Tx, ctx context.Cont
 		newSnapshot.Rating,
 		newSnapshot.VoteCount,
 		newSnapshot.LastUpdated,
+		newSnapshot.RatingDeviation,
+		newSnapshot.RatingVolatility,
 	).Scan(&newSnapshot.Id)
 
 	if err != nil {
@@ -324,13 +354,16 @@ func (r *postgresUserEloSnapshotRepo) UpdateTx(tx *sql.Tx, ctx context.Context,
 
 	_, err := tx.ExecContext(ctx,
 		`UPDATE "UserEloSnapshots"
-		 SET "Rating" = $3, "VoteCount" = $4, "LastUpdated" = $5
+		 SET "Rating" = $3, "VoteCount" = $4, "LastUpdated" = $5,
+		     "RatingDeviation" = $6, "RatingVolatility" = $7
 		 WHERE "UserId" = $1 AND "TorronId" = $2`,
 		snapshot.UserId,
 		snapshot.TorronId,
 		snapshot.Rating,
 		snapshot.VoteCount,
 		snapshot.LastUpdated,
+		snapshot.RatingDeviation,
+		snapshot.RatingVolatility,
 	)
 
 	if err != nil {
@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+type postgresVoteUndoRepo struct {
+	db *sql.DB
+}
+
+func NewVoteUndoRepo(db *sql.DB) domain.VoteUndoRepo {
+	return &postgresVoteUndoRepo{
+		db: db,
+	}
+}
+
+const selectVoteUndo = `
+        SELECT v."UserId", v."ResultId", v."CastAt", v."ClassId", v."AdventDate",
+               res."Pairing", p."Torro1", p."Torro2",
+               res."Torro1RatingBefore", res."Torro2RatingBefore",
+               res."Torro1RatingAfter", res."Torro2RatingAfter",
+               v."Torro1Deviation", v."Torro1Volatility",
+               v."Torro2Deviation", v."Torro2Volatility",
+               v."Snapshot1Rating", v."Snapshot1Deviation", v."Snapshot1Volatility",
+               v."Snapshot1VoteCount", v."Snapshot1LastUpdated",
+               v."Snapshot2Rating", v."Snapshot2Deviation", v."Snapshot2Volatility",
+               v."Snapshot2VoteCount", v."Snapshot2LastUpdated",
+               v."PrevCurrentStreak", v."PrevLongestStreak", v."PrevLastVoteDate"
+        FROM "VoteUndos" v
+        JOIN "Results" res ON res."Id" = v."ResultId"
+        JOIN "Pairings" p ON p."Id" = res."Pairing"
+        WHERE v."UserId" = $1`
+
+func scanVoteUndo(row *sql.Row) (*domain.VoteUndo, error) {
+	u := &domain.VoteUndo{}
+	err := row.Scan(
+		&u.UserId,
+		&u.ResultId,
+		&u.CastAt,
+		&u.ClassId,
+		&u.AdventDate,
+		&u.PairingId,
+		&u.Torro1,
+		&u.Torro2,
+		&u.Rat1Bef,
+		&u.Rat2Bef,
+		&u.Rat1Aft,
+		&u.Rat2Aft,
+		&u.Torro1Before.Deviation,
+		&u.Torro1Before.Volatility,
+		&u.Torro2Before.Deviation,
+		&u.Torro2Before.Volatility,
+		&u.Snapshot1Before.Rating,
+		&u.Snapshot1Before.RatingDeviation,
+		&u.Snapshot1Before.RatingVolatility,
+		&u.Snapshot1Before.VoteCount,
+		&u.Snapshot1Before.LastUpdated,
+		&u.Snapshot2Before.Rating,
+		&u.Snapshot2Before.RatingDeviation,
+		&u.Snapshot2Before.RatingVolatility,
+		&u.Snapshot2Before.VoteCount,
+		&u.Snapshot2Before.LastUpdated,
+		&u.PrevCurrentStreak,
+		&u.PrevLongestStreak,
+		&u.PrevLastVoteDate,
+	)
+	if err != nil {
+		return nil, handleErrors(err)
+	}
+
+	u.Torro1Before.Rating, u.Torro2Before.Rating = u.Rat1Bef, u.Rat2Bef
+	u.Snapshot1Before.UserId, u.Snapshot1Before.TorronId = u.UserId, u.Torro1
+	u.Snapshot2Before.UserId, u.Snapshot2Before.TorronId = u.UserId, u.Torro2
+
+	return u, nil
+}
+
+func (r *postgresVoteUndoRepo) Get(ctx context.Context, userId string) (*domain.VoteUndo, error) {
+	return scanVoteUndo(r.db.QueryRowContext(ctx, selectVoteUndo, userId))
+}
+
+// Transaction methods
+
+func (r *postgresVoteUndoRepo) GetTx(tx *sql.Tx, ctx context.Context, userId string) (*domain.VoteUndo, error) {
+	return scanVoteUndo(tx.QueryRowContext(ctx, selectVoteUndo+` FOR UPDATE OF v`, userId))
+}
+
+func (r *postgresVoteUndoRepo) SaveTx(tx *sql.Tx, ctx context.Context, undo *domain.VoteUndo) error {
+	s1, s2 := undo.Snapshot1Before, undo.Snapshot2Before
+
+	_, err := tx.ExecContext(ctx,
+		`
+        INSERT INTO "VoteUndos" ("UserId", "ResultId", "CastAt", "ClassId", "AdventDate",
+                                 "PrevCurrentStreak", "PrevLongestStreak", "PrevLastVoteDate",
+                                 "Torro1Deviation", "Torro1Volatility", "Torro2Deviation", "Torro2Volatility",
+                                 "Snapshot1Rating", "Snapshot1Deviation", "Snapshot1Volatility",
+                                 "Snapshot1VoteCount", "Snapshot1LastUpdated",
+                                 "Snapshot2Rating", "Snapshot2Deviation", "Snapshot2Volatility",
+                                 "Snapshot2VoteCount", "Snapshot2LastUpdated")
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17,
+                $18, $19, $20, $21, $22)
+        ON CONFLICT ("UserId") DO UPDATE SET
+            "ResultId" = EXCLUDED."ResultId",
+            "CastAt" = EXCLUDED."CastAt",
+            "ClassId" = EXCLUDED."ClassId",
+            "AdventDate" = EXCLUDED."AdventDate",
+            "PrevCurrentStreak" = EXCLUDED."PrevCurrentStreak",
+            "PrevLongestStreak" = EXCLUDED."PrevLongestStreak",
+            "PrevLastVoteDate" = EXCLUDED."PrevLastVoteDate",
+            "Torro1Deviation" = EXCLUDED."Torro1Deviation",
+            "Torro1Volatility" = EXCLUDED."Torro1Volatility",
+            "Torro2Deviation" = EXCLUDED."Torro2Deviation",
+            "Torro2Volatility" = EXCLUDED."Torro2Volatility",
+            "Snapshot1Rating" = EXCLUDED."Snapshot1Rating",
+            "Snapshot1Deviation" = EXCLUDED."Snapshot1Deviation",
+            "Snapshot1Volatility" = EXCLUDED."Snapshot1Volatility",
+            "Snapshot1VoteCount" = EXCLUDED."Snapshot1VoteCount",
+            "Snapshot1LastUpdated" = EXCLUDED."Snapshot1LastUpdated",
+            "Snapshot2Rating" = EXCLUDED."Snapshot2Rating",
+            "Snapshot2Deviation" = EXCLUDED."Snapshot2Deviation",
+            "Snapshot2Volatility" = EXCLUDED."Snapshot2Volatility",
+            "Snapshot2VoteCount" = EXCLUDED."Snapshot2VoteCount",
+            "Snapshot2LastUpdated" = EXCLUDED."Snapshot2LastUpdated"`,
+		undo.UserId,
+		undo.ResultId,
+		undo.CastAt.UTC().Format("2006-01-02 15:04:05.999999"),
+		undo.ClassId,
+		undo.AdventDate,
+		undo.PrevCurrentStreak,
+		undo.PrevLongestStreak,
+		undo.PrevLastVoteDate,
+		undo.Torro1Before.Deviation,
+		undo.Torro1Before.Volatility,
+		undo.Torro2Before.Deviation,
+		undo.Torro2Before.Volatility,
+		s1.Rating,
+		s1.RatingDeviation,
+		s1.RatingVolatility,
+		s1.VoteCount,
+		s1.LastUpdated,
+		s2.Rating,
+		s2.RatingDeviation,
+		s2.RatingVolatility,
+		s2.VoteCount,
+		s2.LastUpdated,
+	)
+
+	return handleErrors(err)
+}
+
+func (r *postgresVoteUndoRepo) RevertTx(tx *sql.Tx, ctx context.Context, undo *domain.VoteUndo) error {
+	// The inverse of IncrementVoteCountTx, and the streak as it was before
+	// UpdateStreakTx ran.
+	_, err := tx.ExecContext(ctx,
+		`UPDATE "Users"
+		 SET "VoteCount" = GREATEST("VoteCount" - 1, 0),
+		     "ClassVotes" = jsonb_set("ClassVotes", ARRAY[$2],
+		         to_jsonb(GREATEST(COALESCE(CAST("ClassVotes"->>$2 AS INTEGER), 1) - 1, 0))),
+		     "CurrentStreak" = $3,
+		     "LongestStreak" = $4,
+		     "LastVoteDate" = $5
+		 WHERE "Id" = $1`,
+		undo.UserId,
+		undo.ClassId,
+		undo.PrevCurrentStreak,
+		undo.PrevLongestStreak,
+		undo.PrevLastVoteDate,
+	)
+	if err != nil {
+		return handleErrors(err)
+	}
+
+	for _, s := range []domain.UserEloSnapshot{undo.Snapshot1Before, undo.Snapshot2Before} {
+		if err := revertSnapshot(ctx, tx, s); err != nil {
+			return err
+		}
+	}
+
+	if undo.AdventDate != nil {
+		if _, err := tx.ExecContext(ctx,
+			`DELETE FROM "AdventVotes" WHERE "UserId" = $1 AND "VoteDate" = $2`,
+			undo.UserId, *undo.AdventDate,
+		); err != nil {
+			return handleErrors(err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM "VoteUndos" WHERE "UserId" = $1`, undo.UserId); err != nil {
+		return handleErrors(err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM "Results" WHERE "Id" = $1`, undo.ResultId); err != nil {
+		return handleErrors(err)
+	}
+
+	return nil
+}
+
+func (r *postgresVoteUndoRepo) ForgetTx(tx *sql.Tx, ctx context.Context, userId string) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM "VoteUndos" WHERE "UserId" = $1`, userId)
+	return handleErrors(err)
+}
+
+// revertSnapshot puts a snapshot back the way it was before the vote, or
+// deletes it if the vote is what created it (GetOrCreateTx).
+func revertSnapshot(ctx context.Context, tx *sql.Tx, before domain.UserEloSnapshot) error {
+	if before.VoteCount == 0 {
+		_, err := tx.ExecContext(ctx,
+			`DELETE FROM "UserEloSnapshots" WHERE "UserId" = $1 AND "TorronId" = $2`,
+			before.UserId, before.TorronId,
+		)
+		return handleErrors(err)
+	}
+
+	_, err := tx.ExecContext(ctx,
+		`UPDATE "UserEloSnapshots"
+		 SET "Rating" = $3, "RatingDeviation" = $4, "RatingVolatility" = $5,
+		     "VoteCount" = $6, "LastUpdated" = $7
+		 WHERE "UserId" = $1 AND "TorronId" = $2`,
+		before.UserId,
+		before.TorronId,
+		before.Rating,
+		before.RatingDeviation,
+		before.RatingVolatility,
+		before.VoteCount,
+		before.LastUpdated,
+	)
+	return handleErrors(err)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"strings"
 
 	"github.com/lib/pq"
 
@@ -41,7 +42,9 @@ type userPairingRow struct {
 // minTotalVotes eligibility threshold (join a user's own vote back to the
 // full crowd tally for that same pairing), then derives both the most
 // contested duel and the most unpopular pick from that single dataset in
-// Go, rather than running two separate SQL queries.
+// Go, rather than running two separate SQL queries. Draws ("can't decide"
+// votes) are left out on both sides: the user made no pick, and the crowd
+// tally is a split between the two torrons.
 func (r *postgresWrappedStatsRepo) DuelStats(ctx context.Context, userId string, minTotalVotes int) (*domain.WrappedDuelStats, error) {
 	rows, err := r.db.QueryContext(ctx,
 		`
@@ -54,6 +57,8 @@ func (r *postgresWrappedStatsRepo) DuelStats(ctx context.Context, userId string,
         JOIN "Pairings" p ON r."Pairing" = p."Id"
         JOIN "Results" g ON g."Pairing" = r."Pairing"
         WHERE r."UserId" = $1
+          AND r."Winner" IS NOT NULL
+          AND g."Winner" IS NOT NULL
         GROUP BY r."Pairing", r."Winner", p."Torro1", p."Torro2"
         HAVING COUNT(*) >= $2`,
 		userId,
@@ -133,9 +138,10 @@ func (r *postgresWrappedStatsRepo) DuelStats(ctx context.Context, userId string,
 
 // BracketPath summarizes a user's participation in one bracket: how many
 // rounds they voted in, how many of those matches are decided, how many
-// they called correctly, and (best-effort, independent of whether the
-// user voted at all) whether the bracket itself has produced a champion
-// and whether the user ever picked that champion in one of their votes.
+// they called correctly, how their pick'em prediction is scoring, and
+// (best-effort, independent of whether the user voted at all) whether the
+// bracket itself has produced a champion and whether the user ever picked
+// that champion, in one of their votes or in their prediction.
 func (r *postgresWrappedStatsRepo) BracketPath(ctx context.Context, userId string, bracketId string) (*domain.BracketPathStat, error) {
 	rows, err := r.db.QueryContext(ctx,
 		`
@@ -180,7 +186,18 @@ func (r *postgresWrappedStatsRepo) BracketPath(ctx context.Context, userId strin
 	}
 	stat.RoundsVoted = len(rounds)
 
-	if !stat.HasVoted {
+	prediction, err := scorePrediction(ctx, r.db, bracketId, userId)
+	if err != nil && !strings.Contains(err.Error(), string(domain.NotFoundError)) {
+		return nil, err
+	}
+	if prediction != nil {
+		stat.HasPrediction = true
+		stat.PredictionPoints = prediction.Points
+		stat.PredictionDecided = prediction.Decided
+		stat.PredictionCorrect = prediction.Correct
+	}
+
+	if !stat.HasVoted && !stat.HasPrediction {
 		return stat, nil
 	}
 
@@ -210,6 +227,7 @@ func (r *postgresWrappedStatsRepo) BracketPath(ctx context.Context, userId strin
 		stat.HasChampion = true
 		stat.ChampionName = championName.String
 		stat.MatchedChampion = userPicks[championId.String]
+		stat.PredictedChampion = prediction != nil && prediction.ChampionId == championId.String
 	}
 
 	return stat, nil
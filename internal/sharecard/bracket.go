@@ -0,0 +1,413 @@
+package sharecard
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"unicode/utf8"
+
+	"golang.org/x/image/font"
+)
+
+// BracketTreeData is the input for the knockout tree of one bracket (GET
+// /bracket/{bracketId}/tree.png and its vector twin, tree.svg; see
+// internal/http/bracket_api.go). Unlike the other variants it is drawn
+// from one layout (layoutBracketTree) twice over: onto the card canvas by
+// RenderBracketTree, and as a standalone SVG by BracketTreeSVG.
+type BracketTreeData struct {
+	ClassName string
+	// Champion is "" while the bracket is still being played.
+	Champion string
+	// Rounds are the tree's columns, left to right. Each round's matches
+	// are in slot order, and match i feeds match i/2 of the next round -
+	// or match i itself when the next round is as long (a grand final's
+	// reset).
+	Rounds []BracketTreeRound
+}
+
+// BracketTreeRound is one column of the tree.
+type BracketTreeRound struct {
+	Label   string
+	Matches []BracketTreeMatch
+}
+
+// BracketTreeMatch is one match of the tree. A place no torró has reached
+// yet is a zero BracketTreeSlot; Bye marks a Top torró that went through
+// unopposed.
+type BracketTreeMatch struct {
+	Top    BracketTreeSlot
+	Bottom BracketTreeSlot
+	Bye    bool
+}
+
+// BracketTreeSlot is one torró's place in a match.
+type BracketTreeSlot struct {
+	Name  string
+	Seed  int
+	Votes int
+	Won   bool
+}
+
+// Decided reports whether the match has a winner.
+func (m BracketTreeMatch) Decided() bool {
+	return m.Top.Won || m.Bottom.Won
+}
+
+// Tree geometry. The card draws the tree between the title and the
+// footer; the SVG sizes itself from its first round instead.
+const (
+	treeTitleTop     = 140
+	treeGapY         = 36
+	treeLabelHeight  = 36
+	treeBoxMaxHeight = 120
+	treeBoxRadius    = 14
+	treeLinkWidth    = 3
+	treeSlotPadX     = 14
+
+	svgColumnWidth = 230
+	svgRowPitch    = 76
+	svgBoxHeight   = 60
+	svgPadding     = 24
+	svgTitleHeight = 96
+)
+
+// Tree type scale, in px.
+const (
+	sizeTreeTitle    = 44.0
+	sizeTreeChampion = 30.0
+	sizeTreeLabel    = 16.0
+	sizeTreeSlotMax  = 22.0
+	sizeTreeSlotMin  = 9.0
+	trackTreeLabel   = 2.0
+)
+
+// treeLayout is where layoutBracketTree puts everything: each column's
+// label area, each match's box ([round][match]) and the elbow connectors
+// from a match to the one its winner goes on to.
+type treeLayout struct {
+	labels []image.Rectangle
+	boxes  [][]image.Rectangle
+	links  []treeLink
+}
+
+// treeLink runs from the right edge of one match's box to the left edge of
+// the next: across to mid, down or up to the next box's height, and across
+// again. Decided links are the ones a winner has already travelled.
+type treeLink struct {
+	from, to image.Point
+	mid      int
+	decided  bool
+}
+
+// layoutBracketTree lays the rounds out as equal columns of area, each
+// match centred on its share of the height, so a match sits level with
+// the midpoint of the two that feed it. Boxes are as tall as the first
+// round leaves room for, capped at maxBoxHeight.
+func layoutBracketTree(rounds []BracketTreeRound, area image.Rectangle, labelHeight, maxBoxHeight int) treeLayout {
+	var layout treeLayout
+	if len(rounds) == 0 || len(rounds[0].Matches) == 0 {
+		return layout
+	}
+
+	colW := area.Dx() / len(rounds)
+	gapX := max(colW/8, 12)
+	top := area.Min.Y + labelHeight
+	treeH := area.Max.Y - top
+	boxH := min(treeH/len(rounds[0].Matches)*4/5, maxBoxHeight)
+
+	layout.boxes = make([][]image.Rectangle, len(rounds))
+	for r, round := range rounds {
+		x := area.Min.X + r*colW
+		layout.labels = append(layout.labels, image.Rect(x, area.Min.Y, x+colW-gapX, top))
+		if len(round.Matches) == 0 {
+			continue
+		}
+		bandH := treeH / len(round.Matches)
+		for i := range round.Matches {
+			centre := top + bandH*i + bandH/2
+			layout.boxes[r] = append(layout.boxes[r], image.Rect(x, centre-boxH/2, x+colW-gapX, centre+boxH/2))
+		}
+
+		if r == 0 {
+			continue
+		}
+		prev := layout.boxes[r-1]
+		for i, box := range layout.boxes[r] {
+			feeders := []int{i}
+			if len(prev) == 2*len(layout.boxes[r]) {
+				feeders = []int{2 * i, 2*i + 1}
+			}
+			for _, f := range feeders {
+				if f >= len(prev) {
+					continue
+				}
+				from := image.Pt(prev[f].Max.X, (prev[f].Min.Y+prev[f].Max.Y)/2)
+				to := image.Pt(box.Min.X, (box.Min.Y+box.Max.Y)/2)
+				layout.links = append(layout.links, treeLink{
+					from:    from,
+					to:      to,
+					mid:     from.X + gapX/2,
+					decided: rounds[r-1].Matches[f].Decided(),
+				})
+			}
+		}
+	}
+	return layout
+}
+
+// treeSlotText returns what a slot shows: its torró, "exempt" for the
+// empty side of a bye, or a dash for a place still to be decided.
+func treeSlotText(slot BracketTreeSlot, bye bool) string {
+	switch {
+	case slot.Name != "":
+		return slot.Name
+	case bye:
+		return "exempt"
+	default:
+		return "—"
+	}
+}
+
+// ---------------------------------------------------------------------
+// PNG
+// ---------------------------------------------------------------------
+
+// RenderBracketTree draws data onto a CanvasWidth x CanvasHeight canvas
+// and returns it PNG-encoded. It never errors on the drawing itself; the
+// returned error only reflects PNG encoding failures.
+func RenderBracketTree(data BracketTreeData) ([]byte, error) {
+	return renderFrame(data.toFrame())
+}
+
+func (d BracketTreeData) toFrame() frame {
+	f := frame{
+		kicker: "QUADRE FINAL",
+		footer: footerContent{
+			shortLink:   "torrorendum.cat",
+			showSponsor: true,
+			sponsorLine: sponsorPlaceholder,
+		},
+	}
+	if d.Champion == "" {
+		f.kicker = "QUADRE EN JOC"
+	}
+
+	if len(d.Rounds) == 0 {
+		f.empty = &emptyMessage{
+			heading: "Aquest quadre no té eliminatòria",
+			sub:     "Consulta'n la classificació a torrorendum.cat",
+		}
+		return f
+	}
+	f.tree = &d
+	return f
+}
+
+// drawBracketTree draws a tree card's title, champion line and tree in
+// place of the hero block.
+func (c *canvas) drawBracketTree(d BracketTreeData) {
+	y := c.drawCenteredBlock(d.ClassName, treeTitleTop, styleSansBold, sizeTreeTitle, 0, colorCream,
+		CanvasWidth-2*heroPaddingX, 1, 0)
+	champion, col := "Encara en joc", creamAlpha(0x9E)
+	if d.Champion != "" {
+		champion, col = "Campió: "+d.Champion, colorGold
+	}
+	y = c.drawCenteredBlock(champion, y+heroGapTiny, styleSerifItalic, sizeTreeChampion, 0, col,
+		CanvasWidth-2*heroPaddingX, 1, 0)
+
+	area := image.Rect(paddingX, y+treeGapY, CanvasWidth-paddingX, footerTop-treeGapY)
+	layout := layoutBracketTree(d.Rounds, area, treeLabelHeight, treeBoxMaxHeight)
+
+	for r, label := range layout.labels {
+		c.drawTracked(d.Rounds[r].Label, label.Min.X, label.Min.Y, styleSansBold, sizeTreeLabel, trackTreeLabel,
+			creamAlpha(0xA6))
+	}
+
+	for _, link := range layout.links {
+		col := colorTileBorder
+		if link.decided {
+			col = goldAlpha(0xB3)
+		}
+		half := treeLinkWidth / 2
+		lowY, highY := min(link.from.Y, link.to.Y), max(link.from.Y, link.to.Y)
+		fillRoundedRect(c.img, image.Rect(link.from.X, link.from.Y-half, link.mid+half+1, link.from.Y-half+treeLinkWidth), 0, col)
+		fillRoundedRect(c.img, image.Rect(link.mid-half, lowY-half, link.mid-half+treeLinkWidth, highY-half+treeLinkWidth), 0, col)
+		fillRoundedRect(c.img, image.Rect(link.mid-half, link.to.Y-half, link.to.X, link.to.Y-half+treeLinkWidth), 0, col)
+	}
+
+	for r, boxes := range layout.boxes {
+		for i, box := range boxes {
+			c.drawTreeMatch(d.Rounds[r].Matches[i], box)
+		}
+	}
+}
+
+// drawTreeMatch draws one match box: its two torrons on a line each,
+// seed first and votes last, the winner in gold and the loser dimmed.
+func (c *canvas) drawTreeMatch(m BracketTreeMatch, box image.Rectangle) {
+	border := colorTileBorder
+	if m.Decided() {
+		border = goldAlpha(0x8C)
+	}
+	fillRoundedRect(c.img, box, treeBoxRadius, colorTileBg)
+	strokeRoundedRect(c.img, box, treeBoxRadius, 2, border)
+
+	lineH := box.Dy() / 2
+	size := max(min(sizeTreeSlotMax, float64(lineH)*0.55), sizeTreeSlotMin)
+	face := c.face(styleSansBold, size)
+	textH := face.Metrics().Height.Ceil()
+
+	for i, slot := range []BracketTreeSlot{m.Top, m.Bottom} {
+		top := box.Min.Y + i*lineH
+		y := vcenter(top, lineH, textH)
+		x := box.Min.X + treeSlotPadX
+		right := box.Max.X - treeSlotPadX
+
+		col := colorCream
+		switch {
+		case slot.Won:
+			col = colorGold
+		case m.Decided() || slot.Name == "":
+			col = creamAlpha(0x73)
+		}
+
+		if slot.Seed > 0 {
+			seed := fmt.Sprintf("%d", slot.Seed)
+			c.drawText(seed, x, y, styleSansBold, size, creamAlpha(0x80))
+			x += measureWidth(face, "00") + treeSlotPadX/2
+		}
+		if slot.Name != "" && !m.Bye {
+			votes := fmt.Sprintf("%d", slot.Votes)
+			right -= measureWidth(face, votes)
+			c.drawText(votes, right, y, styleSansBold, size, col)
+			right -= treeSlotPadX
+		}
+		c.drawText(truncateToWidth(face, treeSlotText(slot, m.Bye && i == 1), right-x), x, y, styleSansBold, size, col)
+	}
+}
+
+// truncateToWidth shortens text with an ellipsis until it fits maxWidthPx.
+func truncateToWidth(face font.Face, text string, maxWidthPx int) string {
+	if measureWidth(face, text) <= maxWidthPx {
+		return text
+	}
+	runes := []rune(text)
+	for n := len(runes) - 1; n > 0; n-- {
+		if short := string(runes[:n]) + "…"; measureWidth(face, short) <= maxWidthPx {
+			return short
+		}
+	}
+	return ""
+}
+
+// ---------------------------------------------------------------------
+// SVG
+// ---------------------------------------------------------------------
+
+// BracketTreeSVG returns the tree as a standalone SVG document, in the
+// card's palette, one svgColumnWidth column per round and one
+// svgRowPitch row per first-round match.
+func BracketTreeSVG(data BracketTreeData) []byte {
+	rows := 1
+	if len(data.Rounds) > 0 {
+		rows = max(len(data.Rounds[0].Matches), 1)
+	}
+	width := max(len(data.Rounds), 2)*svgColumnWidth + 2*svgPadding
+	height := svgTitleHeight + treeLabelHeight + rows*svgRowPitch + svgPadding
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" `+
+		`font-family="Liberation Sans, Arial, sans-serif" font-weight="bold">`+"\n", width, height, width, height)
+	fmt.Fprintf(&b, `<defs><linearGradient id="bg" x1="0" y1="0" x2="0" y2="1">`+
+		`<stop offset="0" stop-color="%s"/><stop offset="0.45" stop-color="%s"/><stop offset="1" stop-color="%s"/>`+
+		`</linearGradient></defs>`+"\n", svgHex(colorBgTop), svgHex(colorBgMid), svgHex(colorBgBottom))
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="url(#bg)"/>`+"\n", width, height)
+
+	fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="30" %s>%s</text>`+"\n",
+		svgPadding, svgPadding+30, svgFill(colorCream), html.EscapeString(data.ClassName))
+	champion, col := "Encara en joc", creamAlpha(0x9E)
+	if data.Champion != "" {
+		champion, col = "Campió: "+data.Champion, colorGold
+	}
+	fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="20" font-family="Liberation Serif, Georgia, serif" font-style="italic" font-weight="normal" %s>%s</text>`+"\n",
+		svgPadding, svgPadding+64, svgFill(col), html.EscapeString(champion))
+
+	area := image.Rect(svgPadding, svgTitleHeight, width-svgPadding, height-svgPadding)
+	layout := layoutBracketTree(data.Rounds, area, treeLabelHeight, svgBoxHeight)
+
+	for r, label := range layout.labels {
+		fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="12" letter-spacing="1.5" %s>%s</text>`+"\n",
+			label.Min.X, label.Min.Y+14, svgFill(creamAlpha(0xA6)), html.EscapeString(data.Rounds[r].Label))
+	}
+	for _, link := range layout.links {
+		col := colorTileBorder
+		if link.decided {
+			col = goldAlpha(0xB3)
+		}
+		fmt.Fprintf(&b, `<path d="M%d %d H%d V%d H%d" fill="none" stroke-width="2" %s/>`+"\n",
+			link.from.X, link.from.Y, link.mid, link.to.Y, link.to.X, svgStroke(col))
+	}
+	for r, boxes := range layout.boxes {
+		for i, box := range boxes {
+			writeSVGMatch(&b, data.Rounds[r].Matches[i], box)
+		}
+	}
+
+	b.WriteString("</svg>\n")
+	return b.Bytes()
+}
+
+// writeSVGMatch writes one match box, laid out as drawTreeMatch draws it.
+// An SVG can't measure its text, so names are cut by length instead.
+func writeSVGMatch(b *bytes.Buffer, m BracketTreeMatch, box image.Rectangle) {
+	border := colorTileBorder
+	if m.Decided() {
+		border = goldAlpha(0x8C)
+	}
+	fmt.Fprintf(b, `<rect x="%d" y="%d" width="%d" height="%d" rx="8" %s stroke-width="1.5" %s/>`+"\n",
+		box.Min.X, box.Min.Y, box.Dx(), box.Dy(), svgFill(colorTileBg), svgStroke(border))
+
+	lineH := box.Dy() / 2
+	maxRunes := (box.Dx() - 4*treeSlotPadX) / 8
+	for i, slot := range []BracketTreeSlot{m.Top, m.Bottom} {
+		baseline := box.Min.Y + i*lineH + lineH/2 + 5
+
+		col := colorCream
+		switch {
+		case slot.Won:
+			col = colorGold
+		case m.Decided() || slot.Name == "":
+			col = creamAlpha(0x73)
+		}
+
+		x := box.Min.X + 10
+		if slot.Seed > 0 {
+			fmt.Fprintf(b, `<text x="%d" y="%d" font-size="11" %s>%d</text>`+"\n", x, baseline, svgFill(creamAlpha(0x80)), slot.Seed)
+		}
+		name := treeSlotText(slot, m.Bye && i == 1)
+		if utf8.RuneCountInString(name) > maxRunes {
+			name = string([]rune(name)[:max(maxRunes-1, 1)]) + "…"
+		}
+		fmt.Fprintf(b, `<text x="%d" y="%d" font-size="14" %s>%s</text>`+"\n", x+22, baseline, svgFill(col), html.EscapeString(name))
+		if slot.Name != "" && !m.Bye {
+			fmt.Fprintf(b, `<text x="%d" y="%d" font-size="14" text-anchor="end" %s>%d</text>`+"\n",
+				box.Max.X-10, baseline, svgFill(col), slot.Votes)
+		}
+	}
+}
+
+// svgHex returns a colour's "#rrggbb", its alpha left to svgFill/svgStroke.
+func svgHex(c color.Color) string {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return fmt.Sprintf("#%02x%02x%02x", n.R, n.G, n.B)
+}
+
+func svgFill(c color.NRGBA) string {
+	return fmt.Sprintf(`fill="%s" fill-opacity="%.2f"`, svgHex(c), float64(c.A)/0xFF)
+}
+
+func svgStroke(c color.NRGBA) string {
+	return fmt.Sprintf(`stroke="%s" stroke-opacity="%.2f"`, svgHex(c), float64(c.A)/0xFF)
+}
@@ -0,0 +1,144 @@
+package sharecard
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"testing"
+)
+
+// treeOfEight is an 8-torró bracket two rounds in: the quarter-finals
+// decided (one of them a bye), one semi-final decided, the final empty.
+func treeOfEight() BracketTreeData {
+	slot := func(name string, seed, votes int, won bool) BracketTreeSlot {
+		return BracketTreeSlot{Name: name, Seed: seed, Votes: votes, Won: won}
+	}
+	return BracketTreeData{
+		ClassName: "Clàssics",
+		Rounds: []BracketTreeRound{
+			{Label: "Ronda 1", Matches: []BracketTreeMatch{
+				{Top: slot("Torró d'Alacant", 1, 0, true), Bye: true},
+				{Top: slot("Torró de Xixona", 4, 12, true), Bottom: slot("Torró de Xocolata <Negra> & Ametlla", 5, 9, false)},
+				{Top: slot("Torró de Crema Cremada", 2, 3, false), Bottom: slot("Torró de Gema", 7, 8, true)},
+				{Top: slot("Torró de Coco", 3, 6, true), Bottom: slot("Torró de Iogurt", 6, 2, false)},
+			}},
+			{Label: "Ronda 2", Matches: []BracketTreeMatch{
+				{Top: slot("Torró d'Alacant", 1, 20, true), Bottom: slot("Torró de Xixona", 4, 11, false)},
+				{Top: slot("Torró de Gema", 7, 1, false), Bottom: slot("Torró de Coco", 3, 1, false)},
+			}},
+			{Label: "Gran Final", Matches: make([]BracketTreeMatch, 1)},
+		},
+	}
+}
+
+func TestRenderBracketTreeProducesValidCanvas(t *testing.T) {
+	big := BracketTreeData{ClassName: "Tots", Champion: "Torró de Xixona"}
+	for size := 64; size > 1; size /= 2 {
+		round := BracketTreeRound{Label: fmt.Sprintf("Ronda %d", len(big.Rounds)+1)}
+		for i := range size / 2 {
+			round.Matches = append(round.Matches, BracketTreeMatch{
+				Top:    BracketTreeSlot{Name: fmt.Sprintf("Torró %d", 2*i+1), Seed: 2*i + 1, Votes: 3, Won: true},
+				Bottom: BracketTreeSlot{Name: fmt.Sprintf("Torró %d", 2*i+2), Seed: 2*i + 2, Votes: 1},
+			})
+		}
+		big.Rounds = append(big.Rounds, round)
+	}
+
+	tests := map[string]BracketTreeData{
+		"no knockout":           {ClassName: "Clàssics"},
+		"bracket of eight":      treeOfEight(),
+		"bracket of sixty-four": big,
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			b, err := RenderBracketTree(data)
+			if err != nil {
+				t.Fatalf("RenderBracketTree() error = %v", err)
+			}
+
+			img, err := png.Decode(bytes.NewReader(b))
+			if err != nil {
+				t.Fatalf("png.Decode() error = %v; output is not a valid PNG", err)
+			}
+
+			bounds := img.Bounds()
+			if bounds.Dx() != CanvasWidth || bounds.Dy() != CanvasHeight {
+				t.Fatalf("got %dx%d, want %dx%d", bounds.Dx(), bounds.Dy(), CanvasWidth, CanvasHeight)
+			}
+		})
+	}
+}
+
+func TestBracketTreeEmptyState(t *testing.T) {
+	if f := (BracketTreeData{}).toFrame(); f.empty == nil || f.tree != nil {
+		t.Fatal("toFrame() with no rounds: expected empty state")
+	}
+	if f := treeOfEight().toFrame(); f.empty != nil || f.tree == nil {
+		t.Fatal("toFrame() with rounds: expected the tree")
+	}
+}
+
+func TestLayoutBracketTreeCentresMatchesOnTheirFeeders(t *testing.T) {
+	data := treeOfEight()
+	layout := layoutBracketTree(data.Rounds, image.Rect(0, 0, 900, 800), 40, 120)
+
+	if len(layout.boxes) != 3 || len(layout.boxes[0]) != 4 || len(layout.boxes[1]) != 2 || len(layout.boxes[2]) != 1 {
+		t.Fatalf("laid out %d rounds of boxes, want 4, 2 and 1 matches", len(layout.boxes))
+	}
+
+	centreY := func(r image.Rectangle) int { return (r.Min.Y + r.Max.Y) / 2 }
+	for r := 1; r < len(layout.boxes); r++ {
+		for i, box := range layout.boxes[r] {
+			feeders := (centreY(layout.boxes[r-1][2*i]) + centreY(layout.boxes[r-1][2*i+1])) / 2
+			if diff := centreY(box) - feeders; diff < -1 || diff > 1 {
+				t.Errorf("round %d match %d is centred at %d, its feeders at %d", r+1, i, centreY(box), feeders)
+			}
+			if box.Min.X <= layout.boxes[r-1][0].Max.X {
+				t.Errorf("round %d overlaps round %d", r+1, r)
+			}
+		}
+	}
+
+	// Every match past the first round has two links in; only the decided
+	// quarter-finals and the decided semi-final count as travelled.
+	if len(layout.links) != 6 {
+		t.Fatalf("got %d links, want 6", len(layout.links))
+	}
+	decided := 0
+	for _, l := range layout.links {
+		if l.decided {
+			decided++
+		}
+	}
+	if decided != 5 {
+		t.Errorf("got %d decided links, want 5", decided)
+	}
+}
+
+func TestBracketTreeSVGIsWellFormed(t *testing.T) {
+	for name, data := range map[string]BracketTreeData{
+		"no knockout":      {ClassName: "Clàssics"},
+		"bracket of eight": treeOfEight(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			svg := BracketTreeSVG(data)
+			decoder := xml.NewDecoder(bytes.NewReader(svg))
+			for {
+				_, err := decoder.Token()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("the SVG doesn't parse: %v\n%s", err, svg)
+				}
+			}
+			if !bytes.HasPrefix(bytes.TrimSpace(svg), []byte("<svg")) && !bytes.HasPrefix(svg, []byte("<?xml")) {
+				t.Errorf("the SVG starts %q", svg[:min(len(svg), 40)])
+			}
+		})
+	}
+}
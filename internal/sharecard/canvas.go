@@ -1,8 +1,9 @@
 // Package sharecard renders every 1080x1920 ("Stories" aspect ratio) PNG
 // this app shares to Instagram/WhatsApp: the plain per-user result card
 // (GET /share/card.png), the personal "Torrorèndum Wrapped" recap (GET
-// /wrapped/card.png), and the aggregate press-kit one-pager (GET
-// /press-kit/card.png). It knows nothing about HTTP, cookies, or the
+// /wrapped/card.png), the aggregate press-kit one-pager (GET
+// /press-kit/card.png) and a bracket's knockout tree (GET
+// /bracket/{bracketId}/tree.png, which bracket.go also writes as an SVG). It knows nothing about HTTP, cookies, or the
 // database — callers (internal/http's Handler) assemble a variant's Data
 // value from domain data and hand it to that variant's Render function.
 //
@@ -230,6 +231,10 @@ type frame struct {
 	kicker string // header badge pill text, e.g. "RESUM 2026"
 	hero   heroContent
 
+	// tree, if non-nil, replaces the hero and everything below it with a
+	// bracket's knockout tree (see bracket.go).
+	tree *BracketTreeData
+
 	dots         *dotGrid  // nil => no dot-grid stat visualization
 	tiles        *tileGrid // nil => no stat-tile grid
 	dividerLabel string    // "" => no section divider drawn
@@ -321,6 +326,8 @@ func renderFrame(f frame) ([]byte, error) {
 
 	if f.empty != nil {
 		c.drawEmptyMessage(*f.empty)
+	} else if f.tree != nil {
+		c.drawBracketTree(*f.tree)
 	} else {
 		y := c.drawHero(f.hero)
 		if f.dots != nil {
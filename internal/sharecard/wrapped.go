@@ -53,6 +53,13 @@ type WrappedData struct {
 	// MatchedChampion is meaningful only if HasChampion: did the user ever
 	// pick the champion in one of their voted matches.
 	MatchedChampion bool
+	// HasPrediction is false if the user made no pick'em prediction for
+	// the bracket; PredictedChampion is meaningful only if HasChampion.
+	HasPrediction     bool
+	PredictionPoints  int
+	PredictionDecided int
+	PredictionCorrect int
+	PredictedChampion bool
 }
 
 // RenderWrapped draws data onto a CanvasWidth x CanvasHeight canvas and
@@ -0,0 +1,95 @@
+package strength
+
+import "math"
+
+func newMatrix(n int) [][]float64 {
+	m := make([][]float64, n)
+	for i := range m {
+		m[i] = make([]float64, n)
+	}
+	return m
+}
+
+// invertSPD inverts a symmetric positive-definite matrix through its
+// Cholesky factor. The information matrices Fit builds always qualify: the
+// prior adds a positive constant to a graph Laplacian's diagonal.
+func invertSPD(a [][]float64) [][]float64 {
+	n := len(a)
+
+	// a = l·lᵀ with l lower triangular.
+	l := newMatrix(n)
+	for i := 0; i < n; i++ {
+		for j := 0; j <= i; j++ {
+			sum := a[i][j]
+			for k := 0; k < j; k++ {
+				sum -= l[i][k] * l[j][k]
+			}
+			if i == j {
+				l[i][i] = math.Sqrt(math.Max(sum, 1e-300))
+			} else {
+				l[i][j] = sum / l[j][j]
+			}
+		}
+	}
+
+	// l⁻¹ by forward substitution, then a⁻¹ = l⁻ᵀ·l⁻¹.
+	linv := newMatrix(n)
+	for i := 0; i < n; i++ {
+		linv[i][i] = 1 / l[i][i]
+		for j := 0; j < i; j++ {
+			sum := 0.0
+			for k := j; k < i; k++ {
+				sum -= l[i][k] * linv[k][j]
+			}
+			linv[i][j] = sum / l[i][i]
+		}
+	}
+
+	inv := newMatrix(n)
+	for i := 0; i < n; i++ {
+		for j := 0; j <= i; j++ {
+			sum := 0.0
+			for k := i; k < n; k++ {
+				sum += linv[k][i] * linv[k][j]
+			}
+			inv[i][j] = sum
+			inv[j][i] = sum
+		}
+	}
+
+	return inv
+}
+
+// components groups the n nodes into the sets connected by edges.
+func components(n int, edges [][2]int) [][]int {
+	parent := make([]int, n)
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for _, e := range edges {
+		parent[find(e[0])] = find(e[1])
+	}
+
+	groups := make(map[int][]int)
+	var roots []int
+	for i := 0; i < n; i++ {
+		root := find(i)
+		if _, ok := groups[root]; !ok {
+			roots = append(roots, root)
+		}
+		groups[root] = append(groups[root], i)
+	}
+
+	out := make([][]int, len(roots))
+	for k, root := range roots {
+		out[k] = groups[root]
+	}
+	return out
+}
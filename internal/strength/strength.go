@@ -0,0 +1,254 @@
+// Package strength fits a Bradley–Terry model to a whole rating season's
+// Results at once. Where the sequential rating engines (internal/rating) move two
+// ratings per vote, and so end up depending on the order votes arrived in,
+// the batch fit finds the strengths that make every recorded outcome most
+// likely together, with a 95% interval for each.
+//
+// The model says torró i beats torró j with probability
+// 1 / (1 + exp(θj − θi)). Draws count as half a win for each side. A weak
+// Gaussian prior on every θ keeps the fit finite for a torró that has won
+// or lost every duel; with a few dozen votes per torró its pull is
+// negligible.
+package strength
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+const (
+	// Scale converts θ, in natural-log odds, to points on the
+	// 1500-centred rating scale: 400 points is a factor of ten in odds,
+	// as in Elo.
+	Scale = 400 / math.Ln10
+
+	// Centre is the strength of a torró exactly as strong as the average
+	// of the torrons it is compared with.
+	Centre = 1500
+
+	// PriorDeviation is the prior's standard deviation in rating points,
+	// matching domain.DefaultRatingDeviation: before any vote a torró is
+	// as uncertain as a fresh Glicko-2 rating.
+	PriorDeviation = domain.DefaultRatingDeviation
+
+	// Z95 is the two-sided 95% normal quantile, used both for the
+	// intervals and for the separability tests.
+	Z95 = 1.959963984540054
+
+	maxIterations = 100
+	tolerance     = 1e-9
+)
+
+// Fit runs the batch fit over records and returns one TorroStrength per
+// torró that appears in at least one of them. Torrons the records mention
+// but torrons does not are ignored. Strengths are centred at Centre within
+// each group of torrons connected by votes, since the data says nothing
+// about how one such group compares to another.
+func Fit(torrons []*domain.Torro, records []*domain.PairRecord, now time.Time) []*domain.TorroStrength {
+	known := make(map[string]*domain.Torro, len(torrons))
+	for _, t := range torrons {
+		known[t.Id] = t
+	}
+
+	index := make(map[string]int)
+	var ids []string
+	indexOf := func(id string) int {
+		i, ok := index[id]
+		if !ok {
+			i = len(ids)
+			index[id] = i
+			ids = append(ids, id)
+		}
+		return i
+	}
+
+	type tally struct {
+		a, b  int
+		games float64
+		score float64 // a's wins plus half the draws
+	}
+	var tallies []tally
+	for _, rec := range records {
+		if known[rec.Torro1] == nil || known[rec.Torro2] == nil || rec.Torro1 == rec.Torro2 {
+			continue
+		}
+		games := float64(rec.Wins1 + rec.Wins2 + rec.Draws)
+		if games == 0 {
+			continue
+		}
+		tallies = append(tallies, tally{
+			a:     indexOf(rec.Torro1),
+			b:     indexOf(rec.Torro2),
+			games: games,
+			score: float64(rec.Wins1) + 0.5*float64(rec.Draws),
+		})
+	}
+
+	n := len(ids)
+	if n == 0 {
+		return nil
+	}
+
+	precision := (Scale / PriorDeviation) * (Scale / PriorDeviation)
+	theta := make([]float64, n)
+	var cov [][]float64
+
+	// Newton's method on the log-posterior, which is concave: the
+	// information matrix below is its negated Hessian, and its inverse at
+	// the optimum is the Laplace approximation of the posterior covariance.
+	for iter := 0; iter < maxIterations; iter++ {
+		grad := make([]float64, n)
+		info := newMatrix(n)
+		for i := range theta {
+			grad[i] = -precision * theta[i]
+			info[i][i] = precision
+		}
+		for _, t := range tallies {
+			p := 1 / (1 + math.Exp(theta[t.b]-theta[t.a]))
+			g := t.score - t.games*p
+			w := t.games * p * (1 - p)
+			grad[t.a] += g
+			grad[t.b] -= g
+			info[t.a][t.a] += w
+			info[t.b][t.b] += w
+			info[t.a][t.b] -= w
+			info[t.b][t.a] -= w
+		}
+
+		cov = invertSPD(info)
+		step := 0.0
+		for i := range theta {
+			delta := 0.0
+			for j := range grad {
+				delta += cov[i][j] * grad[j]
+			}
+			theta[i] += delta
+			step = math.Max(step, math.Abs(delta))
+		}
+		if step < tolerance {
+			break
+		}
+	}
+
+	// Every θ is identified only relative to the others it was compared
+	// with, so report it relative to its connected group's average. The
+	// group's average is exactly zero at the optimum already (the prior is
+	// the only thing pinning it); centring the covariance removes the
+	// prior's uncertainty about that average from each interval.
+	edges := make([][2]int, len(tallies))
+	for k, t := range tallies {
+		edges[k] = [2]int{t.a, t.b}
+	}
+	for _, members := range components(n, edges) {
+		shift := 1 / (precision * float64(len(members)))
+		for _, i := range members {
+			for _, j := range members {
+				cov[i][j] -= shift
+			}
+		}
+	}
+
+	comparisons := make([]int, n)
+	for _, t := range tallies {
+		comparisons[t.a] += int(t.games)
+		comparisons[t.b] += int(t.games)
+	}
+
+	strengths := make([]*domain.TorroStrength, n)
+	for i, id := range ids {
+		se := Scale * math.Sqrt(math.Max(cov[i][i], 0))
+		value := Centre + Scale*theta[i]
+		strengths[i] = &domain.TorroStrength{
+			TorroId:     id,
+			ClassId:     known[id].Class,
+			Strength:    value,
+			Lower:       value - Z95*se,
+			Upper:       value + Z95*se,
+			StdError:    se,
+			Comparisons: comparisons[i],
+			FittedAt:    now,
+		}
+	}
+
+	separable := func(a, b int) bool {
+		variance := cov[a][a] + cov[b][b] - 2*cov[a][b]
+		return theta[a]-theta[b] > Z95*math.Sqrt(math.Max(variance, 0))
+	}
+
+	order := make([]int, 0, n)
+	for i, id := range ids {
+		if !known[id].Discontinued {
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(x, y int) bool {
+		return theta[order[x]] > theta[order[y]]
+	})
+
+	lastInClass := make(map[string]int)
+	for pos, i := range order {
+		strengths[i].Rank = pos + 1
+		if pos > 0 {
+			strengths[order[pos-1]].SeparableFromNext = separable(order[pos-1], i)
+		}
+
+		class := strengths[i].ClassId
+		if prev, ok := lastInClass[class]; ok {
+			strengths[i].ClassRank = strengths[prev].ClassRank + 1
+			strengths[prev].SeparableFromNextInClass = separable(prev, i)
+		} else {
+			strengths[i].ClassRank = 1
+		}
+		lastInClass[class] = i
+	}
+
+	sort.SliceStable(strengths, func(x, y int) bool {
+		return strengths[x].Strength > strengths[y].Strength
+	})
+
+	return strengths
+}
+
+// Leaders reports whether the strongest ranked torró in strengths is
+// separable from the second. strengths may be the whole fit or one class
+// of it. When the two are adjacent in the fit's overall or class ranking
+// the answer comes from the fit's own test, which accounts for how their
+// estimates move together; otherwise (a filtered list) it falls back to
+// treating the two as independent. Returns nil with fewer than two ranked
+// torrons.
+func Leaders(strengths []*domain.TorroStrength) *domain.LeaderSeparation {
+	var first, second *domain.TorroStrength
+	for _, s := range strengths {
+		if s.Rank == 0 {
+			continue
+		}
+		switch {
+		case first == nil || s.Strength > first.Strength:
+			first, second = s, first
+		case second == nil || s.Strength > second.Strength:
+			second = s
+		}
+	}
+	if second == nil {
+		return nil
+	}
+
+	var separable bool
+	switch {
+	case second.Rank == first.Rank+1:
+		separable = first.SeparableFromNext
+	case first.ClassId == second.ClassId && second.ClassRank == first.ClassRank+1:
+		separable = first.SeparableFromNextInClass
+	default:
+		separable = first.Strength-second.Strength > Z95*math.Hypot(first.StdError, second.StdError)
+	}
+
+	return &domain.LeaderSeparation{
+		LeaderId:   first.TorroId,
+		RunnerUpId: second.TorroId,
+		Separable:  separable,
+	}
+}
@@ -0,0 +1,157 @@
+package strength
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+func torro(id, class string) *domain.Torro {
+	return &domain.Torro{Id: id, Class: class}
+}
+
+func byId(strengths []*domain.TorroStrength) map[string]*domain.TorroStrength {
+	m := make(map[string]*domain.TorroStrength, len(strengths))
+	for _, s := range strengths {
+		m[s.TorroId] = s
+	}
+	return m
+}
+
+func TestFitTwoTorrons(t *testing.T) {
+	torrons := []*domain.Torro{torro("a", "1"), torro("b", "1")}
+	records := []*domain.PairRecord{{Torro1: "a", Torro2: "b", Wins1: 300, Wins2: 100}}
+
+	got := byId(Fit(torrons, records, time.Now()))
+	a, b := got["a"], got["b"]
+
+	// With this many votes the prior barely matters: the gap is the
+	// log-odds of the observed win rate.
+	wantGap := Scale * math.Log(3)
+	if gap := a.Strength - b.Strength; math.Abs(gap-wantGap) > 2 {
+		t.Errorf("gap = %.1f, want ≈ %.1f", gap, wantGap)
+	}
+	if mid := (a.Strength + b.Strength) / 2; math.Abs(mid-Centre) > 1e-6 {
+		t.Errorf("strengths centred on %.3f, want %d", mid, Centre)
+	}
+	if a.Lower >= a.Strength || a.Upper <= a.Strength {
+		t.Errorf("interval [%.1f, %.1f] does not contain %.1f", a.Lower, a.Upper, a.Strength)
+	}
+	if a.Comparisons != 400 || b.Comparisons != 400 {
+		t.Errorf("comparisons = %d/%d, want 400/400", a.Comparisons, b.Comparisons)
+	}
+	if a.Rank != 1 || b.Rank != 2 || a.ClassRank != 1 || b.ClassRank != 2 {
+		t.Errorf("ranks = %d/%d (class %d/%d), want 1/2", a.Rank, b.Rank, a.ClassRank, b.ClassRank)
+	}
+	if !a.SeparableFromNext || !a.SeparableFromNextInClass {
+		t.Error("a 3:1 record over 400 votes should be separable")
+	}
+}
+
+func TestFitSeparability(t *testing.T) {
+	torrons := []*domain.Torro{torro("a", "1"), torro("b", "1"), torro("c", "1")}
+	records := []*domain.PairRecord{
+		{Torro1: "a", Torro2: "b", Wins1: 11, Wins2: 9},
+		{Torro1: "a", Torro2: "c", Wins1: 40, Wins2: 2},
+		{Torro1: "c", Torro2: "b", Wins1: 3, Wins2: 38, Draws: 4},
+	}
+
+	got := byId(Fit(torrons, records, time.Now()))
+	if got["a"].Rank != 1 || got["b"].Rank != 2 || got["c"].Rank != 3 {
+		t.Fatalf("ranks a=%d b=%d c=%d, want 1 2 3", got["a"].Rank, got["b"].Rank, got["c"].Rank)
+	}
+	if got["a"].SeparableFromNext {
+		t.Error("an 11-9 edge should not separate a from b")
+	}
+	if !got["b"].SeparableFromNext {
+		t.Error("b's 38-3 record should separate it from c")
+	}
+	if got["c"].SeparableFromNext {
+		t.Error("the last torró has nothing to be separable from")
+	}
+
+	leaders := Leaders(Fit(torrons, records, time.Now()))
+	if leaders == nil || leaders.LeaderId != "a" || leaders.RunnerUpId != "b" || leaders.Separable {
+		t.Errorf("Leaders = %+v, want a ahead of b, not separable", leaders)
+	}
+}
+
+func TestFitUndefeatedStaysFinite(t *testing.T) {
+	torrons := []*domain.Torro{torro("a", "1"), torro("b", "1")}
+	records := []*domain.PairRecord{{Torro1: "a", Torro2: "b", Wins1: 5}}
+
+	for _, s := range Fit(torrons, records, time.Now()) {
+		if math.IsNaN(s.Strength) || math.IsInf(s.Strength, 0) || math.IsNaN(s.StdError) {
+			t.Errorf("%s: strength %v ± %v, want finite", s.TorroId, s.Strength, s.StdError)
+		}
+	}
+}
+
+func TestFitSkipsUnknownAndRanksActiveOnly(t *testing.T) {
+	gone := torro("b", "1")
+	gone.Discontinued = true
+	torrons := []*domain.Torro{torro("a", "1"), gone, torro("c", "2"), torro("d", "2")}
+	records := []*domain.PairRecord{
+		{Torro1: "a", Torro2: "b", Wins1: 2, Wins2: 8},
+		{Torro1: "c", Torro2: "d", Wins1: 6, Wins2: 4},
+		{Torro1: "a", Torro2: "ghost", Wins1: 10},
+	}
+
+	fit := Fit(torrons, records, time.Now())
+	got := byId(fit)
+	if len(fit) != 4 || got["ghost"] != nil {
+		t.Fatalf("fit %d torrons (ghost=%v), want a, b, c and d", len(fit), got["ghost"])
+	}
+	if got["b"].Rank != 0 || got["b"].ClassRank != 0 {
+		t.Errorf("discontinued b ranked %d/%d, want unranked", got["b"].Rank, got["b"].ClassRank)
+	}
+	if got["a"].ClassRank != 1 || got["c"].ClassRank != 1 || got["d"].ClassRank != 2 {
+		t.Errorf("class ranks a=%d c=%d d=%d, want 1 1 2", got["a"].ClassRank, got["c"].ClassRank, got["d"].ClassRank)
+	}
+
+	// The two classes were never compared, so each is centred on its own.
+	if mid := (got["c"].Strength + got["d"].Strength) / 2; math.Abs(mid-Centre) > 1e-6 {
+		t.Errorf("class 2 centred on %.3f, want %d", mid, Centre)
+	}
+}
+
+func TestLeadersFallsBackToIndependentTest(t *testing.T) {
+	strengths := []*domain.TorroStrength{
+		{TorroId: "a", ClassId: "1", Strength: 1700, StdError: 20, Rank: 1, ClassRank: 1},
+		{TorroId: "c", ClassId: "2", Strength: 1550, StdError: 20, Rank: 3, ClassRank: 1},
+	}
+	if got := Leaders(strengths); got == nil || !got.Separable {
+		t.Errorf("Leaders = %+v, want separable (150 points apart, σ≈28)", got)
+	}
+
+	strengths[1].Strength = 1680
+	if got := Leaders(strengths); got == nil || got.Separable {
+		t.Errorf("Leaders = %+v, want not separable (20 points apart)", got)
+	}
+
+	if got := Leaders(strengths[:1]); got != nil {
+		t.Errorf("Leaders of one torró = %+v, want nil", got)
+	}
+}
+
+func TestInvertSPD(t *testing.T) {
+	a := [][]float64{{4, 1, 0.5}, {1, 3, 0.2}, {0.5, 0.2, 2}}
+	inv := invertSPD(a)
+	for i := range a {
+		for j := range a {
+			sum := 0.0
+			for k := range a {
+				sum += a[i][k] * inv[k][j]
+			}
+			want := 0.0
+			if i == j {
+				want = 1
+			}
+			if math.Abs(sum-want) > 1e-12 {
+				t.Errorf("(a·a⁻¹)[%d][%d] = %v, want %v", i, j, sum, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,240 @@
+// Package taste compares users by how they rank torrons. Two users are
+// compared over the torrons both have a personal snapshot of
+// (domain.UserEloSnapshot), by the Spearman rank correlation of their
+// ratings. Ranks rather than raw ratings make the comparison about order
+// alone: someone whose ratings are all spread out and someone whose
+// ratings are bunched together can still agree perfectly.
+//
+// Users who agree strongly are taste twins. What the twins rank highly and
+// the user hasn't tried becomes a pick, but only as an aggregate: nothing
+// here identifies another user.
+package taste
+
+import (
+	"math"
+	"sort"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+const (
+	// MinOverlap is how many torrons two users must both have rated to be
+	// compared. With fewer, a perfect correlation is too easy to hit by
+	// chance.
+	MinOverlap = 5
+
+	// TwinThreshold is the rank correlation from which two users count as
+	// taste twins.
+	TwinThreshold = 0.6
+
+	// ClosestShown is how many of the closest twins' matches are reported.
+	ClosestShown = 5
+
+	// MinPickTwins is how many twins must have a torró among their
+	// favourites before it is a pick, so no pick gives away one person's
+	// ratings.
+	MinPickTwins = 2
+
+	// PickLimit is how many picks are reported.
+	PickLimit = 5
+)
+
+// favouriteShare is the top share of a twin's own rated torrons that count
+// as their favourites.
+const favouriteShare = 1.0 / 3
+
+// Twins compares the user's snapshots with others' (every snapshot of each
+// user to compare with, as TasteTwinRepo.ListOverlapping returns them) and
+// summarises the result. torrons is the catalog, which picks are drawn
+// from: discontinued torrons are never picked.
+func Twins(
+	userSnapshots []*domain.UserEloSnapshot,
+	others []*domain.UserEloSnapshot,
+	torrons []*domain.Torro,
+) *domain.TasteTwins {
+	mine := ratingsOf(userSnapshots)
+
+	byUser := make(map[string]map[string]float64)
+	var order []string
+	for _, s := range others {
+		if byUser[s.UserId] == nil {
+			byUser[s.UserId] = make(map[string]float64)
+			order = append(order, s.UserId)
+		}
+		byUser[s.UserId][s.TorronId] = s.Rating
+	}
+	// Sorted so equal matches always come out in the same order.
+	sort.Strings(order)
+
+	result := &domain.TasteTwins{Closest: []*domain.TasteMatch{}, Picks: []*domain.TwinPick{}}
+	var matches []*domain.TasteMatch
+	var twins []map[string]float64
+	for _, userId := range order {
+		theirs := byUser[userId]
+		match := Compare(mine, theirs)
+		if match == nil {
+			continue
+		}
+
+		result.Compared++
+		matches = append(matches, match)
+		if match.Correlation >= TwinThreshold {
+			twins = append(twins, theirs)
+		}
+		if match.Correlation < 0 && (result.Opposite == nil || match.Correlation < result.Opposite.Correlation) {
+			result.Opposite = match
+		}
+	}
+	result.Twins = len(twins)
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Correlation > matches[j].Correlation
+	})
+	for _, m := range matches[:min(ClosestShown, len(matches))] {
+		if m.Correlation < TwinThreshold {
+			break
+		}
+		result.Closest = append(result.Closest, m)
+	}
+
+	result.Picks = picks(mine, twins, torrons)
+	return result
+}
+
+// Compare returns the rank correlation of two users' ratings over the
+// torrons both rated, nil when they share fewer than MinOverlap or either
+// rated them all the same.
+func Compare(a, b map[string]float64) *domain.TasteMatch {
+	var shared []string
+	for id := range a {
+		if _, ok := b[id]; ok {
+			shared = append(shared, id)
+		}
+	}
+	if len(shared) < MinOverlap {
+		return nil
+	}
+	sort.Strings(shared)
+
+	xs := make([]float64, len(shared))
+	ys := make([]float64, len(shared))
+	for i, id := range shared {
+		xs[i], ys[i] = a[id], b[id]
+	}
+
+	correlation, ok := pearson(ranks(xs), ranks(ys))
+	if !ok {
+		return nil
+	}
+	return &domain.TasteMatch{Correlation: correlation, SharedTorrons: len(shared)}
+}
+
+// picks counts, for every active torró the user hasn't rated, how many
+// twins have it among their favourites, and returns the PickLimit most
+// agreed on by at least MinPickTwins.
+func picks(mine map[string]float64, twins []map[string]float64, torrons []*domain.Torro) []*domain.TwinPick {
+	counts := make(map[string]int)
+	for _, ratings := range twins {
+		for _, id := range favourites(ratings) {
+			if _, rated := mine[id]; !rated {
+				counts[id]++
+			}
+		}
+	}
+
+	picks := []*domain.TwinPick{}
+	for _, t := range torrons {
+		if t.Discontinued || counts[t.Id] < MinPickTwins {
+			continue
+		}
+		picks = append(picks, &domain.TwinPick{
+			TorroId: t.Id,
+			Name:    t.Name,
+			Image:   t.Image,
+			Twins:   counts[t.Id],
+		})
+	}
+
+	sort.SliceStable(picks, func(i, j int) bool {
+		if picks[i].Twins != picks[j].Twins {
+			return picks[i].Twins > picks[j].Twins
+		}
+		return picks[i].TorroId < picks[j].TorroId
+	})
+	return picks[:min(PickLimit, len(picks))]
+}
+
+// favourites returns the top favouriteShare of a user's rated torrons, at
+// least one.
+func favourites(ratings map[string]float64) []string {
+	ids := make([]string, 0, len(ratings))
+	for id := range ratings {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if ratings[ids[i]] != ratings[ids[j]] {
+			return ratings[ids[i]] > ratings[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+
+	n := max(1, int(math.Ceil(float64(len(ids))*favouriteShare)))
+	return ids[:min(n, len(ids))]
+}
+
+// ranks returns each value's rank among values, 1 for the lowest, ties
+// sharing the mean of the ranks they span.
+func ranks(values []float64) []float64 {
+	idx := make([]int, len(values))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.Slice(idx, func(i, j int) bool { return values[idx[i]] < values[idx[j]] })
+
+	out := make([]float64, len(values))
+	for start := 0; start < len(idx); {
+		end := start + 1
+		for end < len(idx) && values[idx[end]] == values[idx[start]] {
+			end++
+		}
+		rank := float64(start+end+1) / 2 // mean of ranks start+1 .. end
+		for _, i := range idx[start:end] {
+			out[i] = rank
+		}
+		start = end
+	}
+	return out
+}
+
+// pearson returns the correlation of xs and ys, false when either doesn't
+// vary.
+func pearson(xs, ys []float64) (float64, bool) {
+	n := float64(len(xs))
+	var meanX, meanY float64
+	for i := range xs {
+		meanX += xs[i]
+		meanY += ys[i]
+	}
+	meanX /= n
+	meanY /= n
+
+	var cov, varX, varY float64
+	for i := range xs {
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return 0, false
+	}
+	return cov / math.Sqrt(varX*varY), true
+}
+
+func ratingsOf(snapshots []*domain.UserEloSnapshot) map[string]float64 {
+	ratings := make(map[string]float64, len(snapshots))
+	for _, s := range snapshots {
+		ratings[s.TorronId] = s.Rating
+	}
+	return ratings
+}
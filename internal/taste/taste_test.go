@@ -0,0 +1,103 @@
+package taste
+
+import (
+	"math"
+	"testing"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+func snap(user, torro string, rating float64) *domain.UserEloSnapshot {
+	return &domain.UserEloSnapshot{UserId: user, TorronId: torro, Rating: rating}
+}
+
+// rate gives user a snapshot of each torró in order, best first.
+func rate(user string, best ...string) []*domain.UserEloSnapshot {
+	snapshots := make([]*domain.UserEloSnapshot, len(best))
+	for i, id := range best {
+		snapshots[i] = snap(user, id, 1700-float64(i*40))
+	}
+	return snapshots
+}
+
+func TestRanksShareTies(t *testing.T) {
+	got := ranks([]float64{1500, 1400, 1500, 1600})
+	want := []float64{2.5, 1, 2.5, 4}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("ranks = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	mine := ratingsOf(rate("me", "a", "b", "c", "d", "e"))
+
+	// Same order on a much narrower scale: still a perfect match.
+	same := map[string]float64{"a": 1510, "b": 1509, "c": 1505, "d": 1501, "e": 1500}
+	if m := Compare(mine, same); m == nil || math.Abs(m.Correlation-1) > 1e-9 || m.SharedTorrons != 5 {
+		t.Errorf("same order = %+v, want correlation 1 over 5", m)
+	}
+
+	reversed := ratingsOf(rate("them", "e", "d", "c", "b", "a"))
+	if m := Compare(mine, reversed); m == nil || math.Abs(m.Correlation+1) > 1e-9 {
+		t.Errorf("reversed order = %+v, want correlation -1", m)
+	}
+
+	few := ratingsOf(rate("them", "a", "b", "c", "d"))
+	if m := Compare(mine, few); m != nil {
+		t.Errorf("%d shared torrons = %+v, want no comparison", len(few), m)
+	}
+
+	flat := map[string]float64{"a": 1500, "b": 1500, "c": 1500, "d": 1500, "e": 1500}
+	if m := Compare(mine, flat); m != nil {
+		t.Errorf("all-equal ratings = %+v, want no comparison", m)
+	}
+}
+
+func TestTwins(t *testing.T) {
+	torrons := []*domain.Torro{}
+	for _, id := range []string{"a", "b", "c", "d", "e", "x", "y", "z", "gone"} {
+		torrons = append(torrons, &domain.Torro{Id: id, Name: "Torró " + id, Discontinued: id == "gone"})
+	}
+
+	me := rate("me", "a", "b", "c", "d", "e")
+
+	var others []*domain.UserEloSnapshot
+	// Three twins who love x, two of whom also love gone; one also loves y.
+	others = append(others, rate("t1", "x", "gone", "a", "b", "c", "d", "e")...)
+	others = append(others, rate("t2", "x", "gone", "a", "b", "c", "e", "d")...)
+	others = append(others, rate("t3", "x", "y", "a", "b", "c", "d", "e")...)
+	// One opposite voter, who loves z.
+	others = append(others, rate("anti", "z", "e", "d", "c", "b", "a")...)
+	// One who shares too little to compare.
+	others = append(others, rate("stranger", "z", "a", "b")...)
+
+	got := Twins(me, others, torrons)
+
+	if got.Compared != 4 || got.Twins != 3 {
+		t.Errorf("compared %d, twins %d, want 4 and 3", got.Compared, got.Twins)
+	}
+	if len(got.Closest) != 3 || got.Closest[0].Correlation < got.Closest[2].Correlation {
+		t.Errorf("closest = %+v, want the 3 twins best first", got.Closest)
+	}
+	if got.Opposite == nil || math.Abs(got.Opposite.Correlation+1) > 1e-9 {
+		t.Errorf("opposite = %+v, want correlation -1", got.Opposite)
+	}
+
+	// x is every twin's favourite; gone is discontinued, y and z have a
+	// single fan among the twins or none.
+	if len(got.Picks) != 1 || got.Picks[0].TorroId != "x" || got.Picks[0].Twins != 3 {
+		t.Errorf("picks = %+v, want only x, from 3 twins", got.Picks)
+	}
+}
+
+func TestTwinsAlone(t *testing.T) {
+	got := Twins(rate("me", "a", "b"), nil, nil)
+	if got.Compared != 0 || got.Twins != 0 || got.Opposite != nil || len(got.Closest) != 0 || len(got.Picks) != 0 {
+		t.Errorf("twins with nobody to compare = %+v, want empty", got)
+	}
+	if got.Closest == nil || got.Picks == nil {
+		t.Error("empty lists should be non-nil so they encode as []")
+	}
+}
@@ -0,0 +1,234 @@
+// Package tournament plans the rounds of Phase 2 bracket formats from the
+// matches already played. It only reads and builds domain.BracketMatch
+// values; storing them and deciding their winners stays with the bracket
+// handler, so every format shares the same per-match vote flow.
+package tournament
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+// A double-elimination bracket of size 2^k is played in bracket-wide
+// rounds, each holding whatever matches are ready on both sides:
+//
+//   - winners side round r (1..k) in round r, exactly as single elimination;
+//   - losers side round r (1..2k-2) in round r+1. Odd losers rounds pair the
+//     survivors of the previous one (round 1 pairs winners round 1's
+//     losers); even ones pit those survivors against the torrons dropping
+//     down from winners round r/2+1;
+//   - the grand final in round 2k, winners-side champion first, and its
+//     reset, when there is one, in round 2k+1.
+//
+// Slots follow the same halving as the winners side, so a match's feeders
+// are always found at fixed slots of earlier rounds.
+
+// MinDoubleEliminationSize is the smallest double-elimination bracket: a
+// size-2 one would have no losers side, only a rematch.
+const MinDoubleEliminationSize = 4
+
+// DoubleEliminationRounds returns how many bracket-wide rounds a
+// double-elimination bracket of size takes through its grand final, not
+// counting a reset.
+func DoubleEliminationRounds(size int) int {
+	return 2 * log2(size)
+}
+
+// SideRound returns a double-elimination match's round within its own side:
+// the winners side counts from 1 like the bracket, the losers side starts a
+// round later, and the grand final is 1, its reset 2.
+func SideRound(size int, m *domain.BracketMatch) int {
+	switch m.Side {
+	case domain.BracketSideLosers:
+		return m.Round - 1
+	case domain.BracketSideGrandFinal:
+		return m.Round - DoubleEliminationRounds(size) + 1
+	default:
+		return m.Round
+	}
+}
+
+// LosersRounds returns how many rounds the losers side of a bracket of size
+// has.
+func LosersRounds(size int) int {
+	return 2*log2(size) - 2
+}
+
+// NextDoubleElimination plans round bracket.CurrentRound+1 of a
+// double-elimination bracket from matches, every match played so far, all
+// of them decided. It returns either the round's matches, some of which may
+// be byes that are already decided, or the champion's ID once there is
+// nothing left to play. A round can come out empty when every feeder slot
+// was a gap; the caller moves past it like a round of byes.
+func NextDoubleElimination(bracket *domain.Bracket, matches []*domain.BracketMatch) ([]*domain.BracketMatch, string, error) {
+	k := log2(bracket.Size)
+	round := bracket.CurrentRound + 1
+	p := newPlanner(bracket, round, matches)
+
+	// Winners side, exactly as in single elimination.
+	if round <= k {
+		for slot := 0; slot < bracket.Size>>round; slot++ {
+			p.add(domain.BracketSideWinners, slot,
+				p.winner(domain.BracketSideWinners, round-1, 2*slot),
+				p.winner(domain.BracketSideWinners, round-1, 2*slot+1))
+		}
+	}
+
+	// Losers side round lr, played in round lr+1: its previous round was
+	// played in round-1.
+	if lr := round - 1; lr >= 1 && lr <= LosersRounds(bracket.Size) {
+		count := bracket.Size >> ((lr+1)/2 + 1)
+		for slot := 0; slot < count; slot++ {
+			switch {
+			case lr == 1:
+				p.add(domain.BracketSideLosers, slot,
+					p.loser(domain.BracketSideWinners, 1, 2*slot),
+					p.loser(domain.BracketSideWinners, 1, 2*slot+1))
+			case lr%2 == 0:
+				dropRound := lr/2 + 1
+				p.add(domain.BracketSideLosers, slot,
+					p.winner(domain.BracketSideLosers, round-1, slot),
+					p.loser(domain.BracketSideWinners, dropRound, dropSlot(slot, count, dropRound)))
+			default:
+				p.add(domain.BracketSideLosers, slot,
+					p.winner(domain.BracketSideLosers, round-1, 2*slot),
+					p.winner(domain.BracketSideLosers, round-1, 2*slot+1))
+			}
+		}
+	}
+
+	grandFinal := DoubleEliminationRounds(bracket.Size)
+	switch {
+	case round < grandFinal:
+		return p.next, "", nil
+
+	case round == grandFinal:
+		winners := p.winner(domain.BracketSideWinners, k, 0)
+		losers := p.winner(domain.BracketSideLosers, round-1, 0)
+		switch {
+		case winners == nil && losers == nil:
+			return nil, "", fmt.Errorf("%s: bracket %s has no champion on either side", domain.ValidationError, bracket.Id)
+		case losers == nil:
+			// Nobody survived the losers side to challenge for the title.
+			return nil, *winners, nil
+		case winners == nil:
+			return nil, *losers, nil
+		}
+		p.add(domain.BracketSideGrandFinal, 0, winners, losers)
+		return p.next, "", nil
+
+	case round == grandFinal+1:
+		final := p.played[matchKey{domain.BracketSideGrandFinal, grandFinal, 0}]
+		if final == nil || final.WinnerId == nil {
+			return nil, "", fmt.Errorf("%s: bracket %s has no decided grand final", domain.ValidationError, bracket.Id)
+		}
+		// The winners-side champion losing the grand final is their first
+		// defeat: with a reset, it is played again.
+		if *final.WinnerId == final.Torro1Id || !bracket.GrandFinalReset {
+			return nil, *final.WinnerId, nil
+		}
+		p.add(domain.BracketSideGrandFinal, 0, &final.Torro1Id, final.Torro2Id)
+		return p.next, "", nil
+
+	case round == grandFinal+2:
+		reset := p.played[matchKey{domain.BracketSideGrandFinal, grandFinal + 1, 0}]
+		if reset == nil || reset.WinnerId == nil {
+			return nil, "", fmt.Errorf("%s: bracket %s has no decided grand final reset", domain.ValidationError, bracket.Id)
+		}
+		return nil, *reset.WinnerId, nil
+
+	default:
+		return nil, "", fmt.Errorf("%s: bracket %s has no round %d", domain.ValidationError, bracket.Id, round)
+	}
+}
+
+// dropSlot maps a losers-side slot to the winners-side slot whose loser
+// drops into it from winners round dropRound. Every other drop round is
+// mirrored, so torrons who met on the winners side don't meet again on the
+// losers side straight away.
+func dropSlot(slot, count, dropRound int) int {
+	if dropRound%2 == 0 {
+		return count - 1 - slot
+	}
+	return slot
+}
+
+type matchKey struct {
+	side  string
+	round int
+	slot  int
+}
+
+// planner indexes the matches played so far and collects the next round's.
+type planner struct {
+	bracket *domain.Bracket
+	round   int
+	played  map[matchKey]*domain.BracketMatch
+	next    []*domain.BracketMatch
+}
+
+func newPlanner(bracket *domain.Bracket, round int, matches []*domain.BracketMatch) *planner {
+	played := make(map[matchKey]*domain.BracketMatch, len(matches))
+	for _, m := range matches {
+		side := m.Side
+		if side == "" {
+			side = domain.BracketSideWinners
+		}
+		played[matchKey{side, m.Round, m.Slot}] = m
+	}
+	return &planner{bracket: bracket, round: round, played: played}
+}
+
+// winner returns who won the match at side, round and slot, nil when there
+// was no match there.
+func (p *planner) winner(side string, round, slot int) *string {
+	if m := p.played[matchKey{side, round, slot}]; m != nil {
+		return m.WinnerId
+	}
+	return nil
+}
+
+// loser returns who lost the match at side, round and slot, nil when there
+// was no match there or it was a bye.
+func (p *planner) loser(side string, round, slot int) *string {
+	if m := p.played[matchKey{side, round, slot}]; m != nil {
+		return m.LoserId()
+	}
+	return nil
+}
+
+// add plans a match between a and b: none when both are missing, a bye the
+// present one wins when one is.
+func (p *planner) add(side string, slot int, a, b *string) {
+	if a == nil && b == nil {
+		return
+	}
+
+	match := &domain.BracketMatch{
+		BracketId: p.bracket.Id,
+		Round:     p.round,
+		Side:      side,
+		Slot:      slot,
+	}
+	if a != nil && b != nil {
+		match.Torro1Id = *a
+		torro2Id := *b
+		match.Torro2Id = &torro2Id
+		match.Status = domain.BracketMatchStatusPending
+	} else {
+		if a == nil {
+			a = b
+		}
+		match.Torro1Id = *a
+		winnerId := *a
+		match.WinnerId = &winnerId
+		match.Status = domain.BracketMatchStatusCompleted
+	}
+	p.next = append(p.next, match)
+}
+
+func log2(size int) int {
+	return bits.Len(uint(size)) - 1
+}
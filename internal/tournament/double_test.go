@@ -0,0 +1,204 @@
+package tournament
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+// roundOne lays out winners round 1 of a size-8 bracket holding seeds 1..n,
+// torró IDs being their seeds: 1v8, 4v5, 2v7, 3v6, a missing seed a bye.
+func roundOne(n int) []*domain.BracketMatch {
+	var matches []*domain.BracketMatch
+	for slot, pair := range [][2]int{{1, 8}, {4, 5}, {2, 7}, {3, 6}} {
+		a, b := pair[0], pair[1]
+		if a > n && b > n {
+			continue
+		}
+		m := &domain.BracketMatch{Round: 1, Side: domain.BracketSideWinners, Slot: slot}
+		switch {
+		case a <= n && b <= n:
+			m.Torro1Id = strconv.Itoa(a)
+			torro2Id := strconv.Itoa(b)
+			m.Torro2Id = &torro2Id
+			m.Status = domain.BracketMatchStatusPending
+		default:
+			m.Torro1Id = strconv.Itoa(min(a, b))
+			m.WinnerId = &m.Torro1Id
+			m.Status = domain.BracketMatchStatusCompleted
+		}
+		matches = append(matches, m)
+	}
+	return matches
+}
+
+// favourite decides a match for the better (lower) seed.
+func favourite(m *domain.BracketMatch) string {
+	a, _ := strconv.Atoi(m.Torro1Id)
+	b, _ := strconv.Atoi(*m.Torro2Id)
+	if a < b {
+		return m.Torro1Id
+	}
+	return *m.Torro2Id
+}
+
+// play runs a size-8 double-elimination bracket to its champion, deciding
+// every pending match with decide.
+func play(t *testing.T, reset bool, matches []*domain.BracketMatch, decide func(*domain.BracketMatch) string) ([]*domain.BracketMatch, string) {
+	t.Helper()
+	bracket := &domain.Bracket{
+		Id:              "b",
+		Size:            8,
+		Format:          domain.BracketFormatDoubleElimination,
+		GrandFinalReset: reset,
+		CurrentRound:    1,
+	}
+
+	for range 20 {
+		for _, m := range matches {
+			if m.Status == domain.BracketMatchStatusPending {
+				winnerId := decide(m)
+				m.WinnerId = &winnerId
+				m.Status = domain.BracketMatchStatusCompleted
+			}
+		}
+
+		next, champion, err := NextDoubleElimination(bracket, matches)
+		if err != nil {
+			t.Fatalf("round %d: %v", bracket.CurrentRound+1, err)
+		}
+		if champion != "" {
+			return matches, champion
+		}
+		matches = append(matches, next...)
+		bracket.CurrentRound++
+	}
+	t.Fatal("the bracket never produced a champion")
+	return nil, ""
+}
+
+// losses counts each torró's defeats.
+func losses(matches []*domain.BracketMatch) map[string]int {
+	count := make(map[string]int)
+	for _, m := range matches {
+		if loser := m.LoserId(); loser != nil {
+			count[*loser]++
+		}
+	}
+	return count
+}
+
+func TestDoubleEliminationFavouritesWin(t *testing.T) {
+	matches, champion := play(t, true, roundOne(8), favourite)
+
+	if champion != "1" {
+		t.Errorf("champion = %s, want seed 1", champion)
+	}
+	// Seven torrons out on two defeats each, and an unbeaten champion.
+	if len(matches) != 14 {
+		t.Errorf("played %d matches, want 14", len(matches))
+	}
+	for id, n := range losses(matches) {
+		if n != 2 {
+			t.Errorf("torró %s lost %d times, want 2", id, n)
+		}
+	}
+
+	last := matches[len(matches)-1]
+	if last.Side != domain.BracketSideGrandFinal || last.Round != DoubleEliminationRounds(8) {
+		t.Fatalf("last match = %s round %d, want the grand final in round %d", last.Side, last.Round, DoubleEliminationRounds(8))
+	}
+	if last.Torro1Id != "1" || *last.Torro2Id != "2" {
+		t.Errorf("grand final = %s v %s, want 1 v 2", last.Torro1Id, *last.Torro2Id)
+	}
+}
+
+func TestDoubleEliminationLosersSideRouting(t *testing.T) {
+	matches, _ := play(t, true, roundOne(8), favourite)
+
+	var losersRounds []int
+	for _, m := range matches {
+		if m.Side == domain.BracketSideLosers {
+			losersRounds = append(losersRounds, SideRound(8, m))
+		}
+	}
+	// 2 + 2 + 1 + 1 matches over losers rounds 1 to 4.
+	want := []int{1, 1, 2, 2, 3, 4}
+	if len(losersRounds) != len(want) {
+		t.Fatalf("losers side rounds = %v, want %v", losersRounds, want)
+	}
+	for i := range want {
+		if losersRounds[i] != want[i] {
+			t.Fatalf("losers side rounds = %v, want %v", losersRounds, want)
+		}
+	}
+
+	// Winners round 2's losers drop in mirrored: 3, beaten in the bottom
+	// half, meets 5, who came through the top half's losers.
+	for _, m := range matches {
+		if m.Side == domain.BracketSideLosers && SideRound(8, m) == 2 && m.Slot == 0 {
+			if m.Torro1Id != "5" || *m.Torro2Id != "3" {
+				t.Errorf("losers round 2 slot 0 = %s v %s, want 5 v 3", m.Torro1Id, *m.Torro2Id)
+			}
+		}
+	}
+}
+
+func TestDoubleEliminationGrandFinalReset(t *testing.T) {
+	// Seed 2 comes through the losers side and beats seed 1 in the grand
+	// final, its first defeat.
+	upset := func(m *domain.BracketMatch) string {
+		if m.Side == domain.BracketSideGrandFinal && m.Round == DoubleEliminationRounds(8) {
+			return *m.Torro2Id
+		}
+		return favourite(m)
+	}
+
+	matches, champion := play(t, true, roundOne(8), upset)
+	if len(matches) != 15 || champion != "1" {
+		t.Errorf("with a reset: %d matches, champion %s, want 15 and seed 1 winning the reset", len(matches), champion)
+	}
+
+	matches, champion = play(t, false, roundOne(8), upset)
+	if len(matches) != 14 || champion != "2" {
+		t.Errorf("without a reset: %d matches, champion %s, want 14 and seed 2", len(matches), champion)
+	}
+}
+
+func TestDoubleEliminationByes(t *testing.T) {
+	// Five torrons: three byes in winners round 1 leave the losers side
+	// with gaps and byes of its own.
+	matches, champion := play(t, true, roundOne(5), favourite)
+
+	if champion != "1" {
+		t.Errorf("champion = %s, want seed 1", champion)
+	}
+	count := losses(matches)
+	for _, id := range []string{"2", "3", "4", "5"} {
+		if count[id] != 2 {
+			t.Errorf("torró %s lost %d times, want 2", id, count[id])
+		}
+	}
+	if count["1"] != 0 {
+		t.Errorf("champion lost %d times, want 0", count["1"])
+	}
+}
+
+func TestSideRound(t *testing.T) {
+	for _, tt := range []struct {
+		side  string
+		round int
+		want  int
+	}{
+		{domain.BracketSideWinners, 3, 3},
+		{domain.BracketSideLosers, 2, 1},
+		{domain.BracketSideLosers, 5, 4},
+		{domain.BracketSideGrandFinal, 6, 1},
+		{domain.BracketSideGrandFinal, 7, 2},
+	} {
+		if got := SideRound(8, &domain.BracketMatch{Side: tt.side, Round: tt.round}); got != tt.want {
+			t.Errorf("SideRound(%s round %d) = %d, want %d", tt.side, tt.round, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package tournament
+
+import (
+	"sort"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+// A groups-then-knockout bracket opens with a group stage:
+//
+//   - the field, in seed order, is split into pots as big as the number of
+//     groups (pot 1 holds seeds 1..G, pot 2 seeds G+1..2G, ...) and every
+//     group draws one torró from each pot, so no group gets two favourites;
+//   - each group plays a round robin, one matchday per bracket round, with
+//     a torró resting each matchday in an odd-sized group;
+//   - group tables rank by wins, then by wins among the torrons still tied,
+//     then by seed, and the top of each table goes through.
+//
+// The knockout is seeded by finishing position: group winners first, then
+// runners-up, each tier by wins and seed, and laid out like any other
+// single-elimination tree.
+
+// MaxGroupSize bounds a group: a group of 8 already plays 7 matchdays.
+const MaxGroupSize = 8
+
+// GroupName returns the letter a group is known by: "A" for position 0.
+func GroupName(position int) string {
+	return string(rune('A' + position))
+}
+
+// DrawGroups draws entries, in seed order, into groups pot by pot. shuffle
+// randomizes each pot's draw, with the signature of rand.Shuffle; nil keeps
+// pot order, seed 1 to group A, seed 2 to group B, and so on. When the
+// field doesn't fill the last pot, the groups it misses are one short.
+func DrawGroups(entries []*domain.BracketEntry, groups int, shuffle func(n int, swap func(i, j int))) [][]*domain.BracketEntry {
+	drawn := make([][]*domain.BracketEntry, groups)
+	for start := 0; start < len(entries); start += groups {
+		pot := append([]*domain.BracketEntry(nil), entries[start:min(start+groups, len(entries))]...)
+		if shuffle != nil {
+			shuffle(len(pot), func(i, j int) { pot[i], pot[j] = pot[j], pot[i] })
+		}
+
+		// A partial last pot still can't pile up in the same groups, so
+		// its torrons go to a random subset of them.
+		targets := make([]int, groups)
+		for i := range targets {
+			targets[i] = i
+		}
+		if len(pot) < groups && shuffle != nil {
+			shuffle(groups, func(i, j int) { targets[i], targets[j] = targets[j], targets[i] })
+		}
+
+		for i, e := range pot {
+			drawn[targets[i]] = append(drawn[targets[i]], e)
+		}
+	}
+	return drawn
+}
+
+// RoundRobin schedules a group with the circle method: matchday d's
+// pairings, every torró meeting every other exactly once. An odd-sized
+// group takes one more matchday, with a different torró resting each time.
+func RoundRobin(ids []string) [][][2]string {
+	circle := append([]string(nil), ids...)
+	if len(circle)%2 == 1 {
+		circle = append(circle, "") // the rest
+	}
+	n := len(circle)
+
+	var matchdays [][][2]string
+	for range n - 1 {
+		var day [][2]string
+		for i := range n / 2 {
+			a, b := circle[i], circle[n-1-i]
+			if a != "" && b != "" {
+				day = append(day, [2]string{a, b})
+			}
+		}
+		matchdays = append(matchdays, day)
+
+		// Keep the first torró fixed and turn everyone else one place.
+		last := circle[n-1]
+		copy(circle[2:], circle[1:n-1])
+		circle[1] = last
+	}
+	return matchdays
+}
+
+// GroupStageMatchdays returns how many matchdays a field of n drawn into
+// groups plays: the largest group's round robin.
+func GroupStageMatchdays(n, groups int) int {
+	if groups < 1 || n < 2 {
+		return 0
+	}
+	largest := (n + groups - 1) / groups
+	if largest%2 == 1 {
+		return largest
+	}
+	return largest - 1
+}
+
+// GroupStageMatches builds every match of the group stage, matchday d of
+// each group being played in bracket round d. Slots number a round's
+// matches across groups. It also returns how many matchdays the stage
+// lasts: the largest group's.
+func GroupStageMatches(bracketId string, groups []*domain.BracketGroup, members [][]*domain.BracketEntry) ([]*domain.BracketMatch, int) {
+	var matches []*domain.BracketMatch
+	matchdays := 0
+	slots := make(map[int]int)
+
+	for g, group := range groups {
+		ids := make([]string, 0, len(members[g]))
+		for _, e := range members[g] {
+			ids = append(ids, e.TorronId)
+		}
+
+		schedule := RoundRobin(ids)
+		matchdays = max(matchdays, len(schedule))
+		for d, day := range schedule {
+			round := d + 1
+			for _, pair := range day {
+				groupId := group.Id
+				torro2Id := pair[1]
+				matches = append(matches, &domain.BracketMatch{
+					BracketId: bracketId,
+					Round:     round,
+					Side:      domain.BracketSideGroup,
+					GroupId:   &groupId,
+					Slot:      slots[round],
+					Torro1Id:  pair[0],
+					Torro2Id:  &torro2Id,
+					Status:    domain.BracketMatchStatusPending,
+				})
+				slots[round]++
+			}
+		}
+	}
+	return matches, matchdays
+}
+
+// GroupStanding is one torró's line in its group table. Rank is 1-based,
+// ties already broken.
+type GroupStanding struct {
+	Rank    int
+	TorroId string
+	Seed    int
+	Played  int
+	Wins    int
+	Losses  int
+}
+
+// GroupStandings ranks one group's members from its decided matches: by
+// wins, then by wins in the matches between the torrons still level, then
+// by seed.
+func GroupStandings(members []*domain.BracketEntry, matches []*domain.BracketMatch) []*GroupStanding {
+	byTorro := make(map[string]*GroupStanding, len(members))
+	table := make([]*GroupStanding, 0, len(members))
+	for _, e := range members {
+		s := &GroupStanding{TorroId: e.TorronId, Seed: e.Seed}
+		byTorro[e.TorronId] = s
+		table = append(table, s)
+	}
+
+	var decided []*domain.BracketMatch
+	for _, m := range matches {
+		loser := m.LoserId()
+		if loser == nil || byTorro[*m.WinnerId] == nil || byTorro[*loser] == nil {
+			continue
+		}
+		decided = append(decided, m)
+		byTorro[*m.WinnerId].Wins++
+		byTorro[*m.WinnerId].Played++
+		byTorro[*loser].Losses++
+		byTorro[*loser].Played++
+	}
+
+	// Head-to-head wins among each torró's level rivals.
+	headToHead := make(map[string]int)
+	for _, m := range decided {
+		loser := *m.LoserId()
+		if byTorro[*m.WinnerId].Wins == byTorro[loser].Wins {
+			headToHead[*m.WinnerId]++
+		}
+	}
+
+	sort.SliceStable(table, func(i, j int) bool {
+		a, b := table[i], table[j]
+		if a.Wins != b.Wins {
+			return a.Wins > b.Wins
+		}
+		if headToHead[a.TorroId] != headToHead[b.TorroId] {
+			return headToHead[a.TorroId] > headToHead[b.TorroId]
+		}
+		return a.Seed < b.Seed
+	})
+	for i, s := range table {
+		s.Rank = i + 1
+	}
+	return table
+}
+
+// KnockoutField returns the torrons going through from the group tables,
+// in knockout seed order: the group winners, then the runners-up, and so
+// on down to advance places, each tier by wins and then seed.
+func KnockoutField(tables [][]*GroupStanding, advance int) []string {
+	var field []string
+	for place := range advance {
+		var tier []*GroupStanding
+		for _, table := range tables {
+			if place < len(table) {
+				tier = append(tier, table[place])
+			}
+		}
+		sort.SliceStable(tier, func(i, j int) bool {
+			if tier[i].Wins != tier[j].Wins {
+				return tier[i].Wins > tier[j].Wins
+			}
+			return tier[i].Seed < tier[j].Seed
+		})
+		for _, s := range tier {
+			field = append(field, s.TorroId)
+		}
+	}
+	return field
+}
+
+// SeparateGroupMates reorders knockout pairings so that, wherever a swap
+// allows it, no first-round match is a rematch between two torrons from
+// the same group: the second torró of such a pairing trades places with
+// the second torró of the nearest later pairing where that clears both.
+func SeparateGroupMates(pairs [][2]string, groupOf map[string]string) {
+	clash := func(a, b string) bool {
+		return a != "" && b != "" && groupOf[a] == groupOf[b]
+	}
+	for i := range pairs {
+		if !clash(pairs[i][0], pairs[i][1]) {
+			continue
+		}
+		for step := 1; step < len(pairs); step++ {
+			j := (i + step) % len(pairs)
+			if !clash(pairs[i][0], pairs[j][1]) && !clash(pairs[j][0], pairs[i][1]) {
+				pairs[i][1], pairs[j][1] = pairs[j][1], pairs[i][1]
+				break
+			}
+		}
+	}
+}
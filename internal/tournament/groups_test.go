@@ -0,0 +1,146 @@
+package tournament
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+func TestDrawGroupsOneFromEachPot(t *testing.T) {
+	reverse := func(n int, swap func(i, j int)) {
+		for i := range n / 2 {
+			swap(i, n-1-i)
+		}
+	}
+	drawn := DrawGroups(swissEntries(8), 4, reverse)
+
+	if len(drawn) != 4 {
+		t.Fatalf("drew %d groups, want 4", len(drawn))
+	}
+	for g, group := range drawn {
+		if len(group) != 2 {
+			t.Fatalf("group %s has %d torrons, want 2", GroupName(g), len(group))
+		}
+		if group[0].Seed > 4 || group[1].Seed <= 4 {
+			t.Errorf("group %s = seeds %d and %d, want one from each pot", GroupName(g), group[0].Seed, group[1].Seed)
+		}
+	}
+	if drawn[0][0].Seed != 4 {
+		t.Errorf("group A's pot 1 torró = seed %d, want 4 after the reversed draw", drawn[0][0].Seed)
+	}
+
+	// Ten torrons in four groups: the last pot leaves two groups short.
+	sizes := make(map[int]int)
+	for _, group := range DrawGroups(swissEntries(10), 4, nil) {
+		sizes[len(group)]++
+	}
+	if sizes[3] != 2 || sizes[2] != 2 {
+		t.Errorf("group sizes = %v, want two of 3 and two of 2", sizes)
+	}
+}
+
+func TestRoundRobinEveryoneMeetsOnce(t *testing.T) {
+	for _, n := range []int{2, 3, 4, 5} {
+		ids := make([]string, n)
+		for i := range ids {
+			ids[i] = strconv.Itoa(i + 1)
+		}
+		schedule := RoundRobin(ids)
+
+		wantDays := n - 1
+		if n%2 == 1 {
+			wantDays = n
+		}
+		if len(schedule) != wantDays {
+			t.Errorf("%d torrons: %d matchdays, want %d", n, len(schedule), wantDays)
+		}
+
+		met := make(map[[2]string]int)
+		for _, day := range schedule {
+			playing := make(map[string]bool)
+			for _, pair := range day {
+				if playing[pair[0]] || playing[pair[1]] {
+					t.Errorf("%d torrons: someone plays twice on one matchday: %v", n, day)
+				}
+				playing[pair[0]], playing[pair[1]] = true, true
+				if pair[0] > pair[1] {
+					pair[0], pair[1] = pair[1], pair[0]
+				}
+				met[pair]++
+			}
+		}
+		if len(met) != n*(n-1)/2 {
+			t.Errorf("%d torrons: %d distinct pairings, want %d", n, len(met), n*(n-1)/2)
+		}
+		for pair, count := range met {
+			if count != 1 {
+				t.Errorf("%d torrons: %v met %d times", n, pair, count)
+			}
+		}
+	}
+}
+
+func TestGroupStandingsTieBreaks(t *testing.T) {
+	decided := func(a, b, winner string) *domain.BracketMatch {
+		return &domain.BracketMatch{Torro1Id: a, Torro2Id: &b, WinnerId: &winner, Status: domain.BracketMatchStatusCompleted}
+	}
+	// 4 and 2 both win twice; 4 beat 2, so 4 tops the group despite its
+	// seed. 1 and 3 are level on one win each; 3 beat 1, so 3 is third.
+	matches := []*domain.BracketMatch{
+		decided("1", "2", "2"),
+		decided("3", "4", "4"),
+		decided("1", "3", "3"),
+		decided("2", "4", "4"),
+		decided("1", "4", "1"),
+		decided("2", "3", "2"),
+	}
+	table := GroupStandings(swissEntries(4), matches)
+
+	var order string
+	for _, s := range table {
+		order += s.TorroId
+	}
+	if order != "4231" {
+		t.Errorf("table = %s, want 4231", order)
+	}
+	if table[0].Played != 3 || table[0].Wins != 2 || table[0].Losses != 1 {
+		t.Errorf("leader = %+v, want 3 played, 2 wins, 1 loss", table[0])
+	}
+}
+
+func TestKnockoutFieldAndSeparation(t *testing.T) {
+	tables := [][]*GroupStanding{
+		{{TorroId: "a1", Seed: 1, Wins: 3}, {TorroId: "a2", Seed: 5, Wins: 2}},
+		{{TorroId: "b1", Seed: 2, Wins: 2}, {TorroId: "b2", Seed: 6, Wins: 2}},
+	}
+	field := KnockoutField(tables, 2)
+	// Runners-up a2 and b2 are level on wins: a2 is the better seed.
+	want := []string{"a1", "b1", "a2", "b2"}
+	for i := range want {
+		if field[i] != want[i] {
+			t.Fatalf("field = %v, want %v", field, want)
+		}
+	}
+
+	// A first round that pairs group mates twice gets untangled.
+	pairs := [][2]string{{"a1", "a2"}, {"b1", "b2"}}
+	SeparateGroupMates(pairs, map[string]string{"a1": "A", "a2": "A", "b1": "B", "b2": "B"})
+	if pairs[0][1] != "b2" || pairs[1][1] != "a2" {
+		t.Errorf("pairs = %v, want a1 v b2 and b1 v a2", pairs)
+	}
+}
+
+func TestGroupStageMatchdays(t *testing.T) {
+	for _, tt := range []struct{ n, groups, want int }{
+		{16, 4, 3},
+		{10, 4, 3},
+		{12, 4, 3},
+		{8, 4, 1},
+		{20, 4, 5},
+	} {
+		if got := GroupStageMatchdays(tt.n, tt.groups); got != tt.want {
+			t.Errorf("GroupStageMatchdays(%d, %d) = %d, want %d", tt.n, tt.groups, got, tt.want)
+		}
+	}
+}
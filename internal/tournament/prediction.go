@@ -0,0 +1,107 @@
+package tournament
+
+import (
+	"fmt"
+	"math/bits"
+	"slices"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+// A pick'em prediction names the winner of every match slot of a
+// single-elimination tree, round by round up to the final:
+//
+//   - a round-1 pick is one of the slot's two entrants;
+//   - a later pick is one of the two torrons the prediction has winning
+//     the slots that feed it, 2*slot and 2*slot+1 of the round before;
+//   - a slot with a single possible winner (a bye, or a round whose other
+//     feeder is empty) needs no pick, and one no torró can reach has none.
+
+// PredictionCandidates returns, indexed [round-1][slot], the torrons that
+// can reach each match of a single-elimination bracket of size whose
+// round-1 matches are roundOne: a round-1 match's entrants, then the
+// entrants of the two slots that feed each later one. A slot of a class
+// smaller than the bracket that no torró can reach is empty.
+func PredictionCandidates(size int, roundOne []*domain.BracketMatch) [][][]string {
+	rounds := bits.Len(uint(size)) - 1
+	candidates := make([][][]string, rounds)
+	if rounds == 0 {
+		return candidates
+	}
+
+	candidates[0] = make([][]string, size/2)
+	for _, m := range roundOne {
+		if m.Slot < 0 || m.Slot >= len(candidates[0]) {
+			continue
+		}
+		candidates[0][m.Slot] = []string{m.Torro1Id}
+		if m.Torro2Id != nil {
+			candidates[0][m.Slot] = append(candidates[0][m.Slot], *m.Torro2Id)
+		}
+	}
+
+	for r := 1; r < rounds; r++ {
+		candidates[r] = make([][]string, len(candidates[r-1])/2)
+		for slot := range candidates[r] {
+			candidates[r][slot] = slices.Concat(candidates[r-1][2*slot], candidates[r-1][2*slot+1])
+		}
+	}
+	return candidates
+}
+
+// CheckPrediction checks picks against the candidates PredictionCandidates
+// returned and fills in the ones with a single possible winner. It returns
+// every pick in round and slot order, and the champion the prediction
+// names.
+func CheckPrediction(candidates [][][]string, picks []*domain.BracketPredictionPick) ([]*domain.BracketPredictionPick, string, error) {
+	type position struct{ round, slot int }
+	picked := make(map[position]string, len(picks))
+	for _, p := range picks {
+		if p.Round < 1 || p.Round > len(candidates) || p.Slot < 0 || p.Slot >= len(candidates[p.Round-1]) ||
+			len(candidates[p.Round-1][p.Slot]) == 0 {
+			return nil, "", fmt.Errorf("%s: round %d has no match %d to predict", domain.ValidationError, p.Round, p.Slot+1)
+		}
+		picked[position{p.Round, p.Slot}] = p.TorronId
+	}
+
+	winners := make([][]string, len(candidates))
+	var checked []*domain.BracketPredictionPick
+	for r := range candidates {
+		winners[r] = make([]string, len(candidates[r]))
+		for slot, reach := range candidates[r] {
+			if len(reach) == 0 {
+				continue
+			}
+
+			allowed := reach
+			if r > 0 {
+				allowed = nil
+				for _, feeder := range []string{winners[r-1][2*slot], winners[r-1][2*slot+1]} {
+					if feeder != "" {
+						allowed = append(allowed, feeder)
+					}
+				}
+			}
+
+			pick := picked[position{r + 1, slot}]
+			if pick == "" && len(allowed) == 1 {
+				pick = allowed[0]
+			}
+			if pick == "" {
+				return nil, "", fmt.Errorf("%s: round %d, match %d has no pick", domain.ValidationError, r+1, slot+1)
+			}
+			if !slices.Contains(allowed, pick) {
+				return nil, "", fmt.Errorf("%s: round %d, match %d can only be won by a torró the prediction sends there",
+					domain.ValidationError, r+1, slot+1)
+			}
+
+			winners[r][slot] = pick
+			checked = append(checked, &domain.BracketPredictionPick{Round: r + 1, Slot: slot, TorronId: pick})
+		}
+	}
+
+	if len(winners) == 0 || len(winners[len(winners)-1]) == 0 || winners[len(winners)-1][0] == "" {
+		return nil, "", fmt.Errorf("%s: the bracket has no final to predict", domain.ValidationError)
+	}
+	return checked, winners[len(winners)-1][0], nil
+}
@@ -0,0 +1,74 @@
+package tournament
+
+import (
+	"testing"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+// predictionRoundOne is round 1 of a bracket of 8 with six torrons: seeds
+// 1 and 2 have byes.
+func predictionRoundOne() []*domain.BracketMatch {
+	pair := func(slot int, a, b string) *domain.BracketMatch {
+		m := &domain.BracketMatch{Round: 1, Slot: slot, Torro1Id: a}
+		if b != "" {
+			m.Torro2Id = &b
+		}
+		return m
+	}
+	return []*domain.BracketMatch{pair(0, "1", ""), pair(1, "4", "5"), pair(2, "3", "6"), pair(3, "2", "")}
+}
+
+func TestPredictionCandidates(t *testing.T) {
+	candidates := PredictionCandidates(8, predictionRoundOne())
+
+	if len(candidates) != 3 {
+		t.Fatalf("%d rounds, want 3", len(candidates))
+	}
+	if got := candidates[1][0]; len(got) != 3 || got[0] != "1" || got[2] != "5" {
+		t.Errorf("round 2, match 1 candidates = %v, want [1 4 5]", got)
+	}
+	if got := candidates[2][0]; len(got) != 6 {
+		t.Errorf("final candidates = %v, want all six torrons", got)
+	}
+}
+
+func TestCheckPrediction(t *testing.T) {
+	candidates := PredictionCandidates(8, predictionRoundOne())
+	pick := func(round, slot int, id string) *domain.BracketPredictionPick {
+		return &domain.BracketPredictionPick{Round: round, Slot: slot, TorronId: id}
+	}
+
+	// The byes need no pick.
+	picks, champion, err := CheckPrediction(candidates, []*domain.BracketPredictionPick{
+		pick(1, 1, "5"), pick(1, 2, "3"),
+		pick(2, 0, "5"), pick(2, 1, "2"),
+		pick(3, 0, "5"),
+	})
+	if err != nil {
+		t.Fatalf("CheckPrediction: %v", err)
+	}
+	if champion != "5" {
+		t.Errorf("champion = %s, want 5", champion)
+	}
+	if len(picks) != 7 || picks[0].TorronId != "1" || picks[3].TorronId != "2" {
+		t.Errorf("picks = %d with round 1 = %s, %s; want 7 with the byes filled in", len(picks), picks[0].TorronId, picks[3].TorronId)
+	}
+
+	for name, bad := range map[string][]*domain.BracketPredictionPick{
+		"a missing pick": {pick(1, 1, "5"), pick(1, 2, "3"), pick(2, 0, "5"), pick(2, 1, "2")},
+		"a knocked-out torró": {
+			pick(1, 1, "5"), pick(1, 2, "3"), pick(2, 0, "4"), pick(2, 1, "2"), pick(3, 0, "4"),
+		},
+		"a torró from the other half": {
+			pick(1, 1, "5"), pick(1, 2, "3"), pick(2, 0, "3"), pick(2, 1, "2"), pick(3, 0, "3"),
+		},
+		"a match that doesn't exist": {
+			pick(1, 1, "5"), pick(1, 2, "3"), pick(2, 0, "5"), pick(2, 1, "2"), pick(3, 0, "5"), pick(4, 0, "5"),
+		},
+	} {
+		if _, _, err := CheckPrediction(candidates, bad); err == nil {
+			t.Errorf("a prediction with %s was accepted", name)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package tournament
+
+import (
+	"cmp"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+// A bracket's seeds come from one of the domain.BracketSeeding*
+// strategies. Each of these orders a class's field best first, seed 1
+// leading; the caller keeps as many as the bracket takes.
+
+// ExcludeSeeds returns field without the torrons in exclude, each of which
+// must be in it.
+func ExcludeSeeds(field []*domain.Torro, exclude []string) ([]*domain.Torro, error) {
+	kept := slices.Clone(field)
+	for _, id := range exclude {
+		i := slices.IndexFunc(kept, func(t *domain.Torro) bool { return t.Id == id })
+		if i < 0 {
+			return nil, fmt.Errorf("%s: can't exclude %s, which is not an active torró of the class", domain.ValidationError, id)
+		}
+		kept = slices.Delete(kept, i, i+1)
+	}
+	return kept, nil
+}
+
+// SeedByScore orders field by descending score, ties broken by id so the
+// order is reproducible. A torró with no score is left out.
+func SeedByScore(field []*domain.Torro, scores map[string]float64) []*domain.Torro {
+	var seeded []*domain.Torro
+	for _, t := range field {
+		if _, ok := scores[t.Id]; ok {
+			seeded = append(seeded, t)
+		}
+	}
+	slices.SortFunc(seeded, func(a, b *domain.Torro) int {
+		if c := cmp.Compare(scores[b.Id], scores[a.Id]); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Id, b.Id)
+	})
+	return seeded
+}
+
+// SeedByDraw shuffles field, taken in id order, with a PCG stream seeded
+// by seed. The Fisher–Yates shuffle reads the stream directly rather than
+// through rand.Rand's helpers, whose algorithms are not promised to stay
+// the same, so a published seed draws the same seeds on any Go release.
+func SeedByDraw(field []*domain.Torro, seed int64) []*domain.Torro {
+	drawn := slices.Clone(field)
+	slices.SortFunc(drawn, func(a, b *domain.Torro) int { return cmp.Compare(a.Id, b.Id) })
+
+	stream := rand.NewPCG(uint64(seed), 0)
+	for i := len(drawn) - 1; i > 0; i-- {
+		j := int(stream.Uint64() % uint64(i+1))
+		drawn[i], drawn[j] = drawn[j], drawn[i]
+	}
+	return drawn
+}
+
+// SeedByList orders field as the seed list ids does, best first. Every id
+// must name a torró of field, once; torrons the list leaves out are not
+// seeded.
+func SeedByList(field []*domain.Torro, ids []string) ([]*domain.Torro, error) {
+	seeded := make([]*domain.Torro, 0, len(ids))
+	for i, id := range ids {
+		if slices.Contains(ids[:i], id) {
+			return nil, fmt.Errorf("%s: the seed list names %s twice", domain.ValidationError, id)
+		}
+		j := slices.IndexFunc(field, func(t *domain.Torro) bool { return t.Id == id })
+		if j < 0 {
+			return nil, fmt.Errorf("%s: the seed list names %s, which is not an active torró of the class or is excluded",
+				domain.ValidationError, id)
+		}
+		seeded = append(seeded, field[j])
+	}
+	return seeded, nil
+}
@@ -0,0 +1,96 @@
+package tournament
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+func seedingField() []*domain.Torro {
+	return []*domain.Torro{{Id: "a"}, {Id: "b"}, {Id: "c"}, {Id: "d"}, {Id: "e"}}
+}
+
+func seedIds(torrons []*domain.Torro) []string {
+	ids := make([]string, 0, len(torrons))
+	for _, t := range torrons {
+		ids = append(ids, t.Id)
+	}
+	return ids
+}
+
+func TestExcludeSeeds(t *testing.T) {
+	field := seedingField()
+	kept, err := ExcludeSeeds(field, []string{"b", "d"})
+	if err != nil {
+		t.Fatalf("ExcludeSeeds: %v", err)
+	}
+	if got := seedIds(kept); !slices.Equal(got, []string{"a", "c", "e"}) {
+		t.Errorf("kept %v, want [a c e]", got)
+	}
+	if len(field) != 5 {
+		t.Errorf("the field was changed to %v", seedIds(field))
+	}
+
+	if _, err := ExcludeSeeds(field, []string{"z"}); err == nil {
+		t.Error("excluding a torró outside the field was accepted")
+	}
+	if _, err := ExcludeSeeds(field, []string{"a", "a"}); err == nil {
+		t.Error("excluding a torró twice was accepted")
+	}
+}
+
+func TestSeedByScore(t *testing.T) {
+	// c and a tie, and e has no score.
+	seeded := SeedByScore(seedingField(), map[string]float64{"a": 1500, "b": 1400, "c": 1500, "d": 1600})
+	if got := seedIds(seeded); !slices.Equal(got, []string{"d", "a", "c", "b"}) {
+		t.Errorf("seeds %v, want [d a c b]", got)
+	}
+}
+
+func TestSeedByDraw(t *testing.T) {
+	field := seedingField()
+	first := seedIds(SeedByDraw(field, 42))
+
+	// The draw depends on the seed alone, not on the field's order.
+	reversed := slices.Clone(field)
+	slices.Reverse(reversed)
+	if again := seedIds(SeedByDraw(reversed, 42)); !slices.Equal(first, again) {
+		t.Errorf("seed 42 drew %v, then %v", first, again)
+	}
+
+	sorted := slices.Clone(first)
+	slices.Sort(sorted)
+	if !slices.Equal(sorted, []string{"a", "b", "c", "d", "e"}) {
+		t.Errorf("seed 42 drew %v, want every torró once", first)
+	}
+
+	differs := false
+	for seed := range int64(8) {
+		if !slices.Equal(seedIds(SeedByDraw(field, seed)), first) {
+			differs = true
+		}
+	}
+	if !differs {
+		t.Error("eight seeds all drew the same order")
+	}
+}
+
+func TestSeedByList(t *testing.T) {
+	seeded, err := SeedByList(seedingField(), []string{"c", "a", "e"})
+	if err != nil {
+		t.Fatalf("SeedByList: %v", err)
+	}
+	if got := seedIds(seeded); !slices.Equal(got, []string{"c", "a", "e"}) {
+		t.Errorf("seeds %v, want [c a e]", got)
+	}
+
+	for name, ids := range map[string][]string{
+		"a repeated torró": {"a", "b", "a"},
+		"an unknown torró": {"a", "z"},
+	} {
+		if _, err := SeedByList(seedingField(), ids); err == nil {
+			t.Errorf("a seed list with %s was accepted", name)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package tournament
+
+import (
+	"math/bits"
+	"sort"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+// A Swiss bracket plays a fixed number of rounds and knocks no one out.
+// Every round pairs torrons on equal (or the closest) running scores, never
+// twice the same pair while that can be avoided:
+//
+//   - each score group, in standings order, is split in two and its top
+//     half meets its bottom half (1 v n/2+1, 2 v n/2+2, ... in round 1,
+//     where everyone is on 0); an odd group's last torró floats down to the
+//     next one;
+//   - when that would be a rematch, the torró meets the next one down it
+//     hasn't met yet;
+//   - an odd field gives a bye, worth a win, to the lowest-ranked torró
+//     that hasn't had one yet.
+//
+// A win, bye included, scores 1. Standings break ties on Buchholz (the sum
+// of the scores of everyone a torró met) and then on Phase 1 seed.
+
+// MinSwissSize is the smallest Swiss bracket worth playing: with two
+// torrons there is only one match to play, over and over.
+const MinSwissSize = 3
+
+// swissPairingBudget bounds the backtracking search for a rematch-free
+// round. A large, late-round field can make one impossible; past the
+// budget, the round is paired off in score-group order and rematches are
+// accepted.
+const swissPairingBudget = 100_000
+
+// DefaultSwissRounds returns the usual number of Swiss rounds for a field
+// of n: enough, ceil(log2 n), to leave a single unbeaten torró.
+func DefaultSwissRounds(n int) int {
+	if n < 2 {
+		return 0
+	}
+	return bits.Len(uint(n - 1))
+}
+
+// MaxSwissRounds returns the most rounds a field of n can play before a
+// rematch becomes unavoidable: everyone has met everyone else.
+func MaxSwissRounds(n int) int {
+	if n%2 == 1 {
+		return n
+	}
+	return n - 1
+}
+
+// SwissStanding is one torró's line in a Swiss bracket's standings. Rank
+// is 1-based, ties already broken.
+type SwissStanding struct {
+	Rank     int
+	TorroId  string
+	Seed     int
+	Score    int
+	Wins     int
+	Losses   int
+	Byes     int
+	Buchholz int
+}
+
+// SwissStandings ranks a Swiss bracket's entries from the matches decided
+// so far: by score, then Buchholz, then seed.
+func SwissStandings(entries []*domain.BracketEntry, matches []*domain.BracketMatch) []*SwissStanding {
+	byTorro := make(map[string]*SwissStanding, len(entries))
+	standings := make([]*SwissStanding, 0, len(entries))
+	for _, e := range entries {
+		s := &SwissStanding{TorroId: e.TorronId, Seed: e.Seed}
+		byTorro[e.TorronId] = s
+		standings = append(standings, s)
+	}
+
+	opponents := make(map[string][]string)
+	for _, m := range matches {
+		if m.WinnerId == nil {
+			continue
+		}
+		if m.IsBye() {
+			if s := byTorro[m.Torro1Id]; s != nil {
+				s.Byes++
+				s.Score++
+			}
+			continue
+		}
+		if s := byTorro[*m.WinnerId]; s != nil {
+			s.Wins++
+			s.Score++
+		}
+		if loser := m.LoserId(); loser != nil {
+			if s := byTorro[*loser]; s != nil {
+				s.Losses++
+			}
+		}
+		opponents[m.Torro1Id] = append(opponents[m.Torro1Id], *m.Torro2Id)
+		opponents[*m.Torro2Id] = append(opponents[*m.Torro2Id], m.Torro1Id)
+	}
+
+	for _, s := range standings {
+		for _, id := range opponents[s.TorroId] {
+			if o := byTorro[id]; o != nil {
+				s.Buchholz += o.Score
+			}
+		}
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Buchholz != b.Buchholz {
+			return a.Buchholz > b.Buchholz
+		}
+		return a.Seed < b.Seed
+	})
+	for i, s := range standings {
+		s.Rank = i + 1
+	}
+	return standings
+}
+
+// PairSwissRound plans round of a Swiss bracket from its entries and every
+// match played so far, all of them decided. Slots number the pairings down
+// the standings; a bye, already decided, takes the last slot.
+func PairSwissRound(bracketId string, round int, entries []*domain.BracketEntry, matches []*domain.BracketMatch) []*domain.BracketMatch {
+	standings := SwissStandings(entries, matches)
+
+	met := make(map[[2]string]bool)
+	hadBye := make(map[string]bool)
+	for _, m := range matches {
+		if m.IsBye() {
+			hadBye[m.Torro1Id] = true
+			continue
+		}
+		met[[2]string{m.Torro1Id, *m.Torro2Id}] = true
+		met[[2]string{*m.Torro2Id, m.Torro1Id}] = true
+	}
+
+	field := make([]string, 0, len(standings))
+	for _, s := range standings {
+		field = append(field, s.TorroId)
+	}
+
+	var bye string
+	if len(field)%2 == 1 {
+		at := len(field) - 1
+		for i := len(field) - 1; i >= 0; i-- {
+			if !hadBye[field[i]] {
+				at = i
+				break
+			}
+		}
+		bye = field[at]
+		field = append(field[:at:at], field[at+1:]...)
+	}
+
+	score := make(map[string]int, len(standings))
+	for _, s := range standings {
+		score[s.TorroId] = s.Score
+	}
+	field = foldScoreGroups(field, score)
+
+	budget := swissPairingBudget
+	pairs := pairAvoidingRematches(field, met, &budget)
+	if pairs == nil {
+		for i := 0; i+1 < len(field); i += 2 {
+			pairs = append(pairs, [2]string{field[i], field[i+1]})
+		}
+	}
+
+	next := make([]*domain.BracketMatch, 0, len(pairs)+1)
+	for slot, pair := range pairs {
+		torro2Id := pair[1]
+		next = append(next, &domain.BracketMatch{
+			BracketId: bracketId,
+			Round:     round,
+			Side:      domain.BracketSideWinners,
+			Slot:      slot,
+			Torro1Id:  pair[0],
+			Torro2Id:  &torro2Id,
+			Status:    domain.BracketMatchStatusPending,
+		})
+	}
+	if bye != "" {
+		winnerId := bye
+		next = append(next, &domain.BracketMatch{
+			BracketId: bracketId,
+			Round:     round,
+			Side:      domain.BracketSideWinners,
+			Slot:      len(pairs),
+			Torro1Id:  bye,
+			WinnerId:  &winnerId,
+			Status:    domain.BracketMatchStatusCompleted,
+		})
+	}
+	return next
+}
+
+// foldScoreGroups reorders field, in standings order, so that pairing it
+// off two by two sets each score group's top half against its bottom half:
+// a group a, b, c, d comes out a, c, b, d.
+func foldScoreGroups(field []string, score map[string]int) []string {
+	folded := make([]string, 0, len(field))
+	for start := 0; start < len(field); {
+		end := start
+		for end < len(field) && score[field[end]] == score[field[start]] {
+			end++
+		}
+		group := field[start:end]
+		half := len(group) / 2
+		for i := range half {
+			folded = append(folded, group[i], group[i+half])
+		}
+		folded = append(folded, group[2*half:]...)
+		start = end
+	}
+	return folded
+}
+
+// pairAvoidingRematches pairs field off two by two so that no pair has
+// met before: the first torró takes the next one it hasn't met, and the
+// search backtracks when that leaves the rest unpairable. Returns nil when
+// no such pairing exists or the budget runs out first.
+func pairAvoidingRematches(field []string, met map[[2]string]bool, budget *int) [][2]string {
+	if len(field) == 0 {
+		return [][2]string{}
+	}
+	if *budget--; *budget < 0 {
+		return nil
+	}
+
+	top := field[0]
+	for i := 1; i < len(field); i++ {
+		if met[[2]string{top, field[i]}] {
+			continue
+		}
+		rest := make([]string, 0, len(field)-2)
+		rest = append(rest, field[1:i]...)
+		rest = append(rest, field[i+1:]...)
+		if pairs := pairAvoidingRematches(rest, met, budget); pairs != nil {
+			return append([][2]string{{top, field[i]}}, pairs...)
+		}
+		if *budget < 0 {
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package tournament
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+// swissEntries seeds torrons 1..n, their IDs being their seeds.
+func swissEntries(n int) []*domain.BracketEntry {
+	entries := make([]*domain.BracketEntry, 0, n)
+	for seed := 1; seed <= n; seed++ {
+		entries = append(entries, &domain.BracketEntry{TorronId: strconv.Itoa(seed), Seed: seed})
+	}
+	return entries
+}
+
+// playSwiss plays rounds of a Swiss bracket, deciding every pending match
+// with decide, and returns every match played.
+func playSwiss(t *testing.T, entries []*domain.BracketEntry, rounds int, decide func(*domain.BracketMatch) string) []*domain.BracketMatch {
+	t.Helper()
+	var matches []*domain.BracketMatch
+	for round := 1; round <= rounds; round++ {
+		next := PairSwissRound("b", round, entries, matches)
+		for _, m := range next {
+			if m.Status == domain.BracketMatchStatusPending {
+				winnerId := decide(m)
+				m.WinnerId = &winnerId
+				m.Status = domain.BracketMatchStatusCompleted
+			}
+		}
+		matches = append(matches, next...)
+	}
+	return matches
+}
+
+func TestSwissRoundOneSplitsTheField(t *testing.T) {
+	matches := PairSwissRound("b", 1, swissEntries(8), nil)
+
+	want := [][2]string{{"1", "5"}, {"2", "6"}, {"3", "7"}, {"4", "8"}}
+	if len(matches) != len(want) {
+		t.Fatalf("round 1 has %d matches, want %d", len(matches), len(want))
+	}
+	for i, m := range matches {
+		if m.Torro1Id != want[i][0] || *m.Torro2Id != want[i][1] || m.Slot != i {
+			t.Errorf("slot %d = %s v %s, want %s v %s", m.Slot, m.Torro1Id, *m.Torro2Id, want[i][0], want[i][1])
+		}
+	}
+}
+
+func TestSwissPairsEqualScoresWithoutRematches(t *testing.T) {
+	entries := swissEntries(8)
+	matches := playSwiss(t, entries, 3, favourite)
+
+	seen := make(map[[2]string]bool)
+	for _, m := range matches {
+		pair := [2]string{m.Torro1Id, *m.Torro2Id}
+		if seen[pair] || seen[[2]string{pair[1], pair[0]}] {
+			t.Errorf("rematch %s v %s", pair[0], pair[1])
+		}
+		seen[pair] = true
+	}
+
+	// After two rounds of favourites winning, 1 and 2 are the only torrons
+	// on 2 points and must meet in round 3.
+	var round3 []*domain.BracketMatch
+	for _, m := range matches {
+		if m.Round == 3 {
+			round3 = append(round3, m)
+		}
+	}
+	if round3[0].Torro1Id != "1" || *round3[0].Torro2Id != "2" {
+		t.Errorf("round 3 top board = %s v %s, want 1 v 2", round3[0].Torro1Id, *round3[0].Torro2Id)
+	}
+
+	standings := SwissStandings(entries, matches)
+	if standings[0].TorroId != "1" || standings[0].Score != 3 || standings[0].Losses != 0 {
+		t.Errorf("leader = %+v, want seed 1 unbeaten on 3", standings[0])
+	}
+}
+
+func TestSwissByeGoesToLowestWithoutOne(t *testing.T) {
+	entries := swissEntries(5)
+	matches := playSwiss(t, entries, 3, favourite)
+
+	byes := make(map[string]int)
+	for _, m := range matches {
+		if m.IsBye() {
+			byes[m.Torro1Id]++
+			if m.WinnerId == nil || *m.WinnerId != m.Torro1Id {
+				t.Errorf("bye for %s is not a win", m.Torro1Id)
+			}
+		}
+	}
+	if len(byes) != 3 {
+		t.Errorf("byes = %v, want three different torrons with one each", byes)
+	}
+	// Round 1's bye goes to the lowest seed.
+	if byes["5"] != 1 {
+		t.Errorf("seed 5 had %d byes, want 1", byes["5"])
+	}
+}
+
+func TestSwissStandingsTieBreaks(t *testing.T) {
+	decided := func(a, b, winner string) *domain.BracketMatch {
+		return &domain.BracketMatch{Torro1Id: a, Torro2Id: &b, WinnerId: &winner, Status: domain.BracketMatchStatusCompleted}
+	}
+	// 3, 1 and 4 all finish on 1 point behind 2, and split on Buchholz:
+	// 3 met 4 and 2 (1 + 2), 1 met 2 and 5 (2 + 0), 4 met 3 and 5 (1 + 0).
+	matches := []*domain.BracketMatch{
+		decided("1", "2", "2"),
+		decided("3", "4", "3"),
+		decided("2", "3", "2"),
+		decided("1", "5", "1"),
+		decided("4", "5", "4"),
+	}
+	standings := SwissStandings(swissEntries(5), matches)
+
+	var order string
+	for _, s := range standings {
+		order += s.TorroId
+	}
+	if order != "23145" {
+		t.Errorf("standings = %s, want 23145", order)
+	}
+	if standings[1].Buchholz != 3 || standings[2].Buchholz != 2 || standings[3].Buchholz != 1 {
+		t.Errorf("Buchholz = %d, %d, %d, want 3, 2, 1", standings[1].Buchholz, standings[2].Buchholz, standings[3].Buchholz)
+	}
+
+	// Level on score and Buchholz, the better seed ranks first.
+	level := SwissStandings(swissEntries(2), nil)
+	if level[0].TorroId != "1" || level[0].Rank != 1 || level[1].Rank != 2 {
+		t.Errorf("level standings = %+v, %+v, want seed 1 first", level[0], level[1])
+	}
+}
+
+func TestSwissRoundCounts(t *testing.T) {
+	for _, tt := range []struct{ n, rounds, max int }{
+		{3, 2, 3},
+		{8, 3, 7},
+		{20, 5, 19},
+		{33, 6, 33},
+	} {
+		if got := DefaultSwissRounds(tt.n); got != tt.rounds {
+			t.Errorf("DefaultSwissRounds(%d) = %d, want %d", tt.n, got, tt.rounds)
+		}
+		if got := MaxSwissRounds(tt.n); got != tt.max {
+			t.Errorf("MaxSwissRounds(%d) = %d, want %d", tt.n, got, tt.max)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+// Package trust holds the domain.VoteWeighting policies that decide how much
+// a vote moves the global ratings: none, where every vote counts in full,
+// and trust, where a vote counts in proportion to how established its voter
+// is. Scoring is a pure function of domain.TrustSignals; tallying the
+// signals and storing the scores is the caller's job.
+package trust
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+// Defaults for Options. A user reaches full trust after two weeks, 300
+// votes and a week-long streak, with a clear record of agreeing with the
+// community; a brand new cookie still counts for a quarter of a vote.
+const (
+	DefaultMinWeight   = 0.25
+	DefaultFullAgeDays = 14
+	DefaultFullVotes   = 300
+	DefaultFullStreak  = 7
+
+	DefaultAgeShare       = 1
+	DefaultVotesShare     = 1
+	DefaultAgreementShare = 2
+	DefaultStreakShare    = 1
+)
+
+// agreementPrior is how many imaginary coin-flip votes a user's agreement
+// rate is smoothed with, so a handful of lucky picks doesn't read as a
+// track record.
+const agreementPrior = 10
+
+// Options carries the tunable parameters of the trust policy. Nil fields
+// and thresholds left at zero fall back to the package defaults.
+type Options struct {
+	// MinWeight is what a vote by a user with no trust at all counts for.
+	// 0 is honoured: such a vote then moves only the voter's snapshots.
+	MinWeight *float64
+
+	// Each component of the score is full once the user reaches these.
+	// They must be positive.
+	FullAgeDays float64
+	FullVotes   float64
+	FullStreak  float64
+
+	// How much each component counts towards the score, relative to the
+	// others. A share of 0 leaves its component out; if every share is 0,
+	// the default shares apply.
+	AgeShare       *float64
+	VotesShare     *float64
+	AgreementShare *float64
+	StreakShare    *float64
+}
+
+// New returns the policy registered under name (one of the
+// domain.VoteWeighting* names). An empty name selects none, which keeps
+// every existing deployment on the behaviour it had before votes were
+// weighted.
+func New(name string, opts Options) (domain.VoteWeighting, error) {
+	switch name {
+	case "", domain.VoteWeightingNone:
+		return NewNone(), nil
+	case domain.VoteWeightingTrust:
+		return NewTrust(opts), nil
+	default:
+		return nil, fmt.Errorf("unknown vote weighting %q", name)
+	}
+}
+
+// None counts every vote in full. Scores are still computed, so switching
+// to Trust later doesn't start from zero.
+type None struct {
+	scorer *Trust
+}
+
+func NewNone() *None {
+	return &None{scorer: NewTrust(Options{})}
+}
+
+func (n *None) Name() string { return domain.VoteWeightingNone }
+
+func (n *None) Score(s *domain.TrustSignals, now time.Time) float64 {
+	return n.scorer.Score(s, now)
+}
+
+func (n *None) Weight(*domain.User) float64 { return 1 }
+
+// Trust counts a vote for MinWeight plus the rest of a full vote in
+// proportion to its voter's trust score.
+type Trust struct {
+	minWeight float64
+
+	fullAgeDays float64
+	fullVotes   float64
+	fullStreak  float64
+
+	ageShare       float64
+	votesShare     float64
+	agreementShare float64
+	streakShare    float64
+}
+
+func NewTrust(opts Options) *Trust {
+	t := &Trust{
+		minWeight:      clamp01(orDefault(opts.MinWeight, DefaultMinWeight)),
+		fullAgeDays:    positiveOr(opts.FullAgeDays, DefaultFullAgeDays),
+		fullVotes:      positiveOr(opts.FullVotes, DefaultFullVotes),
+		fullStreak:     positiveOr(opts.FullStreak, DefaultFullStreak),
+		ageShare:       max(0, orDefault(opts.AgeShare, DefaultAgeShare)),
+		votesShare:     max(0, orDefault(opts.VotesShare, DefaultVotesShare)),
+		agreementShare: max(0, orDefault(opts.AgreementShare, DefaultAgreementShare)),
+		streakShare:    max(0, orDefault(opts.StreakShare, DefaultStreakShare)),
+	}
+	if t.ageShare+t.votesShare+t.agreementShare+t.streakShare == 0 {
+		t.ageShare, t.votesShare = DefaultAgeShare, DefaultVotesShare
+		t.agreementShare, t.streakShare = DefaultAgreementShare, DefaultStreakShare
+	}
+	return t
+}
+
+func (t *Trust) Name() string { return domain.VoteWeightingTrust }
+
+// Score is the share-weighted average of four components, each from 0 to
+// 1: account age, vote count, longest streak and agreement with the
+// community. Agreement is 0 for a user who picks against the ranking at
+// least as often as a coin would and full at 75% agreement; it is what
+// keeps a patient ballot-stuffer from earning trust on volume alone.
+func (t *Trust) Score(s *domain.TrustSignals, now time.Time) float64 {
+	age := clamp01(now.Sub(s.FirstSeen).Hours() / 24 / t.fullAgeDays)
+	votes := clamp01(float64(s.Votes) / t.fullVotes)
+	streak := clamp01(float64(s.LongestStreak) / t.fullStreak)
+
+	rate := (float64(s.Agreed) + agreementPrior/2) / (float64(s.Comparable) + agreementPrior)
+	agreement := clamp01((rate - 0.5) / 0.25)
+
+	total := t.ageShare + t.votesShare + t.agreementShare + t.streakShare
+	return (t.ageShare*age + t.votesShare*votes + t.agreementShare*agreement + t.streakShare*streak) / total
+}
+
+func (t *Trust) Weight(user *domain.User) float64 {
+	if user == nil {
+		return t.minWeight
+	}
+	return t.minWeight + (1-t.minWeight)*clamp01(user.TrustScore)
+}
+
+func orDefault(v *float64, def float64) float64 {
+	if v == nil {
+		return def
+	}
+	return *v
+}
+
+func positiveOr(v, def float64) float64 {
+	if v <= 0 {
+		return def
+	}
+	return v
+}
+
+func clamp01(v float64) float64 {
+	return max(0, min(v, 1))
+}
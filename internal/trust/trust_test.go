@@ -0,0 +1,173 @@
+package trust
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/krtffl/torro/internal/domain"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name     string
+		wantName string
+		wantErr  bool
+	}{
+		{"", domain.VoteWeightingNone, false},
+		{domain.VoteWeightingNone, domain.VoteWeightingNone, false},
+		{domain.VoteWeightingTrust, domain.VoteWeightingTrust, false},
+		{"reputation", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := New(tt.name, Options{})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("New(%q) error = nil, want an error", tt.name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("New(%q) error = %v", tt.name, err)
+			}
+			if policy.Name() != tt.wantName {
+				t.Errorf("New(%q).Name() = %q, want %q", tt.name, policy.Name(), tt.wantName)
+			}
+		})
+	}
+}
+
+func TestTrustScore(t *testing.T) {
+	now := time.Date(2025, 12, 20, 12, 0, 0, 0, time.UTC)
+	policy := NewTrust(Options{})
+
+	tests := []struct {
+		name    string
+		signals domain.TrustSignals
+		want    float64
+	}{
+		{
+			name:    "brand new cookie",
+			signals: domain.TrustSignals{FirstSeen: now},
+			want:    0,
+		},
+		{
+			name: "established and in agreement",
+			signals: domain.TrustSignals{
+				FirstSeen:     now.AddDate(0, 0, -30),
+				Votes:         500,
+				LongestStreak: 10,
+				Comparable:    400,
+				Agreed:        360,
+			},
+			want: 1,
+		},
+		{
+			// Volume, age and streak earn 3 of the 5 shares; picking
+			// against the ranking earns nothing for agreement.
+			name: "established contrarian",
+			signals: domain.TrustSignals{
+				FirstSeen:     now.AddDate(0, 0, -30),
+				Votes:         500,
+				LongestStreak: 10,
+				Comparable:    400,
+				Agreed:        100,
+			},
+			want: 0.6,
+		},
+		{
+			// A week, 150 votes and a 3-day streak: half, half and 3/7,
+			// with a coin-flip agreement record.
+			name: "halfway there",
+			signals: domain.TrustSignals{
+				FirstSeen:     now.AddDate(0, 0, -7),
+				Votes:         150,
+				LongestStreak: 3,
+				Comparable:    100,
+				Agreed:        50,
+			},
+			want: (0.5 + 0.5 + 3.0/7) / 5,
+		},
+		{
+			// The prior keeps a 3 out of 3 start from counting as 75%.
+			name: "few lucky picks",
+			signals: domain.TrustSignals{
+				FirstSeen:  now,
+				Votes:      3,
+				Comparable: 3,
+				Agreed:     3,
+			},
+			want: (3.0/300 + 2*((8.0/13-0.5)/0.25)) / 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := policy.Score(&tt.signals, now)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Score() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrustWeight(t *testing.T) {
+	minWeight := 0.2
+	policy := NewTrust(Options{MinWeight: &minWeight})
+
+	tests := []struct {
+		name string
+		user *domain.User
+		want float64
+	}{
+		{"anonymous", nil, 0.2},
+		{"untrusted", &domain.User{TrustScore: 0}, 0.2},
+		{"half trusted", &domain.User{TrustScore: 0.5}, 0.6},
+		{"fully trusted", &domain.User{TrustScore: 1}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.Weight(tt.user); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Weight() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestTrustZeroOptions checks an explicit 0 is honoured rather than read
+// as unset: a min_weight of 0 lets an untrusted vote count for nothing, and
+// a share of 0 leaves its component out of the score.
+func TestTrustZeroOptions(t *testing.T) {
+	zero := 0.0
+	policy := NewTrust(Options{MinWeight: &zero, AgeShare: &zero, VotesShare: &zero, StreakShare: &zero})
+
+	if got := policy.Weight(&domain.User{TrustScore: 0}); got != 0 {
+		t.Errorf("Weight() with min_weight 0 = %v, want 0", got)
+	}
+	if got := policy.Weight(&domain.User{TrustScore: 0.5}); math.Abs(got-0.5) > 1e-9 {
+		t.Errorf("Weight() with min_weight 0 = %v, want 0.5", got)
+	}
+
+	now := time.Date(2025, 12, 20, 12, 0, 0, 0, time.UTC)
+	veteran := &domain.TrustSignals{FirstSeen: now.AddDate(-1, 0, 0), Votes: 1000, LongestStreak: 30}
+	if got := policy.Score(veteran, now); got != 0 {
+		t.Errorf("Score() on agreement alone, with no agreement record = %v, want 0", got)
+	}
+
+	all := NewTrust(Options{AgeShare: &zero, VotesShare: &zero, AgreementShare: &zero, StreakShare: &zero})
+	if got := all.Score(veteran, now); math.IsNaN(got) || got <= 0 {
+		t.Errorf("Score() with every share 0 = %v, want the default shares' score", got)
+	}
+}
+
+func TestNoneWeight(t *testing.T) {
+	policy := NewNone()
+	for _, user := range []*domain.User{nil, {TrustScore: 0}, {TrustScore: 1}} {
+		if got := policy.Weight(user); got != 1 {
+			t.Errorf("Weight(%v) = %v, want 1", user, got)
+		}
+	}
+}
@@ -0,0 +1,5 @@
+ALTER TABLE "UserEloSnapshots" DROP COLUMN IF EXISTS "RatingVolatility";
+ALTER TABLE "UserEloSnapshots" DROP COLUMN IF EXISTS "RatingDeviation";
+
+ALTER TABLE "Torrons" DROP COLUMN IF EXISTS "RatingVolatility";
+ALTER TABLE "Torrons" DROP COLUMN IF EXISTS "RatingDeviation";
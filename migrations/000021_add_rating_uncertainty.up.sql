@@ -0,0 +1,13 @@
+-- Per-item state for the deviation-aware rating engines (Glicko-2 and
+-- TrueSkill, see internal/rating). Elo ignores both columns, so existing
+-- deployments are unaffected until "rating.algorithm" is changed. Defaults
+-- match domain.DefaultRatingDeviation / domain.DefaultRatingVolatility: every
+-- existing row starts out maximally uncertain, which is the honest prior for
+-- ratings that were never tracked with a deviation before.
+ALTER TABLE "Torrons"
+    ADD COLUMN IF NOT EXISTS "RatingDeviation" NUMERIC NOT NULL DEFAULT 350,
+    ADD COLUMN IF NOT EXISTS "RatingVolatility" NUMERIC NOT NULL DEFAULT 0.06;
+
+ALTER TABLE "UserEloSnapshots"
+    ADD COLUMN IF NOT EXISTS "RatingDeviation" NUMERIC NOT NULL DEFAULT 350,
+    ADD COLUMN IF NOT EXISTS "RatingVolatility" NUMERIC NOT NULL DEFAULT 0.06;
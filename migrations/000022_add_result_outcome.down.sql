@@ -0,0 +1,7 @@
+-- Draws cannot be represented once "Winner" is NOT NULL again, so they are
+-- dropped. Their rating effect on Torrons/UserEloSnapshots is not undone.
+DELETE FROM "Results" WHERE "Outcome" = 'draw';
+
+ALTER TABLE "Results" DROP CONSTRAINT IF EXISTS chk_results_outcome;
+ALTER TABLE "Results" DROP COLUMN IF EXISTS "Outcome";
+ALTER TABLE "Results" ALTER COLUMN "Winner" SET NOT NULL;
@@ -0,0 +1,16 @@
+-- First-class draw ("can't decide") outcome for Phase 1 duels. A draw
+-- scores both torrons 0.5 in the rating engine and names no winner, so
+-- "Winner" becomes nullable and a new "Outcome" column says which kind of
+-- result a row is. Every existing row is a decided duel, hence the 'win'
+-- default. The CHECK keeps the two columns consistent: a win always names
+-- its winner and a draw never does.
+ALTER TABLE "Results" ALTER COLUMN "Winner" DROP NOT NULL;
+
+ALTER TABLE "Results"
+    ADD COLUMN IF NOT EXISTS "Outcome" VARCHAR(10) NOT NULL DEFAULT 'win';
+
+ALTER TABLE "Results"
+    ADD CONSTRAINT chk_results_outcome CHECK (
+        ("Outcome" = 'win' AND "Winner" IS NOT NULL) OR
+        ("Outcome" = 'draw' AND "Winner" IS NULL)
+    );
@@ -0,0 +1,2 @@
+-- Drop UserPairingViews table
+DROP TABLE IF EXISTS "UserPairingViews";
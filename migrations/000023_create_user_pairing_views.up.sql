@@ -0,0 +1,15 @@
+-- Create UserPairingViews table for per-user pairing coverage: one row per
+-- (user, pairing) the vote screen has served to that user, so the coverage
+-- selector can show every matchup of a class before repeating any, then
+-- cycle through them least-recently-seen first.
+CREATE TABLE IF NOT EXISTS "UserPairingViews" (
+    "UserId" VARCHAR(36) NOT NULL
+        CONSTRAINT fk_user_pairing_views_user
+        REFERENCES "Users"("Id") ON DELETE CASCADE,
+    "PairingId" VARCHAR(36) NOT NULL
+        CONSTRAINT fk_user_pairing_views_pairing
+        REFERENCES "Pairings"("Id") ON DELETE CASCADE,
+    "SeenCount" INT NOT NULL DEFAULT 1,
+    "LastSeenAt" TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT pk_user_pairing_views PRIMARY KEY ("UserId", "PairingId")
+);
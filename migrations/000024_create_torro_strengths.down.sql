@@ -0,0 +1,2 @@
+-- Drop TorroStrengths table
+DROP TABLE IF EXISTS "TorroStrengths";
@@ -0,0 +1,27 @@
+-- Create TorroStrengths table for the batch Bradley-Terry fit (see
+-- internal/strength): one row per torró that has been voted on, with its
+-- maximum-likelihood strength and 95% interval on the 1500-centred rating
+-- scale. Unlike "Torrons"."Rating" it does not depend on the order votes
+-- arrived in; the server refits it on a schedule and replaces every row at
+-- once.
+CREATE TABLE IF NOT EXISTS "TorroStrengths" (
+    "TorroId" VARCHAR(36) NOT NULL
+        CONSTRAINT pk_torro_strengths PRIMARY KEY
+        CONSTRAINT fk_torro_strengths_torro
+        REFERENCES "Torrons"("Id") ON DELETE CASCADE,
+    "Strength" NUMERIC NOT NULL,
+    "Lower" NUMERIC NOT NULL,
+    "Upper" NUMERIC NOT NULL,
+    "StdError" NUMERIC NOT NULL,
+    "Comparisons" INT NOT NULL DEFAULT 0,
+    -- Ranks by strength across every class and within the torró's own
+    -- class; 0 for discontinued torrons, whose results still inform the fit
+    -- but which are no longer ranked.
+    "Rank" INT NOT NULL DEFAULT 0,
+    "ClassRank" INT NOT NULL DEFAULT 0,
+    -- Whether the torró is ahead of the next-ranked one, overall and within
+    -- its class, at the 95% level.
+    "SeparableFromNext" BOOLEAN NOT NULL DEFAULT FALSE,
+    "SeparableFromNextInClass" BOOLEAN NOT NULL DEFAULT FALSE,
+    "FittedAt" TIMESTAMP NOT NULL DEFAULT NOW()
+);
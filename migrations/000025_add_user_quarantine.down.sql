@@ -0,0 +1,7 @@
+-- Drop vote-fraud quarantine columns
+DROP INDEX IF EXISTS idx_users_ip_hash;
+ALTER TABLE "Users" DROP COLUMN IF EXISTS "IpHash";
+ALTER TABLE "Users" DROP COLUMN IF EXISTS "FraudScore";
+ALTER TABLE "Users" DROP COLUMN IF EXISTS "QuarantineReleasedAt";
+ALTER TABLE "Users" DROP COLUMN IF EXISTS "QuarantinedAt";
+ALTER TABLE "Users" DROP COLUMN IF EXISTS "Quarantined";
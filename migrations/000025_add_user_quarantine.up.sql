@@ -0,0 +1,24 @@
+-- Vote-fraud quarantine (see internal/fraud). A quarantined user's votes
+-- stay in "Results" and keep feeding their personal snapshots, but no
+-- longer move any global rating.
+ALTER TABLE "Users"
+    ADD COLUMN IF NOT EXISTS "Quarantined" BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE "Users"
+    ADD COLUMN IF NOT EXISTS "QuarantinedAt" TIMESTAMP;
+
+-- Set when an admin releases a user, so the analyzer never quarantines
+-- them again.
+ALTER TABLE "Users"
+    ADD COLUMN IF NOT EXISTS "QuarantineReleasedAt" TIMESTAMP;
+
+-- The analyzer's score when it quarantined the user.
+ALTER TABLE "Users"
+    ADD COLUMN IF NOT EXISTS "FraudScore" NUMERIC NOT NULL DEFAULT 0;
+
+-- Truncated SHA-256 of the client IP the user was created from, never the
+-- IP itself. Only used to count fresh identities minted from one address.
+ALTER TABLE "Users"
+    ADD COLUMN IF NOT EXISTS "IpHash" VARCHAR(64);
+
+CREATE INDEX IF NOT EXISTS idx_users_ip_hash ON "Users"("IpHash");
@@ -0,0 +1,2 @@
+-- Drop the vote undo state
+DROP TABLE IF EXISTS "VoteUndos";
@@ -0,0 +1,43 @@
+-- Create VoteUndos table backing the duel screen's "undo" toast: for each
+-- user, everything their latest Phase 1 vote overwrote, so that vote can be
+-- reversed exactly within a short grace window. One row per user, replaced
+-- on every vote, so only the latest vote is ever undoable. The Result's own
+-- before/after ratings are read from "Results".
+CREATE TABLE IF NOT EXISTS "VoteUndos" (
+    "UserId" VARCHAR(36) NOT NULL
+        CONSTRAINT pk_vote_undos PRIMARY KEY
+        CONSTRAINT fk_vote_undos_user
+        REFERENCES "Users"("Id") ON DELETE CASCADE,
+    "ResultId" VARCHAR(36) NOT NULL
+        CONSTRAINT fk_vote_undos_result
+        REFERENCES "Results"("Id") ON DELETE CASCADE,
+    "CastAt" TIMESTAMP NOT NULL,
+    -- The class whose "ClassVotes" counter the vote incremented.
+    "ClassId" VARCHAR(36) NOT NULL,
+    -- Set when the vote was also today's advent duel.
+    "AdventDate" DATE,
+
+    -- The user's streak before the vote.
+    "PrevCurrentStreak" INT NOT NULL,
+    "PrevLongestStreak" INT NOT NULL,
+    "PrevLastVoteDate" DATE,
+
+    -- Both torrons' deviation and volatility before the vote.
+    "Torro1Deviation" NUMERIC NOT NULL,
+    "Torro1Volatility" NUMERIC NOT NULL,
+    "Torro2Deviation" NUMERIC NOT NULL,
+    "Torro2Volatility" NUMERIC NOT NULL,
+
+    -- The user's snapshots of both torrons before the vote. A VoteCount of
+    -- 0 means the vote created the snapshot.
+    "Snapshot1Rating" NUMERIC NOT NULL,
+    "Snapshot1Deviation" NUMERIC NOT NULL,
+    "Snapshot1Volatility" NUMERIC NOT NULL,
+    "Snapshot1VoteCount" INT NOT NULL,
+    "Snapshot1LastUpdated" TIMESTAMP NOT NULL,
+    "Snapshot2Rating" NUMERIC NOT NULL,
+    "Snapshot2Deviation" NUMERIC NOT NULL,
+    "Snapshot2Volatility" NUMERIC NOT NULL,
+    "Snapshot2VoteCount" INT NOT NULL,
+    "Snapshot2LastUpdated" TIMESTAMP NOT NULL
+);
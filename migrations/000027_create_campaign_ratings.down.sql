@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "CampaignUserEloSnapshots";
+DROP TABLE IF EXISTS "CampaignRatings";
+DROP TABLE IF EXISTS "RatingSeason";
@@ -0,0 +1,64 @@
+-- Campaign-scoped ratings. "Torrons"."Rating" and "UserEloSnapshots" keep
+-- holding the live ratings, but only for the current rating season: when a
+-- new campaign becomes active, the server archives them under the campaign
+-- they belonged to and starts every torró and every personal ranking over
+-- from the rating engine's initial state.
+
+-- RatingSeason is a single row naming the campaign the live ratings belong
+-- to and when its season started; the rating replay and the strength fit
+-- only read Results cast since then.
+CREATE TABLE IF NOT EXISTS "RatingSeason" (
+    "Id" BOOLEAN NOT NULL DEFAULT TRUE
+        CONSTRAINT pk_rating_season PRIMARY KEY
+        CONSTRAINT ck_rating_season_single CHECK ("Id"),
+    "CampaignId" VARCHAR(36)
+        CONSTRAINT fk_rating_season_campaign
+        REFERENCES "Campaigns"("Id") ON DELETE SET NULL,
+    "StartedAt" TIMESTAMP NOT NULL DEFAULT '-infinity'
+);
+
+-- Today's ratings belong to whichever campaign was voted in last; with no
+-- campaign yet, the first one to become active adopts them as they are.
+INSERT INTO "RatingSeason" ("Id", "CampaignId")
+VALUES (TRUE, (SELECT "CampaignId" FROM "Results"
+               WHERE "CampaignId" IS NOT NULL
+               ORDER BY "Timestamp" DESC
+               LIMIT 1))
+ON CONFLICT ("Id") DO NOTHING;
+
+-- Each past campaign's final global ratings, for every torró voted on in
+-- its season, with the ranks they ended at.
+CREATE TABLE IF NOT EXISTS "CampaignRatings" (
+    "CampaignId" VARCHAR(36) NOT NULL
+        CONSTRAINT fk_campaign_ratings_campaign
+        REFERENCES "Campaigns"("Id") ON DELETE CASCADE,
+    "TorroId" VARCHAR(36) NOT NULL
+        CONSTRAINT fk_campaign_ratings_torro
+        REFERENCES "Torrons"("Id") ON DELETE CASCADE,
+    "ClassId" VARCHAR(36) NOT NULL,
+    "Rating" NUMERIC NOT NULL,
+    "RatingDeviation" NUMERIC NOT NULL,
+    "RatingVolatility" NUMERIC NOT NULL,
+    "Votes" INT NOT NULL DEFAULT 0,
+    "Rank" INT NOT NULL,
+    "ClassRank" INT NOT NULL,
+    CONSTRAINT pk_campaign_ratings PRIMARY KEY ("CampaignId", "TorroId")
+);
+
+-- Each past campaign's personal snapshots, as they stood when it ended.
+CREATE TABLE IF NOT EXISTS "CampaignUserEloSnapshots" (
+    "CampaignId" VARCHAR(36) NOT NULL
+        CONSTRAINT fk_campaign_user_elo_campaign
+        REFERENCES "Campaigns"("Id") ON DELETE CASCADE,
+    "UserId" VARCHAR(36) NOT NULL
+        CONSTRAINT fk_campaign_user_elo_user
+        REFERENCES "Users"("Id") ON DELETE CASCADE,
+    "TorronId" VARCHAR(36) NOT NULL
+        CONSTRAINT fk_campaign_user_elo_torron
+        REFERENCES "Torrons"("Id") ON DELETE CASCADE,
+    "Rating" NUMERIC NOT NULL,
+    "RatingDeviation" NUMERIC NOT NULL,
+    "RatingVolatility" NUMERIC NOT NULL,
+    "VoteCount" INT NOT NULL DEFAULT 0,
+    CONSTRAINT pk_campaign_user_elo PRIMARY KEY ("CampaignId", "UserId", "TorronId")
+);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_pairings_active_class;
+
+ALTER TABLE "Pairings"
+    DROP COLUMN IF EXISTS "RetiredAt";
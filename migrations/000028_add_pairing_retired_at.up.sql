@@ -0,0 +1,12 @@
+-- Pairing reconciliation (see internal/catalog). A pairing whose matchup
+-- is no longer wanted - a torró was discontinued or changed class, or a
+-- global cross-category pairing dropped out of the top-rated set - is
+-- retired rather than deleted, so its Results keep pointing at it. Retired
+-- pairings are never served; reconciliation clears "RetiredAt" again if the
+-- matchup comes back.
+ALTER TABLE "Pairings"
+    ADD COLUMN IF NOT EXISTS "RetiredAt" TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_pairings_active_class
+    ON "Pairings" ("Class")
+    WHERE "RetiredAt" IS NULL;
@@ -0,0 +1,4 @@
+-- Drop ranking submissions. Their Results stay, as ordinary duel votes.
+DROP INDEX IF EXISTS idx_results_submission;
+ALTER TABLE "Results" DROP COLUMN IF EXISTS "SubmissionId";
+DROP TABLE IF EXISTS "RankingSubmissions";
@@ -0,0 +1,31 @@
+-- Create RankingSubmissions: one row per "rank these torrons" vote, where
+-- the user orders 3 or 4 torrons of a class at once. The ranking itself is
+-- recorded as the pairwise Results it implies, each tagged with the
+-- submission so /history can show the whole ranking as a single action.
+CREATE TABLE IF NOT EXISTS "RankingSubmissions" (
+    "Id" VARCHAR(36) NOT NULL
+        CONSTRAINT pk_ranking_submissions PRIMARY KEY,
+    "UserId" VARCHAR(36)
+        CONSTRAINT fk_ranking_submissions_user
+        REFERENCES "Users"("Id") ON DELETE SET NULL,
+    "ClassId" VARCHAR(36) NOT NULL
+        CONSTRAINT fk_ranking_submissions_class
+        REFERENCES "Classes"("Id"),
+    "CampaignId" VARCHAR(36)
+        CONSTRAINT fk_ranking_submissions_campaign
+        REFERENCES "Campaigns"("Id") ON DELETE SET NULL,
+    -- The ranked torrons, best first.
+    "TorroIds" VARCHAR(36)[] NOT NULL,
+    "CreatedAt" TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ranking_submissions_user_created
+    ON "RankingSubmissions"("UserId", "CreatedAt" DESC);
+
+ALTER TABLE "Results"
+    ADD COLUMN IF NOT EXISTS "SubmissionId" VARCHAR(36)
+        CONSTRAINT fk_result_submission
+        REFERENCES "RankingSubmissions"("Id") ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_results_submission
+    ON "Results"("SubmissionId") WHERE "SubmissionId" IS NOT NULL;
@@ -0,0 +1,8 @@
+ALTER TABLE "Results"
+    DROP COLUMN IF EXISTS "Weight";
+
+ALTER TABLE "Users"
+    DROP COLUMN IF EXISTS "TrustScoredAt";
+
+ALTER TABLE "Users"
+    DROP COLUMN IF EXISTS "TrustScore";
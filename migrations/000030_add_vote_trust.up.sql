@@ -0,0 +1,14 @@
+-- Trust-weighted votes (see internal/trust). Each user's trust score, from
+-- 0 to 1, is recomputed periodically from their account age, vote count,
+-- agreement with the community ranking and streaks; a vote's weight is the
+-- share of a full rating update it was cast with, kept on the Result so a
+-- replay moves the ratings exactly as the live vote did. Votes cast before
+-- weighting count in full.
+ALTER TABLE "Users"
+    ADD COLUMN IF NOT EXISTS "TrustScore" NUMERIC NOT NULL DEFAULT 0;
+
+ALTER TABLE "Users"
+    ADD COLUMN IF NOT EXISTS "TrustScoredAt" TIMESTAMP;
+
+ALTER TABLE "Results"
+    ADD COLUMN IF NOT EXISTS "Weight" NUMERIC NOT NULL DEFAULT 1;
@@ -0,0 +1,4 @@
+-- Drop vote reasons and their vocabulary.
+DROP INDEX IF EXISTS idx_result_reasons_tag;
+DROP TABLE IF EXISTS "ResultReasons";
+DROP TABLE IF EXISTS "ReasonTags";
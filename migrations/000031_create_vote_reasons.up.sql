@@ -0,0 +1,34 @@
+-- Reason tags: an optional "why?" a voter can attach to a decided duel.
+-- The vocabulary lives in ReasonTags so tags can be added, relabelled or
+-- retired (Active = FALSE) without touching templates; a retired tag keeps
+-- its past ResultReasons. A Result carries each tag at most once, and an
+-- undone vote takes its reasons with it.
+CREATE TABLE IF NOT EXISTS "ReasonTags" (
+    "Id" VARCHAR(36) NOT NULL
+        CONSTRAINT pk_reason_tags PRIMARY KEY,
+    "Label" VARCHAR(64) NOT NULL,
+    "Position" INT NOT NULL DEFAULT 0,
+    "Active" BOOLEAN NOT NULL DEFAULT TRUE
+);
+
+INSERT INTO "ReasonTags" ("Id", "Label", "Position") VALUES
+    ('textura', 'Textura', 1),
+    ('dolcor', 'Dolçor', 2),
+    ('intensitat', 'Intensitat', 3),
+    ('ingredients', 'Ingredients', 4),
+    ('aspecte', 'Aspecte', 5)
+ON CONFLICT ("Id") DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS "ResultReasons" (
+    "ResultId" VARCHAR(36) NOT NULL
+        CONSTRAINT fk_result_reasons_result
+        REFERENCES "Results"("Id") ON DELETE CASCADE,
+    "TagId" VARCHAR(36) NOT NULL
+        CONSTRAINT fk_result_reasons_tag
+        REFERENCES "ReasonTags"("Id"),
+    "CreatedAt" TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT pk_result_reasons PRIMARY KEY ("ResultId", "TagId")
+);
+
+CREATE INDEX IF NOT EXISTS idx_result_reasons_tag
+    ON "ResultReasons"("TagId");
@@ -0,0 +1,2 @@
+-- Drop the recommender's item-item similarities.
+DROP TABLE IF EXISTS "TorroSimilarities";
@@ -0,0 +1,19 @@
+-- Create TorroSimilarities for the recommender (see internal/recommend): the
+-- item-item similarity of two torrons across every user's personal
+-- snapshots, i.e. how alike people who rated both feel about them. One row
+-- per pair with Torro1 < Torro2 and enough users in common; the server
+-- refits it on a schedule and replaces every row at once.
+CREATE TABLE IF NOT EXISTS "TorroSimilarities" (
+    "Torro1" VARCHAR(36) NOT NULL
+        CONSTRAINT fk_torro_similarities_torro1
+        REFERENCES "Torrons"("Id") ON DELETE CASCADE,
+    "Torro2" VARCHAR(36) NOT NULL
+        CONSTRAINT fk_torro_similarities_torro2
+        REFERENCES "Torrons"("Id") ON DELETE CASCADE,
+    -- Shrunk adjusted cosine, from -1 to 1.
+    "Similarity" NUMERIC NOT NULL,
+    -- How many users have a snapshot of both.
+    "CoRaters" INT NOT NULL,
+    "FittedAt" TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT pk_torro_similarities PRIMARY KEY ("Torro1", "Torro2")
+);
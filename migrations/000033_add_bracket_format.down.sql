@@ -0,0 +1,8 @@
+DELETE FROM "BracketMatches" WHERE "Side" <> 'winners';
+
+DROP INDEX IF EXISTS idx_bracket_matches_slot;
+CREATE UNIQUE INDEX idx_bracket_matches_slot ON "BracketMatches"("BracketId", "Round", "Slot");
+
+ALTER TABLE "BracketMatches" DROP COLUMN IF EXISTS "Side";
+ALTER TABLE "Brackets" DROP COLUMN IF EXISTS "GrandFinalReset";
+ALTER TABLE "Brackets" DROP COLUMN IF EXISTS "Format";
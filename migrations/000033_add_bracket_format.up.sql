@@ -0,0 +1,26 @@
+-- Bracket formats: alongside the original single-elimination knockout, a
+-- double-elimination bracket gives every torró a second life on a losers
+-- side before the grand final. Existing brackets keep the old behaviour
+-- through the column default.
+ALTER TABLE "Brackets"
+    ADD COLUMN IF NOT EXISTS "Format" VARCHAR(20) NOT NULL DEFAULT 'single_elimination'
+        CONSTRAINT chk_bracket_format
+        CHECK ("Format" IN ('single_elimination', 'double_elimination'));
+
+-- Double elimination only: whether a grand final lost by the winners-side
+-- champion (their first defeat) is replayed, so the title always takes two
+-- defeats to lose.
+ALTER TABLE "Brackets"
+    ADD COLUMN IF NOT EXISTS "GrandFinalReset" BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- Which side of the bracket a match belongs to. A single-elimination
+-- bracket only ever has winners-side matches; "Round" stays the
+-- bracket-wide round the match is played in, so both sides of a
+-- double-elimination bracket can share a round.
+ALTER TABLE "BracketMatches"
+    ADD COLUMN IF NOT EXISTS "Side" VARCHAR(20) NOT NULL DEFAULT 'winners'
+        CONSTRAINT chk_bracket_match_side
+        CHECK ("Side" IN ('winners', 'losers', 'grand_final'));
+
+DROP INDEX IF EXISTS idx_bracket_matches_slot;
+CREATE UNIQUE INDEX idx_bracket_matches_slot ON "BracketMatches"("BracketId", "Round", "Side", "Slot");
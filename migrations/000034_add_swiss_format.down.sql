@@ -0,0 +1,8 @@
+DELETE FROM "Brackets" WHERE "Format" = 'swiss';
+
+ALTER TABLE "Brackets" DROP COLUMN IF EXISTS "Rounds";
+
+ALTER TABLE "Brackets" DROP CONSTRAINT IF EXISTS chk_bracket_format;
+ALTER TABLE "Brackets"
+    ADD CONSTRAINT chk_bracket_format
+    CHECK ("Format" IN ('single_elimination', 'double_elimination'));
@@ -0,0 +1,14 @@
+-- Swiss-system brackets: a fixed number of rounds in which torrons with
+-- equal running scores meet, no one is knocked out, and the final standings
+-- (score, then Buchholz, then seed) name the champion. Matches reuse the
+-- "BracketMatches"/"BracketMatchVotes" tables, all on the winners side.
+ALTER TABLE "Brackets" DROP CONSTRAINT IF EXISTS chk_bracket_format;
+ALTER TABLE "Brackets"
+    ADD CONSTRAINT chk_bracket_format
+    CHECK ("Format" IN ('single_elimination', 'double_elimination', 'swiss'));
+
+-- How many rounds a Swiss bracket plays. Knockout formats leave it at 0:
+-- their rounds follow from "Size".
+ALTER TABLE "Brackets"
+    ADD COLUMN IF NOT EXISTS "Rounds" INT NOT NULL DEFAULT 0
+        CONSTRAINT chk_bracket_rounds_non_negative CHECK ("Rounds" >= 0);
@@ -0,0 +1,17 @@
+DELETE FROM "Brackets" WHERE "Format" = 'groups_knockout';
+
+DROP INDEX IF EXISTS idx_bracket_matches_group;
+ALTER TABLE "BracketMatches" DROP COLUMN IF EXISTS "GroupId";
+ALTER TABLE "BracketEntries" DROP COLUMN IF EXISTS "GroupId";
+
+DROP TABLE IF EXISTS "BracketGroups";
+
+ALTER TABLE "BracketMatches" DROP CONSTRAINT IF EXISTS chk_bracket_match_side;
+ALTER TABLE "BracketMatches"
+    ADD CONSTRAINT chk_bracket_match_side
+    CHECK ("Side" IN ('winners', 'losers', 'grand_final'));
+
+ALTER TABLE "Brackets" DROP CONSTRAINT IF EXISTS chk_bracket_format;
+ALTER TABLE "Brackets"
+    ADD CONSTRAINT chk_bracket_format
+    CHECK ("Format" IN ('single_elimination', 'double_elimination', 'swiss'));
@@ -0,0 +1,39 @@
+-- Groups then knockout: a bracket's torrons are first drawn, pot by pot,
+-- into round-robin groups; the top of each group table goes through to the
+-- usual single-elimination tree. Group matches reuse "BracketMatches" and
+-- "BracketMatchVotes" on their own side, tagged with their group.
+ALTER TABLE "Brackets" DROP CONSTRAINT IF EXISTS chk_bracket_format;
+ALTER TABLE "Brackets"
+    ADD CONSTRAINT chk_bracket_format
+    CHECK ("Format" IN ('single_elimination', 'double_elimination', 'swiss', 'groups_knockout'));
+
+ALTER TABLE "BracketMatches" DROP CONSTRAINT IF EXISTS chk_bracket_match_side;
+ALTER TABLE "BracketMatches"
+    ADD CONSTRAINT chk_bracket_match_side
+    CHECK ("Side" IN ('winners', 'losers', 'grand_final', 'group'));
+
+-- BracketGroups: the groups of a groups-then-knockout bracket, "A", "B", ...
+CREATE TABLE IF NOT EXISTS "BracketGroups" (
+    "Id" VARCHAR(36) NOT NULL
+        CONSTRAINT pk_bracket_groups PRIMARY KEY,
+    "BracketId" VARCHAR(36) NOT NULL
+        CONSTRAINT fk_bracket_group_bracket
+        REFERENCES "Brackets"("Id") ON DELETE CASCADE,
+    "Position" INT NOT NULL
+        CONSTRAINT chk_bracket_group_position_non_negative CHECK ("Position" >= 0),
+    "Name" VARCHAR(8) NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_bracket_groups_position ON "BracketGroups"("BracketId", "Position");
+
+ALTER TABLE "BracketEntries"
+    ADD COLUMN IF NOT EXISTS "GroupId" VARCHAR(36)
+        CONSTRAINT fk_bracket_entry_group
+        REFERENCES "BracketGroups"("Id") ON DELETE CASCADE;
+
+ALTER TABLE "BracketMatches"
+    ADD COLUMN IF NOT EXISTS "GroupId" VARCHAR(36)
+        CONSTRAINT fk_bracket_match_group
+        REFERENCES "BracketGroups"("Id") ON DELETE CASCADE;
+
+CREATE INDEX idx_bracket_matches_group ON "BracketMatches"("GroupId");
@@ -0,0 +1,18 @@
+-- Deadline-driven rounds: a bracket created with a schedule gets one row
+-- per round with the time it opens and the deadline it closes at. The
+-- bracket scheduler resolves a round whose deadline has passed as if an
+-- admin had forced it; a round voted on every match still closes early.
+-- Brackets without rows here only advance by votes or by an admin.
+CREATE TABLE IF NOT EXISTS "BracketRounds" (
+    "BracketId" VARCHAR(36) NOT NULL
+        CONSTRAINT fk_bracket_round_bracket
+        REFERENCES "Brackets"("Id") ON DELETE CASCADE,
+    "Round" INT NOT NULL
+        CONSTRAINT chk_bracket_round_positive CHECK ("Round" >= 1),
+    "OpensAt" TIMESTAMP NOT NULL,
+    "ClosesAt" TIMESTAMP NOT NULL,
+    CONSTRAINT pk_bracket_rounds PRIMARY KEY ("BracketId", "Round"),
+    CONSTRAINT chk_bracket_round_window CHECK ("ClosesAt" > "OpensAt")
+);
+
+CREATE INDEX idx_bracket_rounds_closes_at ON "BracketRounds"("ClosesAt");
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "BracketPredictionPicks";
+DROP TABLE IF EXISTS "BracketPredictions";
@@ -0,0 +1,36 @@
+-- Bracket pick'em: before a single-elimination bracket's first round
+-- opens, a user can predict it whole, one winner per match slot through to
+-- the champion. A user has at most one prediction per bracket; submitting
+-- again before the lock replaces its picks. Nothing here stores a score:
+-- picks are scored against the bracket's decided matches on every read.
+CREATE TABLE IF NOT EXISTS "BracketPredictions" (
+    "Id" VARCHAR(36) PRIMARY KEY,
+    "BracketId" VARCHAR(36) NOT NULL
+        CONSTRAINT fk_bracket_prediction_bracket
+        REFERENCES "Brackets"("Id") ON DELETE CASCADE,
+    "UserId" VARCHAR(36) NOT NULL
+        CONSTRAINT fk_bracket_prediction_user
+        REFERENCES "Users"("Id") ON DELETE CASCADE,
+    "ChampionId" VARCHAR(36) NOT NULL
+        CONSTRAINT fk_bracket_prediction_champion
+        REFERENCES "Torrons"("Id") ON DELETE CASCADE,
+    "CreatedAt" TIMESTAMP NOT NULL DEFAULT NOW(),
+    "UpdatedAt" TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_bracket_prediction_user UNIQUE ("BracketId", "UserId")
+);
+
+-- One pick per (Round, Slot) of the winners side, the same position a
+-- BracketMatches row takes once the match is played.
+CREATE TABLE IF NOT EXISTS "BracketPredictionPicks" (
+    "PredictionId" VARCHAR(36) NOT NULL
+        CONSTRAINT fk_bracket_prediction_pick_prediction
+        REFERENCES "BracketPredictions"("Id") ON DELETE CASCADE,
+    "Round" INT NOT NULL
+        CONSTRAINT chk_bracket_prediction_pick_round CHECK ("Round" >= 1),
+    "Slot" INT NOT NULL
+        CONSTRAINT chk_bracket_prediction_pick_slot CHECK ("Slot" >= 0),
+    "TorronId" VARCHAR(36) NOT NULL
+        CONSTRAINT fk_bracket_prediction_pick_torron
+        REFERENCES "Torrons"("Id") ON DELETE CASCADE,
+    CONSTRAINT pk_bracket_prediction_picks PRIMARY KEY ("PredictionId", "Round", "Slot")
+);
@@ -0,0 +1,2 @@
+ALTER TABLE "Brackets" DROP COLUMN IF EXISTS "SeedingInputs";
+ALTER TABLE "Brackets" DROP COLUMN IF EXISTS "SeedingStrategy";
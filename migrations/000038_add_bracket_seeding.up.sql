@@ -0,0 +1,12 @@
+-- Selectable bracket seeding: how a bracket's field was chosen and ordered,
+-- and what it was chosen from, so the seeds can be audited and drawn again.
+-- "SeedingInputs" holds the strategy's inputs (the campaign rated, the
+-- published random seed, an admin's seed list, the excluded torrons, the
+-- strength fit's time). Existing brackets were all seeded by global rating.
+ALTER TABLE "Brackets"
+    ADD COLUMN IF NOT EXISTS "SeedingStrategy" VARCHAR(20) NOT NULL DEFAULT 'rating'
+        CONSTRAINT chk_bracket_seeding_strategy
+        CHECK ("SeedingStrategy" IN ('rating', 'campaign_rating', 'strength', 'random', 'manual'));
+
+ALTER TABLE "Brackets"
+    ADD COLUMN IF NOT EXISTS "SeedingInputs" JSONB NOT NULL DEFAULT '{}'::jsonb;
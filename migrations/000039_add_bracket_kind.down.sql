@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_brackets_kind;
+ALTER TABLE "Brackets" DROP COLUMN IF EXISTS "Kind";
@@ -0,0 +1,12 @@
+-- The Gran Final: a meta-bracket for the Global class whose entries are the
+-- champions (and optionally runners-up) of a campaign's completed class
+-- brackets, created automatically once the last of them completes. "Kind"
+-- tells it apart from the class brackets. Existing brackets are all class
+-- brackets; idx_brackets_campaign_class still allows one Gran Final per
+-- campaign.
+ALTER TABLE "Brackets"
+    ADD COLUMN IF NOT EXISTS "Kind" VARCHAR(20) NOT NULL DEFAULT 'class'
+        CONSTRAINT chk_bracket_kind
+        CHECK ("Kind" IN ('class', 'gran_final'));
+
+CREATE INDEX IF NOT EXISTS idx_brackets_kind ON "Brackets"("Kind", "CreatedAt");
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_results_seq;
+ALTER TABLE "Results" DROP COLUMN IF EXISTS "Seq";
+DROP SEQUENCE IF EXISTS "Results_Seq_seq";
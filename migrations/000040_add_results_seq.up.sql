@@ -0,0 +1,29 @@
+-- The order votes were applied in. The duels of one ranking vote share a
+-- transaction, and so could share a "Timestamp", while the tie-breaking
+-- "Id" is a random UUID: ordering by the two, a replay or a torró's rating
+-- history could take those duels in another order than the live vote did.
+-- "Seq" is drawn from a sequence on every insert, under the same torró row
+-- locks the rating update holds, so it follows the order the ratings moved
+-- in. Existing rows are numbered in the order they used to be replayed.
+CREATE SEQUENCE IF NOT EXISTS "Results_Seq_seq" AS BIGINT;
+
+ALTER TABLE "Results"
+    ADD COLUMN IF NOT EXISTS "Seq" BIGINT;
+
+UPDATE "Results" res
+SET "Seq" = numbered.seq
+FROM (
+    SELECT "Id", ROW_NUMBER() OVER (ORDER BY "Timestamp", "Id") AS seq
+    FROM "Results"
+) numbered
+WHERE res."Id" = numbered."Id";
+
+SELECT setval('"Results_Seq_seq"', COALESCE((SELECT MAX("Seq") FROM "Results"), 0) + 1, false);
+
+ALTER TABLE "Results"
+    ALTER COLUMN "Seq" SET DEFAULT nextval('"Results_Seq_seq"'),
+    ALTER COLUMN "Seq" SET NOT NULL;
+
+ALTER SEQUENCE "Results_Seq_seq" OWNED BY "Results"."Seq";
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_results_seq ON "Results"("Seq");